@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"net/http"
 	"os"
 	"os/signal"
@@ -52,7 +53,19 @@ import (
 // @tag.name Todos
 // @tag.description Todo item management operations within lists
 
+// serverRuntime holds process-wide state reported in the shutdown summary
+type serverRuntime struct {
+	db        *gorm.DB
+	inFlight  *middleware.InFlightTracker
+	purger    *storage.RetentionPurger      // nil when completed todo retention is disabled
+	demo      *auth.DemoPurger              // nil when demo accounts are disabled
+	keys      *middleware.IdempotencyPurger // nil when Idempotency-Key handling is disabled
+	startTime time.Time
+}
+
 func main() {
+	startTime := time.Now()
+
 	// Initialize logging first
 	logConfig := logging.NewLogConfigFromEnv()
 	logging.InitLogger(logConfig)
@@ -69,16 +82,25 @@ func main() {
 	var listHandler *handlers.ListHandler
 	var todoHandler *handlers.TodoHandler
 	var authHandler *handlers.AuthHandler
+	var adminHandler *handlers.AdminHandler
 	var healthHandler *handlers.HealthHandler
 	var jwtConfig *auth.JWTConfig
+	var demoPurger *auth.DemoPurger
 	var db *gorm.DB
+	var store storage.Store
+	var emailVerifier middleware.EmailVerifier   // nil in in-memory mode
+	var accountChecker middleware.AccountChecker // nil in in-memory mode
+
+	todoConfig := handlers.NewTodoConfigFromEnv()
+	handlers.ApplyValidationConfig(handlers.NewValidationConfigFromEnv())
+	storageConfig := storage.NewConfigFromEnv()
 
 	if useInMemory {
 		logging.Logger.Info("Using in-memory storage")
 		logging.Logger.Warn("In-memory storage does not support authentication - API will run without auth")
-		store := storage.NewStorage()
+		store = storage.NewStorageWithConfig(storageConfig)
 		listHandler = handlers.NewListHandler(store)
-		todoHandler = handlers.NewTodoHandler(store)
+		todoHandler = handlers.NewTodoHandlerWithConfig(store, todoConfig)
 	} else {
 		// Initialize PostgreSQL connection
 		dbConfig := database.NewConfigFromEnv()
@@ -92,6 +114,9 @@ func main() {
 		if err := database.AutoMigrate(db); err != nil {
 			logging.Logger.Fatalf("Failed to run migrations: %v", err)
 		}
+		if err := database.ConfigureUniqueTodoDescriptions(db, storageConfig.UniqueTodoDescriptions); err != nil {
+			logging.Logger.Fatalf("Failed to configure unique todo descriptions: %v", err)
+		}
 
 		logging.Logger.Info("PostgreSQL storage initialized successfully")
 
@@ -99,25 +124,71 @@ func main() {
 		jwtConfig = auth.NewJWTConfigFromEnv()
 
 		// Initialize authentication service
-		authService := auth.NewService(db, jwtConfig)
+		authConfig := auth.NewServiceConfigFromEnv()
+		authService := auth.NewServiceWithConfig(db, jwtConfig, authConfig)
 		authHandler = handlers.NewAuthHandler(authService)
+		adminHandler = handlers.NewAdminHandler(authService)
+		emailVerifier = authService
+		accountChecker = authService
+
+		// Periodically purge expired demo accounts if demo mode is enabled
+		if authConfig.DemoAccountTTL > 0 {
+			demoPurger = auth.NewDemoPurger(authService, authConfig.DemoPurgeInterval)
+			demoPurger.Start()
+			logging.Logger.Infof("Demo accounts enabled: expiring after %s, purged every %s",
+				authConfig.DemoAccountTTL, authConfig.DemoPurgeInterval)
+		}
 
 		// Initialize PostgreSQL storage
-		store := storage.NewPostgresStorage(db)
+		store = storage.NewPostgresStorageWithConfig(db, storageConfig)
 		listHandler = handlers.NewListHandler(store)
-		todoHandler = handlers.NewTodoHandler(store)
+		todoHandler = handlers.NewTodoHandlerWithSettings(store, todoConfig, authService)
 
 		// Initialize health handler with database connection
-		healthHandler = handlers.NewHealthHandler(db)
+		healthHandler = handlers.NewHealthHandlerWithConfig(db, handlers.NewHealthConfigFromEnv())
+	}
+
+	// Periodically purge old completed and deleted todos if a retention period is configured
+	var purger *storage.RetentionPurger
+	if storageConfig.CompletedRetention > 0 || storageConfig.TrashRetention > 0 {
+		purger = storage.NewRetentionPurgerWithConfig(store, storageConfig)
+		purger.Start()
+		logging.Logger.Infof("Purging completed todos older than %s and deleted todos older than %s every %s (0s = never)",
+			storageConfig.CompletedRetention, storageConfig.TrashRetention, storageConfig.RetentionInterval)
+	}
+
+	// Idempotency keys are looked up one at a time; expired ones are deleted in bulk periodically
+	idempotencyConfig := middleware.NewIdempotencyConfigFromEnv()
+	idempotencyStore := middleware.NewIdempotencyStore(idempotencyConfig, db)
+	var idempotencyPurger *middleware.IdempotencyPurger
+	if idempotencyConfig.Enabled {
+		idempotencyPurger = middleware.NewIdempotencyPurger(idempotencyStore, idempotencyConfig.PurgeInterval)
+		idempotencyPurger.Start()
 	}
 
 	// Set up Gin router (without default logger since we'll use our own)
 	router := gin.New()
 	router.Use(gin.Recovery()) // Add recovery middleware
 
-	// Add security headers (should be first)
+	// Add security headers (first after recovery, so every response has them)
 	router.Use(middleware.SecurityHeaders())
 
+	// Tag each request with an ID that is logged and echoed in X-Request-ID
+	router.Use(middleware.RequestID())
+
+	// Track in-flight requests so shutdown can report how many were drained
+	inFlight := middleware.NewInFlightTracker()
+	router.Use(inFlight.Middleware())
+
+	// Record the response field naming (camelCase or snake_case)
+	router.Use(middleware.JSONFieldCase(middleware.NewJSONFieldCaseFromEnv()))
+
+	// Pick the locale of error messages from Accept-Language
+	router.Use(middleware.Locale(middleware.NewDefaultLocaleFromEnv()))
+
+	// Compress large JSON responses for clients that accept gzip or deflate
+	router.Use(middleware.Compression(middleware.NewCompressionConfigFromEnv()))
+
 	// Add CORS middleware
 	corsConfig := middleware.NewCORSConfigFromEnv()
 	router.Use(middleware.CORS(corsConfig))
@@ -127,11 +198,22 @@ func main() {
 	router.Use(middleware.RequestSizeLimit(securityConfig.MaxRequestBodySize))
 
 	// Add request logging middleware
-	router.Use(middleware.RequestLogger())
+	router.Use(middleware.RequestLoggerWithConfig(middleware.NewRequestLogConfigFromEnv()))
 
 	// Add error sanitization (catches panics and sanitizes errors)
 	router.Use(middleware.ErrorSanitizer())
 
+	// Reject mutating requests while read-only mode is enabled
+	router.Use(middleware.ReadOnly(middleware.NewReadOnlyConfigFromEnv()))
+
+	// Answer 406 to requests that accept none of a route's media types when
+	// strict negotiation is enabled; routes not declared here are JSON-only
+	negotiationConfig := middleware.NewContentNegotiationConfigFromEnv()
+	negotiationConfig.Produces("/api/v1/lists/:listId/export", "application/json", "text/csv")
+	negotiationConfig.Produces("/api/v1/lists/:listId/todos/:todoId/export", "application/json", "text/markdown")
+	negotiationConfig.Produces("/api/v1/lists/:listId/calendar.ics", "text/calendar")
+	router.Use(middleware.ContentNegotiation(negotiationConfig))
+
 	// Initialize rate limiting configuration
 	rateLimitConfig := middleware.NewRateLimitConfigFromEnv()
 
@@ -141,8 +223,24 @@ func main() {
 	// API version 1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// Server time (public - lets clients reconcile clock skew)
+		v1.GET("/time", handlers.NewTimeHandler().ServerTime)
+
+		// Lists shared through a share link (public - read-only, the token is the credential)
+		public := v1.Group("/public")
+		public.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+		public.GET("/lists/:token", listHandler.GetSharedList)
+
+		// Deactivated and deleted users' access tokens are refused, and role
+		// changes apply without waiting for tokens to expire
+		active := middleware.RequireActiveAccount(accountChecker)
+
 		// Authentication routes (public - no auth required)
 		if authHandler != nil {
+			// Token status is kept out of the auth group's brute-force limiter so
+			// clients can poll it; the global limiter still applies
+			v1.GET("/auth/token/status", authHandler.TokenStatus)
+
 			auth := v1.Group("/auth")
 			// Apply stricter rate limiting to auth endpoints to prevent brute-force
 			auth.Use(middleware.PerUserAuthRateLimiter(rateLimitConfig))
@@ -151,42 +249,142 @@ func main() {
 				auth.POST("/login", authHandler.Login)
 				auth.POST("/refresh", authHandler.RefreshToken)
 				auth.POST("/logout", authHandler.Logout)
+				auth.POST("/demo", authHandler.CreateDemo)
+				auth.POST("/password/forgot", authHandler.ForgotPassword)
+				auth.POST("/password/reset", authHandler.ResetPassword)
+				auth.GET("/verify/confirm", authHandler.ConfirmEmail)
+				auth.POST("/verify/confirm", authHandler.ConfirmEmail)
 
 				// Protected auth routes (require authentication)
 				// These use per-user rate limiting after auth middleware sets user_id
 				protected := auth.Group("")
 				protected.Use(middleware.AuthMiddleware(jwtConfig))
+				protected.Use(active)
 				protected.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+				protected.POST("/logout-all", authHandler.LogoutAll)
 				protected.GET("/profile", authHandler.GetProfile)
 				protected.PUT("/profile", authHandler.UpdateProfile)
 				protected.PUT("/password", authHandler.ChangePassword)
+				protected.DELETE("/account", authHandler.DeleteAccount)
+				protected.POST("/verify/request", authHandler.RequestVerification)
+				protected.GET("/sessions", authHandler.ListSessions)
+				protected.DELETE("/sessions/:sessionId", middleware.UUIDValidator("sessionId"), authHandler.RevokeSession)
+				protected.GET("/settings", authHandler.GetSettings)
+				protected.PUT("/settings", authHandler.UpdateSettings)
 			}
+
+			// User management (admin only)
+			admin := v1.Group("/admin")
+			admin.Use(middleware.AuthMiddleware(jwtConfig))
+			admin.Use(active)
+			admin.Use(middleware.AdminMiddleware())
+			admin.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.PATCH("/users/:userId", middleware.UUIDValidator("userId"), adminHandler.UpdateUser)
 		}
 
+		// Creating lists and todos can be limited to users with a verified email
+		verified := middleware.RequireVerifiedEmail(middleware.NewEmailVerificationConfigFromEnv(), emailVerifier)
+
+		// Creating lists, todos and subtasks honors Idempotency-Key so clients can retry safely
+		idempotent := middleware.Idempotency(idempotencyConfig, idempotencyStore)
+
 		// Todo List routes (protected - require authentication)
 		lists := v1.Group("/lists")
 		if jwtConfig != nil {
 			lists.Use(middleware.AuthMiddleware(jwtConfig))
+			lists.Use(active)
 			// Apply per-user rate limiting after auth (user_id is set in context)
 			lists.Use(middleware.PerUserRateLimiter(rateLimitConfig))
 		}
 		lists.GET("", listHandler.GetAllLists)
-		lists.POST("", listHandler.CreateList)
+		lists.POST("", verified, idempotent, listHandler.CreateList)
+		lists.GET("/summary", listHandler.GetListSummary)
 
 		// Routes with listId parameter - validate UUID
 		lists.GET("/:listId", middleware.UUIDValidator("listId"), listHandler.GetListByID)
 		lists.PUT("/:listId", middleware.UUIDValidator("listId"), listHandler.UpdateList)
+		lists.PATCH("/:listId", middleware.UUIDValidator("listId"), listHandler.UpdateList)
 		lists.DELETE("/:listId", middleware.UUIDValidator("listId"), listHandler.DeleteList)
+		lists.POST("/:listId/merge", middleware.UUIDValidator("listId"), listHandler.MergeLists)
+		lists.GET("/:listId/export", middleware.UUIDValidator("listId"), listHandler.ExportList)
+		lists.GET("/:listId/calendar.ics", middleware.UUIDValidator("listId"), listHandler.GetListCalendar)
+		lists.POST("/:listId/import", middleware.UUIDValidator("listId"), verified, todoHandler.ImportTodos)
+		lists.POST("/:listId/duplicate", middleware.UUIDValidator("listId"), verified, listHandler.DuplicateList)
+		lists.POST("/:listId/archive", middleware.UUIDValidator("listId"), listHandler.ArchiveList)
+		lists.POST("/:listId/unarchive", middleware.UUIDValidator("listId"), listHandler.UnarchiveList)
+		lists.GET("/:listId/stats", middleware.UUIDValidator("listId"), listHandler.GetListStats)
+		lists.HEAD("/:listId/stats", middleware.UUIDValidator("listId"), listHandler.HeadListStats)
+		lists.POST("/:listId/share-link", middleware.UUIDValidator("listId"), listHandler.CreateShareLink)
+		lists.DELETE("/:listId/share-link/:linkId", middleware.UUIDValidator("listId", "linkId"), listHandler.RevokeShareLink)
 
 		// Todo routes (nested under lists) - validate both listId and todoId
 		lists.GET("/:listId/todos", middleware.UUIDValidator("listId"), todoHandler.GetTodosByList)
-		lists.POST("/:listId/todos", middleware.UUIDValidator("listId"), todoHandler.CreateTodo)
+		lists.POST("/:listId/todos", middleware.UUIDValidator("listId"), verified, idempotent, todoHandler.CreateTodo)
+		lists.POST("/:listId/todos/batch", middleware.UUIDValidator("listId"), verified, idempotent, todoHandler.BatchCreateTodos)
+		lists.PATCH("/:listId/todos/batch", middleware.UUIDValidator("listId"), todoHandler.BatchUpdateTodos)
+		lists.POST("/:listId/todos/import-text", middleware.UUIDValidator("listId"), verified, todoHandler.ImportText)
 		lists.GET("/:listId/todos/:todoId", middleware.UUIDValidator("listId", "todoId"), todoHandler.GetTodoByID)
 		lists.PUT("/:listId/todos/:todoId", middleware.UUIDValidator("listId", "todoId"), todoHandler.UpdateTodo)
+		lists.PATCH("/:listId/todos/:todoId", middleware.UUIDValidator("listId", "todoId"), todoHandler.UpdateTodo)
 		lists.DELETE("/:listId/todos/:todoId", middleware.UUIDValidator("listId", "todoId"), todoHandler.DeleteTodo)
+		lists.POST("/:listId/todos/:todoId/clone", middleware.UUIDValidator("listId", "todoId"), verified, todoHandler.CloneTodo)
+		lists.POST("/:listId/todos/:todoId/move", middleware.UUIDValidator("listId", "todoId"), todoHandler.MoveTodo)
+		lists.POST("/:listId/todos/:todoId/pin", middleware.UUIDValidator("listId", "todoId"), todoHandler.PinTodo)
+		lists.POST("/:listId/todos/:todoId/unpin", middleware.UUIDValidator("listId", "todoId"), todoHandler.UnpinTodo)
+		lists.PUT("/:listId/todos/:todoId/position", middleware.UUIDValidator("listId", "todoId"), todoHandler.ReorderTodo)
+		lists.GET("/:listId/todos/:todoId/export", middleware.UUIDValidator("listId", "todoId"), todoHandler.ExportTodo)
+		lists.GET("/:listId/trash", middleware.UUIDValidator("listId"), todoHandler.GetTrash)
+		lists.POST("/:listId/trash/:todoId/restore", middleware.UUIDValidator("listId", "todoId"), todoHandler.RestoreTodo)
+		lists.GET("/:listId/todos/:todoId/subtasks", middleware.UUIDValidator("listId", "todoId"), todoHandler.GetSubtasks)
+		lists.POST("/:listId/todos/:todoId/subtasks", middleware.UUIDValidator("listId", "todoId"), idempotent, todoHandler.CreateSubtask)
+		lists.PUT("/:listId/todos/:todoId/subtasks/:subtaskId",
+			middleware.UUIDValidator("listId", "todoId", "subtaskId"), todoHandler.UpdateSubtask)
+		lists.DELETE("/:listId/todos/:todoId/subtasks/:subtaskId",
+			middleware.UUIDValidator("listId", "todoId", "subtaskId"), todoHandler.DeleteSubtask)
+
+		// Cross-list todo routes (protected - require authentication)
+		todos := v1.Group("/todos")
+		if jwtConfig != nil {
+			todos.Use(middleware.AuthMiddleware(jwtConfig))
+			todos.Use(active)
+			todos.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+		}
+		todos.GET("", todoHandler.GetAllTodos)
+		todos.GET("/overdue", todoHandler.GetOverdueTodos)
+		todos.POST("/triage", todoHandler.TriageOverdueTodos)
+
+		// Search across the user's todos (protected - require authentication)
+		search := v1.Group("/search")
+		if jwtConfig != nil {
+			search.Use(middleware.AuthMiddleware(jwtConfig))
+			search.Use(active)
+			search.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+		}
+		search.GET("", todoHandler.SearchTodos)
+
+		// Statistics across the user's lists (protected - require authentication)
+		stats := v1.Group("/stats")
+		if jwtConfig != nil {
+			stats.Use(middleware.AuthMiddleware(jwtConfig))
+			stats.Use(active)
+			stats.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+		}
+		stats.GET("/summary", listHandler.GetUserStats)
+
+		// Overdue and due-soon notifications (protected - require authentication)
+		notifications := v1.Group("/notifications")
+		if jwtConfig != nil {
+			notifications.Use(middleware.AuthMiddleware(jwtConfig))
+			notifications.Use(active)
+			notifications.Use(middleware.PerUserRateLimiter(rateLimitConfig))
+		}
+		notifications.GET("", todoHandler.GetNotifications)
+		notifications.POST("/:notificationId/ack", todoHandler.AcknowledgeNotification)
 	}
 
 	// Health check endpoints
+	router.GET("/health/metrics", handlers.NewMetricsHandler(inFlight).RequestMetrics)
 	if healthHandler != nil {
 		router.GET("/health", healthHandler.BasicHealth)
 		router.GET("/health/detailed", healthHandler.DetailedHealth)
@@ -215,18 +413,20 @@ func main() {
 
 	// Check if TLS is enabled
 	tlsConf := tlsconfig.NewConfigFromEnv()
+	runtime := &serverRuntime{db: db, inFlight: inFlight, purger: purger, demo: demoPurger,
+		keys: idempotencyPurger, startTime: startTime}
 
 	if tlsConf.Enabled {
 		// Run with HTTPS
-		startHTTPSServer(router, tlsConf, port, db)
+		startHTTPSServer(router, tlsConf, port, runtime)
 	} else {
 		// Run with HTTP only
-		startHTTPServer(router, port, db)
+		startHTTPServer(router, port, runtime)
 	}
 }
 
 // startHTTPServer starts an HTTP-only server
-func startHTTPServer(router *gin.Engine, port string, db *gorm.DB) {
+func startHTTPServer(router *gin.Engine, port string, runtime *serverRuntime) {
 	srv := &http.Server{
 		Addr:           ":" + port,
 		Handler:        router,
@@ -244,11 +444,11 @@ func startHTTPServer(router *gin.Engine, port string, db *gorm.DB) {
 	}()
 
 	// Wait for interrupt signal to gracefully shutdown
-	waitForShutdown(db, srv)
+	waitForShutdown(runtime, srv)
 }
 
 // startHTTPSServer starts an HTTPS server with optional HTTP redirect
-func startHTTPSServer(router *gin.Engine, tlsConf *tlsconfig.Config, httpPort string, db *gorm.DB) {
+func startHTTPSServer(router *gin.Engine, tlsConf *tlsconfig.Config, httpPort string, runtime *serverRuntime) {
 	// Create TLS config
 	tlsConfig, err := tlsConf.CreateTLSConfig()
 	if err != nil {
@@ -293,11 +493,12 @@ func startHTTPSServer(router *gin.Engine, tlsConf *tlsconfig.Config, httpPort st
 	}
 
 	// Wait for interrupt signal to gracefully shutdown both servers
-	waitForShutdown(db, httpsSrv, httpSrv)
+	waitForShutdown(runtime, httpsSrv, httpSrv)
 }
 
-// waitForShutdown waits for interrupt signal and gracefully shuts down servers
-func waitForShutdown(db *gorm.DB, servers ...*http.Server) {
+// waitForShutdown waits for interrupt signal, gracefully shuts down servers
+// and logs a summary of drained requests, database pool stats and uptime
+func waitForShutdown(runtime *serverRuntime, servers ...*http.Server) {
 	// Setup signal catching
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -305,6 +506,7 @@ func waitForShutdown(db *gorm.DB, servers ...*http.Server) {
 	// Wait for signal
 	sig := <-quit
 	logging.Logger.Infof("Received signal %v, shutting down gracefully...", sig)
+	inFlightAtSignal := runtime.inFlight.Active()
 
 	// Gracefully shutdown servers
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -318,11 +520,31 @@ func waitForShutdown(db *gorm.DB, servers ...*http.Server) {
 		}
 	}
 
-	// Close database connection if it exists
-	if db != nil {
+	abandoned := runtime.inFlight.Active()
+	drained := inFlightAtSignal - abandoned
+	if drained < 0 {
+		drained = 0
+	}
+
+	// Stop the background purges before the database goes away
+	if runtime.purger != nil {
+		runtime.purger.Stop()
+	}
+	if runtime.demo != nil {
+		runtime.demo.Stop()
+	}
+	if runtime.keys != nil {
+		runtime.keys.Stop()
+	}
+
+	// Close database connection if it exists, capturing pool stats first
+	var dbStats *sql.DBStats
+	if runtime.db != nil {
 		logging.Logger.Info("Closing database connection...")
-		sqlDB, err := db.DB()
+		sqlDB, err := runtime.db.DB()
 		if err == nil {
+			stats := sqlDB.Stats()
+			dbStats = &stats
 			if err := sqlDB.Close(); err != nil {
 				logging.Logger.Errorf("Database close error: %v", err)
 			} else {
@@ -331,5 +553,6 @@ func waitForShutdown(db *gorm.DB, servers ...*http.Server) {
 		}
 	}
 
-	logging.Logger.Info("Server stopped")
+	summary := handlers.ShutdownSummary(time.Since(runtime.startTime), drained, abandoned, dbStats)
+	logging.Logger.WithFields(summary).Info("Server stopped")
 }
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrCannotModifySelf is returned when an admin tries to deactivate or demote
+// themselves, which could leave a deployment without an active admin
+var ErrCannotModifySelf = errors.New("admins cannot deactivate or demote themselves")
+
+// ListUsers returns a page of users, oldest account first, for admins
+func (s *Service) ListUsers(page, limit int) ([]models.User, *models.Pagination, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	var total int64
+	if err := s.db.Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	users := make([]models.User, 0, limit)
+	err := s.db.Order("created_at ASC, id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, &models.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (int(total) + limit - 1) / limit,
+		TotalItems: int(total),
+	}, nil
+}
+
+// SetUserActive activates or deactivates a user on behalf of the admin
+// actorID. Deactivating also revokes the user's refresh tokens; their access
+// tokens are refused by the middleware that checks account status.
+func (s *Service) SetUserActive(actorID, userID uuid.UUID, active bool) (*models.User, error) {
+	if actorID == userID && !active {
+		return nil, ErrCannotModifySelf
+	}
+
+	return s.updateUser(userID, func(tx *gorm.DB, user *models.User) error {
+		if err := tx.Model(user).Update("is_active", active).Error; err != nil {
+			return err
+		}
+		if active {
+			return nil
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// SetUserRole changes a user's role on behalf of the admin actorID
+func (s *Service) SetUserRole(actorID, userID uuid.UUID, role models.UserRole) (*models.User, error) {
+	if actorID == userID && role != models.RoleAdmin {
+		return nil, ErrCannotModifySelf
+	}
+
+	return s.updateUser(userID, func(tx *gorm.DB, user *models.User) error {
+		return tx.Model(user).Update("role", role).Error
+	})
+}
+
+// updateUser loads a user and applies update to it in a transaction,
+// returning the updated user or ErrUserNotFound
+func (s *Service) updateUser(userID uuid.UUID, update func(tx *gorm.DB, user *models.User) error) (*models.User, error) {
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if findErr := tx.Where("id = ?", userID).First(&user).Error; findErr != nil {
+			if errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return findErr
+		}
+		return update(tx, &user)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return &user, nil
+}
+
+// AccountStatus reports whether a user may still use the API and their current
+// role. Deleted, deactivated and expired demo accounts are reported as inactive.
+func (s *Service) AccountStatus(userID uuid.UUID) (bool, models.UserRole, error) {
+	var users []models.User
+	err := s.db.Select("id", "role", "is_active", "expires_at").Where("id = ?", userID).Limit(1).Find(&users).Error
+	if err != nil {
+		return false, "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if len(users) == 0 || !users[0].IsActive || users[0].IsExpired(time.Now()) {
+		return false, "", nil
+	}
+	return true, users[0].Role, nil
+}
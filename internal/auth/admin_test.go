@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"testing"
+
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUsers(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+		_, err := service.Register(&models.RegisterRequest{Email: email, Password: "SecurePass123!"})
+		require.NoError(t, err)
+	}
+
+	users, pagination, err := service.ListUsers(1, 2)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, &models.Pagination{Page: 1, Limit: 2, TotalPages: 2, TotalItems: 3}, pagination)
+
+	rest, _, err := service.ListUsers(2, 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	for _, user := range users {
+		assert.NotEqual(t, rest[0].ID, user.ID)
+	}
+}
+
+func TestSetUserActive(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	admin, err := service.Register(&models.RegisterRequest{Email: "admin@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	_, err = service.Register(&models.RegisterRequest{Email: "member@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	session, err := service.Login(&models.LoginRequest{Email: "member@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	member := session.User
+
+	t.Run("deactivating revokes sessions and access", func(t *testing.T) {
+		user, err := service.SetUserActive(admin.ID, member.ID, false)
+		require.NoError(t, err)
+		assert.False(t, user.IsActive)
+
+		active, _, err := service.AccountStatus(member.ID)
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		_, err = service.RefreshAccessToken(session.RefreshToken)
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		_, err = service.Login(&models.LoginRequest{Email: "member@example.com", Password: "SecurePass123!"})
+		assert.ErrorIs(t, err, ErrUserInactive)
+	})
+
+	t.Run("reactivating restores login", func(t *testing.T) {
+		user, err := service.SetUserActive(admin.ID, member.ID, true)
+		require.NoError(t, err)
+		assert.True(t, user.IsActive)
+
+		active, role, err := service.AccountStatus(member.ID)
+		require.NoError(t, err)
+		assert.True(t, active)
+		assert.Equal(t, models.RoleUser, role)
+
+		_, err = service.Login(&models.LoginRequest{Email: "member@example.com", Password: "SecurePass123!"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("admins cannot deactivate themselves", func(t *testing.T) {
+		_, err := service.SetUserActive(admin.ID, admin.ID, false)
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+	})
+
+	t.Run("unknown users are not found", func(t *testing.T) {
+		_, err := service.SetUserActive(admin.ID, uuid.New(), false)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		active, _, err := service.AccountStatus(uuid.New())
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+}
+
+func TestSetUserRole(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	admin, err := service.Register(&models.RegisterRequest{Email: "admin@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	member, err := service.Register(&models.RegisterRequest{Email: "member@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	user, err := service.SetUserRole(admin.ID, member.ID, models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+
+	_, role, err := service.AccountStatus(member.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, role)
+
+	_, err = service.SetUserRole(admin.ID, admin.ID, models.RoleUser)
+	assert.ErrorIs(t, err, ErrCannotModifySelf)
+
+	_, err = service.SetUserRole(admin.ID, uuid.New(), models.RoleAdmin)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// demoTodo is a todo seeded into every new demo account
+type demoTodo struct {
+	description string
+	priority    models.Priority
+	dueIn       time.Duration // 0 = no due date
+	completed   bool
+}
+
+// demoTodos shows off priorities, due dates and completion in the seeded list
+var demoTodos = []demoTodo{
+	{description: "Explore the API with this demo account", priority: models.PriorityHigh, dueIn: time.Hour},
+	{description: "Create a list of your own", priority: models.PriorityMedium, dueIn: 24 * time.Hour},
+	{description: "Try filtering todos by priority", priority: models.PriorityLow},
+	{description: "Sign up for a demo account", priority: models.PriorityMedium, completed: true},
+}
+
+// CreateDemoUser creates a throwaway account that expires after the configured
+// demo TTL, seeds it with a sample list and returns tokens for it, recording
+// client on the session
+func (s *Service) CreateDemoUser(client models.ClientInfo) (*models.AuthResponse, error) {
+	if s.config.DemoAccountTTL <= 0 {
+		return nil, ErrDemoDisabled
+	}
+
+	// Demo accounts are only reachable through their tokens, so the password
+	// is random and never returned
+	password, err := GenerateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate demo password: %w", err)
+	}
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.DemoAccountTTL)
+	userID := uuid.New()
+	// The address cannot receive mail, so the account counts as verified
+	user := &models.User{
+		ID:            userID,
+		Email:         "demo-" + userID.String() + "@demo.invalid",
+		PasswordHash:  hashedPassword,
+		FirstName:     "Demo",
+		LastName:      "User",
+		Role:          models.RoleUser,
+		IsActive:      true,
+		EmailVerified: true,
+		ExpiresAt:     &expiresAt,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if createErr := tx.Create(user).Error; createErr != nil {
+			return createErr
+		}
+		return seedDemoData(tx, user.ID, now)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create demo user: %w", err)
+	}
+
+	return s.generateAuthResponse(user, client)
+}
+
+// seedDemoData creates the sample list and todos a demo account starts with
+func seedDemoData(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	list := &models.TodoList{
+		UserID:      userID,
+		Name:        "Getting started",
+		Description: "Sample todos for trying out the API",
+		Version:     1,
+	}
+	if err := tx.Create(list).Error; err != nil {
+		return err
+	}
+
+	for i, seed := range demoTodos {
+		todo := &models.Todo{
+			ListID:      list.ID,
+			Description: seed.description,
+			Priority:    seed.priority,
+			Position:    i,
+			Version:     1,
+		}
+		if seed.dueIn > 0 {
+			due := now.Add(seed.dueIn).UTC()
+			todo.DueDate = &due
+		}
+		if seed.completed {
+			todo.Completed = true
+			todo.CompletedAt = &now
+		}
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredDemoUsers permanently deletes demo accounts that expired before
+// now, along with their lists, todos, subtasks, tokens and settings. It returns the
+// number of accounts deleted.
+func (s *Service) PurgeExpiredDemoUsers(now time.Time) (int64, error) {
+	var purged int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&models.User{}).Select("id").
+			Where("expires_at IS NOT NULL AND expires_at <= ?", now)
+		if err := deleteUserData(tx, expired); err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("expires_at IS NOT NULL AND expires_at <= ?", now).Delete(&models.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired demo users: %w", err)
+	}
+	return purged, nil
+}
+
+// deleteUserData permanently deletes everything owned by the users whose IDs
+// the users subquery selects: their lists and todos, including soft-deleted
+// ones, with the todos' tags, subtasks and acknowledged notifications, and
+// their tokens, settings and password history. The users themselves are left
+// to the caller. Rows are removed explicitly rather than relying on ON DELETE
+// CASCADE, which a soft delete never triggers.
+func deleteUserData(tx *gorm.DB, users *gorm.DB) error {
+	lists := tx.Unscoped().Model(&models.TodoList{}).Select("id").Where("user_id IN (?)", users)
+	todos := tx.Unscoped().Model(&models.Todo{}).Select("id").Where("list_id IN (?)", lists)
+
+	// Delete children before their parents
+	for _, child := range []interface{}{&models.TodoTag{}, &models.Subtask{}, &models.AcknowledgedNotification{}} {
+		if err := tx.Where("todo_id IN (?)", todos).Delete(child).Error; err != nil {
+			return err
+		}
+	}
+	for _, child := range []interface{}{&models.Todo{}, &models.ListShareLink{}} {
+		if err := tx.Unscoped().Where("list_id IN (?)", lists).Delete(child).Error; err != nil {
+			return err
+		}
+	}
+	for _, owned := range []interface{}{
+		&models.TodoList{}, &models.RefreshToken{}, &models.UserSettings{}, &models.PasswordHistory{},
+		&models.PasswordResetToken{}, &models.EmailVerificationToken{}, &models.IdempotencyKey{},
+	} {
+		if err := tx.Unscoped().Where("user_id IN (?)", users).Delete(owned).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DemoPurger periodically deletes expired demo accounts and their data
+type DemoPurger struct {
+	service  *Service
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewDemoPurger creates a purger that removes expired demo accounts every interval
+func NewDemoPurger(service *Service, interval time.Duration) *DemoPurger {
+	return &DemoPurger{
+		service:  service,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the purge loop in the background until Stop is called
+func (p *DemoPurger) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.purge(now)
+			}
+		}
+	}()
+}
+
+// Stop ends the purge loop, waiting for a purge in progress to finish
+func (p *DemoPurger) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// purge deletes demo accounts that expired before now
+func (p *DemoPurger) purge(now time.Time) {
+	purged, err := p.service.PurgeExpiredDemoUsers(now)
+	if err != nil {
+		logging.Logger.Errorf("Demo account purge failed: %v", err)
+		return
+	}
+	if purged > 0 {
+		logging.Logger.WithField("purged", purged).Info("Purged expired demo accounts")
+	}
+}
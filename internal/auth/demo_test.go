@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupDemoService(t *testing.T, ttl time.Duration) (*Service, *gorm.DB) {
+	service, db := setupTestService(t)
+	service.config = &ServiceConfig{DemoAccountTTL: ttl}
+
+	// Scope list names per user as the real schema does so several demo
+	// accounts can have the sample list
+	require.NoError(t, db.Exec(
+		"CREATE UNIQUE INDEX idx_user_list_name ON todo_lists(user_id, name) WHERE deleted_at IS NULL AND archived = false",
+	).Error)
+	return service, db
+}
+
+// countOwned returns how many lists, todos and refresh tokens a user has
+func countOwned(t *testing.T, db *gorm.DB, user *models.UserInfo) (lists, todos, tokens int64) {
+	require.NoError(t, db.Unscoped().Model(&models.TodoList{}).Where("user_id = ?", user.ID).Count(&lists).Error)
+	require.NoError(t, db.Unscoped().Model(&models.Todo{}).
+		Where("list_id IN (?)", db.Unscoped().Model(&models.TodoList{}).Select("id").Where("user_id = ?", user.ID)).
+		Count(&todos).Error)
+	require.NoError(t, db.Model(&models.RefreshToken{}).Where("user_id = ?", user.ID).Count(&tokens).Error)
+	return lists, todos, tokens
+}
+
+func TestCreateDemoUser(t *testing.T) {
+	t.Run("disabled without a TTL", func(t *testing.T) {
+		service, _ := setupTestService(t)
+
+		_, err := service.CreateDemoUser(models.ClientInfo{})
+		assert.ErrorIs(t, err, ErrDemoDisabled)
+	})
+
+	t.Run("creates a seeded account with working tokens", func(t *testing.T) {
+		service, db := setupDemoService(t, time.Hour)
+
+		resp, err := service.CreateDemoUser(models.ClientInfo{})
+		require.NoError(t, err)
+		require.NotNil(t, resp.User.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *resp.User.ExpiresAt, time.Minute)
+
+		claims, err := ValidateAccessToken(resp.AccessToken, service.jwtConfig)
+		require.NoError(t, err)
+		assert.Equal(t, resp.User.ID, claims.UserID)
+
+		lists, todos, _ := countOwned(t, db, resp.User)
+		assert.Equal(t, int64(1), lists)
+		assert.Equal(t, int64(len(demoTodos)), todos)
+
+		// The refresh token is capped at the account's expiry
+		var token models.RefreshToken
+		require.NoError(t, db.Where("user_id = ?", resp.User.ID).First(&token).Error)
+		assert.False(t, token.ExpiresAt.After(*resp.User.ExpiresAt))
+
+		refreshed, err := service.RefreshAccessToken(resp.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, resp.User.ID, refreshed.User.ID)
+
+		// Each demo account is separate
+		other, err := service.CreateDemoUser(models.ClientInfo{})
+		require.NoError(t, err)
+		assert.NotEqual(t, resp.User.Email, other.User.Email)
+	})
+}
+
+func TestPurgeExpiredDemoUsers(t *testing.T) {
+	service, db := setupDemoService(t, time.Hour)
+
+	demo, err := service.CreateDemoUser(models.ClientInfo{})
+	require.NoError(t, err)
+	_, err = service.Register(&models.RegisterRequest{Email: "regular@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	// Nothing has expired yet
+	purged, err := service.PurgeExpiredDemoUsers(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	purged, err = service.PurgeExpiredDemoUsers(time.Now().Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var users int64
+	require.NoError(t, db.Unscoped().Model(&models.User{}).Where("id = ?", demo.User.ID).Count(&users).Error)
+	assert.Zero(t, users)
+	lists, todos, tokens := countOwned(t, db, demo.User)
+	assert.Zero(t, lists)
+	assert.Zero(t, todos)
+	assert.Zero(t, tokens)
+
+	_, err = service.RefreshAccessToken(demo.RefreshToken)
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	// Regular accounts never expire
+	_, err = service.Login(&models.LoginRequest{Email: "regular@example.com", Password: "SecurePass123!"})
+	assert.NoError(t, err)
+}
+
+func TestDemoPurgeIntervalFromEnv(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	for value, want := range map[string]time.Duration{
+		"":    10 * time.Minute,
+		"30s": 30 * time.Second,
+		"0":   10 * time.Minute,
+		"-1m": 10 * time.Minute,
+	} {
+		t.Run("DEMO_PURGE_INTERVAL="+value, func(t *testing.T) {
+			t.Setenv("DEMO_PURGE_INTERVAL", value)
+			assert.Equal(t, want, NewServiceConfigFromEnv().DemoPurgeInterval)
+		})
+	}
+}
+
+func TestDemoPurger(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	service, db := setupDemoService(t, time.Millisecond)
+	demo, err := service.CreateDemoUser(models.ClientInfo{})
+	require.NoError(t, err)
+
+	purger := NewDemoPurger(service, 5*time.Millisecond)
+	purger.Start()
+
+	assert.Eventually(t, func() bool {
+		var users int64
+		db.Unscoped().Model(&models.User{}).Where("id = ?", demo.User.ID).Count(&users)
+		return users == 0
+	}, time.Second, 5*time.Millisecond)
+
+	purger.Stop()
+	purger.Stop() // stopping twice is safe
+}
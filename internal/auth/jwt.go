@@ -47,16 +47,26 @@ type Claims struct {
 	UserID uuid.UUID       `json:"user_id"`
 	Email  string          `json:"email"`
 	Role   models.UserRole `json:"role"`
+	// SessionID is the ID of the refresh token issued alongside this access
+	// token, or uuid.Nil if there is none
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 // GenerateAccessToken generates a new JWT access token
 func GenerateAccessToken(user *models.User, config *JWTConfig) (string, error) {
+	return generateSessionAccessToken(user, uuid.Nil, config)
+}
+
+// generateSessionAccessToken generates a new JWT access token belonging to
+// the session with the given ID
+func generateSessionAccessToken(user *models.User, sessionID uuid.UUID, config *JWTConfig) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(), // Add unique JWT ID to ensure each token is unique
 			ExpiresAt: jwt.NewNumericDate(now.Add(config.AccessTokenDuration)),
@@ -147,6 +157,26 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvBool retrieves a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration retrieves a duration environment variable (e.g. "2h") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 // generateDefaultSecret generates a default secret key for development
 // WARNING: This should NEVER be used in production!
 func generateDefaultSecret() string {
@@ -0,0 +1,25 @@
+package auth
+
+import "time"
+
+// Mailer delivers account emails. Implementations plug in a real email
+// provider; the default NoopMailer sends nothing.
+type Mailer interface {
+	// SendPasswordReset emails a password reset token that expires at expiresAt
+	SendPasswordReset(email, token string, expiresAt time.Time) error
+	// SendEmailVerification emails an email verification token that expires at expiresAt
+	SendEmailVerification(email, token string, expiresAt time.Time) error
+}
+
+// NoopMailer is a Mailer that discards every email
+type NoopMailer struct{}
+
+// SendPasswordReset discards the reset email
+func (NoopMailer) SendPasswordReset(_, _ string, _ time.Time) error {
+	return nil
+}
+
+// SendEmailVerification discards the verification email
+func (NoopMailer) SendEmailVerification(_, _ string, _ time.Time) error {
+	return nil
+}
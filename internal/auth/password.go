@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"unicode"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -17,11 +18,49 @@ const (
 )
 
 var (
-	ErrPasswordTooShort = errors.New("password is too short")
-	ErrPasswordTooLong  = errors.New("password is too long")
-	ErrInvalidPassword  = errors.New("invalid password")
+	ErrPasswordTooShort      = errors.New("password is too short")
+	ErrPasswordTooLong       = errors.New("password is too long")
+	ErrInvalidPassword       = errors.New("invalid password")
+	ErrPasswordMissingUpper  = errors.New("password must contain an uppercase letter")
+	ErrPasswordMissingLower  = errors.New("password must contain a lowercase letter")
+	ErrPasswordMissingDigit  = errors.New("password must contain a digit")
+	ErrPasswordMissingSymbol = errors.New("password must contain a symbol")
 )
 
+// PasswordPolicy holds the rules a new password must satisfy beyond the
+// bcrypt length limits. The zero value only enforces those limits.
+type PasswordPolicy struct {
+	MinLength     int  // Minimum length in bytes, raised to MinPasswordLength if lower
+	RequireUpper  bool // Require an uppercase letter
+	RequireLower  bool // Require a lowercase letter
+	RequireDigit  bool // Require a digit
+	RequireSymbol bool // Require a character that is not a letter, digit or space
+}
+
+// NewPasswordPolicyFromEnv creates a password policy from environment variables
+func NewPasswordPolicyFromEnv() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     getEnvInt("PASSWORD_MIN_LENGTH", MinPasswordLength),
+		RequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", false),
+		RequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", false),
+		RequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+		RequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+	}
+}
+
+// MinimumLength returns the effective minimum length, which is never below
+// MinPasswordLength nor above MaxPasswordLength
+func (p PasswordPolicy) MinimumLength() int {
+	switch {
+	case p.MinLength < MinPasswordLength:
+		return MinPasswordLength
+	case p.MinLength > MaxPasswordLength:
+		return MaxPasswordLength
+	default:
+		return p.MinLength
+	}
+}
+
 // HashPassword hashes a password using bcrypt
 func HashPassword(password string) (string, error) {
 	if len(password) < MinPasswordLength {
@@ -51,13 +90,40 @@ func VerifyPassword(password, hashedPassword string) error {
 	return nil
 }
 
-// ValidatePasswordRequirements validates password requirements without hashing
-func ValidatePasswordRequirements(password string) error {
-	if len(password) < MinPasswordLength {
+// ValidatePasswordRequirements validates a password against the policy without
+// hashing it, returning the first rule it breaks: too short or too long, then
+// a missing uppercase letter, lowercase letter, digit or symbol
+func ValidatePasswordRequirements(password string, policy PasswordPolicy) error {
+	if len(password) < policy.MinimumLength() {
 		return ErrPasswordTooShort
 	}
 	if len(password) > MaxPasswordLength {
 		return ErrPasswordTooLong
 	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case policy.RequireUpper && !hasUpper:
+		return ErrPasswordMissingUpper
+	case policy.RequireLower && !hasLower:
+		return ErrPasswordMissingLower
+	case policy.RequireDigit && !hasDigit:
+		return ErrPasswordMissingDigit
+	case policy.RequireSymbol && !hasSymbol:
+		return ErrPasswordMissingSymbol
+	}
 	return nil
 }
@@ -96,33 +96,89 @@ func TestValidatePasswordRequirements(t *testing.T) {
 		}
 
 		for _, password := range passwords {
-			err := ValidatePasswordRequirements(password)
+			err := ValidatePasswordRequirements(password, PasswordPolicy{})
 			assert.NoError(t, err, "Password %s should be valid", password)
 		}
 	})
 
 	t.Run("rejects password too short", func(t *testing.T) {
-		err := ValidatePasswordRequirements("Short1!")
+		err := ValidatePasswordRequirements("Short1!", PasswordPolicy{})
 		assert.ErrorIs(t, err, ErrPasswordTooShort)
 	})
 
 	t.Run("rejects password too long", func(t *testing.T) {
 		password := strings.Repeat("a", 73)
-		err := ValidatePasswordRequirements(password)
+		err := ValidatePasswordRequirements(password, PasswordPolicy{})
 		assert.ErrorIs(t, err, ErrPasswordTooLong)
 	})
 
 	t.Run("accepts boundary length passwords", func(t *testing.T) {
 		// Minimum length (8 characters)
-		err := ValidatePasswordRequirements("12345678")
+		err := ValidatePasswordRequirements("12345678", PasswordPolicy{})
 		assert.NoError(t, err)
 
 		// Maximum length (72 characters)
-		err = ValidatePasswordRequirements(strings.Repeat("a", 72))
+		err = ValidatePasswordRequirements(strings.Repeat("a", 72), PasswordPolicy{})
 		assert.NoError(t, err)
 	})
 }
 
+func TestPasswordPolicy(t *testing.T) {
+	strict := PasswordPolicy{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	t.Run("accepts a password with every class", func(t *testing.T) {
+		assert.NoError(t, ValidatePasswordRequirements("Secure-Pass1", strict))
+		assert.NoError(t, ValidatePasswordRequirements("Ünïcødé-Pâss1", strict))
+	})
+
+	t.Run("names the missing class", func(t *testing.T) {
+		tests := []struct {
+			password string
+			want     error
+		}{
+			{"secure-pass1", ErrPasswordMissingUpper},
+			{"SECURE-PASS1", ErrPasswordMissingLower},
+			{"Secure-Pass", ErrPasswordMissingDigit},
+			{"SecurePass1", ErrPasswordMissingSymbol},
+			{"Secure Pass1", ErrPasswordMissingSymbol},
+		}
+		for _, tt := range tests {
+			err := ValidatePasswordRequirements(tt.password, strict)
+			assert.ErrorIs(t, err, tt.want, tt.password)
+		}
+	})
+
+	t.Run("only enforces enabled classes", func(t *testing.T) {
+		assert.NoError(t, ValidatePasswordRequirements("alllowercase", PasswordPolicy{RequireLower: true}))
+		assert.ErrorIs(t, ValidatePasswordRequirements("alllowercase", PasswordPolicy{RequireDigit: true}), ErrPasswordMissingDigit)
+	})
+
+	t.Run("checks length before classes", func(t *testing.T) {
+		assert.ErrorIs(t, ValidatePasswordRequirements("short", strict), ErrPasswordTooShort)
+	})
+
+	t.Run("applies a configured minimum length", func(t *testing.T) {
+		policy := PasswordPolicy{MinLength: 12}
+		assert.ErrorIs(t, ValidatePasswordRequirements("elevenchars", policy), ErrPasswordTooShort)
+		assert.NoError(t, ValidatePasswordRequirements("twelve chars", policy))
+	})
+
+	t.Run("keeps the minimum length within bcrypt limits", func(t *testing.T) {
+		assert.Equal(t, MinPasswordLength, PasswordPolicy{MinLength: 4}.MinimumLength())
+		assert.Equal(t, MaxPasswordLength, PasswordPolicy{MinLength: 100}.MinimumLength())
+		assert.ErrorIs(t, ValidatePasswordRequirements("seven77", PasswordPolicy{MinLength: 4}), ErrPasswordTooShort)
+	})
+
+	t.Run("reads the policy from the environment", func(t *testing.T) {
+		t.Setenv("PASSWORD_MIN_LENGTH", "10")
+		t.Setenv("PASSWORD_REQUIRE_UPPER", "true")
+		t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+
+		policy := NewPasswordPolicyFromEnv()
+		assert.Equal(t, PasswordPolicy{MinLength: 10, RequireUpper: true, RequireSymbol: true}, policy)
+	})
+}
+
 func TestPasswordSecurity(t *testing.T) {
 	t.Run("hash is not reversible", func(t *testing.T) {
 		password := testPassword
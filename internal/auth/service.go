@@ -5,36 +5,130 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"todolist-api/internal/logging"
 	"todolist-api/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// uniqueViolationCode is the PostgreSQL error code for a unique constraint violation
+const uniqueViolationCode = "23505"
+
+const (
+	// defaultPasswordResetTTL is how long a password reset token lasts when
+	// PasswordResetTTL is not set
+	defaultPasswordResetTTL = time.Hour
+	// defaultEmailVerificationTTL is how long an email verification token
+	// lasts when EmailVerificationTTL is not set
+	defaultEmailVerificationTTL = 24 * time.Hour
+
+	// maxUserAgentLength and maxIPAddressLength match the session columns
+	maxUserAgentLength = 255
+	maxIPAddressLength = 45
+)
+
 var (
 	ErrUserAlreadyExists   = errors.New("user with this email already exists")
 	ErrUserNotFound        = errors.New("user not found")
 	ErrInvalidCredentials  = errors.New("invalid credentials")
 	ErrUserInactive        = errors.New("user account is inactive")
 	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrInvalidTimezone     = errors.New("invalid timezone")
+	ErrPasswordReused      = errors.New("password was used recently")
+	ErrDemoDisabled        = errors.New("demo accounts are disabled")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrResetTokenInvalid   = errors.New("password reset token is invalid")
+	ErrResetTokenExpired   = errors.New("password reset token has expired")
+	ErrResetTokenUsed      = errors.New("password reset token has already been used")
+
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationTokenInvalid = errors.New("email verification token is invalid")
+	ErrVerificationTokenExpired = errors.New("email verification token has expired")
 )
 
+// ServiceConfig holds account security settings for the authentication service
+type ServiceConfig struct {
+	// PasswordHistorySize is how many of a user's most recent passwords,
+	// including the current one, a new password must differ from (0 = disabled)
+	PasswordHistorySize int
+	// PasswordPolicy is the complexity new passwords must meet
+	PasswordPolicy PasswordPolicy
+
+	// DemoAccountTTL is how long an account created by POST /auth/demo
+	// lasts before it is purged with its data (0 = demo mode disabled)
+	DemoAccountTTL time.Duration
+	// DemoPurgeInterval is how often expired demo accounts are purged
+	DemoPurgeInterval time.Duration
+
+	// PasswordResetTTL is how long a password reset token stays usable
+	PasswordResetTTL time.Duration
+	// EmailVerificationTTL is how long an email verification token stays usable
+	EmailVerificationTTL time.Duration
+	// Mailer delivers password reset and verification emails (nil = NoopMailer)
+	Mailer Mailer
+}
+
+// NewServiceConfigFromEnv creates authentication service config from environment variables
+func NewServiceConfigFromEnv() *ServiceConfig {
+	return &ServiceConfig{
+		PasswordHistorySize:  getEnvInt("PASSWORD_HISTORY_SIZE", 0),
+		PasswordPolicy:       NewPasswordPolicyFromEnv(),
+		DemoAccountTTL:       getEnvDuration("DEMO_ACCOUNT_TTL", 0),
+		DemoPurgeInterval:    getEnvPositiveDuration("DEMO_PURGE_INTERVAL", 10*time.Minute),
+		PasswordResetTTL:     getEnvDuration("PASSWORD_RESET_TTL", defaultPasswordResetTTL),
+		EmailVerificationTTL: getEnvDuration("EMAIL_VERIFICATION_TTL", defaultEmailVerificationTTL),
+	}
+}
+
+// getEnvPositiveDuration retrieves a duration environment variable that must be
+// positive, such as a ticker interval, falling back to the default with a
+// warning when it is zero or negative
+func getEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnvDuration(key, defaultValue)
+	if value <= 0 {
+		logging.Logger.Warnf("%s must be positive, got %s; using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
 // Service provides authentication operations
 type Service struct {
 	db        *gorm.DB
 	jwtConfig *JWTConfig
+	config    *ServiceConfig
 }
 
-// NewService creates a new authentication service
+// NewService creates a new authentication service with password history disabled
 func NewService(db *gorm.DB, jwtConfig *JWTConfig) *Service {
+	return NewServiceWithConfig(db, jwtConfig, &ServiceConfig{})
+}
+
+// NewServiceWithConfig creates a new authentication service with the given configuration
+func NewServiceWithConfig(db *gorm.DB, jwtConfig *JWTConfig, config *ServiceConfig) *Service {
 	return &Service{
 		db:        db,
 		jwtConfig: jwtConfig,
+		config:    config,
 	}
 }
 
+// ValidatePassword checks a new password against the configured password policy
+func (s *Service) ValidatePassword(password string) error {
+	return ValidatePasswordRequirements(password, s.config.PasswordPolicy)
+}
+
+// PasswordPolicy returns the configured password policy
+func (s *Service) PasswordPolicy() PasswordPolicy {
+	return s.config.PasswordPolicy
+}
+
 // Register creates a new user account
 func (s *Service) Register(req *models.RegisterRequest) (*models.User, error) {
 	// Check if user already exists
@@ -64,12 +158,29 @@ func (s *Service) Register(req *models.RegisterRequest) (*models.User, error) {
 	}
 
 	if err := s.db.Create(user).Error; err != nil {
+		// A concurrent registration can insert the same email between the
+		// check above and this insert
+		if isDuplicateEmail(err) {
+			return nil, ErrUserAlreadyExists
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return user, nil
 }
 
+// isDuplicateEmail reports whether err is a violation of the unique index on
+// users.email
+func isDuplicateEmail(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == uniqueViolationCode && pgErr.TableName == "users" &&
+			strings.Contains(pgErr.ConstraintName, "email")
+	}
+	// Other drivers, such as SQLite in tests, only name the column in the message
+	return strings.Contains(err.Error(), "users.email")
+}
+
 // Login authenticates a user and returns tokens
 func (s *Service) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
 	// Find user by email
@@ -98,7 +209,7 @@ func (s *Service) Login(req *models.LoginRequest) (*models.AuthResponse, error)
 	s.db.Model(&user).Update("last_login_at", now)
 
 	// Generate tokens
-	return s.generateAuthResponse(&user)
+	return s.generateAuthResponse(&user, req.Client)
 }
 
 // RefreshAccessToken generates a new access token using a refresh token
@@ -121,13 +232,16 @@ func (s *Service) RefreshAccessToken(refreshTokenString string) (*models.AuthRes
 		return nil, ErrRefreshTokenInvalid
 	}
 
-	// Check if user is active
-	if !refreshToken.User.IsActive {
+	// Check if user is active and, for demo accounts, not yet expired
+	if !refreshToken.User.IsActive || refreshToken.User.IsExpired(time.Now()) {
 		return nil, ErrUserInactive
 	}
 
-	// Generate new tokens
-	return s.generateAuthResponse(&refreshToken.User)
+	// Generate new tokens, continuing the session on the same client
+	return s.generateAuthResponse(&refreshToken.User, models.ClientInfo{
+		UserAgent: refreshToken.UserAgent,
+		IPAddress: refreshToken.IPAddress,
+	})
 }
 
 // RevokeRefreshToken revokes a refresh token
@@ -150,21 +264,101 @@ func (s *Service) RevokeRefreshToken(refreshTokenString string) error {
 	return nil
 }
 
-// RevokeAllUserTokens revokes all refresh tokens for a user
-func (s *Service) RevokeAllUserTokens(userID uuid.UUID) error {
+// RevokeAllUserTokens revokes all of a user's active refresh tokens and
+// returns how many it revoked. Tokens already revoked or expired are left as
+// they are.
+func (s *Service) RevokeAllUserTokens(userID uuid.UUID) (int64, error) {
 	now := time.Now()
 
-	err := s.db.Model(&models.RefreshToken{}).
-		Where("user_id = ? AND revoked_at IS NULL", userID).
-		Update("revoked_at", now).Error
+	result := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Update("revoked_at", now)
 
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke user tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListActiveSessions returns the user's refresh tokens that have neither
+// expired nor been revoked, newest first, flagging the one with currentID
+func (s *Service) ListActiveSessions(userID, currentID uuid.UUID) ([]models.Session, error) {
+	var tokens []models.RefreshToken
+	err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
 	if err != nil {
-		return fmt.Errorf("failed to revoke user tokens: %w", err)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]models.Session, len(tokens))
+	for i := range tokens {
+		sessions[i] = models.Session{
+			ID:        tokens[i].ID,
+			UserAgent: tokens[i].UserAgent,
+			IPAddress: tokens[i].IPAddress,
+			CreatedAt: tokens[i].CreatedAt,
+			ExpiresAt: tokens[i].ExpiresAt,
+			Current:   tokens[i].ID == currentID,
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeRefreshTokenByID revokes one of the user's sessions. Returns
+// ErrSessionNotFound if the user has no active session with that ID, which
+// includes sessions belonging to other users.
+func (s *Service) RevokeRefreshTokenByID(userID, sessionID uuid.UUID) error {
+	result := s.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, time.Now()).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
 	}
 
 	return nil
 }
 
+// TokenStatus reports whether an access token is valid at now and, if so,
+// whose it is and how many seconds it has left. Tokens that have expired, are
+// malformed or carry a bad signature, and tokens of users who are no longer
+// active, are reported as not valid with the reason.
+func (s *Service) TokenStatus(tokenString string, now time.Time) (*models.TokenStatus, error) {
+	claims, err := ValidateAccessToken(tokenString, s.jwtConfig)
+	if errors.Is(err, ErrExpiredToken) {
+		return &models.TokenStatus{Reason: "expired"}, nil
+	}
+	if err != nil {
+		return &models.TokenStatus{Reason: "invalid"}, nil
+	}
+
+	active, _, err := s.AccountStatus(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return &models.TokenStatus{Reason: "inactive"}, nil
+	}
+
+	expiresAt := claims.ExpiresAt.Time
+	remaining := int64(expiresAt.Sub(now) / time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &models.TokenStatus{
+		Valid:            true,
+		UserID:           &claims.UserID,
+		ExpiresAt:        &expiresAt,
+		SecondsRemaining: remaining,
+	}, nil
+}
+
 // GetUserByID retrieves a user by ID
 func (s *Service) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	var user models.User
@@ -223,20 +417,31 @@ func (s *Service) ChangePassword(userID uuid.UUID, req *models.ChangePasswordReq
 		return ErrInvalidCredentials
 	}
 
+	// Refuse recently used passwords when history is enabled
+	if reuseErr := s.checkPasswordReuse(&user, req.NewPassword); reuseErr != nil {
+		return reuseErr
+	}
+
 	// Hash new password
 	hashedPassword, err := HashPassword(req.NewPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Update password
-	if err := s.db.Model(&user).Update("password_hash", hashedPassword).Error; err != nil {
+	// Update password, remembering the old hash
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if historyErr := s.recordPasswordHistory(tx, user.ID, user.PasswordHash); historyErr != nil {
+			return historyErr
+		}
+		return tx.Model(&user).Update("password_hash", hashedPassword).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
 	// Revoke all existing refresh tokens for security
 	// We ignore errors here as it's not critical if token revocation fails
-	if revokeErr := s.RevokeAllUserTokens(userID); revokeErr != nil {
+	if _, revokeErr := s.RevokeAllUserTokens(userID); revokeErr != nil {
 		// Log but don't fail password change
 		fmt.Printf("Warning: Failed to revoke user tokens: %v\n", revokeErr)
 	}
@@ -244,6 +449,372 @@ func (s *Service) ChangePassword(userID uuid.UUID, req *models.ChangePasswordReq
 	return nil
 }
 
+// DeleteAccount permanently deletes a user after confirming their password,
+// together with their lists, todos and refresh tokens, in one transaction.
+// Access tokens already issued are refused by the account status check.
+func (s *Service) DeleteAccount(userID uuid.UUID, password string) error {
+	var user models.User
+	err := s.db.Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if verifyErr := VerifyPassword(password, user.PasswordHash); verifyErr != nil {
+		return ErrInvalidCredentials
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		account := tx.Unscoped().Model(&models.User{}).Select("id").Where("id = ?", user.ID)
+		if deleteErr := deleteUserData(tx, account); deleteErr != nil {
+			return deleteErr
+		}
+		return tx.Unscoped().Delete(&models.User{}, "id = ?", user.ID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
+
+// checkPasswordReuse returns ErrPasswordReused if password matches the user's
+// current password or one of the previous ones still inside the history window
+func (s *Service) checkPasswordReuse(user *models.User, password string) error {
+	size := s.config.PasswordHistorySize
+	if size <= 0 {
+		return nil
+	}
+
+	if VerifyPassword(password, user.PasswordHash) == nil {
+		return ErrPasswordReused
+	}
+	if size == 1 {
+		return nil
+	}
+
+	var history []models.PasswordHistory
+	err := s.db.Where("user_id = ?", user.ID).
+		Order("created_at DESC").
+		Limit(size - 1).
+		Find(&history).Error
+	if err != nil {
+		return fmt.Errorf("failed to load password history: %w", err)
+	}
+
+	for i := range history {
+		if VerifyPassword(password, history[i].PasswordHash) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory stores a replaced password hash and drops entries
+// that have fallen out of the history window
+func (s *Service) recordPasswordHistory(tx *gorm.DB, userID uuid.UUID, passwordHash string) error {
+	// The current password is checked directly, so only size-1 older ones are kept
+	keep := s.config.PasswordHistorySize - 1
+	if keep <= 0 {
+		return nil
+	}
+
+	entry := &models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+
+	var ids []uuid.UUID
+	if err := tx.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) > keep {
+		return tx.Where("id IN ?", ids[keep:]).Delete(&models.PasswordHistory{}).Error
+	}
+	return nil
+}
+
+// CreatePasswordResetToken issues a password reset token for the active user
+// with the given email, replacing any unused one, and hands it to the
+// configured Mailer. Only the token's hash is stored. Returns ErrUserNotFound
+// if no active user has the email.
+func (s *Service) CreatePasswordResetToken(email string) (string, error) {
+	var user models.User
+	err := s.db.Where("email = ?", email).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive || user.IsExpired(time.Now()) {
+		return "", ErrUserNotFound
+	}
+
+	token, err := GenerateRefreshToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	ttl := s.config.PasswordResetTTL
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	// Only the newest token is usable
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if deleteErr := tx.Where("user_id = ? AND used_at IS NULL", user.ID).
+			Delete(&models.PasswordResetToken{}).Error; deleteErr != nil {
+			return deleteErr
+		}
+		return tx.Create(resetToken).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	// A failed send must not reveal to the caller that the account exists
+	if mailErr := s.mailer().SendPasswordReset(user.Email, token, resetToken.ExpiresAt); mailErr != nil {
+		logging.Logger.Errorf("Failed to send password reset email: %v", mailErr)
+	}
+
+	return token, nil
+}
+
+// ResetPasswordWithToken sets a new password for the user a reset token was
+// issued to, uses up the token and revokes all the user's refresh tokens.
+// Returns ErrResetTokenInvalid, ErrResetTokenExpired or ErrResetTokenUsed if
+// the token cannot be used.
+func (s *Service) ResetPasswordWithToken(token, newPassword string) error {
+	var resetToken models.PasswordResetToken
+	err := s.db.Preload("User").Where("token_hash = ?", hashToken(token)).First(&resetToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrResetTokenInvalid
+		}
+		return fmt.Errorf("failed to find reset token: %w", err)
+	}
+
+	if resetToken.UsedAt != nil {
+		return ErrResetTokenUsed
+	}
+	now := time.Now()
+	if !now.Before(resetToken.ExpiresAt) {
+		return ErrResetTokenExpired
+	}
+
+	user := &resetToken.User
+	if !user.IsActive || user.IsExpired(now) {
+		return ErrUserInactive
+	}
+
+	// Refuse recently used passwords when history is enabled
+	if reuseErr := s.checkPasswordReuse(user, newPassword); reuseErr != nil {
+		return reuseErr
+	}
+
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Claim the token first so that concurrent resets with it cannot both succeed
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrResetTokenUsed
+		}
+
+		if historyErr := s.recordPasswordHistory(tx, user.ID, user.PasswordHash); historyErr != nil {
+			return historyErr
+		}
+		if updateErr := tx.Model(user).Update("password_hash", hashedPassword).Error; updateErr != nil {
+			return updateErr
+		}
+
+		// Sessions started with the old password must not survive the reset
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error
+	})
+	if errors.Is(err, ErrResetTokenUsed) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	return nil
+}
+
+// SendVerification issues an email verification token for the user,
+// invalidating any earlier one, and hands it to the configured Mailer. Only
+// the token's hash is stored. Returns ErrEmailAlreadyVerified if the user has
+// already confirmed their email.
+func (s *Service) SendVerification(userID uuid.UUID) (string, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if user.EmailVerified {
+		return "", ErrEmailAlreadyVerified
+	}
+
+	token, err := GenerateRefreshToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	ttl := s.config.EmailVerificationTTL
+	if ttl <= 0 {
+		ttl = defaultEmailVerificationTTL
+	}
+	verificationToken := &models.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	// Re-requesting invalidates every token sent before
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if deleteErr := tx.Where("user_id = ?", user.ID).Delete(&models.EmailVerificationToken{}).Error; deleteErr != nil {
+			return deleteErr
+		}
+		return tx.Create(verificationToken).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	if mailErr := s.mailer().SendEmailVerification(user.Email, token, verificationToken.ExpiresAt); mailErr != nil {
+		return "", fmt.Errorf("failed to send verification email: %w", mailErr)
+	}
+
+	return token, nil
+}
+
+// ConfirmEmail marks the email of the user a verification token was issued to
+// as verified. Confirming an already verified account succeeds without
+// changing it. Returns ErrVerificationTokenInvalid or
+// ErrVerificationTokenExpired if the token cannot be used.
+func (s *Service) ConfirmEmail(token string) (*models.User, error) {
+	var verificationToken models.EmailVerificationToken
+	err := s.db.Preload("User").Where("token_hash = ?", hashToken(token)).First(&verificationToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVerificationTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to find verification token: %w", err)
+	}
+
+	user := &verificationToken.User
+	if user.EmailVerified {
+		return user, nil
+	}
+	if !time.Now().Before(verificationToken.ExpiresAt) {
+		return nil, ErrVerificationTokenExpired
+	}
+
+	if err := s.db.Model(user).Update("email_verified", true).Error; err != nil {
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+	return user, nil
+}
+
+// IsEmailVerified reports whether the user has confirmed their email. Unknown
+// users are reported as unverified.
+func (s *Service) IsEmailVerified(userID uuid.UUID) (bool, error) {
+	var verified []bool
+	err := s.db.Model(&models.User{}).Where("id = ?", userID).Limit(1).Pluck("email_verified", &verified).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+	return len(verified) > 0 && verified[0], nil
+}
+
+// mailer returns the configured Mailer, or a NoopMailer if there is none
+func (s *Service) mailer() Mailer {
+	if s.config.Mailer == nil {
+		return NoopMailer{}
+	}
+	return s.config.Mailer
+}
+
+// GetSettings retrieves a user's settings, falling back to defaults if none are saved
+func (s *Service) GetSettings(userID uuid.UUID) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := s.db.Where("user_id = ?", userID).First(&settings).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultUserSettings(userID), nil
+		}
+		return nil, fmt.Errorf("failed to find settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpdateSettings applies the provided changes to a user's settings, creating them if needed
+func (s *Service) UpdateSettings(userID uuid.UUID, req *models.UpdateUserSettingsRequest) (*models.UserSettings, error) {
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	if _, err := s.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	var settings models.UserSettings
+	err := s.db.Where("user_id = ?", userID).First(&settings).Error
+	exists := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find settings: %w", err)
+	}
+	if !exists {
+		settings = *models.DefaultUserSettings(userID)
+	}
+
+	// Update fields if provided
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+	if req.HideCompleted != nil {
+		settings.HideCompleted = *req.HideCompleted
+	}
+	if req.DefaultSortBy != nil {
+		settings.DefaultSortBy = *req.DefaultSortBy
+	}
+	if req.DefaultSortOrder != nil {
+		settings.DefaultSortOrder = *req.DefaultSortOrder
+	}
+
+	if exists {
+		err = s.db.Save(&settings).Error
+	} else {
+		err = s.db.Create(&settings).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
 // CleanupExpiredTokens removes expired refresh tokens from the database
 func (s *Service) CleanupExpiredTokens() error {
 	err := s.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{}).Error
@@ -255,29 +826,36 @@ func (s *Service) CleanupExpiredTokens() error {
 
 // Private helper methods
 
-// generateAuthResponse generates access and refresh tokens for a user
-func (s *Service) generateAuthResponse(user *models.User) (*models.AuthResponse, error) {
-	// Generate access token
-	accessToken, err := GenerateAccessToken(user, s.jwtConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
-	}
-
+// generateAuthResponse generates access and refresh tokens for a user,
+// recording the client on the new session
+func (s *Service) generateAuthResponse(user *models.User, client models.ClientInfo) (*models.AuthResponse, error) {
 	// Generate refresh token
 	refreshTokenString, err := GenerateRefreshToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Store refresh token in database (hashed)
+	// Store refresh token in database (hashed), never outliving a demo account
+	expiresAt := time.Now().Add(s.jwtConfig.RefreshTokenDuration)
+	if user.ExpiresAt != nil && user.ExpiresAt.Before(expiresAt) {
+		expiresAt = *user.ExpiresAt
+	}
 	refreshToken := &models.RefreshToken{
 		UserID:    user.ID,
 		Token:     hashToken(refreshTokenString),
-		ExpiresAt: time.Now().Add(s.jwtConfig.RefreshTokenDuration),
+		ExpiresAt: expiresAt,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		IPAddress: truncate(client.IPAddress, maxIPAddressLength),
+	}
+
+	if createErr := s.db.Create(refreshToken).Error; createErr != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", createErr)
 	}
 
-	if err := s.db.Create(refreshToken).Error; err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	// Generate access token, tied to the session so it can be recognized
+	accessToken, err := generateSessionAccessToken(user, refreshToken.ID, s.jwtConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Build response
@@ -287,15 +865,28 @@ func (s *Service) generateAuthResponse(user *models.User) (*models.AuthResponse,
 		TokenType:    "Bearer",
 		ExpiresIn:    int(s.jwtConfig.AccessTokenDuration.Seconds()),
 		User: &models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role:      user.Role,
+			ID:            user.ID,
+			Email:         user.Email,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Role:          user.Role,
+			EmailVerified: user.EmailVerified,
+			ExpiresAt:     user.ExpiresAt,
 		},
 	}, nil
 }
 
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // hashToken creates a SHA-256 hash of a token for storage
 func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
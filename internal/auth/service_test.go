@@ -4,11 +4,15 @@ import (
 	"testing"
 	"time"
 
+	"todolist-api/internal/logging"
 	"todolist-api/internal/models"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -24,7 +28,13 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	err = db.Exec("PRAGMA foreign_keys = ON").Error
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.TodoList{}, &models.Todo{})
+	err = db.AutoMigrate(
+		&models.User{}, &models.RefreshToken{}, &models.TodoList{}, &models.Todo{}, &models.TodoTag{},
+		&models.Subtask{}, &models.UserSettings{}, &models.PasswordHistory{},
+		&models.PasswordResetToken{}, &models.EmailVerificationToken{}, &models.AcknowledgedNotification{},
+		&models.ListShareLink{},
+		&models.IdempotencyKey{},
+	)
 	require.NoError(t, err)
 
 	return db
@@ -95,6 +105,45 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+func TestRegisterConcurrentDuplicate(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	service := NewService(db, &JWTConfig{SecretKey: "test-secret-key-32-characters!!"})
+	register := func(insertErr error) error {
+		// The existence check passes, then another registration wins the race
+		mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "users"`).WillReturnError(insertErr)
+		mock.ExpectRollback()
+
+		_, err := service.Register(&models.RegisterRequest{
+			Email:    "race@example.com",
+			Password: "SecurePass123!",
+		})
+		return err
+	}
+
+	t.Run("maps the unique email violation", func(t *testing.T) {
+		err := register(&pgconn.PgError{Code: "23505", TableName: "users", ConstraintName: "users_email_key"})
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leaves other unique violations alone", func(t *testing.T) {
+		err := register(&pgconn.PgError{Code: "23505", TableName: "users", ConstraintName: "users_pkey"})
+		assert.NotErrorIs(t, err, ErrUserAlreadyExists)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestLogin(t *testing.T) {
 	service, _ := setupTestService(t)
 
@@ -212,6 +261,39 @@ func TestRevokeRefreshToken(t *testing.T) {
 	})
 }
 
+func TestRevokeAllUserTokens(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.Register(&models.RegisterRequest{Email: "everywhere@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	loginReq := &models.LoginRequest{Email: "everywhere@example.com", Password: "SecurePass123!"}
+	first, err := service.Login(loginReq)
+	require.NoError(t, err)
+	second, err := service.Login(loginReq)
+	require.NoError(t, err)
+	loggedOut, err := service.Login(loginReq)
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeRefreshToken(loggedOut.RefreshToken))
+
+	t.Run("revokes only active tokens", func(t *testing.T) {
+		revoked, err := service.RevokeAllUserTokens(first.User.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+
+		for _, token := range []string{first.RefreshToken, second.RefreshToken, loggedOut.RefreshToken} {
+			_, err = service.RefreshAccessToken(token)
+			assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		}
+	})
+
+	t.Run("revokes nothing the second time", func(t *testing.T) {
+		revoked, err := service.RevokeAllUserTokens(first.User.ID)
+		require.NoError(t, err)
+		assert.Zero(t, revoked)
+	})
+}
+
 func TestGetUserByID(t *testing.T) {
 	service, _ := setupTestService(t)
 
@@ -328,6 +410,474 @@ func TestChangePassword(t *testing.T) {
 	})
 }
 
+func TestChangePasswordHistory(t *testing.T) {
+	service, db := setupTestService(t)
+	service.config = &ServiceConfig{PasswordHistorySize: 2}
+
+	user, err := service.Register(&models.RegisterRequest{
+		Email:    "history@example.com",
+		Password: "FirstPassword1!",
+	})
+	require.NoError(t, err)
+
+	change := func(current, next string) error {
+		return service.ChangePassword(user.ID, &models.ChangePasswordRequest{
+			CurrentPassword: current,
+			NewPassword:     next,
+		})
+	}
+
+	require.NoError(t, change("FirstPassword1!", "SecondPassword2!"))
+
+	t.Run("rejects the immediately previous password", func(t *testing.T) {
+		assert.ErrorIs(t, change("SecondPassword2!", "FirstPassword1!"), ErrPasswordReused)
+	})
+
+	t.Run("rejects the current password", func(t *testing.T) {
+		assert.ErrorIs(t, change("SecondPassword2!", "SecondPassword2!"), ErrPasswordReused)
+	})
+
+	t.Run("allows a password older than the history window", func(t *testing.T) {
+		require.NoError(t, change("SecondPassword2!", "ThirdPassword3!"))
+		require.NoError(t, change("ThirdPassword3!", "FirstPassword1!"))
+	})
+
+	t.Run("keeps only the history window", func(t *testing.T) {
+		var count int64
+		require.NoError(t, db.Model(&models.PasswordHistory{}).Where("user_id = ?", user.ID).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	service, db := setupTestService(t)
+
+	register := func(email string) *models.UserInfo {
+		_, err := service.Register(&models.RegisterRequest{Email: email, Password: "SecurePass123!"})
+		require.NoError(t, err)
+		resp, err := service.Login(&models.LoginRequest{Email: email, Password: "SecurePass123!"})
+		require.NoError(t, err)
+		return resp.User
+	}
+	seed := func(user *models.UserInfo) {
+		list := &models.TodoList{ID: uuid.New(), UserID: user.ID, Name: "Inbox", Version: 1}
+		require.NoError(t, db.Create(list).Error)
+		for _, description := range []string{"Kept", "Trashed"} {
+			todo := &models.Todo{ID: uuid.New(), ListID: list.ID, Description: description, Priority: models.PriorityLow, Version: 1}
+			require.NoError(t, db.Create(todo).Error)
+			require.NoError(t, db.Create(&models.TodoTag{TodoID: todo.ID, Tag: "home"}).Error)
+			require.NoError(t, db.Create(&models.Subtask{ID: uuid.New(), TodoID: todo.ID, Description: "Step"}).Error)
+			if description == "Trashed" {
+				require.NoError(t, db.Delete(todo).Error)
+			}
+		}
+	}
+
+	user := register("leaving@example.com")
+	seed(user)
+	second, err := service.Login(&models.LoginRequest{Email: "leaving@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	other := register("staying@example.com")
+	seed(other)
+
+	t.Run("keeps everything when the password is wrong", func(t *testing.T) {
+		err := service.DeleteAccount(user.ID, "WrongPassword1!")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+		lists, todos, tokens := countOwned(t, db, user)
+		assert.Equal(t, int64(1), lists)
+		assert.Equal(t, int64(2), todos)
+		assert.Equal(t, int64(2), tokens)
+	})
+
+	t.Run("deletes the user and everything they own", func(t *testing.T) {
+		require.NoError(t, service.DeleteAccount(user.ID, "SecurePass123!"))
+
+		var users int64
+		require.NoError(t, db.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Count(&users).Error)
+		assert.Zero(t, users)
+		lists, todos, tokens := countOwned(t, db, user)
+		assert.Zero(t, lists)
+		assert.Zero(t, todos, "soft-deleted todos are removed too")
+		assert.Zero(t, tokens)
+
+		// Only the other user's tags and subtasks remain
+		var tags, subtasks int64
+		require.NoError(t, db.Model(&models.TodoTag{}).Count(&tags).Error)
+		require.NoError(t, db.Model(&models.Subtask{}).Count(&subtasks).Error)
+		assert.Equal(t, int64(2), tags)
+		assert.Equal(t, int64(2), subtasks)
+
+		_, err := service.RefreshAccessToken(second.RefreshToken)
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		_, err = service.Login(&models.LoginRequest{Email: "leaving@example.com", Password: "SecurePass123!"})
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+		lists, todos, tokens = countOwned(t, db, other)
+		assert.Equal(t, int64(1), lists)
+		assert.Equal(t, int64(2), todos)
+		assert.Equal(t, int64(1), tokens)
+	})
+
+	t.Run("returns not found once deleted", func(t *testing.T) {
+		err := service.DeleteAccount(user.ID, "SecurePass123!")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestTokenStatus(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.Register(&models.RegisterRequest{Email: "status@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	resp, err := service.Login(&models.LoginRequest{Email: "status@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	t.Run("reports the remaining lifetime of a valid token", func(t *testing.T) {
+		status, err := service.TokenStatus(resp.AccessToken, time.Now().Add(5*time.Minute))
+		require.NoError(t, err)
+		assert.True(t, status.Valid)
+		assert.Empty(t, status.Reason)
+		assert.Equal(t, resp.User.ID, *status.UserID)
+		assert.InDelta(t, 10*60, status.SecondsRemaining, 2)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), *status.ExpiresAt, 2*time.Second)
+	})
+
+	t.Run("reports an expired token as not valid", func(t *testing.T) {
+		expiredConfig := *service.jwtConfig
+		expiredConfig.AccessTokenDuration = -time.Minute
+		user, err := service.GetUserByID(resp.User.ID)
+		require.NoError(t, err)
+		expired, err := GenerateAccessToken(user, &expiredConfig)
+		require.NoError(t, err)
+
+		status, err := service.TokenStatus(expired, time.Now())
+		require.NoError(t, err)
+		assert.False(t, status.Valid)
+		assert.Equal(t, "expired", status.Reason)
+		assert.Zero(t, status.SecondsRemaining)
+		assert.Nil(t, status.UserID)
+	})
+
+	t.Run("reports malformed tokens as invalid", func(t *testing.T) {
+		status, err := service.TokenStatus("not-a-jwt", time.Now())
+		require.NoError(t, err)
+		assert.False(t, status.Valid)
+		assert.Equal(t, "invalid", status.Reason)
+	})
+
+	t.Run("reports tokens of deactivated users as inactive", func(t *testing.T) {
+		_, err := service.SetUserActive(uuid.Nil, resp.User.ID, false)
+		require.NoError(t, err)
+
+		status, err := service.TokenStatus(resp.AccessToken, time.Now())
+		require.NoError(t, err)
+		assert.False(t, status.Valid)
+		assert.Equal(t, "inactive", status.Reason)
+	})
+}
+
+func TestSessions(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.Register(&models.RegisterRequest{Email: "sessions@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	other, err := service.Register(&models.RegisterRequest{Email: "other@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	login := func(email, userAgent string) *models.AuthResponse {
+		resp, loginErr := service.Login(&models.LoginRequest{
+			Email:    email,
+			Password: "SecurePass123!",
+			Client:   models.ClientInfo{UserAgent: userAgent, IPAddress: "203.0.113.7"},
+		})
+		require.NoError(t, loginErr)
+		return resp
+	}
+	sessionOf := func(resp *models.AuthResponse) uuid.UUID {
+		claims, claimsErr := ValidateAccessToken(resp.AccessToken, service.jwtConfig)
+		require.NoError(t, claimsErr)
+		return claims.SessionID
+	}
+
+	laptop := login("sessions@example.com", "Laptop/1.0")
+	phone := login("sessions@example.com", "Phone/2.0")
+	otherSession := login("other@example.com", "Other/1.0")
+	userID := laptop.User.ID
+
+	t.Run("lists the user's sessions with the current one flagged", func(t *testing.T) {
+		sessions, err := service.ListActiveSessions(userID, sessionOf(phone))
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		byAgent := map[string]models.Session{}
+		for _, session := range sessions {
+			byAgent[session.UserAgent] = session
+		}
+		assert.True(t, byAgent["Phone/2.0"].Current)
+		assert.False(t, byAgent["Laptop/1.0"].Current)
+		assert.Equal(t, sessionOf(laptop), byAgent["Laptop/1.0"].ID)
+		assert.Equal(t, "203.0.113.7", byAgent["Laptop/1.0"].IPAddress)
+	})
+
+	t.Run("refreshing keeps the client of the session", func(t *testing.T) {
+		refreshed, err := service.RefreshAccessToken(laptop.RefreshToken)
+		require.NoError(t, err)
+
+		sessions, err := service.ListActiveSessions(userID, sessionOf(refreshed))
+		require.NoError(t, err)
+		for _, session := range sessions {
+			if session.Current {
+				assert.Equal(t, "Laptop/1.0", session.UserAgent)
+			}
+		}
+	})
+
+	t.Run("cannot revoke another user's session", func(t *testing.T) {
+		err := service.RevokeRefreshTokenByID(userID, sessionOf(otherSession))
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+
+		sessions, err := service.ListActiveSessions(other.ID, uuid.Nil)
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("revokes one session", func(t *testing.T) {
+		require.NoError(t, service.RevokeRefreshTokenByID(userID, sessionOf(phone)))
+
+		_, err := service.RefreshAccessToken(phone.RefreshToken)
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		_, err = service.RefreshAccessToken(laptop.RefreshToken)
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, service.RevokeRefreshTokenByID(userID, sessionOf(phone)), ErrSessionNotFound)
+	})
+}
+
+// recordingMailer remembers the last email instead of sending it
+type recordingMailer struct {
+	email string
+	token string
+	err   error
+}
+
+func (m *recordingMailer) SendPasswordReset(email, token string, _ time.Time) error {
+	m.email, m.token = email, token
+	return m.err
+}
+
+func (m *recordingMailer) SendEmailVerification(email, token string, _ time.Time) error {
+	m.email, m.token = email, token
+	return m.err
+}
+
+func TestPasswordReset(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	service, db := setupTestService(t)
+	mailer := &recordingMailer{}
+	service.config = &ServiceConfig{PasswordResetTTL: time.Hour, Mailer: mailer}
+
+	user, err := service.Register(&models.RegisterRequest{
+		Email:    "reset@example.com",
+		Password: "OldPassword1!",
+	})
+	require.NoError(t, err)
+
+	t.Run("mails a token and stores only its hash", func(t *testing.T) {
+		token, err := service.CreatePasswordResetToken("reset@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "reset@example.com", mailer.email)
+		assert.Equal(t, token, mailer.token)
+
+		var stored models.PasswordResetToken
+		require.NoError(t, db.Where("user_id = ?", user.ID).First(&stored).Error)
+		assert.Equal(t, hashToken(token), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		_, err := service.CreatePasswordResetToken("nobody@example.com")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("a mailer failure does not fail the request", func(t *testing.T) {
+		mailer.err = assert.AnError
+		defer func() { mailer.err = nil }()
+		_, err := service.CreatePasswordResetToken("reset@example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("a new token replaces the unused one", func(t *testing.T) {
+		first, err := service.CreatePasswordResetToken("reset@example.com")
+		require.NoError(t, err)
+		_, err = service.CreatePasswordResetToken("reset@example.com")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, service.ResetPasswordWithToken(first, "NewPassword2!"), ErrResetTokenInvalid)
+	})
+
+	t.Run("resets the password once and revokes refresh tokens", func(t *testing.T) {
+		session, err := service.Login(&models.LoginRequest{Email: "reset@example.com", Password: "OldPassword1!"})
+		require.NoError(t, err)
+
+		token, err := service.CreatePasswordResetToken("reset@example.com")
+		require.NoError(t, err)
+		require.NoError(t, service.ResetPasswordWithToken(token, "NewPassword2!"))
+
+		_, err = service.Login(&models.LoginRequest{Email: "reset@example.com", Password: "NewPassword2!"})
+		require.NoError(t, err)
+		_, err = service.RefreshAccessToken(session.RefreshToken)
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+		assert.ErrorIs(t, service.ResetPasswordWithToken(token, "OtherPassword3!"), ErrResetTokenUsed)
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		token, err := service.CreatePasswordResetToken("reset@example.com")
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&models.PasswordResetToken{}).
+			Where("token_hash = ?", hashToken(token)).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		assert.ErrorIs(t, service.ResetPasswordWithToken(token, "OtherPassword3!"), ErrResetTokenExpired)
+	})
+
+	t.Run("rejects an unknown token", func(t *testing.T) {
+		assert.ErrorIs(t, service.ResetPasswordWithToken("not-a-token", "OtherPassword3!"), ErrResetTokenInvalid)
+	})
+}
+
+func TestEmailVerification(t *testing.T) {
+	service, db := setupTestService(t)
+	mailer := &recordingMailer{}
+	service.config = &ServiceConfig{EmailVerificationTTL: time.Hour, Mailer: mailer}
+
+	user, err := service.Register(&models.RegisterRequest{
+		Email:    "verify@example.com",
+		Password: "SecurePass123!",
+	})
+	require.NoError(t, err)
+	assert.False(t, user.EmailVerified)
+
+	t.Run("re-requesting invalidates the earlier token", func(t *testing.T) {
+		first, err := service.SendVerification(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "verify@example.com", mailer.email)
+		assert.Equal(t, first, mailer.token)
+
+		second, err := service.SendVerification(user.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+
+		_, err = service.ConfirmEmail(first)
+		assert.ErrorIs(t, err, ErrVerificationTokenInvalid)
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		token, err := service.SendVerification(user.ID)
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&models.EmailVerificationToken{}).
+			Where("token_hash = ?", hashToken(token)).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		_, err = service.ConfirmEmail(token)
+		assert.ErrorIs(t, err, ErrVerificationTokenExpired)
+	})
+
+	t.Run("confirms the email and is idempotent", func(t *testing.T) {
+		token, err := service.SendVerification(user.ID)
+		require.NoError(t, err)
+
+		verified, err := service.IsEmailVerified(user.ID)
+		require.NoError(t, err)
+		assert.False(t, verified)
+
+		confirmed, err := service.ConfirmEmail(token)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, confirmed.ID)
+
+		_, err = service.ConfirmEmail(token)
+		assert.NoError(t, err)
+
+		verified, err = service.IsEmailVerified(user.ID)
+		require.NoError(t, err)
+		assert.True(t, verified)
+	})
+
+	t.Run("refuses to send once verified", func(t *testing.T) {
+		_, err := service.SendVerification(user.ID)
+		assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+	})
+
+	t.Run("unknown users", func(t *testing.T) {
+		_, err := service.SendVerification(uuid.New())
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		verified, err := service.IsEmailVerified(uuid.New())
+		require.NoError(t, err)
+		assert.False(t, verified)
+
+		_, err = service.ConfirmEmail("not-a-token")
+		assert.ErrorIs(t, err, ErrVerificationTokenInvalid)
+	})
+}
+
+func TestUserSettings(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	user, err := service.Register(&models.RegisterRequest{
+		Email:    "settings@example.com",
+		Password: "SecurePass123!",
+	})
+	require.NoError(t, err)
+
+	t.Run("returns defaults before anything is saved", func(t *testing.T) {
+		settings, err := service.GetSettings(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", settings.Timezone)
+		assert.False(t, settings.HideCompleted)
+		assert.Equal(t, "createdAt", settings.DefaultSortBy)
+		assert.Equal(t, "asc", settings.DefaultSortOrder)
+	})
+
+	t.Run("round-trips updates", func(t *testing.T) {
+		tz := "Europe/Amsterdam"
+		hide := true
+		sortBy := "priority"
+		_, err := service.UpdateSettings(user.ID, &models.UpdateUserSettingsRequest{
+			Timezone:      &tz,
+			HideCompleted: &hide,
+			DefaultSortBy: &sortBy,
+		})
+		require.NoError(t, err)
+
+		// A second partial update keeps earlier values
+		sortOrder := "desc"
+		_, err = service.UpdateSettings(user.ID, &models.UpdateUserSettingsRequest{DefaultSortOrder: &sortOrder})
+		require.NoError(t, err)
+
+		settings, err := service.GetSettings(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Amsterdam", settings.Timezone)
+		assert.True(t, settings.HideCompleted)
+		assert.Equal(t, "priority", settings.DefaultSortBy)
+		assert.Equal(t, "desc", settings.DefaultSortOrder)
+	})
+
+	t.Run("rejects unknown timezone", func(t *testing.T) {
+		tz := "Mars/Olympus_Mons"
+		_, err := service.UpdateSettings(user.ID, &models.UpdateUserSettingsRequest{Timezone: &tz})
+		assert.ErrorIs(t, err, ErrInvalidTimezone)
+	})
+
+	t.Run("fails for unknown user", func(t *testing.T) {
+		hide := true
+		_, err := service.UpdateSettings(uuid.New(), &models.UpdateUserSettingsRequest{HideCompleted: &hide})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
 func TestInactiveUser(t *testing.T) {
 	service, db := setupTestService(t)
 
@@ -89,17 +89,27 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.RefreshToken{},
 		&models.TodoList{},
 		&models.Todo{},
+		&models.TodoTag{},
+		&models.Subtask{},
+		&models.UserSettings{},
+		&models.PasswordHistory{},
+		&models.PasswordResetToken{},
+		&models.EmailVerificationToken{},
+		&models.AcknowledgedNotification{},
+		&models.ListShareLink{},
+		&models.IdempotencyKey{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Create composite unique index for user_id + list name
+	// Create composite unique index for user_id + list name; archived lists
+	// do not hold on to their names
 	err = db.Exec(`
 		CREATE UNIQUE INDEX IF NOT EXISTS idx_user_list_name
 		ON todo_lists(user_id, name)
-		WHERE deleted_at IS NULL
+		WHERE deleted_at IS NULL AND archived = FALSE
 	`).Error
 
 	if err != nil {
@@ -110,6 +120,32 @@ func AutoMigrate(db *gorm.DB) error {
 	return nil
 }
 
+// UniqueTodoDescriptionIndex is the partial unique index that keeps open todo
+// descriptions unique (ignoring case) within a list when enabled
+const UniqueTodoDescriptionIndex = "idx_todos_list_description_unique"
+
+// ConfigureUniqueTodoDescriptions creates the unique todo description index when
+// enabled and drops it otherwise. Creating it fails if a list already holds open
+// todos with the same description.
+func ConfigureUniqueTodoDescriptions(db *gorm.DB, enabled bool) error {
+	if !enabled {
+		if err := db.Exec("DROP INDEX IF EXISTS " + UniqueTodoDescriptionIndex).Error; err != nil {
+			return fmt.Errorf("failed to drop unique todo description index: %w", err)
+		}
+		return nil
+	}
+
+	err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS ` + UniqueTodoDescriptionIndex + `
+		ON todos(list_id, LOWER(description))
+		WHERE deleted_at IS NULL AND completed = false
+	`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create unique todo description index: %w", err)
+	}
+	return nil
+}
+
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
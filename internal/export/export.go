@@ -0,0 +1,149 @@
+// Package export writes a todo list and its todos as a JSON or CSV document.
+// Todos are written a page at a time so a large list streams to the client
+// instead of being built in memory first.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"todolist-api/internal/models"
+)
+
+// Supported export formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// ErrUnsupportedFormat is returned by New for a format other than json or csv
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// csvHeader names the columns of a CSV export
+var csvHeader = []string{"description", "priority", "completed", "dueDate", "createdAt"}
+
+// Exporter writes one list: Begin once with the list, WriteTodos for each
+// page of its todos, then End
+type Exporter interface {
+	// ContentType is the MIME type of the document
+	ContentType() string
+	Begin(list *models.TodoList) error
+	WriteTodos(todos []models.Todo) error
+	End() error
+}
+
+// New returns an exporter writing the given format to w
+func New(format string, w io.Writer) (Exporter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonExporter{w: w}, nil
+	case FormatCSV:
+		return &csvExporter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Filename returns the download filename for a list exported in format
+func Filename(list *models.TodoList, format string) string {
+	return "list-" + list.ID.String() + "." + format
+}
+
+// jsonExporter writes {"list": {...}, "todos": [...]}, encoding one todo at
+// a time
+type jsonExporter struct {
+	w       io.Writer
+	written int
+}
+
+func (e *jsonExporter) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (e *jsonExporter) Begin(list *models.TodoList) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(e.w, `{"list":`); err != nil {
+		return err
+	}
+	if _, err = e.w.Write(data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, `,"todos":[`)
+	return err
+}
+
+func (e *jsonExporter) WriteTodos(todos []models.Todo) error {
+	for i := range todos {
+		data, err := json.Marshal(&todos[i])
+		if err != nil {
+			return err
+		}
+		if e.written > 0 {
+			if _, err = io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err = e.w.Write(data); err != nil {
+			return err
+		}
+		e.written++
+	}
+	return nil
+}
+
+func (e *jsonExporter) End() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+// csvExporter writes a header row and one row per todo. encoding/csv quotes
+// fields containing commas, quotes or newlines.
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *csvExporter) Begin(_ *models.TodoList) error {
+	if err := e.w.Write(csvHeader); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) WriteTodos(todos []models.Todo) error {
+	for i := range todos {
+		todo := &todos[i]
+		dueDate := ""
+		if todo.DueDate != nil {
+			dueDate = todo.DueDate.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			todo.Description,
+			string(todo.Priority),
+			strconv.FormatBool(todo.Completed),
+			dueDate,
+			todo.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
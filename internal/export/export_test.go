@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportList(t *testing.T, format string, list *models.TodoList, pages ...[]models.Todo) string {
+	t.Helper()
+	var buf bytes.Buffer
+	exporter, err := New(format, &buf)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Begin(list))
+	for _, page := range pages {
+		require.NoError(t, exporter.WriteTodos(page))
+	}
+	require.NoError(t, exporter.End())
+	return buf.String()
+}
+
+func TestExport(t *testing.T) {
+	list := &models.TodoList{ID: uuid.New(), Name: "Groceries"}
+	created := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	due := time.Date(2030, 2, 3, 9, 0, 0, 0, time.UTC)
+	first := []models.Todo{
+		{ID: uuid.New(), ListID: list.ID, Description: `Milk, "oat"`, Priority: models.PriorityHigh, DueDate: &due, CreatedAt: created},
+		{ID: uuid.New(), ListID: list.ID, Description: "Eggs\nfree range", Priority: models.PriorityLow, Completed: true, CreatedAt: created},
+	}
+	second := []models.Todo{
+		{ID: uuid.New(), ListID: list.ID, Description: "Bread", Priority: models.PriorityMedium, CreatedAt: created},
+	}
+
+	t.Run("csv escapes commas, quotes and newlines", func(t *testing.T) {
+		out := exportList(t, FormatCSV, list, first, second)
+
+		records, err := csv.NewReader(bytes.NewBufferString(out)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"description", "priority", "completed", "dueDate", "createdAt"},
+			{`Milk, "oat"`, "high", "false", "2030-02-03T09:00:00Z", "2030-01-02T15:04:05Z"},
+			{"Eggs\nfree range", "low", "true", "", "2030-01-02T15:04:05Z"},
+			{"Bread", "medium", "false", "", "2030-01-02T15:04:05Z"},
+		}, records)
+		assert.Contains(t, out, `"Milk, ""oat"""`)
+	})
+
+	t.Run("json holds the list and every page of todos", func(t *testing.T) {
+		out := exportList(t, FormatJSON, list, first, nil, second)
+
+		var doc struct {
+			List  models.TodoList `json:"list"`
+			Todos []models.Todo   `json:"todos"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &doc))
+		assert.Equal(t, list.ID, doc.List.ID)
+		assert.Equal(t, "Groceries", doc.List.Name)
+		require.Len(t, doc.Todos, 3)
+		assert.Equal(t, `Milk, "oat"`, doc.Todos[0].Description)
+		assert.Equal(t, "Bread", doc.Todos[2].Description)
+	})
+
+	t.Run("empty list exports an empty todo array", func(t *testing.T) {
+		out := exportList(t, FormatJSON, list)
+		assert.Contains(t, out, `"todos":[]`)
+
+		out = exportList(t, FormatCSV, list)
+		assert.Equal(t, "description,priority,completed,dueDate,createdAt\n", out)
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		_, err := New("xml", &bytes.Buffer{})
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+
+	t.Run("names the download after the list", func(t *testing.T) {
+		assert.Equal(t, "list-"+list.ID.String()+".csv", Filename(list, FormatCSV))
+	})
+}
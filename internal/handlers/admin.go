@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"todolist-api/internal/auth"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminHandler handles user management requests from admins
+type AdminHandler struct {
+	authService *auth.Service
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(authService *auth.Service) *AdminHandler {
+	return &AdminHandler{
+		authService: authService,
+	}
+}
+
+// ListUsers returns a page of users
+// @Summary List users
+// @Description List every user account, oldest first, with pagination. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Success 200 {object} models.PaginatedUsersResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, limit := parsePagination(c)
+
+	users, pagination, err := h.authService.ListUsers(page, limit)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "USERS_FETCH_FAILED",
+			Message: "Failed to fetch users",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PaginatedUsersResponse{
+		Data:       users,
+		Pagination: pagination,
+	})
+}
+
+// UpdateUser activates, deactivates or changes the role of a user
+// @Summary Update user
+// @Description Set a user's isActive flag or role. Deactivating a user revokes their sessions and their access
+// @Description tokens stop working immediately. Admins cannot deactivate or demote themselves. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param request body models.AdminUpdateUserRequest true "Fields to change"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{userId} [patch]
+func (h *AdminHandler) UpdateUser(c *gin.Context) {
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_USER_ID",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	var req models.AdminUpdateUserRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request payload",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+	if req.IsActive == nil && req.Role == nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Provide isActive or role",
+		})
+		return
+	}
+
+	var user *models.User
+	if req.Role != nil {
+		user, err = h.authService.SetUserRole(actorID, userID, *req.Role)
+	}
+	if err == nil && req.IsActive != nil {
+		user, err = h.authService.SetUserActive(actorID, userID, *req.IsActive)
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "USER_NOT_FOUND",
+				Message: "User not found",
+			})
+			return
+		}
+		if errors.Is(err, auth.ErrCannotModifySelf) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "CANNOT_MODIFY_SELF",
+				Message: "Admins cannot deactivate or demote themselves",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "USER_UPDATE_FAILED",
+			Message: "Failed to update user",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, user)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todolist-api/internal/auth"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtConfig := &auth.JWTConfig{
+		SecretKey:            "test-secret-key-for-testing-only",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+	}
+	authService := auth.NewService(testutil.SetupTestDB(t), jwtConfig)
+	handler := NewAdminHandler(authService)
+
+	// The routes are wired as in the server, so tokens go through the
+	// account status check before the admin check
+	router := gin.New()
+	protected := router.Group("", middleware.AuthMiddleware(jwtConfig), middleware.RequireActiveAccount(authService))
+	protected.GET("/profile", func(c *gin.Context) { c.Status(http.StatusOK) })
+	admin := protected.Group("/admin", middleware.AdminMiddleware())
+	admin.GET("/users", handler.ListUsers)
+	admin.PATCH("/users/:userId", handler.UpdateUser)
+
+	login := func(email string) *models.AuthResponse {
+		_, err := authService.Register(&models.RegisterRequest{Email: email, Password: "SecurePass123!"})
+		require.NoError(t, err)
+		resp, err := authService.Login(&models.LoginRequest{Email: email, Password: "SecurePass123!"})
+		require.NoError(t, err)
+		return resp
+	}
+	serve := func(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+		req := testutil.MakeJSONRequest(t, method, path, body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		return errResp.Code
+	}
+
+	adminSession := login("admin@example.com")
+	_, err := authService.SetUserRole(uuid.Nil, adminSession.User.ID, models.RoleAdmin)
+	require.NoError(t, err)
+	member := login("member@example.com")
+
+	t.Run("lists users for admins only", func(t *testing.T) {
+		w := serve("GET", "/admin/users?limit=1", adminSession.AccessToken, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.PaginatedUsersResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Len(t, resp.Data, 1)
+		assert.GreaterOrEqual(t, resp.Pagination.TotalItems, 2)
+		assert.Equal(t, resp.Pagination.TotalItems, resp.Pagination.TotalPages)
+		assert.NotContains(t, w.Body.String(), "passwordHash")
+
+		w = serve("GET", "/admin/users", member.AccessToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "FORBIDDEN", errorCode(w))
+	})
+
+	t.Run("validates the update", func(t *testing.T) {
+		path := "/admin/users/" + member.User.ID.String()
+		w := serve("PATCH", path, adminSession.AccessToken, map[string]interface{}{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_REQUEST", errorCode(w))
+
+		w = serve("PATCH", path, adminSession.AccessToken, map[string]interface{}{"role": "owner"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_REQUEST", errorCode(w))
+
+		w = serve("PATCH", "/admin/users/"+uuid.New().String(), adminSession.AccessToken, map[string]interface{}{"isActive": false})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "USER_NOT_FOUND", errorCode(w))
+
+		w = serve("PATCH", "/admin/users/"+adminSession.User.ID.String(), adminSession.AccessToken, map[string]interface{}{"isActive": false})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "CANNOT_MODIFY_SELF", errorCode(w))
+	})
+
+	t.Run("deactivation rejects the user's existing access token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, serve("GET", "/profile", member.AccessToken, nil).Code)
+
+		w := serve("PATCH", "/admin/users/"+member.User.ID.String(), adminSession.AccessToken, map[string]interface{}{"isActive": false})
+		require.Equal(t, http.StatusOK, w.Code)
+		var user models.User
+		testutil.ParseJSONResponse(t, w, &user)
+		assert.False(t, user.IsActive)
+
+		w = serve("GET", "/profile", member.AccessToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "USER_INACTIVE", errorCode(w))
+	})
+
+	t.Run("promotion takes effect on the existing access token", func(t *testing.T) {
+		w := serve("PATCH", "/admin/users/"+member.User.ID.String(), adminSession.AccessToken,
+			map[string]interface{}{"isActive": true, "role": "admin"})
+		require.Equal(t, http.StatusOK, w.Code)
+		var user models.User
+		testutil.ParseJSONResponse(t, w, &user)
+		assert.True(t, user.IsActive)
+		assert.Equal(t, models.RoleAdmin, user.Role)
+
+		assert.Equal(t, http.StatusOK, serve("GET", "/admin/users", member.AccessToken, nil).Code)
+	})
+}
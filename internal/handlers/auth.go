@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"todolist-api/internal/auth"
 	"todolist-api/internal/middleware"
@@ -37,20 +38,17 @@ func NewAuthHandler(authService *auth.Service) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": err.Error()},
+			Details: bindingErrorDetails(err),
 		})
 		return
 	}
 
 	// Validate password requirements
-	if err := auth.ValidatePasswordRequirements(req.Password); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    "INVALID_PASSWORD",
-			Message: err.Error(),
-		})
+	if err := h.authService.ValidatePassword(req.Password); err != nil {
+		h.respondInvalidPassword(c, err)
 		return
 	}
 
@@ -58,14 +56,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	user, err := h.authService.Register(&req)
 	if err != nil {
 		if errors.Is(err, auth.ErrUserAlreadyExists) {
-			c.JSON(http.StatusConflict, models.ErrorResponse{
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
 				Code:    "USER_EXISTS",
 				Message: "A user with this email already exists",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "REGISTRATION_FAILED",
 			Message: "Failed to register user",
 		})
@@ -76,12 +74,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	loginReq := &models.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(c),
 	}
 
 	authResponse, err := h.authService.Login(loginReq)
 	if err != nil {
 		// Registration succeeded but login failed - still return success
-		c.JSON(http.StatusCreated, models.UserInfo{
+		respondJSON(c, http.StatusCreated, models.UserInfo{
 			ID:        user.ID,
 			Email:     user.Email,
 			FirstName: user.FirstName,
@@ -91,7 +90,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, authResponse)
+	respondJSON(c, http.StatusCreated, authResponse)
 }
 
 // Login handles user login
@@ -108,18 +107,19 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": err.Error()},
+			Details: bindingErrorDetails(err),
 		})
 		return
 	}
 
+	req.Client = clientInfo(c)
 	authResponse, err := h.authService.Login(&req)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 				Code:    "INVALID_CREDENTIALS",
 				Message: "Invalid email or password",
 			})
@@ -127,21 +127,21 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}
 
 		if errors.Is(err, auth.ErrUserInactive) {
-			c.JSON(http.StatusForbidden, models.ErrorResponse{
+			respondJSON(c, http.StatusForbidden, models.ErrorResponse{
 				Code:    "USER_INACTIVE",
 				Message: "User account is inactive",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "LOGIN_FAILED",
 			Message: "Failed to login",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, authResponse)
+	respondJSON(c, http.StatusOK, authResponse)
 }
 
 // RefreshToken handles access token refresh
@@ -158,10 +158,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": err.Error()},
+			Details: bindingErrorDetails(err),
 		})
 		return
 	}
@@ -169,7 +169,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	authResponse, err := h.authService.RefreshAccessToken(req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, auth.ErrRefreshTokenInvalid) {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 				Code:    "INVALID_REFRESH_TOKEN",
 				Message: "Refresh token is invalid or expired",
 			})
@@ -177,21 +177,51 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		}
 
 		if errors.Is(err, auth.ErrUserInactive) {
-			c.JSON(http.StatusForbidden, models.ErrorResponse{
+			respondJSON(c, http.StatusForbidden, models.ErrorResponse{
 				Code:    "USER_INACTIVE",
 				Message: "User account is inactive",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "REFRESH_FAILED",
 			Message: "Failed to refresh token",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, authResponse)
+	respondJSON(c, http.StatusOK, authResponse)
+}
+
+// CreateDemo handles demo account creation
+// @Summary Create a demo account
+// @Description Create a throwaway account seeded with a sample list and return tokens for it.
+// @Description The account and its data are deleted once user.expiresAt passes.
+// @Tags Authentication
+// @Produce json
+// @Success 201 {object} models.AuthResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /auth/demo [post]
+func (h *AuthHandler) CreateDemo(c *gin.Context) {
+	authResponse, err := h.authService.CreateDemoUser(clientInfo(c))
+	if err != nil {
+		if errors.Is(err, auth.ErrDemoDisabled) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "DEMO_DISABLED",
+				Message: "Demo accounts are not enabled on this server",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "DEMO_FAILED",
+			Message: "Failed to create demo account",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, authResponse)
 }
 
 // Logout handles user logout
@@ -208,10 +238,10 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": err.Error()},
+			Details: bindingErrorDetails(err),
 		})
 		return
 	}
@@ -219,14 +249,14 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	err := h.authService.RevokeRefreshToken(req.RefreshToken)
 	if err != nil {
 		if errors.Is(err, auth.ErrRefreshTokenInvalid) {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 				Code:    "INVALID_REFRESH_TOKEN",
 				Message: "Refresh token is invalid",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "LOGOUT_FAILED",
 			Message: "Failed to logout",
 		})
@@ -236,6 +266,139 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// TokenStatus reports whether the request's access token is still valid
+// @Summary Access token status
+// @Description Check the access token in the Authorization header and return its user, expiry and seconds
+// @Description remaining, so clients can refresh it ahead of time. An expired or otherwise unusable token
+// @Description returns 200 with valid false and the reason (expired, invalid or inactive).
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} models.TokenStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/token/status [get]
+func (h *AuthHandler) TokenStatus(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "Authorization header is required",
+		})
+		return
+	}
+
+	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "INVALID_TOKEN",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.authService.TokenStatus(tokenString, time.Now())
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "TOKEN_STATUS_FAILED",
+			Message: "Failed to check token status",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, status)
+}
+
+// LogoutAll handles logging out of every session
+// @Summary Logout everywhere
+// @Description Revoke every active refresh token of the authenticated user and return how many were revoked
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	revoked, err := h.authService.RevokeAllUserTokens(userID)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "LOGOUT_FAILED",
+			Message: "Failed to logout",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"revoked": revoked,
+	})
+}
+
+// DeleteAccount permanently deletes the current user's account
+// @Summary Delete account
+// @Description Permanently delete the authenticated user with their lists, todos and sessions, after confirming the password
+// @Tags User
+// @Accept json
+// @Param request body models.DeleteAccountRequest true "Current password"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/account [delete]
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request payload",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	err = h.authService.DeleteAccount(userID, req.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+				Code:    "INVALID_CURRENT_PASSWORD",
+				Message: "Password is incorrect",
+			})
+			return
+		}
+		if errors.Is(err, auth.ErrUserNotFound) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "USER_NOT_FOUND",
+				Message: "User not found",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "ACCOUNT_DELETE_FAILED",
+			Message: "Failed to delete account",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetProfile returns the current user's profile
 // @Summary Get user profile
 // @Description Get the authenticated user's profile information
@@ -248,7 +411,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 			Code:    "UNAUTHORIZED",
 			Message: "User not authenticated",
 		})
@@ -258,26 +421,27 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	user, err := h.authService.GetUserByID(userID)
 	if err != nil {
 		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "USER_NOT_FOUND",
 				Message: "User not found",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "PROFILE_FETCH_FAILED",
 			Message: "Failed to fetch profile",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.UserInfo{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
+	respondJSON(c, http.StatusOK, models.UserInfo{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
 	})
 }
 
@@ -296,7 +460,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 			Code:    "UNAUTHORIZED",
 			Message: "User not authenticated",
 		})
@@ -305,10 +469,10 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 	var req models.UpdateProfileRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": bindErr.Error()},
+			Details: bindingErrorDetails(bindErr),
 		})
 		return
 	}
@@ -316,26 +480,27 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	user, err := h.authService.UpdateProfile(userID, &req)
 	if err != nil {
 		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "USER_NOT_FOUND",
 				Message: "User not found",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "PROFILE_UPDATE_FAILED",
 			Message: "Failed to update profile",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.UserInfo{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
+	respondJSON(c, http.StatusOK, models.UserInfo{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
 	})
 }
 
@@ -354,7 +519,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 			Code:    "UNAUTHORIZED",
 			Message: "User not authenticated",
 		})
@@ -363,42 +528,47 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	var req models.ChangePasswordRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_REQUEST",
 			Message: "Invalid request payload",
-			Details: map[string]interface{}{"error": bindErr.Error()},
+			Details: bindingErrorDetails(bindErr),
 		})
 		return
 	}
 
 	// Validate new password requirements
-	if validateErr := auth.ValidatePasswordRequirements(req.NewPassword); validateErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    "INVALID_PASSWORD",
-			Message: validateErr.Error(),
-		})
+	if validateErr := h.authService.ValidatePassword(req.NewPassword); validateErr != nil {
+		h.respondInvalidPassword(c, validateErr)
 		return
 	}
 
 	err = h.authService.ChangePassword(userID, &req)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
 				Code:    "INVALID_CURRENT_PASSWORD",
 				Message: "Current password is incorrect",
 			})
 			return
 		}
 
+		if errors.Is(err, auth.ErrPasswordReused) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "PASSWORD_REUSED",
+				Message: "The new password matches a recently used password",
+			})
+			return
+		}
+
 		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "USER_NOT_FOUND",
 				Message: "User not found",
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "PASSWORD_CHANGE_FAILED",
 			Message: "Failed to change password",
 		})
@@ -407,3 +577,350 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// ForgotPassword starts a password reset
+// @Summary Request a password reset
+// @Description Email a single-use password reset token to the account with the given email.
+// @Description Always responds 200, whether or not the account exists, so accounts cannot be enumerated.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request payload",
+			Details: bindingErrorDetails(err),
+		})
+		return
+	}
+
+	_, err := h.authService.CreatePasswordResetToken(req.Email)
+	if err != nil && !errors.Is(err, auth.ErrUserNotFound) {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "PASSWORD_RESET_FAILED",
+			Message: "Failed to start password reset",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": "If an account exists for that email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword completes a password reset
+// @Summary Reset password
+// @Description Set a new password using an emailed reset token. The token can be used once,
+// @Description and every refresh token of the account is revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request payload",
+			Details: bindingErrorDetails(err),
+		})
+		return
+	}
+
+	// Validate new password requirements
+	if err := h.authService.ValidatePassword(req.NewPassword); err != nil {
+		h.respondInvalidPassword(c, err)
+		return
+	}
+
+	err := h.authService.ResetPasswordWithToken(req.Token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, auth.ErrResetTokenInvalid) || errors.Is(err, auth.ErrUserInactive) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_RESET_TOKEN",
+				Message: "Password reset token is invalid",
+			})
+			return
+		}
+
+		if errors.Is(err, auth.ErrResetTokenExpired) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "RESET_TOKEN_EXPIRED",
+				Message: "Password reset token has expired; request a new one",
+			})
+			return
+		}
+
+		if errors.Is(err, auth.ErrResetTokenUsed) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "RESET_TOKEN_USED",
+				Message: "Password reset token has already been used; request a new one",
+			})
+			return
+		}
+
+		if errors.Is(err, auth.ErrPasswordReused) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "PASSWORD_REUSED",
+				Message: "The new password matches a recently used password",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "PASSWORD_RESET_FAILED",
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestVerification emails an email verification token
+// @Summary Request email verification
+// @Description Email a verification token to the authenticated user. Requesting again invalidates earlier tokens.
+// @Tags User
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/verify/request [post]
+func (h *AuthHandler) RequestVerification(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	_, err = h.authService.SendVerification(userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrEmailAlreadyVerified) {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "EMAIL_ALREADY_VERIFIED",
+				Message: "Email is already verified",
+			})
+			return
+		}
+
+		if errors.Is(err, auth.ErrUserNotFound) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "USER_NOT_FOUND",
+				Message: "User not found",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "VERIFICATION_FAILED",
+			Message: "Failed to send verification email",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// ConfirmEmail verifies the email of the account a token was issued to
+// @Summary Confirm email
+// @Description Mark the account's email as verified using an emailed token.
+// @Description Confirming an already verified account succeeds again.
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} models.UserInfo
+// @Failure 400 {object} models.ErrorResponse
+// @Router /auth/verify/confirm [get]
+// @Router /auth/verify/confirm [post]
+func (h *AuthHandler) ConfirmEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_VERIFICATION_TOKEN",
+			Message: "token query parameter is required",
+		})
+		return
+	}
+
+	user, err := h.authService.ConfirmEmail(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrVerificationTokenInvalid) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_VERIFICATION_TOKEN",
+				Message: "Email verification token is invalid",
+			})
+			return
+		}
+
+		if errors.Is(err, auth.ErrVerificationTokenExpired) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "VERIFICATION_TOKEN_EXPIRED",
+				Message: "Email verification token has expired; request a new one",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "VERIFICATION_FAILED",
+			Message: "Failed to verify email",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.UserInfo{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          user.Role,
+		EmailVerified: true,
+	})
+}
+
+// GetSettings returns the current user's settings
+// @Summary Get user settings
+// @Description Get the authenticated user's preferences
+// @Tags User
+// @Produce json
+// @Success 200 {object} models.UserSettings
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/settings [get]
+func (h *AuthHandler) GetSettings(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	settings, err := h.authService.GetSettings(userID)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "SETTINGS_FETCH_FAILED",
+			Message: "Failed to fetch settings",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, settings)
+}
+
+// UpdateSettings updates the current user's settings
+// @Summary Update user settings
+// @Description Update the authenticated user's preferences
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateUserSettingsRequest true "Settings updates"
+// @Success 200 {object} models.UserSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/settings [put]
+func (h *AuthHandler) UpdateSettings(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	var req models.UpdateUserSettingsRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_REQUEST",
+			Message: "Invalid request payload",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	settings, err := h.authService.UpdateSettings(userID, &req)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidTimezone) {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_TIMEZONE",
+				Message: "Timezone must be a valid IANA time zone name",
+			})
+			return
+		}
+		if errors.Is(err, auth.ErrUserNotFound) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "USER_NOT_FOUND",
+				Message: "User not found",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "SETTINGS_UPDATE_FAILED",
+			Message: "Failed to update settings",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, settings)
+}
+
+// passwordReasons names each password policy violation for clients
+var passwordReasons = []struct {
+	err    error
+	reason string
+}{
+	{auth.ErrPasswordTooShort, "too_short"},
+	{auth.ErrPasswordTooLong, "too_long"},
+	{auth.ErrPasswordMissingUpper, "missing_uppercase"},
+	{auth.ErrPasswordMissingLower, "missing_lowercase"},
+	{auth.ErrPasswordMissingDigit, "missing_digit"},
+	{auth.ErrPasswordMissingSymbol, "missing_symbol"},
+}
+
+// respondInvalidPassword responds 400 INVALID_PASSWORD for a password the
+// policy rejects, with the broken rule in details.reason and the policy in
+// details.policy so the client can guide the user
+func (h *AuthHandler) respondInvalidPassword(c *gin.Context, err error) {
+	reason := "invalid"
+	for _, r := range passwordReasons {
+		if errors.Is(err, r.err) {
+			reason = r.reason
+			break
+		}
+	}
+
+	policy := h.authService.PasswordPolicy()
+	respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+		Code:    "INVALID_PASSWORD",
+		Message: err.Error(),
+		Details: map[string]interface{}{
+			"reason": reason,
+			"policy": map[string]interface{}{
+				"minLength":     policy.MinimumLength(),
+				"maxLength":     auth.MaxPasswordLength,
+				"requireUpper":  policy.RequireUpper,
+				"requireLower":  policy.RequireLower,
+				"requireDigit":  policy.RequireDigit,
+				"requireSymbol": policy.RequireSymbol,
+			},
+		},
+	})
+}
@@ -144,8 +144,47 @@ func TestRegister(t *testing.T) {
 		var errResp models.ErrorResponse
 		testutil.ParseJSONResponse(t, w, &errResp)
 
-		// Password too long is caught by binding validation (max=72)
-		assert.Equal(t, "INVALID_REQUEST", errResp.Code)
+		assert.Equal(t, "INVALID_PASSWORD", errResp.Code)
+		assert.Equal(t, "too_long", errResp.Details["reason"])
+	})
+
+	t.Run("names the password rule the configured policy requires", func(t *testing.T) {
+		policy := auth.PasswordPolicy{MinLength: 10, RequireDigit: true, RequireSymbol: true}
+		handler := NewAuthHandler(auth.NewServiceWithConfig(testutil.SetupTestDB(t), &auth.JWTConfig{
+			SecretKey:            "test-secret-key-for-testing-only",
+			AccessTokenDuration:  15 * time.Minute,
+			RefreshTokenDuration: 7 * 24 * time.Hour,
+		}, &auth.ServiceConfig{PasswordPolicy: policy}))
+
+		tests := []struct {
+			password string
+			reason   string
+		}{
+			{"Short1!", "too_short"},
+			{"NoDigitsHere!", "missing_digit"},
+			{"NoSymbols123", "missing_symbol"},
+		}
+		for _, tt := range tests {
+			req := testutil.MakeJSONRequest(t, "POST", "/auth/register", models.RegisterRequest{
+				Email:    "policy@example.com",
+				Password: tt.password,
+			})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.Register(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, tt.password)
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, "INVALID_PASSWORD", errResp.Code)
+			assert.Equal(t, tt.reason, errResp.Details["reason"], tt.password)
+			rules, ok := errResp.Details["policy"].(map[string]interface{})
+			require.True(t, ok)
+			assert.Equal(t, float64(10), rules["minLength"])
+			assert.Equal(t, true, rules["requireDigit"])
+		}
 	})
 
 	t.Run("returns error for invalid email format", func(t *testing.T) {
@@ -466,6 +505,54 @@ func TestRefreshToken(t *testing.T) {
 	})
 }
 
+func TestCreateDemo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	createDemo := func(handler *AuthHandler) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/auth/demo", http.NoBody)
+		handler.CreateDemo(c)
+		return w
+	}
+
+	t.Run("returns 404 when demo mode is disabled", func(t *testing.T) {
+		handler, _ := setupAuthHandler(t)
+
+		w := createDemo(handler)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "DEMO_DISABLED", response.Code)
+	})
+
+	t.Run("creates an expiring account with tokens", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		jwtConfig := &auth.JWTConfig{
+			SecretKey:            "test-secret-key-for-testing-only",
+			AccessTokenDuration:  15 * time.Minute,
+			RefreshTokenDuration: 7 * 24 * time.Hour,
+		}
+		authService := auth.NewServiceWithConfig(db, jwtConfig, &auth.ServiceConfig{DemoAccountTTL: time.Hour})
+		handler := NewAuthHandler(authService)
+
+		w := createDemo(handler)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.AuthResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.NotEmpty(t, response.RefreshToken)
+		require.NotNil(t, response.User.ExpiresAt)
+		assert.True(t, response.User.ExpiresAt.After(time.Now()))
+
+		var lists int64
+		require.NoError(t, db.Model(&models.TodoList{}).Where("user_id = ?", response.User.ID).Count(&lists).Error)
+		assert.Equal(t, int64(1), lists)
+	})
+}
+
 func TestLogout(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -878,8 +965,8 @@ func TestChangePassword(t *testing.T) {
 		var errResp models.ErrorResponse
 		testutil.ParseJSONResponse(t, w, &errResp)
 
-		// Password too long is caught by binding validation (max=72)
-		assert.Equal(t, "INVALID_REQUEST", errResp.Code)
+		assert.Equal(t, "INVALID_PASSWORD", errResp.Code)
+		assert.Equal(t, "too_short", errResp.Details["reason"])
 	})
 
 	t.Run("returns error when not authenticated", func(t *testing.T) {
@@ -934,4 +1021,549 @@ func TestChangePassword(t *testing.T) {
 
 		assert.Equal(t, "INVALID_REQUEST", errResp.Code)
 	})
+
+	t.Run("returns PASSWORD_REUSED for a recent password", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		authService := auth.NewServiceWithConfig(db, &auth.JWTConfig{
+			SecretKey:            "test-secret-key-for-testing-only",
+			AccessTokenDuration:  15 * time.Minute,
+			RefreshTokenDuration: 7 * 24 * time.Hour,
+		}, &auth.ServiceConfig{PasswordHistorySize: 3})
+		handler := NewAuthHandler(authService)
+
+		user, err := authService.Register(&models.RegisterRequest{
+			Email:    "reuse@example.com",
+			Password: "OldPass123!",
+		})
+		require.NoError(t, err)
+		require.NoError(t, authService.ChangePassword(user.ID, &models.ChangePasswordRequest{
+			CurrentPassword: "OldPass123!",
+			NewPassword:     "NewPass123!",
+		}))
+
+		reqBody := models.ChangePasswordRequest{
+			CurrentPassword: "NewPass123!",
+			NewPassword:     "OldPass123!",
+		}
+
+		req := testutil.MakeJSONRequest(t, "PUT", "/auth/password", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("user_id", user.ID)
+
+		handler.ChangePassword(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "PASSWORD_REUSED", errResp.Code)
+	})
+}
+
+func TestLogoutAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, authService := setupAuthHandler(t)
+	_, err := authService.Register(&models.RegisterRequest{Email: "everywhere@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	loginReq := &models.LoginRequest{Email: "everywhere@example.com", Password: "SecurePass123!"}
+	first, err := authService.Login(loginReq)
+	require.NoError(t, err)
+	second, err := authService.Login(loginReq)
+	require.NoError(t, err)
+
+	logoutAll := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/auth/logout-all", http.NoBody)
+		c.Set("user_id", first.User.ID)
+		handler.LogoutAll(c)
+		return w
+	}
+
+	t.Run("returns the number of revoked tokens", func(t *testing.T) {
+		w := logoutAll()
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]int64
+		testutil.ParseJSONResponse(t, w, &body)
+		assert.Equal(t, int64(2), body["revoked"])
+
+		w = logoutAll()
+		testutil.ParseJSONResponse(t, w, &body)
+		assert.Zero(t, body["revoked"])
+	})
+
+	t.Run("old refresh tokens fail with INVALID_REFRESH_TOKEN", func(t *testing.T) {
+		for _, token := range []string{first.RefreshToken, second.RefreshToken} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = testutil.MakeJSONRequest(t, "POST", "/auth/refresh", models.RefreshTokenRequest{RefreshToken: token})
+			handler.RefreshToken(c)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, "INVALID_REFRESH_TOKEN", errResp.Code)
+		}
+	})
+}
+
+func TestTokenStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, authService := setupAuthHandler(t)
+	_, err := authService.Register(&models.RegisterRequest{Email: "lifetime@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	session, err := authService.Login(&models.LoginRequest{Email: "lifetime@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	tokenStatus := func(authHeader string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/auth/token/status", http.NoBody)
+		if authHeader != "" {
+			c.Request.Header.Set("Authorization", authHeader)
+		}
+		handler.TokenStatus(c)
+		return w
+	}
+
+	t.Run("returns the remaining seconds of a valid token", func(t *testing.T) {
+		w := tokenStatus("Bearer " + session.AccessToken)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var status models.TokenStatus
+		testutil.ParseJSONResponse(t, w, &status)
+		assert.True(t, status.Valid)
+		assert.Equal(t, session.User.ID, *status.UserID)
+		assert.InDelta(t, 15*60, status.SecondsRemaining, 2)
+		require.NotNil(t, status.ExpiresAt)
+	})
+
+	t.Run("returns valid false for an expired token", func(t *testing.T) {
+		expired, err := auth.GenerateAccessToken(&models.User{ID: session.User.ID, Email: session.User.Email, Role: models.RoleUser},
+			&auth.JWTConfig{SecretKey: "test-secret-key-for-testing-only", AccessTokenDuration: -time.Minute})
+		require.NoError(t, err)
+
+		w := tokenStatus("Bearer " + expired)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var status models.TokenStatus
+		testutil.ParseJSONResponse(t, w, &status)
+		assert.False(t, status.Valid)
+		assert.Equal(t, "expired", status.Reason)
+		assert.Zero(t, status.SecondsRemaining)
+	})
+
+	t.Run("requires a bearer token", func(t *testing.T) {
+		w := tokenStatus("")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = tokenStatus("Basic abc")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_TOKEN", errResp.Code)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, authService := setupAuthHandler(t)
+	_, err := authService.Register(&models.RegisterRequest{Email: "goodbye@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+	session, err := authService.Login(&models.LoginRequest{Email: "goodbye@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	deleteAccount := func(body interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "DELETE", "/auth/account", body)
+		c.Set("user_id", session.User.ID)
+		handler.DeleteAccount(c)
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	t.Run("requires the password", func(t *testing.T) {
+		w := deleteAccount(map[string]string{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_REQUEST", errResp.Code)
+	})
+
+	t.Run("returns 401 for a wrong password", func(t *testing.T) {
+		w := deleteAccount(models.DeleteAccountRequest{Password: "WrongPassword1!"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_CURRENT_PASSWORD", errResp.Code)
+	})
+
+	t.Run("deletes the account and its sessions", func(t *testing.T) {
+		w := deleteAccount(models.DeleteAccountRequest{Password: "SecurePass123!"})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err := authService.RefreshAccessToken(session.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenInvalid)
+
+		w = deleteAccount(models.DeleteAccountRequest{Password: "SecurePass123!"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, authService := setupAuthHandler(t)
+	_, err := authService.Register(&models.RegisterRequest{Email: "sessions@example.com", Password: "SecurePass123!"})
+	require.NoError(t, err)
+
+	// Log in through the handler so the client is recorded
+	login := func(userAgent string) *models.AuthResponse {
+		req := testutil.MakeJSONRequest(t, "POST", "/auth/login", models.LoginRequest{
+			Email:    "sessions@example.com",
+			Password: "SecurePass123!",
+		})
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		handler.Login(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.AuthResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return &resp
+	}
+	first := login("Laptop/1.0")
+	second := login("Phone/2.0")
+
+	// newContext authenticates a request the way AuthMiddleware does
+	newContext := func(method, path, accessToken string) (*gin.Context, *httptest.ResponseRecorder) {
+		claims, claimsErr := auth.ValidateAccessToken(accessToken, &auth.JWTConfig{SecretKey: "test-secret-key-for-testing-only"})
+		require.NoError(t, claimsErr)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, path, http.NoBody)
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		return c, w
+	}
+
+	t.Run("lists sessions and flags the current one", func(t *testing.T) {
+		c, w := newContext("GET", "/auth/sessions", second.AccessToken)
+		handler.ListSessions(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var sessions []models.Session
+		testutil.ParseJSONResponse(t, w, &sessions)
+		require.Len(t, sessions, 2)
+		for _, session := range sessions {
+			assert.Equal(t, session.UserAgent == "Phone/2.0", session.Current)
+		}
+	})
+
+	t.Run("revokes a session", func(t *testing.T) {
+		c, w := newContext("GET", "/auth/sessions", second.AccessToken)
+		handler.ListSessions(c)
+		var sessions []models.Session
+		testutil.ParseJSONResponse(t, w, &sessions)
+		var laptopID uuid.UUID
+		for _, session := range sessions {
+			if !session.Current {
+				laptopID = session.ID
+			}
+		}
+
+		c, w = newContext("DELETE", "/auth/sessions/"+laptopID.String(), second.AccessToken)
+		c.Params = gin.Params{{Key: "sessionId", Value: laptopID.String()}}
+		handler.RevokeSession(c)
+		c.Writer.WriteHeaderNow()
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err := authService.RefreshAccessToken(first.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrRefreshTokenInvalid)
+	})
+
+	t.Run("returns 404 for sessions of other users", func(t *testing.T) {
+		c, w := newContext("DELETE", "/auth/sessions/x", second.AccessToken)
+		c.Params = gin.Params{{Key: "sessionId", Value: uuid.New().String()}}
+		handler.RevokeSession(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "SESSION_NOT_FOUND", errResp.Code)
+	})
+}
+
+// captureMailer keeps the last mailed token instead of emailing it
+type captureMailer struct {
+	token string
+}
+
+func (m *captureMailer) SendPasswordReset(_, token string, _ time.Time) error {
+	m.token = token
+	return nil
+}
+
+func (m *captureMailer) SendEmailVerification(_, token string, _ time.Time) error {
+	m.token = token
+	return nil
+}
+
+func TestPasswordReset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+	mailer := &captureMailer{}
+	authService := auth.NewServiceWithConfig(db, &auth.JWTConfig{
+		SecretKey:            "test-secret-key-for-testing-only",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+	}, &auth.ServiceConfig{Mailer: mailer})
+	handler := NewAuthHandler(authService)
+
+	_, err := authService.Register(&models.RegisterRequest{
+		Email:    "forgot@example.com",
+		Password: "OldPass123!",
+	})
+	require.NoError(t, err)
+
+	forgot := func(email string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/auth/password/forgot", models.ForgotPasswordRequest{Email: email})
+		handler.ForgotPassword(c)
+		return w
+	}
+	reset := func(token, password string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/auth/password/reset", models.ResetPasswordRequest{
+			Token:       token,
+			NewPassword: password,
+		})
+		handler.ResetPassword(c)
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	t.Run("forgot answers the same for unknown emails", func(t *testing.T) {
+		known := forgot("forgot@example.com")
+		unknown := forgot("nobody@example.com")
+
+		assert.Equal(t, http.StatusOK, known.Code)
+		assert.Equal(t, http.StatusOK, unknown.Code)
+		assert.Equal(t, known.Body.String(), unknown.Body.String())
+	})
+
+	t.Run("forgot rejects an invalid email", func(t *testing.T) {
+		w := forgot("not-an-email")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("resets the password with the mailed token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, forgot("forgot@example.com").Code)
+		token := mailer.token
+
+		w := reset(token, "NewPass123!")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err := authService.Login(&models.LoginRequest{Email: "forgot@example.com", Password: "NewPass123!"})
+		assert.NoError(t, err)
+
+		w = reset(token, "OtherPass123!")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "RESET_TOKEN_USED", errResp.Code)
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, forgot("forgot@example.com").Code)
+		require.NoError(t, db.Model(&models.PasswordResetToken{}).
+			Where("used_at IS NULL").
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		w := reset(mailer.token, "OtherPass123!")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "RESET_TOKEN_EXPIRED", errResp.Code)
+	})
+
+	t.Run("rejects an unknown token", func(t *testing.T) {
+		w := reset("not-a-token", "OtherPass123!")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_RESET_TOKEN", errResp.Code)
+	})
+}
+
+func TestEmailVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+	mailer := &captureMailer{}
+	authService := auth.NewServiceWithConfig(db, &auth.JWTConfig{
+		SecretKey:            "test-secret-key-for-testing-only",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 7 * 24 * time.Hour,
+	}, &auth.ServiceConfig{Mailer: mailer})
+	handler := NewAuthHandler(authService)
+
+	user, err := authService.Register(&models.RegisterRequest{
+		Email:    "verify@example.com",
+		Password: "SecurePass123!",
+	})
+	require.NoError(t, err)
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/auth/verify/request", http.NoBody)
+		c.Set("user_id", user.ID)
+		handler.RequestVerification(c)
+		return w
+	}
+	confirm := func(method, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/auth/verify/confirm?token="+token, http.NoBody)
+		handler.ConfirmEmail(c)
+		return w
+	}
+
+	t.Run("rejects a missing or unknown token", func(t *testing.T) {
+		for _, token := range []string{"", "not-a-token"} {
+			w := confirm("GET", token)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, "INVALID_VERIFICATION_TOKEN", errResp.Code)
+		}
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request().Code)
+		require.NoError(t, db.Model(&models.EmailVerificationToken{}).
+			Where("user_id = ?", user.ID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		w := confirm("POST", mailer.token)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "VERIFICATION_TOKEN_EXPIRED", errResp.Code)
+	})
+
+	t.Run("confirms with GET or POST, repeatably", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request().Code)
+		token := mailer.token
+
+		for _, method := range []string{"GET", "POST"} {
+			w := confirm(method, token)
+			assert.Equal(t, http.StatusOK, w.Code)
+			var info models.UserInfo
+			testutil.ParseJSONResponse(t, w, &info)
+			assert.True(t, info.EmailVerified)
+		}
+	})
+
+	t.Run("refuses to send once verified", func(t *testing.T) {
+		w := request()
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "EMAIL_ALREADY_VERIFIED", errResp.Code)
+	})
+}
+
+func TestUserSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, authService := setupAuthHandler(t)
+	user, err := authService.Register(&models.RegisterRequest{
+		Email:    "settings@example.com",
+		Password: "SecurePass123!",
+	})
+	require.NoError(t, err)
+
+	t.Run("updates and returns settings", func(t *testing.T) {
+		reqBody := models.UpdateUserSettingsRequest{
+			Timezone:      testutil.StringPtr("America/New_York"),
+			DefaultSortBy: testutil.StringPtr("dueDate"),
+		}
+
+		req := testutil.MakeJSONRequest(t, "PUT", "/auth/settings", reqBody)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("user_id", user.ID)
+
+		handler.UpdateSettings(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		req = httptest.NewRequest("GET", "/auth/settings", http.NoBody)
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("user_id", user.ID)
+
+		handler.GetSettings(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var settings models.UserSettings
+		testutil.ParseJSONResponse(t, w, &settings)
+		assert.Equal(t, "America/New_York", settings.Timezone)
+		assert.Equal(t, "dueDate", settings.DefaultSortBy)
+		assert.Equal(t, "asc", settings.DefaultSortOrder)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body models.UpdateUserSettingsRequest
+			code string
+		}{
+			{"unknown timezone", models.UpdateUserSettingsRequest{Timezone: testutil.StringPtr("Nowhere/Land")}, "INVALID_TIMEZONE"},
+			{"unknown sort field", models.UpdateUserSettingsRequest{DefaultSortBy: testutil.StringPtr("name")}, "INVALID_REQUEST"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := testutil.MakeJSONRequest(t, "PUT", "/auth/settings", tt.body)
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = req
+				c.Set("user_id", user.ID)
+
+				handler.UpdateSettings(c)
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+
+				var response models.ErrorResponse
+				testutil.ParseJSONResponse(t, w, &response)
+				assert.Equal(t, tt.code, response.Code)
+			})
+		}
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/auth/settings", http.NoBody)
+
+		handler.GetSettings(c)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
 }
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIfMatch parses an optional If-Match header holding a resource version,
+// written as an ETag ("3" or W/"3"), a bare number, or the ETag returned by a
+// GET ("3-<hash>"), whose version prefix is used. It returns nil when the
+// header is absent or "*", and writes a 400 response when it is malformed.
+func parseIfMatch(c *gin.Context) (*int, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, true
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_IF_MATCH",
+			Message: `If-Match must be a resource version such as "3"`,
+		})
+		return nil, false
+	}
+	return &version, true
+}
+
+// respondWithETag answers a GET with obj and an ETag made of the resource's
+// version and a hash of its JSON encoding, so the tag changes whenever any
+// returned field does and can be sent back in If-Match. When the If-None-Match
+// header already holds that tag it answers 304 Not Modified with the ETag and
+// no body instead.
+func respondWithETag(c *gin.Context, version int, obj interface{}) {
+	etag, err := computeETag(version, obj, middleware.GetJSONFieldCase(c))
+	if err != nil {
+		respondJSON(c, http.StatusOK, obj)
+		return
+	}
+
+	c.Header("ETag", etag)
+	if matchesIfNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	respondJSON(c, http.StatusOK, obj)
+}
+
+// computeETag returns a strong ETag, "<version>-<hash>", for obj at version
+// as encoded with fieldCase
+func computeETag(version int, obj interface{}, fieldCase string) (string, error) {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(fieldCase+":"), encoded...))
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// matchesIfNoneMatch reports whether an If-None-Match header value lists
+// etag or is "*". Weak tags (W/"...") match their strong form, as RFC 9110
+// requires for If-None-Match.
+func matchesIfNoneMatch(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
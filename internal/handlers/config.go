@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// TodoConfig holds configuration for todo handlers
+type TodoConfig struct {
+	// DefaultHideCompleted makes GET /lists/:listId/todos return only incomplete
+	// todos when the completed query parameter is omitted entirely
+	DefaultHideCompleted bool
+	// DueSoonWindow is how far ahead of its due date an incomplete todo raises
+	// a due_soon notification (0 = defaultDueSoonWindow)
+	DueSoonWindow time.Duration
+	// MaxImportLines caps the todos a single text import can create, bounding
+	// the size of its transaction (0 = defaultMaxImportLines)
+	MaxImportLines int
+}
+
+const (
+	// defaultDueSoonWindow is the DueSoonWindow used when none is configured
+	defaultDueSoonWindow = 24 * time.Hour
+	// defaultMaxImportLines is the MaxImportLines used when none is configured
+	defaultMaxImportLines = 1000
+)
+
+// NewTodoConfigFromEnv creates todo handler config from environment variables
+func NewTodoConfigFromEnv() *TodoConfig {
+	return &TodoConfig{
+		DefaultHideCompleted: getEnvBool("TODOS_DEFAULT_HIDE_COMPLETED", false),
+		DueSoonWindow:        getEnvDuration("NOTIFICATION_DUE_SOON_WINDOW", defaultDueSoonWindow),
+		MaxImportLines:       getEnvInt("MAX_IMPORT_LINES", defaultMaxImportLines),
+	}
+}
+
+// ValidationConfig holds the tunable length limits enforced on request fields.
+// The database column sizes remain hard caps.
+type ValidationConfig struct {
+	ListNameMaxLength        int
+	TodoDescriptionMaxLength int
+}
+
+// NewValidationConfigFromEnv creates validation config from environment variables
+func NewValidationConfigFromEnv() *ValidationConfig {
+	return &ValidationConfig{
+		ListNameMaxLength:        getEnvInt("LIST_NAME_MAX_LENGTH", listNameColumnLength),
+		TodoDescriptionMaxLength: getEnvInt("TODO_DESCRIPTION_MAX_LENGTH", todoDescriptionColumnLength),
+	}
+}
+
+// HealthConfig holds configuration for health check handlers
+type HealthConfig struct {
+	// ReadinessFailureThreshold is the number of consecutive failed database
+	// checks required before the readiness probe reports not_ready
+	ReadinessFailureThreshold int
+	// RetryAfterSeconds is the base Retry-After value sent with a not_ready response
+	RetryAfterSeconds int
+	// RetryAfterJitterSeconds is the maximum random delay added to RetryAfterSeconds
+	// so that clients do not all retry at the same moment
+	RetryAfterJitterSeconds int
+	// CheckTimeout bounds each individual health check; a check that has not
+	// finished in time is reported as unhealthy
+	CheckTimeout time.Duration
+}
+
+// NewHealthConfigFromEnv creates health handler config from environment variables
+func NewHealthConfigFromEnv() *HealthConfig {
+	return &HealthConfig{
+		ReadinessFailureThreshold: getEnvInt("READINESS_FAILURE_THRESHOLD", 3),
+		RetryAfterSeconds:         getEnvInt("READINESS_RETRY_AFTER_SECONDS", 5),
+		RetryAfterJitterSeconds:   getEnvInt("READINESS_RETRY_AFTER_JITTER_SECONDS", 5),
+		CheckTimeout:              time.Duration(getEnvInt("HEALTH_CHECK_TIMEOUT_MS", 2000)) * time.Millisecond,
+	}
+}
+
+// getEnvInt retrieves an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration retrieves a duration environment variable (e.g. "2h") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool retrieves a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"todolist-api/internal/export"
+	"todolist-api/internal/ical"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	exportFormatJSON     = "json"
+	exportFormatMarkdown = "md"
+)
+
+// streamPageSize is how many todos the streamed list downloads (export and
+// calendar) load and write at a time
+const streamPageSize = 100
+
+// ExportList handles GET /lists/:listId/export?format=json|csv, streaming the
+// list and its active todos as a download a page of todos at a time
+func (h *ListHandler) ExportList(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	format := c.DefaultQuery("format", export.FormatJSON)
+	exporter, err := export.New(format, c.Writer)
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_FORMAT",
+			Message: "format must be 'json' or 'csv'",
+		})
+		return
+	}
+
+	list, pages, ok := h.loadStreamedList(c, userID, "Failed to export list")
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", exporter.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+export.Filename(list, format)+`"`)
+	c.Status(http.StatusOK)
+
+	if err = exporter.Begin(list); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !pages.stream(c, exporter.WriteTodos) {
+		return
+	}
+	if err = exporter.End(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// todoPages walks a list's active todos in position order a page at a time,
+// holding the page most recently loaded
+type todoPages struct {
+	storage    storage.Store
+	userID     uuid.UUID
+	listID     uuid.UUID
+	opts       storage.ListTodosOptions
+	todos      []models.Todo
+	pagination *models.Pagination
+}
+
+// loadStreamedList loads the list in the request path and the first page of
+// its todos before anything is written, so a failure can still be reported as
+// an error response; failure is the message of a 500
+func (h *ListHandler) loadStreamedList(c *gin.Context, userID uuid.UUID, failure string) (*models.TodoList, *todoPages, bool) {
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return nil, nil, false
+	}
+
+	list, err := h.storage.GetListByID(userID, listID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return nil, nil, false
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: failure,
+		})
+		return nil, nil, false
+	}
+
+	pages := &todoPages{
+		storage: h.storage,
+		userID:  userID,
+		listID:  listID,
+		opts:    storage.ListTodosOptions{SortBy: "position", SortOrder: "asc", Page: 1, Limit: streamPageSize},
+	}
+	pages.todos, pages.pagination, err = h.storage.GetTodosByList(userID, listID, pages.opts)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: failure,
+		})
+		return nil, nil, false
+	}
+	return list, pages, true
+}
+
+// stream writes the loaded page, then loads and writes each following page,
+// flushing after every page. Once streaming has started the status cannot
+// change, so a failure is recorded for the request log and ends the stream,
+// returning false.
+func (p *todoPages) stream(c *gin.Context, write func([]models.Todo) error) bool {
+	for {
+		if err := write(p.todos); err != nil {
+			_ = c.Error(err)
+			return false
+		}
+		c.Writer.Flush()
+
+		if p.opts.Page >= p.pagination.TotalPages {
+			return true
+		}
+		p.opts.Page++
+		var err error
+		p.todos, p.pagination, err = p.storage.GetTodosByList(p.userID, p.listID, p.opts)
+		if err != nil {
+			_ = c.Error(err)
+			return false
+		}
+	}
+}
+
+// ExportTodo handles GET /lists/:listId/todos/:todoId/export?format=md|json,
+// returning a single todo as a downloadable JSON or Markdown document
+func (h *TodoHandler) ExportTodo(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	format := c.DefaultQuery("format", exportFormatJSON)
+	if format != exportFormatJSON && format != exportFormatMarkdown {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_FORMAT",
+			Message: "format must be 'json' or 'md'",
+		})
+		return
+	}
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	todoID, err := uuid.Parse(c.Param("todoId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_TODO_ID",
+			Message: "Invalid todo ID format",
+		})
+		return
+	}
+
+	todo, err := h.storage.GetTodoByID(userID, listID, todoID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo was not found",
+			})
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to export todo",
+		})
+		return
+	}
+
+	filename := "todo-" + todo.ID.String() + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == exportFormatMarkdown {
+		subtasks, subtasksErr := h.storage.GetSubtasks(userID, listID, todoID)
+		if subtasksErr != nil {
+			respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to export todo",
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(renderTodoMarkdown(todo, subtasks)))
+		return
+	}
+	respondJSON(c, http.StatusOK, todo)
+}
+
+// renderTodoMarkdown renders a todo as a small Markdown document: the
+// description as a heading, a completion checkbox, a list of details and
+// its subtasks, in position order, as checkboxes
+func renderTodoMarkdown(todo *models.Todo, subtasks []models.Subtask) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", todo.Description)
+
+	checkbox := " "
+	if todo.Completed {
+		checkbox = "x"
+	}
+	fmt.Fprintf(&b, "- [%s] Completed\n\n", checkbox)
+
+	fmt.Fprintf(&b, "- **Priority:** %s\n", todo.Priority)
+	if todo.DueDate != nil {
+		fmt.Fprintf(&b, "- **Due:** %s\n", todo.DueDate.UTC().Format(time.RFC3339))
+	}
+	if todo.EstimateMinutes != nil {
+		fmt.Fprintf(&b, "- **Estimate:** %d minutes\n", *todo.EstimateMinutes)
+	}
+	if len(todo.Tags) > 0 {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", strings.Join(todo.Tags, ", "))
+	}
+	if todo.RecurrenceRule != "" {
+		fmt.Fprintf(&b, "- **Repeats:** %s\n", todo.RecurrenceRule)
+	}
+	if todo.CompletedAt != nil {
+		fmt.Fprintf(&b, "- **Completed at:** %s\n", todo.CompletedAt.UTC().Format(time.RFC3339))
+	}
+	if todo.ArchivedAt != nil {
+		fmt.Fprintf(&b, "- **Archived at:** %s\n", todo.ArchivedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "- **Created:** %s\n", todo.CreatedAt.UTC().Format(time.RFC3339))
+
+	if len(subtasks) > 0 {
+		b.WriteString("\n## Subtasks\n\n")
+		for _, subtask := range subtasks {
+			checkbox := " "
+			if subtask.Completed {
+				checkbox = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s\n", checkbox, subtask.Description)
+		}
+	}
+
+	return b.String()
+}
+
+// GetListCalendar handles GET /lists/:listId/calendar.ics, streaming the
+// list's active todos that have a due date as an iCalendar feed for calendar
+// apps to subscribe to
+func (h *ListHandler) GetListCalendar(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	list, pages, ok := h.loadStreamedList(c, userID, "Failed to build calendar")
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", ical.ContentType)
+	c.Header("Content-Disposition", `inline; filename="list-`+list.ID.String()+`.ics"`)
+	c.Status(http.StatusOK)
+
+	encoder := ical.NewEncoder(c.Writer)
+	if err := encoder.Begin(list); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !pages.stream(c, encoder.WriteTodos) {
+		return
+	}
+	if err := encoder.End(); err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exportRequest := func(listID, todoID uuid.UUID, format string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		url := "/lists/" + listID.String() + "/todos/" + todoID.String() + "/export"
+		if format != "" {
+			url += "?format=" + format
+		}
+		c.Request = httptest.NewRequest("GET", url, nil)
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+		return w, c
+	}
+
+	due := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	estimate := 45
+
+	t.Run("renders markdown with description heading and checkbox", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description:     "Write quarterly report",
+			Priority:        models.PriorityHigh,
+			DueDate:         &due,
+			EstimateMinutes: &estimate,
+		})
+		require.NoError(t, err)
+
+		w, c := exportRequest(listID, todo.ID, "md")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "todo-"+todo.ID.String()+".md")
+
+		body := w.Body.String()
+		assert.Contains(t, body, "# Write quarterly report\n")
+		assert.Contains(t, body, "- [ ] Completed")
+		assert.Contains(t, body, "**Priority:** high")
+		assert.Contains(t, body, "**Due:** 2030-01-02T15:04:05Z")
+		assert.Contains(t, body, "**Estimate:** 45 minutes")
+	})
+
+	t.Run("renders subtasks as checkboxes in position order", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Pack for trip",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		_, err = store.CreateSubtask(testUserID, listID, todo.ID, models.CreateSubtaskRequest{Description: "Passport", Completed: true})
+		require.NoError(t, err)
+		_, err = store.CreateSubtask(testUserID, listID, todo.ID, models.CreateSubtaskRequest{Description: "Charger"})
+		require.NoError(t, err)
+
+		w, c := exportRequest(listID, todo.ID, "md")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "\n## Subtasks\n\n- [x] Passport\n- [ ] Charger\n")
+	})
+
+	t.Run("checks the box for completed todos", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Done already",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+		_, err = store.UpdateTodo(testUserID, listID, todo.ID, models.UpdateTodoRequest{Completed: boolPtr(true)})
+		require.NoError(t, err)
+
+		w, c := exportRequest(listID, todo.ID, "md")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "- [x] Completed")
+		assert.Contains(t, w.Body.String(), "**Completed at:**")
+	})
+
+	t.Run("renders json with all fields by default", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description:     "Book flights",
+			Priority:        models.PriorityMedium,
+			DueDate:         &due,
+			EstimateMinutes: &estimate,
+		})
+		require.NoError(t, err)
+
+		w, c := exportRequest(listID, todo.ID, "")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "todo-"+todo.ID.String()+".json")
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fields))
+		expectedFields := []string{
+			"id", "listId", "description", "priority", "dueDate", "estimateMinutes", "completed", "createdAt", "updatedAt",
+		}
+		for _, key := range expectedFields {
+			assert.Contains(t, fields, key)
+		}
+		assert.Equal(t, "Book flights", fields["description"])
+		assert.Equal(t, todo.ID.String(), fields["id"])
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Anything",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w, c := exportRequest(listID, todo.ID, "pdf")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_FORMAT")
+	})
+
+	t.Run("returns 404 for missing todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := exportRequest(listID, uuid.New(), "md")
+		handler.ExportTodo(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "TODO_NOT_FOUND")
+	})
+}
+
+func TestExportList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exportRequest := func(handler *ListHandler, listID uuid.UUID, format string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		url := "/lists/" + listID.String() + "/export"
+		if format != "" {
+			url += "?format=" + format
+		}
+		c.Request = httptest.NewRequest("GET", url, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.ExportList(c)
+		return w
+	}
+
+	t.Run("streams every page of todos as CSV", func(t *testing.T) {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Big List"})
+		require.NoError(t, err)
+
+		total := streamPageSize + 20
+		for i := 0; i < total; i++ {
+			_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+				Description: fmt.Sprintf("Task %d, with comma", i),
+				Priority:    models.PriorityLow,
+			})
+			require.NoError(t, err)
+		}
+
+		w := exportRequest(handler, list.ID, "csv")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="list-`+list.ID.String()+`.csv"`, w.Header().Get("Content-Disposition"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, total+1)
+		assert.Equal(t, []string{"description", "priority", "completed", "dueDate", "createdAt"}, records[0])
+		assert.Equal(t, "Task 0, with comma", records[1][0])
+		assert.Equal(t, fmt.Sprintf("Task %d, with comma", total-1), records[total][0])
+	})
+
+	t.Run("exports JSON by default", func(t *testing.T) {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Trip"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityHigh})
+		require.NoError(t, err)
+
+		w := exportRequest(handler, list.ID, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "list-"+list.ID.String()+".json")
+
+		var doc struct {
+			List  models.TodoList `json:"list"`
+			Todos []models.Todo   `json:"todos"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, "Trip", doc.List.Name)
+		require.Len(t, doc.Todos, 1)
+		assert.Equal(t, "Pack", doc.Todos[0].Description)
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Trip"})
+		require.NoError(t, err)
+
+		w := exportRequest(handler, list.ID, "xml")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_FORMAT")
+	})
+
+	t.Run("returns 404 for a list of another user", func(t *testing.T) {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(uuid.New(), models.CreateTodoListRequest{Name: "Theirs"})
+		require.NoError(t, err)
+
+		w := exportRequest(handler, list.ID, "csv")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "LIST_NOT_FOUND")
+	})
+}
+
+func TestGetListCalendar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calendarRequest := func(handler *ListHandler, listID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/calendar.ics", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetListCalendar(c)
+		return w
+	}
+
+	t.Run("lists todos with due dates as VTODOs", func(t *testing.T) {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Deadlines"})
+		require.NoError(t, err)
+
+		due := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+		dated, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+			Description: "File taxes", Priority: models.PriorityHigh, DueDate: &due,
+		})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Someday", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := calendarRequest(handler, list.ID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Equal(t, 1, strings.Count(body, "BEGIN:VTODO"))
+		assert.Contains(t, body, "UID:"+dated.ID.String()+"@todolist-api\r\n")
+		assert.Contains(t, body, "SUMMARY:File taxes\r\n")
+		assert.Contains(t, body, "DUE:20300304T090000Z\r\n")
+		assert.NotContains(t, body, "Someday")
+	})
+
+	t.Run("returns 404 for a missing list", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		w := calendarRequest(handler, uuid.New())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "LIST_NOT_FOUND")
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// groupByPriority groups todos by priority, high first
+	groupByPriority = "priority"
+	// groupByDueBucket groups todos by how soon they are due
+	groupByDueBucket = "dueBucket"
+)
+
+// Due buckets, in the order groupBy=dueBucket returns them
+const (
+	dueBucketOverdue = "overdue"
+	dueBucketToday   = "today"
+	dueBucketWeek    = "week"
+	dueBucketLater   = "later"
+	dueBucketNone    = "none"
+)
+
+var (
+	// priorityGroupOrder is the order of the groupBy=priority groups
+	priorityGroupOrder = []string{string(models.PriorityHigh), string(models.PriorityMedium), string(models.PriorityLow)}
+	// dueBucketOrder is the order of the groupBy=dueBucket groups
+	dueBucketOrder = []string{dueBucketOverdue, dueBucketToday, dueBucketWeek, dueBucketLater, dueBucketNone}
+)
+
+// parseGroupBy parses the optional groupBy query parameter, returning "" when
+// the listing is not grouped
+func parseGroupBy(c *gin.Context) (string, bool) {
+	groupBy := c.Query("groupBy")
+	if groupBy != "" && groupBy != groupByPriority && groupBy != groupByDueBucket {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_GROUP_BY",
+			Message: "groupBy must be priority or dueBucket",
+		})
+		return "", false
+	}
+	return groupBy, true
+}
+
+// userLocation returns the user's configured timezone, or UTC when settings
+// are unavailable or name an unknown zone
+func (h *TodoHandler) userLocation(userID uuid.UUID) *time.Location {
+	if h.settings == nil {
+		return time.UTC
+	}
+	settings, err := h.settings.GetSettings(userID)
+	if err != nil {
+		return time.UTC
+	}
+	location, loadErr := time.LoadLocation(settings.Timezone)
+	if loadErr != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// groupTodos splits todos into groups in the fixed order of groupBy, keeping
+// their order within each group. Groups without todos are omitted.
+func groupTodos(todos []models.Todo, groupBy string, now time.Time) []models.TodoGroup {
+	keyOf := func(todo *models.Todo) string { return string(todo.Priority) }
+	order := priorityGroupOrder
+	if groupBy == groupByDueBucket {
+		keyOf = func(todo *models.Todo) string { return dueBucket(todo, now) }
+		order = dueBucketOrder
+	}
+
+	byKey := make(map[string][]models.Todo, len(order))
+	for i := range todos {
+		key := keyOf(&todos[i])
+		byKey[key] = append(byKey[key], todos[i])
+	}
+
+	groups := make([]models.TodoGroup, 0, len(byKey))
+	for _, key := range order {
+		if len(byKey[key]) > 0 {
+			groups = append(groups, models.TodoGroup{Key: key, Todos: byKey[key]})
+		}
+	}
+	return groups
+}
+
+// dueBucket returns the due bucket of a todo, with the day boundaries taken
+// in now's location: overdue when it is incomplete and past due, today when
+// due by the end of today (including completed todos past their due date),
+// week when due within the 6 days after today, later after that and none
+// without a due date
+func dueBucket(todo *models.Todo, now time.Time) string {
+	if todo.DueDate == nil {
+		return dueBucketNone
+	}
+	if !todo.Completed && todo.DueDate.Before(now) {
+		return dueBucketOverdue
+	}
+
+	year, month, day := now.Date()
+	tomorrow := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+	switch {
+	case todo.DueDate.Before(tomorrow):
+		return dueBucketToday
+	case todo.DueDate.Before(tomorrow.AddDate(0, 0, 6)):
+		return dueBucketWeek
+	default:
+		return dueBucketLater
+	}
+}
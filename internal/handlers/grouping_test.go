@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueBucket(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	at := func(tm time.Time) *time.Time { return &tm }
+
+	tests := []struct {
+		name      string
+		dueDate   *time.Time
+		completed bool
+		want      string
+	}{
+		{"no due date", nil, false, "none"},
+		{"past due", at(now.Add(-time.Minute)), false, "overdue"},
+		{"completed past due", at(now.Add(-48 * time.Hour)), true, "today"},
+		{"later today", at(time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC)), false, "today"},
+		{"tomorrow", at(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)), false, "week"},
+		{"sixth day after today", at(time.Date(2026, 3, 16, 23, 59, 0, 0, time.UTC)), false, "week"},
+		{"seventh day after today", at(time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)), false, "later"},
+	}
+	for _, tt := range tests {
+		todo := models.Todo{DueDate: tt.dueDate, Completed: tt.completed}
+		assert.Equal(t, tt.want, dueBucket(&todo, now), tt.name)
+	}
+
+	t.Run("day boundaries follow the location", func(t *testing.T) {
+		// 15:00 UTC on March 10 is already March 11 in UTC+9, the day the
+		// todo is due there
+		due := time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC)
+		todo := models.Todo{DueDate: &due}
+		assert.Equal(t, "week", dueBucket(&todo, now))
+		assert.Equal(t, "today", dueBucket(&todo, now.In(time.FixedZone("UTC+9", 9*60*60))))
+	})
+}
+
+func TestGetTodosByListGroupBy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store, listID := setupTodoHandler()
+	now := time.Now()
+	create := func(description string, priority models.Priority, dueDate *time.Time) {
+		_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: description,
+			Priority:    priority,
+			DueDate:     dueDate,
+		})
+		require.NoError(t, err)
+	}
+	past := now.Add(-time.Hour)
+	nextMonth := now.AddDate(0, 1, 0)
+	create("Late", models.PriorityHigh, &past)
+	create("Someday", models.PriorityLow, nil)
+	create("Eventually", models.PriorityHigh, &nextMonth)
+
+	get := func(groupBy string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos?groupBy="+groupBy, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		return w
+	}
+
+	descriptions := func(group models.TodoGroup) []string {
+		var result []string
+		for _, todo := range group.Todos {
+			result = append(result, todo.Description)
+		}
+		return result
+	}
+
+	t.Run("groups by priority without empty groups", func(t *testing.T) {
+		w := get("priority")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.GroupedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		require.Len(t, resp.Groups, 2)
+		assert.Equal(t, "high", resp.Groups[0].Key)
+		assert.Equal(t, []string{"Late", "Eventually"}, descriptions(resp.Groups[0]))
+		assert.Equal(t, "low", resp.Groups[1].Key)
+		assert.Equal(t, []string{"Someday"}, descriptions(resp.Groups[1]))
+		assert.Equal(t, 3, resp.Pagination.TotalItems)
+	})
+
+	t.Run("groups by due bucket without empty groups", func(t *testing.T) {
+		w := get("dueBucket")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.GroupedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		require.Len(t, resp.Groups, 3)
+		assert.Equal(t, "overdue", resp.Groups[0].Key)
+		assert.Equal(t, []string{"Late"}, descriptions(resp.Groups[0]))
+		assert.Equal(t, "later", resp.Groups[1].Key)
+		assert.Equal(t, []string{"Eventually"}, descriptions(resp.Groups[1]))
+		assert.Equal(t, "none", resp.Groups[2].Key)
+		assert.Equal(t, []string{"Someday"}, descriptions(resp.Groups[2]))
+	})
+
+	t.Run("rejects unknown groupings", func(t *testing.T) {
+		w := get("tag")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_GROUP_BY", errResp.Code)
+	})
+}
@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,19 +17,45 @@ import (
 
 const (
 	statusHealthy = "healthy"
+
+	// defaultHealthCheckTimeout bounds each health check when no timeout is configured
+	defaultHealthCheckTimeout = 2 * time.Second
 )
 
+// healthCheckFunc runs a single health check, giving up once ctx is done
+type healthCheckFunc func(ctx context.Context) HealthCheck
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
 	db        *gorm.DB
 	startTime time.Time
+	config    *HealthConfig
+
+	// checks overrides the named checks DetailedHealth runs; nil means the
+	// standard database, migrations and system checks
+	checks map[string]healthCheckFunc
+
+	// readinessMu guards readinessFailures, the count of consecutive failed
+	// readiness checks since the last successful one
+	readinessMu       sync.Mutex
+	readinessFailures int
 }
 
-// NewHealthHandler creates a new health handler
+// NewHealthHandler creates a new health handler that reports not_ready on the
+// first failed readiness check
 func NewHealthHandler(db *gorm.DB) *HealthHandler {
+	return NewHealthHandlerWithConfig(db, &HealthConfig{
+		ReadinessFailureThreshold: 1,
+		CheckTimeout:              defaultHealthCheckTimeout,
+	})
+}
+
+// NewHealthHandlerWithConfig creates a new health handler with the given configuration
+func NewHealthHandlerWithConfig(db *gorm.DB, config *HealthConfig) *HealthHandler {
 	return &HealthHandler{
 		db:        db,
 		startTime: time.Now(),
+		config:    config,
 	}
 }
 
@@ -53,7 +83,7 @@ type HealthCheck struct {
 // @Success 200 {object} map[string]string
 // @Router /health [get]
 func (h *HealthHandler) BasicHealth(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
+	respondJSON(c, http.StatusOK, gin.H{
 		"status": statusHealthy,
 	})
 }
@@ -67,23 +97,14 @@ func (h *HealthHandler) BasicHealth(c *gin.Context) {
 // @Failure 503 {object} HealthResponse
 // @Router /health/detailed [get]
 func (h *HealthHandler) DetailedHealth(c *gin.Context) {
-	checks := make(map[string]HealthCheck)
-	overallStatus := statusHealthy
+	// Run all checks in parallel so one hung check cannot stall the response
+	checks := h.runChecks(c.Request.Context())
 
-	// Check database connectivity
-	dbCheck := h.checkDatabase()
-	checks["database"] = dbCheck
-	if dbCheck.Status != statusHealthy {
+	overallStatus := statusHealthy
+	if checks["database"].Status != statusHealthy {
 		overallStatus = "unhealthy"
 	}
 
-	// Check migration status
-	migrationCheck := h.checkMigrations()
-	checks["migrations"] = migrationCheck
-
-	// System information
-	checks["system"] = h.getSystemInfo()
-
 	// Calculate uptime
 	uptime := time.Since(h.startTime)
 
@@ -97,14 +118,17 @@ func (h *HealthHandler) DetailedHealth(c *gin.Context) {
 
 	// Return 503 if unhealthy
 	if overallStatus == "unhealthy" {
-		c.JSON(http.StatusServiceUnavailable, response)
+		respondJSON(c, http.StatusServiceUnavailable, response)
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
-// ReadinessProbe checks if the application is ready to serve traffic
+// ReadinessProbe checks if the application is ready to serve traffic.
+// Brief database blips are tolerated: the probe only reports not_ready after
+// ReadinessFailureThreshold consecutive failures, and then sets a jittered
+// Retry-After header so orchestrators stagger their retries.
 // @Summary Readiness probe
 // @Description Kubernetes-style readiness probe - checks if app can handle requests
 // @Tags Health
@@ -114,10 +138,20 @@ func (h *HealthHandler) DetailedHealth(c *gin.Context) {
 // @Router /health/ready [get]
 func (h *HealthHandler) ReadinessProbe(c *gin.Context) {
 	// Check if database is accessible
-	dbCheck := h.checkDatabase()
+	dbCheck := runCheckWithTimeout(c.Request.Context(), h.checkTimeout(), h.checkDatabase)
 
 	if dbCheck.Status != statusHealthy {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
+		if !h.recordReadinessFailure() {
+			// Still within the grace period - keep serving traffic
+			respondJSON(c, http.StatusOK, gin.H{
+				"status":  "ready",
+				"warning": "database_check_failed",
+			})
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(h.retryAfterSeconds()))
+		respondJSON(c, http.StatusServiceUnavailable, gin.H{
 			"status":  "not_ready",
 			"reason":  "database_unavailable",
 			"message": dbCheck.Message,
@@ -125,11 +159,49 @@ func (h *HealthHandler) ReadinessProbe(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	h.resetReadinessFailures()
+	respondJSON(c, http.StatusOK, gin.H{
 		"status": "ready",
 	})
 }
 
+// recordReadinessFailure counts a failed readiness check and reports whether
+// the consecutive failure threshold has been reached
+func (h *HealthHandler) recordReadinessFailure() bool {
+	threshold := 1
+	if h.config != nil && h.config.ReadinessFailureThreshold > 1 {
+		threshold = h.config.ReadinessFailureThreshold
+	}
+
+	h.readinessMu.Lock()
+	defer h.readinessMu.Unlock()
+	h.readinessFailures++
+	return h.readinessFailures >= threshold
+}
+
+// resetReadinessFailures clears the consecutive failure count after a successful check
+func (h *HealthHandler) resetReadinessFailures() {
+	h.readinessMu.Lock()
+	defer h.readinessMu.Unlock()
+	h.readinessFailures = 0
+}
+
+// retryAfterSeconds returns the base Retry-After value plus random jitter
+func (h *HealthHandler) retryAfterSeconds() int {
+	if h.config == nil {
+		return 1
+	}
+
+	seconds := h.config.RetryAfterSeconds
+	if seconds < 1 {
+		seconds = 1
+	}
+	if h.config.RetryAfterJitterSeconds > 0 {
+		seconds += rand.IntN(h.config.RetryAfterJitterSeconds + 1) //nolint:gosec // G404: jitter does not need a secure source
+	}
+	return seconds
+}
+
 // LivenessProbe checks if the application is alive
 // @Summary Liveness probe
 // @Description Kubernetes-style liveness probe - checks if app is running
@@ -138,13 +210,80 @@ func (h *HealthHandler) ReadinessProbe(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /health/live [get]
 func (h *HealthHandler) LivenessProbe(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
+	respondJSON(c, http.StatusOK, gin.H{
 		"status": "alive",
 	})
 }
 
+// checkTimeout returns the per-check timeout, falling back to the default
+func (h *HealthHandler) checkTimeout() time.Duration {
+	if h.config == nil || h.config.CheckTimeout <= 0 {
+		return defaultHealthCheckTimeout
+	}
+	return h.config.CheckTimeout
+}
+
+// runChecks runs every configured check concurrently, each bounded by the
+// check timeout, and fans the results back in keyed by check name
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]HealthCheck {
+	type checkResult struct {
+		name  string
+		check HealthCheck
+	}
+
+	named := h.checks
+	if named == nil {
+		named = map[string]healthCheckFunc{
+			"database":   h.checkDatabase,
+			"migrations": h.checkMigrations,
+			"system":     h.getSystemInfo,
+		}
+	}
+
+	timeout := h.checkTimeout()
+	results := make(chan checkResult, len(named))
+	for name, check := range named {
+		go func() {
+			results <- checkResult{name: name, check: runCheckWithTimeout(ctx, timeout, check)}
+		}()
+	}
+
+	checks := make(map[string]HealthCheck, len(named))
+	for range named {
+		result := <-results
+		checks[result.name] = result.check
+	}
+	return checks
+}
+
+// runCheckWithTimeout runs check with a deadline and reports it as unhealthy
+// if it has not returned by then. A check that ignores its context keeps
+// running in the background, but no longer holds up the caller.
+func runCheckWithTimeout(parent context.Context, timeout time.Duration, check healthCheckFunc) HealthCheck {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	done := make(chan HealthCheck, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return HealthCheck{
+			Status:  "unhealthy",
+			Message: "Check timed out",
+			Details: map[string]interface{}{
+				"timeout_ms": timeout.Milliseconds(),
+			},
+		}
+	}
+}
+
 // checkDatabase verifies database connectivity
-func (h *HealthHandler) checkDatabase() HealthCheck {
+func (h *HealthHandler) checkDatabase(ctx context.Context) HealthCheck {
 	if h.db == nil {
 		return HealthCheck{
 			Status:  "unhealthy",
@@ -161,10 +300,6 @@ func (h *HealthHandler) checkDatabase() HealthCheck {
 		}
 	}
 
-	// Ping database with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
 	if err := sqlDB.PingContext(ctx); err != nil {
 		return HealthCheck{
 			Status:  "unhealthy",
@@ -194,7 +329,7 @@ func (h *HealthHandler) checkDatabase() HealthCheck {
 }
 
 // checkMigrations verifies migration status
-func (h *HealthHandler) checkMigrations() HealthCheck {
+func (h *HealthHandler) checkMigrations(ctx context.Context) HealthCheck {
 	if h.db == nil {
 		return HealthCheck{
 			Status:  "unknown",
@@ -203,8 +338,9 @@ func (h *HealthHandler) checkMigrations() HealthCheck {
 	}
 
 	// Check if schema_migrations table exists
+	db := h.db.WithContext(ctx)
 	var exists bool
-	err := h.db.Raw(`
+	err := db.Raw(`
 		SELECT EXISTS (
 			SELECT FROM information_schema.tables
 			WHERE table_name = 'schema_migrations'
@@ -221,7 +357,7 @@ func (h *HealthHandler) checkMigrations() HealthCheck {
 	// Get current migration version
 	var version uint
 	var dirty bool
-	err = h.db.Raw(`
+	err = db.Raw(`
 		SELECT version, dirty
 		FROM schema_migrations
 		LIMIT 1
@@ -252,7 +388,7 @@ func (h *HealthHandler) checkMigrations() HealthCheck {
 }
 
 // getSystemInfo returns system information
-func (h *HealthHandler) getSystemInfo() HealthCheck {
+func (h *HealthHandler) getSystemInfo(_ context.Context) HealthCheck {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
@@ -269,6 +405,31 @@ func (h *HealthHandler) getSystemInfo() HealthCheck {
 	}
 }
 
+// ShutdownSummary builds the structured log fields reported when the server
+// stops: requests drained during graceful shutdown, requests still in flight
+// when the shutdown deadline expired, database pool stats and total uptime.
+// dbStats is nil when the server ran without a database.
+func ShutdownSummary(uptime time.Duration, drained, abandoned int64, dbStats *sql.DBStats) map[string]interface{} {
+	summary := map[string]interface{}{
+		"uptime":             formatDuration(uptime),
+		"uptime_seconds":     int64(uptime.Seconds()),
+		"requests_drained":   drained,
+		"requests_abandoned": abandoned,
+	}
+
+	if dbStats != nil {
+		summary["db_open_connections"] = dbStats.OpenConnections
+		summary["db_in_use"] = dbStats.InUse
+		summary["db_idle"] = dbStats.Idle
+		summary["db_wait_count"] = dbStats.WaitCount
+		summary["db_wait_duration_ms"] = dbStats.WaitDuration.Milliseconds()
+		summary["db_max_idle_closed"] = dbStats.MaxIdleClosed
+		summary["db_max_lifetime_closed"] = dbStats.MaxLifetimeClosed
+	}
+
+	return summary
+}
+
 // formatDuration formats a duration into a human-readable string
 func formatDuration(d time.Duration) string {
 	days := int(d.Hours() / 24)
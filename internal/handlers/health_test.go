@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -37,6 +39,10 @@ func setupHealthTest(t *testing.T) (*HealthHandler, sqlmock.Sqlmock, func()) {
 	})
 	require.NoError(t, err)
 
+	// DetailedHealth runs its checks concurrently, so the ping and the
+	// migration queries may reach the mock in either order
+	mock.MatchExpectationsInOrder(false)
+
 	handler := NewHealthHandler(db)
 
 	cleanup := func() {
@@ -73,6 +79,7 @@ func TestBasicHealth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.BasicHealth(c)
 
@@ -103,6 +110,7 @@ func TestDetailedHealth_Healthy(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.DetailedHealth(c)
 
@@ -145,6 +153,7 @@ func TestDetailedHealth_DatabaseUnhealthy(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.DetailedHealth(c)
 
@@ -170,6 +179,7 @@ func TestDetailedHealth_NilDatabase(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.DetailedHealth(c)
 
@@ -185,6 +195,73 @@ func TestDetailedHealth_NilDatabase(t *testing.T) {
 	assert.Equal(t, "Database connection not initialized", response.Checks["database"].Message)
 }
 
+func TestDetailedHealth_SlowCheckTimesOut(t *testing.T) {
+	handler := NewHealthHandlerWithConfig(nil, &HealthConfig{
+		ReadinessFailureThreshold: 1,
+		CheckTimeout:              50 * time.Millisecond,
+	})
+
+	// A database check that hangs well past the timeout and ignores its context
+	release := make(chan struct{})
+	defer close(release)
+	handler.checks = map[string]healthCheckFunc{
+		"database": func(_ context.Context) HealthCheck {
+			<-release
+			return HealthCheck{Status: statusHealthy}
+		},
+		"system": handler.getSystemInfo,
+	}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
+
+	start := time.Now()
+	handler.DetailedHealth(c)
+	elapsed := time.Since(start)
+
+	assert.Less(t, elapsed, time.Second, "a hung check must not stall the response")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response HealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.Equal(t, "unhealthy", response.Status)
+	assert.Equal(t, "unhealthy", response.Checks["database"].Status)
+	assert.Equal(t, "Check timed out", response.Checks["database"].Message)
+	assert.Equal(t, "info", response.Checks["system"].Status, "other checks still report")
+}
+
+func TestDetailedHealth_ChecksSeeRequestContext(t *testing.T) {
+	handler := NewHealthHandlerWithConfig(nil, &HealthConfig{CheckTimeout: time.Second})
+
+	// The client has gone away, so the checks should be told to stop
+	seen := make(chan error, 1)
+	handler.checks = map[string]healthCheckFunc{
+		"database": func(ctx context.Context) HealthCheck {
+			seen <- ctx.Err()
+			return HealthCheck{Status: statusHealthy}
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Request = httptest.NewRequest("GET", "/health/detailed", http.NoBody).WithContext(ctx)
+
+	handler.DetailedHealth(c)
+
+	select {
+	case err := <-seen:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("the check never ran")
+	}
+}
+
 func TestReadinessProbe_Ready(t *testing.T) {
 	handler, mock, cleanup := setupHealthTest(t)
 	defer cleanup()
@@ -195,6 +272,7 @@ func TestReadinessProbe_Ready(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.ReadinessProbe(c)
 
@@ -219,6 +297,7 @@ func TestReadinessProbe_NotReady(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.ReadinessProbe(c)
 
@@ -244,6 +323,7 @@ func TestReadinessProbe_NilDatabase(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.ReadinessProbe(c)
 
@@ -257,6 +337,85 @@ func TestReadinessProbe_NilDatabase(t *testing.T) {
 	assert.Equal(t, "database_unavailable", response["reason"])
 }
 
+func TestReadinessProbe_FailureThreshold(t *testing.T) {
+	handler := NewHealthHandlerWithConfig(nil, &HealthConfig{
+		ReadinessFailureThreshold: 3,
+		RetryAfterSeconds:         5,
+		RetryAfterJitterSeconds:   5,
+	})
+	gin.SetMode(gin.TestMode)
+
+	probe := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
+		handler.ReadinessProbe(c)
+		return w
+	}
+
+	t.Run("stays ready below the threshold", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			w := probe()
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Retry-After"))
+
+			var response map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "ready", response["status"])
+			assert.Equal(t, "database_check_failed", response["warning"])
+		}
+	})
+
+	t.Run("reports not ready with jittered Retry-After at the threshold", func(t *testing.T) {
+		w := probe()
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, retryAfter, 5)
+		assert.LessOrEqual(t, retryAfter, 10)
+	})
+}
+
+func TestReadinessProbe_ResetsAfterSuccess(t *testing.T) {
+	handler, mock, cleanup := setupHealthTest(t)
+	defer cleanup()
+	handler.config = &HealthConfig{ReadinessFailureThreshold: 2}
+	gin.SetMode(gin.TestMode)
+
+	mock.ExpectPing().WillReturnError(errors.New("database unavailable"))
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("database unavailable"))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
+		handler.ReadinessProbe(c)
+		// The success in the middle resets the count, so the threshold is never reached
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadinessProbe_NotReadySetsRetryAfter(t *testing.T) {
+	handler, mock, cleanup := setupHealthTest(t)
+	defer cleanup()
+
+	mock.ExpectPing().WillReturnError(errors.New("database unavailable"))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
+
+	handler.ReadinessProbe(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
 func TestLivenessProbe(t *testing.T) {
 	handler, _, cleanup := setupHealthTest(t)
 	defer cleanup()
@@ -264,6 +423,7 @@ func TestLivenessProbe(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", http.NoBody)
 
 	handler.LivenessProbe(c)
 
@@ -283,7 +443,7 @@ func TestCheckDatabase_Healthy(t *testing.T) {
 	// Mock database ping
 	mock.ExpectPing()
 
-	check := handler.checkDatabase()
+	check := handler.checkDatabase(context.Background())
 
 	assert.Equal(t, "healthy", check.Status)
 	assert.Equal(t, "Database connection is healthy", check.Message)
@@ -300,7 +460,7 @@ func TestCheckDatabase_PingFailed(t *testing.T) {
 	// Mock database ping failure
 	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
 
-	check := handler.checkDatabase()
+	check := handler.checkDatabase(context.Background())
 
 	assert.Equal(t, "unhealthy", check.Status)
 	assert.Equal(t, "Database ping failed", check.Message)
@@ -316,7 +476,7 @@ func TestCheckDatabase_NilDB(t *testing.T) {
 		startTime: time.Now(),
 	}
 
-	check := handler.checkDatabase()
+	check := handler.checkDatabase(context.Background())
 
 	assert.Equal(t, "unhealthy", check.Status)
 	assert.Equal(t, "Database connection not initialized", check.Message)
@@ -334,7 +494,7 @@ func TestCheckMigrations_Healthy(t *testing.T) {
 	mock.ExpectQuery(`SELECT version, dirty\s+FROM schema_migrations\s+LIMIT 1`).
 		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1, false))
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "healthy", check.Status)
 	assert.Equal(t, "Migrations are up to date", check.Message)
@@ -357,7 +517,7 @@ func TestCheckMigrations_Dirty(t *testing.T) {
 	mock.ExpectQuery(`SELECT version, dirty\s+FROM schema_migrations\s+LIMIT 1`).
 		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(2, true))
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "warning", check.Status)
 	assert.Equal(t, "Database is in dirty state - manual intervention required", check.Message)
@@ -376,7 +536,7 @@ func TestCheckMigrations_TableNotFound(t *testing.T) {
 	mock.ExpectQuery(`SELECT EXISTS`).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "unknown", check.Status)
 	assert.Equal(t, "Migration table not found", check.Message)
@@ -390,7 +550,7 @@ func TestCheckMigrations_NilDB(t *testing.T) {
 		startTime: time.Now(),
 	}
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "unknown", check.Status)
 	assert.Equal(t, "Database not available", check.Message)
@@ -400,7 +560,7 @@ func TestGetSystemInfo(t *testing.T) {
 	handler, _, cleanup := setupHealthTest(t)
 	defer cleanup()
 
-	check := handler.getSystemInfo()
+	check := handler.getSystemInfo(context.Background())
 
 	assert.Equal(t, "info", check.Status)
 	assert.Equal(t, "System information", check.Message)
@@ -472,6 +632,37 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestShutdownSummary(t *testing.T) {
+	t.Run("includes drain counts, uptime and pool stats", func(t *testing.T) {
+		stats := &sql.DBStats{
+			OpenConnections: 4,
+			InUse:           1,
+			Idle:            3,
+			WaitCount:       7,
+			WaitDuration:    1500 * time.Millisecond,
+		}
+
+		summary := ShutdownSummary(90*time.Minute, 3, 1, stats)
+
+		assert.Equal(t, "1h 30m 0s", summary["uptime"])
+		assert.Equal(t, int64(5400), summary["uptime_seconds"])
+		assert.Equal(t, int64(3), summary["requests_drained"])
+		assert.Equal(t, int64(1), summary["requests_abandoned"])
+		assert.Equal(t, 4, summary["db_open_connections"])
+		assert.Equal(t, 1, summary["db_in_use"])
+		assert.Equal(t, 3, summary["db_idle"])
+		assert.Equal(t, int64(7), summary["db_wait_count"])
+		assert.Equal(t, int64(1500), summary["db_wait_duration_ms"])
+	})
+
+	t.Run("omits pool stats without a database", func(t *testing.T) {
+		summary := ShutdownSummary(time.Second, 0, 0, nil)
+
+		assert.Equal(t, "1s", summary["uptime"])
+		assert.NotContains(t, summary, "db_open_connections")
+	})
+}
+
 func TestHealthHandler_Uptime(t *testing.T) {
 	handler, _, cleanup := setupHealthTest(t)
 	defer cleanup()
@@ -545,7 +736,7 @@ func TestCheckDatabase_GetDBError(t *testing.T) {
 		startTime: time.Now(),
 	}
 
-	check := handler.checkDatabase()
+	check := handler.checkDatabase(context.Background())
 
 	assert.Equal(t, "unhealthy", check.Status)
 	assert.Contains(t, check.Message, "Database")
@@ -560,7 +751,7 @@ func TestCheckMigrations_QueryError(t *testing.T) {
 	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT FROM information_schema\.tables\s+WHERE table_name = 'schema_migrations'\s+\)`).
 		WillReturnError(errors.New("query failed"))
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "unknown", check.Status)
 	assert.Equal(t, "Migration table not found", check.Message)
@@ -581,7 +772,7 @@ func TestCheckMigrations_ScanError(t *testing.T) {
 	mock.ExpectQuery(`SELECT version, dirty\s+FROM schema_migrations\s+LIMIT 1`).
 		WillReturnError(sql.ErrNoRows)
 
-	check := handler.checkMigrations()
+	check := handler.checkMigrations(context.Background())
 
 	assert.Equal(t, "unknown", check.Status)
 	assert.Equal(t, "Could not read migration status", check.Message)
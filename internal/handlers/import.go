@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// importPriorities maps the leading "!" markers of an imported line to a priority
+var importPriorities = map[int]models.Priority{
+	1: models.PriorityLow,
+	2: models.PriorityMedium,
+	3: models.PriorityHigh,
+}
+
+// ImportText handles POST /lists/:listId/todos/import-text, creating one todo
+// per non-empty line of the submitted text in a single batch
+func (h *TodoHandler) ImportText(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	var req models.ImportTextRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	if maxLines := h.maxImportLines(); countImportLines(req.Text) > maxLines {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "IMPORT_TOO_LARGE",
+			Message: "Too many lines in one import",
+			Details: map[string]interface{}{"maxLines": maxLines},
+		})
+		return
+	}
+
+	reqs, errResp := parseImportText(req.Text)
+	if errResp != nil {
+		respondJSON(c, http.StatusBadRequest, *errResp)
+		return
+	}
+
+	todos, err := h.storage.BatchCreateTodos(userID, listID, reqs)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDueDateRequired {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "DUE_DATE_REQUIRED",
+				Message: "This list requires a due date on every todo",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to import todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, todos)
+}
+
+// maxImportLines returns the configured cap on todos per text import
+func (h *TodoHandler) maxImportLines() int {
+	if h.config.MaxImportLines <= 0 {
+		return defaultMaxImportLines
+	}
+	return h.config.MaxImportLines
+}
+
+// countImportLines returns the number of todos text would import, one per
+// non-empty line
+func countImportLines(text string) int {
+	count := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// parseImportText turns each non-empty line of text into a create request.
+// A leading "[ ]" or "[x]" sets completion and a following "!", "!!" or "!!!"
+// sets low, medium or high priority; unmarked lines are medium priority.
+func parseImportText(text string) ([]models.CreateTodoRequest, *models.ErrorResponse) {
+	maxLength := TodoDescriptionMaxLength()
+
+	var reqs []models.CreateTodoRequest
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		req := models.CreateTodoRequest{Priority: models.PriorityMedium}
+		if len(line) >= 3 && line[0] == '[' && line[2] == ']' && strings.ContainsRune(" xX", rune(line[1])) {
+			req.Completed = line[1] != ' '
+			line = strings.TrimSpace(line[3:])
+		}
+		if marks := len(line) - len(strings.TrimLeft(line, "!")); importPriorities[marks] != "" {
+			req.Priority = importPriorities[marks]
+			line = strings.TrimSpace(line[marks:])
+		}
+		req.Description = line
+
+		if req.Description == "" {
+			return nil, &models.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: "Imported line has no description",
+				Details: map[string]interface{}{"line": i + 1},
+			}
+		}
+		if utf8.RuneCountInString(req.Description) > maxLength {
+			return nil, &models.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: "Imported line exceeds the maximum description length",
+				Details: map[string]interface{}{"line": i + 1, "maxLength": maxLength},
+			}
+		}
+		reqs = append(reqs, req)
+	}
+
+	if len(reqs) == 0 {
+		return nil, &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Text contains no todos to import",
+		}
+	}
+	return reqs, nil
+}
+
+// onError modes for ImportTodos
+const (
+	importOnErrorFail = "fail"
+	importOnErrorSkip = "skip"
+)
+
+const (
+	// maxImportRows caps the number of rows a single ImportTodos request can
+	// hold, bounding the size of its transaction
+	maxImportRows = 1000
+	// maxImportFileBytes caps the size of an uploaded CSV file
+	maxImportFileBytes = 1 << 20
+)
+
+// importCSVColumns is the column order of a CSV import without a header row,
+// matching the list export so an exported file imports unchanged
+var importCSVColumns = []string{"description", "priority", "completed", "duedate", "createdat"}
+
+// ImportTodos handles POST /lists/:listId/import, creating todos in the list
+// from a JSON array of todos or a CSV file uploaded in the multipart field
+// "file", all in one batch. With onError=fail (the default) any invalid row
+// rejects the import; with onError=skip the valid rows are imported and the
+// rest reported.
+func (h *TodoHandler) ImportTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	onError := c.DefaultQuery("onError", importOnErrorFail)
+	if onError != importOnErrorFail && onError != importOnErrorSkip {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_ON_ERROR",
+			Message: "onError must be 'skip' or 'fail'",
+		})
+		return
+	}
+
+	var reqs []models.CreateTodoRequest
+	var rowErrors []map[string]interface{}
+	var errResp *models.ErrorResponse
+	if c.ContentType() == "multipart/form-data" {
+		reqs, rowErrors, errResp = parseImportCSVUpload(c)
+	} else {
+		reqs, rowErrors, errResp = parseImportJSON(c.Request.Body)
+	}
+	if errResp != nil {
+		respondJSON(c, http.StatusBadRequest, *errResp)
+		return
+	}
+	if len(rowErrors) > 0 && onError == importOnErrorFail {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "One or more rows are invalid",
+			Details: map[string]interface{}{"errors": rowErrors},
+		})
+		return
+	}
+
+	todos, err := h.storage.BatchCreateTodos(userID, listID, reqs)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDueDateRequired {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "DUE_DATE_REQUIRED",
+				Message: "This list requires a due date on every todo",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to import todos",
+		})
+		return
+	}
+
+	if rowErrors == nil {
+		rowErrors = make([]map[string]interface{}, 0)
+	}
+	respondJSON(c, http.StatusCreated, models.ImportResult{
+		Imported: len(todos),
+		Skipped:  len(rowErrors),
+		Errors:   rowErrors,
+	})
+}
+
+// parseImportJSON decodes a JSON array of todos, validating each one. Invalid
+// rows are reported by their 1-based position in the array.
+func parseImportJSON(body io.Reader) ([]models.CreateTodoRequest, []map[string]interface{}, *models.ErrorResponse) {
+	var items []json.RawMessage
+	if decodeErr := json.NewDecoder(body).Decode(&items); decodeErr != nil {
+		return nil, nil, &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Request body must be a JSON array of todos or a multipart CSV upload",
+			Details: map[string]interface{}{"error": decodeErr.Error()},
+		}
+	}
+	if errResp := checkImportRows(len(items)); errResp != nil {
+		return nil, nil, errResp
+	}
+
+	var reqs []models.CreateTodoRequest
+	var rowErrors []map[string]interface{}
+	for i, item := range items {
+		var req models.CreateTodoRequest
+		if itemErr := json.Unmarshal(item, &req); itemErr != nil {
+			rowErrors = append(rowErrors, map[string]interface{}{"row": i + 1, "error": itemErr.Error()})
+			continue
+		}
+		if validateErr := binding.Validator.ValidateStruct(&req); validateErr != nil {
+			details := bindingErrorDetails(validateErr)
+			details["row"] = i + 1
+			rowErrors = append(rowErrors, details)
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, rowErrors, nil
+}
+
+// parseImportCSVUpload reads the CSV file uploaded in the "file" field
+func parseImportCSVUpload(c *gin.Context) ([]models.CreateTodoRequest, []map[string]interface{}, *models.ErrorResponse) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, nil, &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "A CSV file is required in the multipart field 'file'",
+		}
+	}
+	if header.Size > maxImportFileBytes {
+		return nil, nil, &models.ErrorResponse{
+			Code:    "IMPORT_TOO_LARGE",
+			Message: "The import file is too large",
+			Details: map[string]interface{}{"maxBytes": maxImportFileBytes},
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "The uploaded file could not be read",
+		}
+	}
+	defer file.Close()
+
+	return parseImportCSV(file)
+}
+
+// parseImportCSV parses CSV rows into create requests. A first row with a
+// "description" field is a header naming the columns in any order;
+// without one the columns are description, priority, completed, dueDate and
+// createdAt as in a list export. Empty priority means medium and createdAt
+// and unknown columns are ignored. Invalid rows are reported by their 1-based
+// row number in the file, counting the header.
+func parseImportCSV(r io.Reader) ([]models.CreateTodoRequest, []map[string]interface{}, *models.ErrorResponse) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "The uploaded file is not valid CSV",
+			Details: map[string]interface{}{"error": err.Error()},
+		}
+	}
+
+	columns := importCSVColumns
+	firstRow := 1
+	if len(records) > 0 && len(records[0]) > 0 {
+		// Spreadsheet exports often start with a byte order mark
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+		if header := csvHeaderColumns(records[0]); header != nil {
+			columns = header
+			records = records[1:]
+			firstRow = 2
+		}
+	}
+	if errResp := checkImportRows(len(records)); errResp != nil {
+		return nil, nil, errResp
+	}
+
+	var reqs []models.CreateTodoRequest
+	var rowErrors []map[string]interface{}
+	for i, record := range records {
+		req, details := parseImportCSVRecord(columns, record)
+		if details == nil {
+			if validateErr := binding.Validator.ValidateStruct(&req); validateErr != nil {
+				details = bindingErrorDetails(validateErr)
+			}
+		}
+		if details != nil {
+			details["row"] = firstRow + i
+			rowErrors = append(rowErrors, details)
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, rowErrors, nil
+}
+
+// csvHeaderColumns returns the lower-cased column names of record when it is a
+// header row, one with a "description" field, or nil otherwise
+func csvHeaderColumns(record []string) []string {
+	columns := make([]string, len(record))
+	isHeader := false
+	for i, name := range record {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+		if columns[i] == "description" {
+			isHeader = true
+		}
+	}
+	if !isHeader {
+		return nil
+	}
+	return columns
+}
+
+// parseImportCSVRecord maps one CSV record onto a create request, returning
+// error details when a completed or dueDate value cannot be parsed
+func parseImportCSVRecord(columns, record []string) (models.CreateTodoRequest, map[string]interface{}) {
+	req := models.CreateTodoRequest{Priority: models.PriorityMedium}
+	for i, value := range record {
+		if i >= len(columns) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		switch columns[i] {
+		case "description":
+			req.Description = value
+		case "priority":
+			if value != "" {
+				req.Priority = models.Priority(strings.ToLower(value))
+			}
+		case "completed":
+			if value == "" {
+				continue
+			}
+			completed, err := strconv.ParseBool(value)
+			if err != nil {
+				return req, map[string]interface{}{"field": "completed", "error": "completed must be true or false"}
+			}
+			req.Completed = completed
+		case "duedate":
+			if value == "" {
+				continue
+			}
+			dueDate, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return req, map[string]interface{}{"field": "dueDate", "error": "dueDate must be an RFC 3339 timestamp"}
+			}
+			req.DueDate = &dueDate
+		}
+	}
+	return req, nil
+}
+
+// checkImportRows rejects an import holding more than maxImportRows rows
+func checkImportRows(rows int) *models.ErrorResponse {
+	if rows == 0 {
+		return &models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "The import contains no todos",
+		}
+	}
+	if rows > maxImportRows {
+		return &models.ErrorResponse{
+			Code:    "IMPORT_TOO_LARGE",
+			Message: "Too many rows in one import",
+			Details: map[string]interface{}{"maxRows": maxImportRows},
+		}
+	}
+	return nil
+}
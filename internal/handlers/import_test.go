@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	importRequest := func(t *testing.T, listID uuid.UUID, text string) (*httptest.ResponseRecorder, *gin.Context) {
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos/import-text", models.ImportTextRequest{Text: text})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		return w, c
+	}
+
+	t.Run("parses checkboxes and priority markers and skips blank lines", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		text := "[ ] !!! File taxes\n\n   \n[x] Buy milk\r\n! Water plants\n!! [not a checkbox]\n"
+		w, c := importRequest(t, listID, text)
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var todos []models.Todo
+		testutil.ParseJSONResponse(t, w, &todos)
+		require.Len(t, todos, 4)
+
+		assert.Equal(t, "File taxes", todos[0].Description)
+		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
+		assert.False(t, todos[0].Completed)
+
+		assert.Equal(t, "Buy milk", todos[1].Description)
+		assert.Equal(t, models.PriorityMedium, todos[1].Priority)
+		assert.True(t, todos[1].Completed)
+		assert.NotNil(t, todos[1].CompletedAt)
+
+		assert.Equal(t, "Water plants", todos[2].Description)
+		assert.Equal(t, models.PriorityLow, todos[2].Priority)
+
+		assert.Equal(t, "[not a checkbox]", todos[3].Description)
+		assert.Equal(t, models.PriorityMedium, todos[3].Priority)
+
+		for _, todo := range todos {
+			assert.Equal(t, listID, todo.ListID)
+		}
+	})
+
+	t.Run("creates nothing when a line is invalid", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		text := "First\n[x] !!\nThird"
+		w, c := importRequest(t, listID, text)
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "INVALID_INPUT", resp.Code)
+		assert.Equal(t, float64(2), resp.Details["line"])
+
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+	})
+
+	t.Run("rejects lines longer than the description limit", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := importRequest(t, listID, "ok\n"+strings.Repeat("a", TodoDescriptionMaxLength()+1))
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, float64(2), resp.Details["line"])
+		assert.Equal(t, float64(TodoDescriptionMaxLength()), resp.Details["maxLength"])
+	})
+
+	t.Run("rejects text with only blank lines", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := importRequest(t, listID, "\n  \n\t\n")
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 404 for another user's list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		w, c := importRequest(t, uuid.New(), "Something")
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("caps the number of imported lines", func(t *testing.T) {
+		_, store, listID := setupTodoHandler()
+		handler := NewTodoHandlerWithConfig(store, &TodoConfig{MaxImportLines: 3})
+
+		w, c := importRequest(t, listID, "One\nTwo\n\nThree\nFour")
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "IMPORT_TOO_LARGE", errResp.Code)
+		assert.Equal(t, float64(3), errResp.Details["maxLines"])
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+
+		// Blank lines don't count towards the cap
+		w, c = importRequest(t, listID, "One\n\n  \nTwo\nThree\n")
+		handler.ImportText(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		testutil.ParseJSONResponse(t, w, &todos)
+		assert.Len(t, todos, 3)
+	})
+}
+
+func TestImportTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	run := func(handler *TodoHandler, listID uuid.UUID, query string, req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		c.Request.URL.RawQuery = query
+		handler.ImportTodos(c)
+		return w
+	}
+
+	jsonImport := func(t *testing.T, handler *TodoHandler, listID uuid.UUID, query string, body interface{}) *httptest.ResponseRecorder {
+		return run(handler, listID, query, testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/import", body))
+	}
+
+	csvImport := func(t *testing.T, handler *TodoHandler, listID uuid.UUID, query, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "todos.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest("POST", "/lists/"+listID.String()+"/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return run(handler, listID, query, req)
+	}
+
+	listTodos := func(t *testing.T, store storage.Store, listID uuid.UUID) []models.Todo {
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		return todos
+	}
+
+	rows := []map[string]interface{}{
+		{"description": "Valid", "priority": "high"},
+		{"description": "Bad priority", "priority": "urgent"},
+		{"description": "Also valid", "priority": "low", "completed": true},
+	}
+
+	t.Run("fails the whole JSON import on an invalid row by default", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		w := jsonImport(t, handler, listID, "", rows)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+		rowErrors := errResp.Details["errors"].([]interface{})
+		require.Len(t, rowErrors, 1)
+		assert.Equal(t, float64(2), rowErrors[0].(map[string]interface{})["row"])
+		assert.Empty(t, listTodos(t, store, listID))
+	})
+
+	t.Run("skips invalid JSON rows with onError=skip", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		w := jsonImport(t, handler, listID, "onError=skip", rows)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var result models.ImportResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Skipped)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, float64(2), result.Errors[0]["row"])
+
+		todos := listTodos(t, store, listID)
+		require.Len(t, todos, 2)
+		assert.Equal(t, "Valid", todos[0].Description)
+		assert.True(t, todos[1].Completed)
+	})
+
+	t.Run("imports CSV with a header row and quoted fields", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		content := "\ufeffpriority,Description,dueDate\n" +
+			`high,"Buy milk, eggs and ""good"" bread",2030-01-02T15:04:05Z` + "\n" +
+			`,"Line one` + "\n" + `line two",` + "\n"
+		w := csvImport(t, handler, listID, "", content)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var result models.ImportResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 0, result.Skipped)
+		assert.Empty(t, result.Errors)
+
+		todos := listTodos(t, store, listID)
+		require.Len(t, todos, 2)
+		assert.Equal(t, `Buy milk, eggs and "good" bread`, todos[0].Description)
+		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
+		require.NotNil(t, todos[0].DueDate)
+		assert.Equal(t, "Line one\nline two", todos[1].Description)
+		assert.Equal(t, models.PriorityMedium, todos[1].Priority)
+	})
+
+	t.Run("imports headerless CSV in export column order", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		content := "Water plants,low,true,,2030-01-02T15:04:05Z\nPay rent,HIGH,false,2030-02-01T00:00:00Z,2030-01-02T15:04:05Z\n"
+		w := csvImport(t, handler, listID, "", content)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		todos := listTodos(t, store, listID)
+		require.Len(t, todos, 2)
+		assert.True(t, todos[0].Completed)
+		assert.Equal(t, models.PriorityHigh, todos[1].Priority)
+	})
+
+	t.Run("reports CSV rows by their line in the file", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		content := "description,priority,completed\nFine,low,false\nUrgent,urgent,false\nMaybe,low,sometimes\n"
+		w := csvImport(t, handler, listID, "onError=skip", content)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var result models.ImportResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, 2, result.Skipped)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, float64(3), result.Errors[0]["row"])
+		assert.Equal(t, float64(4), result.Errors[1]["row"])
+		assert.Equal(t, "completed", result.Errors[1]["field"])
+	})
+
+	t.Run("rejects imports over the row limit", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		var content strings.Builder
+		for i := 0; i <= maxImportRows; i++ {
+			fmt.Fprintf(&content, "Task %d,low\n", i)
+		}
+		w := csvImport(t, handler, listID, "", content.String())
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "IMPORT_TOO_LARGE", errResp.Code)
+		assert.Empty(t, listTodos(t, store, listID))
+	})
+
+	t.Run("rejects malformed input", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w := jsonImport(t, handler, listID, "onError=maybe", rows)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ON_ERROR")
+
+		w = jsonImport(t, handler, listID, "", map[string]string{"description": "Not an array"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = csvImport(t, handler, listID, "", `"unterminated`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "not valid CSV")
+	})
+
+	t.Run("returns 404 for a missing list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		w := jsonImport(t, handler, uuid.New(), "", rows[:1])
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
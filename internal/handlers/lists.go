@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"todolist-api/internal/middleware"
 	"todolist-api/internal/models"
@@ -22,33 +23,74 @@ func NewListHandler(store storage.Store) *ListHandler {
 	return &ListHandler{storage: store}
 }
 
-// GetAllLists handles GET /lists
+// GetAllLists handles GET /lists. Archived lists are left out unless
+// archived=true (only archived lists) or includeArchived=true (every list).
 func (h *ListHandler) GetAllLists(c *gin.Context) {
 	// Get user ID (or default for unauthenticated access)
 	userID := middleware.GetUserIDOrDefault(c)
 
 	// Parse query parameters
-	page, pageErr := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if pageErr != nil || page < 1 {
-		page = 1
+	page, limit := parsePagination(c)
+
+	// Optional case-insensitive substring match on name and description
+	search := strings.TrimSpace(c.Query("search"))
+
+	createdAfter, createdBefore, ok := parseCreatedRange(c)
+	if !ok {
+		return
+	}
+
+	archived, ok := parseArchivedLists(c)
+	if !ok {
+		return
+	}
+
+	expandTodos, todosLimit, ok := parseExpandTodos(c)
+	if !ok {
+		return
+	}
+
+	lists, pagination, err := h.storage.GetAllLists(userID, page, limit, search, createdAfter, createdBefore, archived)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve lists",
+		})
+		return
 	}
 
-	limit, limitErr := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if limitErr != nil || limit < 1 || limit > 100 {
-		limit = 20
+	if !expandTodos {
+		respondJSON(c, http.StatusOK, models.PaginatedListsResponse{
+			Data:       lists,
+			Pagination: pagination,
+		})
+		return
 	}
 
-	lists, pagination, err := h.storage.GetAllLists(userID, page, limit)
+	// Fetch the todos of the whole page at once rather than list by list
+	listIDs := make([]uuid.UUID, len(lists))
+	for i := range lists {
+		listIDs[i] = lists[i].ID
+	}
+	todosByList, err := h.storage.GetTodosForLists(userID, listIDs, todosLimit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: "Failed to retrieve lists",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.PaginatedListsResponse{
-		Data:       lists,
+	expanded := make([]models.TodoListWithTodos, len(lists))
+	for i := range lists {
+		todos := todosByList[lists[i].ID]
+		if todos == nil {
+			todos = []models.Todo{}
+		}
+		expanded[i] = models.TodoListWithTodos{TodoList: lists[i], Todos: todos}
+	}
+	respondJSON(c, http.StatusOK, models.PaginatedListsWithTodosResponse{
+		Data:       expanded,
 		Pagination: pagination,
 	})
 }
@@ -60,10 +102,10 @@ func (h *ListHandler) CreateList(c *gin.Context) {
 
 	var req models.CreateTodoListRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_INPUT",
 			Message: "Invalid request body",
-			Details: map[string]interface{}{"error": err.Error()},
+			Details: bindingErrorDetails(err),
 		})
 		return
 	}
@@ -71,20 +113,23 @@ func (h *ListHandler) CreateList(c *gin.Context) {
 	list, err := h.storage.CreateList(userID, req)
 	if err != nil {
 		if err == storage.ErrListNameExists {
-			c.JSON(http.StatusConflict, models.ErrorResponse{
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
 				Code:    "LIST_NAME_EXISTS",
 				Message: "A list with this name already exists",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: "Failed to create list",
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, list)
+	respondCreated(c, list.ID.String(), list)
 }
 
 // GetListByID handles GET /lists/:listId
@@ -94,52 +139,111 @@ func (h *ListHandler) GetListByID(c *gin.Context) {
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
 		return
 	}
 
+	list, ok := h.loadList(c, userID, listID)
+	if !ok {
+		return
+	}
+
+	respondWithETag(c, list.Version, list)
+}
+
+// loadList fetches a list, responding with the matching error if it cannot
+func (h *ListHandler) loadList(c *gin.Context, userID, listID uuid.UUID) (*models.TodoList, bool) {
 	list, err := h.storage.GetListByID(userID, listID)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
-			return
+			return nil, false
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: "Failed to retrieve list",
 		})
+		return nil, false
+	}
+	return list, true
+}
+
+// GetListSummary handles GET /lists/summary, counting the user's lists by state
+// so clients can show badges without fetching every list
+func (h *ListHandler) GetListSummary(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	summary, err := h.storage.CountLists(userID)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to count lists",
+		})
 		return
 	}
 
-	c.JSON(http.StatusOK, list)
+	respondJSON(c, http.StatusOK, summary)
 }
 
-// UpdateList handles PUT /lists/:listId
+// GetUserStats handles GET /stats/summary, summarizing the user's todos across
+// all of their lists. A user without lists gets all zeros.
+func (h *ListHandler) GetUserStats(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	stats, err := h.storage.GetUserStats(userID)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to compute statistics",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, stats)
+}
+
+// UpdateList handles PUT and PATCH /lists/:listId. A PATCH with an empty body
+// changes nothing and returns the list as it is.
 func (h *ListHandler) UpdateList(c *gin.Context) {
 	// Get user ID (or default for unauthenticated access)
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
 		return
 	}
 
+	includeDiff, ok := parseBoolFlag(c, "includeDiff", "INVALID_INCLUDE_DIFF")
+	if !ok {
+		return
+	}
+
+	// Not a conditional GET, so no ETag check: a PATCH never returns 304
+	if isEmptyPatch(c) {
+		if list, found := h.loadList(c, userID, listID); found {
+			respondJSON(c, http.StatusOK, list)
+		}
+		return
+	}
+
 	var req models.UpdateTodoListRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_INPUT",
 			Message: "Invalid request body",
-			Details: map[string]interface{}{"error": bindErr.Error()},
+			Details: bindingErrorDetails(bindErr),
 		})
 		return
 	}
@@ -147,27 +251,149 @@ func (h *ListHandler) UpdateList(c *gin.Context) {
 	list, err := h.storage.UpdateList(userID, listID, req)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
 		if err == storage.ErrListNameExists {
-			c.JSON(http.StatusConflict, models.ErrorResponse{
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
 				Code:    "LIST_NAME_EXISTS",
 				Message: "A list with this name already exists",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if err == storage.ErrVersionConflict {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "VERSION_CONFLICT",
+				Message: "The list was modified by another request. Reload it and try again.",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: "Failed to update list",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, list)
+	if !includeDiff {
+		list.Changed = nil
+	}
+	respondJSON(c, http.StatusOK, list)
+}
+
+// DuplicateList handles POST /lists/:listId/duplicate, copying the list and its
+// todos, reopened, into a new list named "Copy of <name>"
+func (h *ListHandler) DuplicateList(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	list, err := h.storage.DuplicateList(userID, listID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to duplicate list",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, list)
+}
+
+// ArchiveList handles POST /lists/:listId/archive, hiding the list from the
+// default listing without deleting it
+func (h *ListHandler) ArchiveList(c *gin.Context) {
+	h.setListArchived(c, h.storage.ArchiveList, "Failed to archive list")
+}
+
+// UnarchiveList handles POST /lists/:listId/unarchive
+func (h *ListHandler) UnarchiveList(c *gin.Context) {
+	h.setListArchived(c, h.storage.UnarchiveList, "Failed to unarchive list")
+}
+
+// setListArchived runs an archive or unarchive operation on the list in the
+// path and writes the resulting list, falling back to a 500 with the given
+// message
+func (h *ListHandler) setListArchived(
+	c *gin.Context, operation func(userID, listID uuid.UUID) (*models.TodoList, error), failure string,
+) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	list, err := operation(userID, listID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrListNameExists {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "LIST_NAME_EXISTS",
+				Message: "An active list with this name already exists. Rename one of the lists first.",
+			})
+			return
+		}
+		if err == storage.ErrVersionConflict {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "VERSION_CONFLICT",
+				Message: "The list was modified by another request. Reload it and try again.",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: failure,
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, list)
 }
 
 // DeleteList handles DELETE /lists/:listId
@@ -177,23 +403,38 @@ func (h *ListHandler) DeleteList(c *gin.Context) {
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
 		return
 	}
 
-	err = h.storage.DeleteList(userID, listID)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.storage.DeleteList(userID, listID, version)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if err == storage.ErrVersionConflict {
+			respondJSON(c, http.StatusPreconditionFailed, models.ErrorResponse{
+				Code:    "PRECONDITION_FAILED",
+				Message: "The list was modified since it was last read. Reload it and try again.",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: "Failed to delete list",
 		})
@@ -202,3 +443,201 @@ func (h *ListHandler) DeleteList(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// MergeLists handles POST /lists/:listId/merge, moving every todo from the
+// source list in the body into this list and deleting the source
+func (h *ListHandler) MergeLists(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	var req models.MergeListsRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	list, err := h.storage.MergeLists(userID, listID, req.SourceListID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrSourceNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "SOURCE_LIST_NOT_FOUND",
+				Message: "The source todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrMergeSameList {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_SOURCE_LIST",
+				Message: "A list cannot be merged into itself",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to merge lists",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, list)
+}
+
+// GetListStats handles GET /lists/:listId/stats
+func (h *ListHandler) GetListStats(c *gin.Context) {
+	stats, ok := h.loadListStats(c)
+	if !ok {
+		return
+	}
+
+	respondJSON(c, http.StatusOK, stats)
+}
+
+// HeadListStats handles HEAD /lists/:listId/stats, returning the aggregate
+// numbers as response headers with no body for lightweight polling
+func (h *ListHandler) HeadListStats(c *gin.Context) {
+	stats, ok := h.loadListStats(c)
+	if !ok {
+		return
+	}
+
+	for header, value := range listStatsHeaders(stats) {
+		c.Header(header, value)
+	}
+	c.Status(http.StatusOK)
+}
+
+// loadListStats fetches statistics for the list in the request path,
+// writing an error response and returning false if that fails
+func (h *ListHandler) loadListStats(c *gin.Context) (*models.ListStats, bool) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return nil, false
+	}
+
+	stats, err := h.storage.GetListStats(userID, listID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return nil, false
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve list statistics",
+		})
+		return nil, false
+	}
+
+	return stats, true
+}
+
+// listStatsHeaders maps each aggregate in a ListStats body to the header
+// HEAD /lists/:listId/stats reports it in
+func listStatsHeaders(stats *models.ListStats) map[string]string {
+	return map[string]string{
+		"X-Todos-Total":                strconv.Itoa(stats.Total),
+		"X-Todos-Completed":            strconv.Itoa(stats.Completed),
+		"X-Todos-Incomplete":           strconv.Itoa(stats.Incomplete),
+		"X-Todos-Overdue":              strconv.Itoa(stats.Overdue),
+		"X-Todos-High-Priority":        strconv.Itoa(stats.ByPriority.High),
+		"X-Todos-Medium-Priority":      strconv.Itoa(stats.ByPriority.Medium),
+		"X-Todos-Low-Priority":         strconv.Itoa(stats.ByPriority.Low),
+		"X-Total-Estimate-Minutes":     strconv.Itoa(stats.TotalEstimateMinutes),
+		"X-Remaining-Estimate-Minutes": strconv.Itoa(stats.RemainingEstimateMinutes),
+	}
+}
+
+// parseArchivedLists parses the archived and includeArchived query parameters
+// of GET /lists; includeArchived wins when both are set
+func parseArchivedLists(c *gin.Context) (storage.ArchivedLists, bool) {
+	archived, ok := parseBoolFlag(c, "archived", "INVALID_ARCHIVED")
+	if !ok {
+		return storage.ActiveLists, false
+	}
+	includeArchived, ok := parseBoolFlag(c, "includeArchived", "INVALID_INCLUDE_ARCHIVED")
+	if !ok {
+		return storage.ActiveLists, false
+	}
+
+	switch {
+	case includeArchived:
+		return storage.AllLists, true
+	case archived:
+		return storage.OnlyArchivedLists, true
+	default:
+		return storage.ActiveLists, true
+	}
+}
+
+// maxEmbeddedTodos caps how many todos GET /lists?expand=todos embeds per list,
+// and is also the default
+const maxEmbeddedTodos = 50
+
+// parseExpandTodos parses the expand and todosLimit query parameters of
+// GET /lists. expand may only be "todos"; todosLimit must be 1..maxEmbeddedTodos.
+func parseExpandTodos(c *gin.Context) (expand bool, todosLimit int, ok bool) {
+	switch c.Query("expand") {
+	case "":
+		return false, 0, true
+	case "todos":
+	default:
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_EXPAND",
+			Message: "expand must be todos",
+		})
+		return false, 0, false
+	}
+
+	todosLimit = maxEmbeddedTodos
+	if raw := c.Query("todosLimit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxEmbeddedTodos {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_TODOS_LIMIT",
+				Message: "todosLimit must be between 1 and " + strconv.Itoa(maxEmbeddedTodos),
+			})
+			return false, 0, false
+		}
+		todosLimit = parsed
+	}
+	return true, todosLimit, true
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
 	"todolist-api/internal/models"
 	"todolist-api/internal/storage"
@@ -25,6 +28,15 @@ func setupListHandler() (*ListHandler, storage.Store) {
 	return handler, store
 }
 
+// busyStore simulates a storage backend whose write slots are exhausted
+type busyStore struct {
+	storage.Store
+}
+
+func (busyStore) CreateList(_ uuid.UUID, _ models.CreateTodoListRequest) (*models.TodoList, error) {
+	return nil, storage.ErrDBBusy
+}
+
 func TestGetAllLists(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -111,7 +123,7 @@ func TestGetAllLists(t *testing.T) {
 		_, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test"})
 		require.NoError(t, err)
 
-		// Test limit > 100 (should cap at 20)
+		// Test limit > 100 (should cap at 100)
 		req := httptest.NewRequest("GET", "/lists?page=1&limit=200", http.NoBody)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -122,7 +134,180 @@ func TestGetAllLists(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		var response models.PaginatedListsResponse
 		testutil.ParseJSONResponse(t, w, &response)
-		assert.Equal(t, 20, response.Pagination.Limit)
+		assert.Equal(t, 100, response.Pagination.Limit)
+	})
+
+	t.Run("filters by created range with inclusive bounds", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		var created []*models.TodoList
+		for _, req := range []models.CreateTodoListRequest{
+			{Name: "Old Shopping"},
+			{Name: "Boundary Shopping"},
+			{Name: "Boundary Work"},
+			{Name: "New Shopping"},
+		} {
+			list, err := store.CreateList(testUserID, req)
+			require.NoError(t, err)
+			created = append(created, list)
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		getLists := func(query string) models.PaginatedListsResponse {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/lists"+query, http.NoBody)
+			handler.GetAllLists(c)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response models.PaginatedListsResponse
+			testutil.ParseJSONResponse(t, w, &response)
+			return response
+		}
+
+		after := created[1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		before := created[2].CreatedAt.UTC().Format(time.RFC3339Nano)
+
+		response := getLists("?createdAfter=" + after + "&createdBefore=" + before)
+		assert.Equal(t, 2, response.Pagination.TotalItems)
+
+		response = getLists("?createdAfter=" + after + "&search=shopping")
+		assert.Equal(t, 2, response.Pagination.TotalItems)
+		for _, list := range response.Data {
+			assert.Contains(t, []string{"Boundary Shopping", "New Shopping"}, list.Name)
+		}
+	})
+
+	t.Run("rejects a malformed created date", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists?createdAfter=last-week", http.NoBody)
+		handler.GetAllLists(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "INVALID_CREATED_DATE", response.Code)
+	})
+
+	t.Run("filters by search term with filtered pagination", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		for _, req := range []models.CreateTodoListRequest{
+			{Name: "Groceries", Description: "Weekly shopping"},
+			{Name: "Hardware", Description: "Shopping for the shed"},
+			{Name: "Shopping Mall"},
+			{Name: "Work", Description: "Quarterly goals"},
+		} {
+			_, err := store.CreateList(testUserID, req)
+			require.NoError(t, err)
+		}
+
+		req := httptest.NewRequest("GET", "/lists?search=SHOPPING&page=1&limit=2", http.NoBody)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		handler.GetAllLists(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.PaginatedListsResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, 3, response.Pagination.TotalItems)
+		assert.Equal(t, 2, response.Pagination.TotalPages)
+		for _, list := range response.Data {
+			assert.NotEqual(t, "Work", list.Name)
+		}
+	})
+}
+
+func TestGetAllListsExpandTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(handler *ListHandler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists"+query, http.NoBody)
+		handler.GetAllLists(c)
+		return w
+	}
+
+	t.Run("embeds each list's todos up to todosLimit", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		work, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Work"})
+		require.NoError(t, err)
+		_, err = store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Empty"})
+		require.NoError(t, err)
+		for _, description := range []string{"First", "Second", "Third"} {
+			_, err = store.CreateTodo(testUserID, work.ID, models.CreateTodoRequest{
+				Description: description,
+				Priority:    models.PriorityMedium,
+			})
+			require.NoError(t, err)
+		}
+
+		w := get(handler, "?expand=todos&todosLimit=2")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedListsWithTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+
+		require.Len(t, response.Data, 2)
+		embedded := map[string][]string{}
+		for _, list := range response.Data {
+			require.NotNil(t, list.Todos, "lists without todos embed an empty array")
+			for _, todo := range list.Todos {
+				embedded[list.Name] = append(embedded[list.Name], todo.Description)
+			}
+		}
+		assert.Equal(t, map[string][]string{"Work": {"First", "Second"}}, embedded)
+		for _, list := range response.Data {
+			if list.Name == "Work" {
+				assert.Equal(t, 3, list.TodoCount, "todoCount still counts every todo")
+			}
+		}
+	})
+
+	t.Run("omits todos without expand", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		work, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Work"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, work.ID, models.CreateTodoRequest{Description: "First", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := get(handler, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 1)
+		assert.NotContains(t, response.Data[0], "todos")
+	})
+
+	t.Run("rejects invalid expand and todosLimit values", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		for query, code := range map[string]string{
+			"?expand=owner":                "INVALID_EXPAND",
+			"?expand=todos&todosLimit=0":   "INVALID_TODOS_LIMIT",
+			"?expand=todos&todosLimit=51":  "INVALID_TODOS_LIMIT",
+			"?expand=todos&todosLimit=abc": "INVALID_TODOS_LIMIT",
+		} {
+			w := get(handler, query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, code, errResp.Code, query)
+		}
 	})
 }
 
@@ -226,6 +411,197 @@ func TestCreateList(t *testing.T) {
 	})
 }
 
+func TestCreateListDBBusy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewListHandler(busyStore{Store: storage.NewStorage()})
+
+	req := testutil.MakeJSONRequest(t, "POST", "/lists", models.CreateTodoListRequest{Name: "Busy"})
+	w := httptest.NewRecorder()
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.CreateList(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var errResp models.ErrorResponse
+	testutil.ParseJSONResponse(t, w, &errResp)
+	assert.Equal(t, "DB_BUSY", errResp.Code)
+}
+
+func TestGetListSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store := setupListHandler()
+	var last *models.TodoList
+	for _, name := range []string{"Work", "Home", "Errands"} {
+		var err error
+		last, err = store.CreateList(testUserID, models.CreateTodoListRequest{Name: name})
+		require.NoError(t, err)
+	}
+	_, err := store.ArchiveList(testUserID, last.ID)
+	require.NoError(t, err)
+	_, err = store.CreateList(uuid.New(), models.CreateTodoListRequest{Name: "Someone else's"})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/lists/summary", http.NoBody)
+
+	handler.GetListSummary(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var summary models.ListSummary
+	testutil.ParseJSONResponse(t, w, &summary)
+
+	assert.Equal(t, models.ListSummary{Active: 2, Archived: 1, Total: 3}, summary)
+}
+
+func TestDuplicateList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	duplicate := func(handler *ListHandler, listID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/lists/"+listID+"/duplicate", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID}}
+		handler.DuplicateList(c)
+		return w
+	}
+
+	t.Run("creates a copy with its todos", func(t *testing.T) {
+		handler, store := setupListHandler()
+		source, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Trip"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, source.ID, models.CreateTodoRequest{
+			Description: "Pack", Priority: models.PriorityLow, Completed: true,
+		})
+		require.NoError(t, err)
+
+		w := duplicate(handler, source.ID.String())
+		require.Equal(t, http.StatusCreated, w.Code)
+		var copied models.TodoList
+		testutil.ParseJSONResponse(t, w, &copied)
+		assert.Equal(t, "Copy of Trip", copied.Name)
+		assert.Equal(t, 1, copied.TodoCount)
+
+		w = duplicate(handler, source.ID.String())
+		require.Equal(t, http.StatusCreated, w.Code)
+		testutil.ParseJSONResponse(t, w, &copied)
+		assert.Equal(t, "Copy of Trip (2)", copied.Name)
+	})
+
+	t.Run("returns 404 for an unknown list", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		w := duplicate(handler, uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+	})
+}
+
+func TestArchiveList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// call runs a list handler against the given list ID and query string
+	call := func(handle gin.HandlerFunc, method, listID, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/lists"+query, http.NoBody)
+		if listID != "" {
+			c.Params = gin.Params{{Key: "listId", Value: listID}}
+		}
+		handle(c)
+		return w
+	}
+
+	// listNames fetches GET /lists with the given query string
+	listNames := func(t *testing.T, handler *ListHandler, query string) []string {
+		t.Helper()
+		w := call(handler.GetAllLists, "GET", "", query)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.PaginatedListsResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		names := []string{}
+		for i := range response.Data {
+			names = append(names, response.Data[i].Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("archives and unarchives a list", func(t *testing.T) {
+		handler, store := setupListHandler()
+		_, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Active"})
+		require.NoError(t, err)
+		done, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Done"})
+		require.NoError(t, err)
+
+		w := call(handler.ArchiveList, "POST", done.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var archived models.TodoList
+		testutil.ParseJSONResponse(t, w, &archived)
+		assert.True(t, archived.Archived)
+		assert.NotNil(t, archived.ArchivedAt)
+
+		assert.Equal(t, []string{"Active"}, listNames(t, handler, ""))
+		assert.Equal(t, []string{"Done"}, listNames(t, handler, "?archived=true"))
+		assert.Equal(t, []string{"Active", "Done"}, listNames(t, handler, "?includeArchived=true"))
+
+		w = call(handler.UnarchiveList, "POST", done.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var unarchived models.TodoList
+		testutil.ParseJSONResponse(t, w, &unarchived)
+		assert.False(t, unarchived.Archived)
+		assert.Nil(t, unarchived.ArchivedAt)
+		assert.Equal(t, []string{"Active", "Done"}, listNames(t, handler, ""))
+	})
+
+	t.Run("refuses to unarchive over an active list's name", func(t *testing.T) {
+		handler, store := setupListHandler()
+		old, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Project"})
+		require.NoError(t, err)
+		w := call(handler.ArchiveList, "POST", old.ID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		// The archived list's name is free for a new list
+		_, err = store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Project"})
+		require.NoError(t, err)
+
+		w = call(handler.UnarchiveList, "POST", old.ID.String(), "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NAME_EXISTS", errResp.Code)
+	})
+
+	t.Run("rejects unknown lists and invalid input", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		w := call(handler.ArchiveList, "POST", uuid.New().String(), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+
+		w = call(handler.UnarchiveList, "POST", "not-a-uuid", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
+
+		w = call(handler.GetAllLists, "GET", "", "?includeArchived=yes")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_INCLUDE_ARCHIVED", errResp.Code)
+	})
+}
+
 func TestGetListByID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -299,6 +675,85 @@ func TestGetListByID(t *testing.T) {
 	})
 }
 
+func TestGetListByIDETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store := setupListHandler()
+	router := gin.New()
+	router.GET("/lists/:listId", handler.GetListByID)
+	router.DELETE("/lists/:listId", handler.DeleteList)
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Polling"})
+	require.NoError(t, err)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/lists/"+list.ID.String(), http.NoBody)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	t.Run("returns 304 without a body while unchanged", func(t *testing.T) {
+		for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+			w := get(ifNoneMatch)
+			assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Zero(t, w.Body.Len())
+		}
+
+		w := get(`"other"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("changes with any returned field", func(t *testing.T) {
+		_, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Counted", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := get(etag)
+		assert.Equal(t, http.StatusOK, w.Code)
+		afterTodo := w.Header().Get("ETag")
+		assert.NotEqual(t, etag, afterTodo)
+
+		renamed := "Renamed"
+		_, err = store.UpdateList(testUserID, list.ID, models.UpdateTodoListRequest{Name: &renamed})
+		require.NoError(t, err)
+
+		w = get(afterTodo)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, afterTodo, w.Header().Get("ETag"))
+	})
+
+	t.Run("is accepted by If-Match on DELETE", func(t *testing.T) {
+		remove := func(ifMatch string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("DELETE", "/lists/"+list.ID.String(), http.NoBody)
+			req.Header.Set("If-Match", ifMatch)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		stale := get("").Header().Get("ETag")
+		renamed := "Renamed again"
+		_, err := store.UpdateList(testUserID, list.ID, models.UpdateTodoListRequest{Name: &renamed})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, remove(stale).Code)
+
+		current := get("").Header().Get("ETag")
+		assert.Equal(t, http.StatusNoContent, remove(current).Code)
+		_, err = store.GetListByID(testUserID, list.ID)
+		assert.ErrorIs(t, err, storage.ErrListNotFound)
+	})
+}
+
 func TestUpdateList(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -336,6 +791,90 @@ func TestUpdateList(t *testing.T) {
 		assert.Equal(t, "Original Description", list.Description)
 	})
 
+	t.Run("returns the unchanged list for an empty PATCH body", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		created, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Original Name"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("PATCH", "/lists/"+created.ID.String(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: created.ID.String()}}
+
+		handler.UpdateList(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var list models.TodoList
+		testutil.ParseJSONResponse(t, w, &list)
+
+		assert.Equal(t, "Original Name", list.Name)
+		assert.Equal(t, created.Version, list.Version)
+	})
+
+	t.Run("empty PATCH body ignores If-None-Match", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		created, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Original Name"})
+		require.NoError(t, err)
+		params := gin.Params{{Key: "listId", Value: created.ID.String()}}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+created.ID.String(), http.NoBody)
+		c.Params = params
+		handler.GetListByID(c)
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		req := httptest.NewRequest("PATCH", "/lists/"+created.ID.String(), http.NoBody)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = params
+		handler.UpdateList(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list models.TodoList
+		testutil.ParseJSONResponse(t, w, &list)
+		assert.Equal(t, "Original Name", list.Name)
+	})
+
+	t.Run("rejects stale version with conflict", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		created, err := store.CreateList(testUserID, models.CreateTodoListRequest{
+			Name: "Original Name",
+		})
+		require.NoError(t, err)
+		stale := created.Version
+		otherName := "Other Name"
+		_, err = store.UpdateList(testUserID, created.ID, models.UpdateTodoListRequest{Name: &otherName})
+		require.NoError(t, err)
+
+		newName := "Updated Name"
+		reqBody := models.UpdateTodoListRequest{
+			Name:    &newName,
+			Version: &stale,
+		}
+
+		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+created.ID.String(), reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: created.ID.String()}}
+
+		handler.UpdateList(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "VERSION_CONFLICT")
+	})
+
 	t.Run("successfully updates list description", func(t *testing.T) {
 		handler, store := setupListHandler()
 
@@ -498,6 +1037,35 @@ func TestUpdateList(t *testing.T) {
 		testutil.ParseJSONResponse(t, w, &errResp)
 		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
 	})
+	t.Run("returns a field diff when includeDiff=true", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		created, err := store.CreateList(testUserID, models.CreateTodoListRequest{
+			Name:        "Original Name",
+			Description: "Original Description",
+		})
+		require.NoError(t, err)
+
+		sameName, keep := "Original Name", true
+		reqBody := models.UpdateTodoListRequest{Name: &sameName, KeepCompleted: &keep}
+
+		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+created.ID.String()+"?includeDiff=true", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: created.ID.String()}}
+
+		handler.UpdateList(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, map[string]interface{}{
+			"keepCompleted": map[string]interface{}{"old": false, "new": true},
+		}, resp["changed"])
+	})
 }
 
 func TestDeleteList(t *testing.T) {
@@ -530,6 +1098,47 @@ func TestDeleteList(t *testing.T) {
 		assert.ErrorIs(t, err, storage.ErrListNotFound)
 	})
 
+	t.Run("honors If-Match against the list version", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		created, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+		require.NoError(t, err)
+		name := "Renamed"
+		_, err = store.UpdateList(testUserID, created.ID, models.UpdateTodoListRequest{Name: &name})
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			ifMatch string
+			status  int
+		}{
+			{`"1"`, http.StatusPreconditionFailed},
+			{"abc", http.StatusBadRequest},
+			{`W/"2"`, http.StatusNoContent},
+		} {
+			req := httptest.NewRequest("DELETE", "/lists/"+created.ID.String(), http.NoBody)
+			req.Header.Set("If-Match", tc.ifMatch)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "listId", Value: created.ID.String()}}
+
+			handler.DeleteList(c)
+			c.Writer.WriteHeaderNow()
+
+			assert.Equal(t, tc.status, w.Code, "If-Match %s", tc.ifMatch)
+			if tc.status == http.StatusPreconditionFailed {
+				var response models.ErrorResponse
+				testutil.ParseJSONResponse(t, w, &response)
+				assert.Equal(t, "PRECONDITION_FAILED", response.Code)
+				_, err = store.GetListByID(testUserID, created.ID)
+				require.NoError(t, err)
+			}
+		}
+
+		_, err = store.GetListByID(testUserID, created.ID)
+		assert.ErrorIs(t, err, storage.ErrListNotFound)
+	})
+
 	t.Run("returns 404 for non-existent list", func(t *testing.T) {
 		handler, _ := setupListHandler()
 
@@ -598,7 +1207,285 @@ func TestDeleteList(t *testing.T) {
 		assert.Contains(t, []int{http.StatusOK, http.StatusNoContent}, w.Code)
 
 		// Verify todos are also deleted (indirectly through list not found)
-		_, err = store.GetTodosByList(testUserID, list.ID, nil, nil, "createdAt", "asc")
+		_, _, err = store.GetTodosByList(
+			testUserID, list.ID, storage.ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		assert.ErrorIs(t, err, storage.ErrListNotFound)
+	})
+}
+
+func TestMergeLists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mergeRequest := func(t *testing.T, listID uuid.UUID, body interface{}) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/merge", body)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		return w, c
+	}
+
+	t.Run("moves source todos into target and deletes source", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+		source, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Source"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, target.ID, models.CreateTodoRequest{Description: "Existing", Priority: models.PriorityLow})
+		require.NoError(t, err)
+		for _, description := range []string{"First", "Second"} {
+			_, err = store.CreateTodo(testUserID, source.ID, models.CreateTodoRequest{Description: description, Priority: models.PriorityLow})
+			require.NoError(t, err)
+		}
+
+		w, c := mergeRequest(t, target.ID, models.MergeListsRequest{SourceListID: source.ID})
+		handler.MergeLists(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var merged models.TodoList
+		testutil.ParseJSONResponse(t, w, &merged)
+		assert.Equal(t, target.ID, merged.ID)
+		assert.Equal(t, 3, merged.TodoCount)
+
+		todos, _, err := store.GetTodosByList(
+			testUserID, target.ID, storage.ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Len(t, todos, 3)
+
+		_, err = store.GetListByID(testUserID, source.ID)
 		assert.ErrorIs(t, err, storage.ErrListNotFound)
 	})
+
+	t.Run("returns 404 when source list not found", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+
+		w, c := mergeRequest(t, target.ID, models.MergeListsRequest{SourceListID: uuid.New()})
+		handler.MergeLists(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "SOURCE_LIST_NOT_FOUND")
+	})
+
+	t.Run("rejects merging a list into itself", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+
+		w, c := mergeRequest(t, target.ID, models.MergeListsRequest{SourceListID: target.ID})
+		handler.MergeLists(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SOURCE_LIST")
+	})
+
+	t.Run("requires a source list ID", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+
+		w, c := mergeRequest(t, target.ID, map[string]string{})
+		handler.MergeLists(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+	})
+}
+
+func TestGetListStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("returns counts and estimate totals", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+		require.NoError(t, err)
+
+		estimate := 25
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+			Description:     "Estimated",
+			Priority:        models.PriorityHigh,
+			EstimateMinutes: &estimate,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/stats", http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+
+		handler.GetListStats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stats models.ListStats
+		testutil.ParseJSONResponse(t, w, &stats)
+		assert.Equal(t, list.ID, stats.ListID)
+		assert.Equal(t, 25, stats.TotalEstimateMinutes)
+		assert.Equal(t, 25, stats.RemainingEstimateMinutes)
+		assert.Equal(t, 1, stats.Total)
+		assert.Equal(t, 1, stats.Incomplete)
+		assert.Equal(t, 1, stats.ByPriority.High)
+	})
+
+	t.Run("empty list reports zeros rather than nulls", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Empty"})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/stats", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+
+		handler.GetListStats(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":0`)
+		assert.Contains(t, w.Body.String(), `"overdue":0`)
+		assert.Contains(t, w.Body.String(), `"byPriority":{"high":0,"medium":0,"low":0}`)
+	})
+
+	t.Run("returns 404 for non-existent list", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		nonExistentID := uuid.New()
+		req := httptest.NewRequest("GET", "/lists/"+nonExistentID.String()+"/stats", http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: nonExistentID.String()}}
+
+		handler.GetListStats(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+	})
+}
+
+func TestGetUserStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	call := func(handler *ListHandler) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/stats/summary", http.NoBody)
+		handler.GetUserStats(c)
+		return w
+	}
+
+	t.Run("returns zeros for a user without lists", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		w := call(handler)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"lists":0,"totalTodos":0,"completedTodos":0,"completionRate":0,`+
+			`"completedLast7Days":0,"completedLast30Days":0,"overdue":0}`, w.Body.String())
+	})
+
+	t.Run("summarizes todos across lists", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		for _, name := range []string{"Home", "Work"} {
+			list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: name})
+			require.NoError(t, err)
+			todo, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Task", Priority: models.PriorityLow})
+			require.NoError(t, err)
+			if name == "Work" {
+				completed := true
+				_, err = store.UpdateTodo(testUserID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+				require.NoError(t, err)
+			}
+		}
+
+		w := call(handler)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats models.UserStats
+		testutil.ParseJSONResponse(t, w, &stats)
+		assert.Equal(t, models.UserStats{
+			Lists:               2,
+			TotalTodos:          2,
+			CompletedTodos:      1,
+			CompletionRate:      0.5,
+			CompletedLast7Days:  1,
+			CompletedLast30Days: 1,
+		}, stats)
+	})
+}
+
+func TestHeadListStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("returns stats as headers matching the GET body", func(t *testing.T) {
+		handler, store := setupListHandler()
+
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+		require.NoError(t, err)
+
+		for _, minutes := range []int{25, 40} {
+			estimate := minutes
+			_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+				Description:     "Estimated",
+				Priority:        models.PriorityMedium,
+				EstimateMinutes: &estimate,
+			})
+			require.NoError(t, err)
+		}
+
+		get := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(get)
+		c.Request = httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/stats", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		handler.GetListStats(c)
+		require.Equal(t, http.StatusOK, get.Code)
+
+		var stats models.ListStats
+		testutil.ParseJSONResponse(t, get, &stats)
+
+		head := httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(head)
+		c.Request = httptest.NewRequest("HEAD", "/lists/"+list.ID.String()+"/stats", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		handler.HeadListStats(c)
+
+		assert.Equal(t, http.StatusOK, head.Code)
+		assert.Empty(t, head.Body.String())
+		assert.Equal(t, strconv.Itoa(stats.TotalEstimateMinutes), head.Header().Get("X-Total-Estimate-Minutes"))
+		assert.Equal(t, strconv.Itoa(stats.RemainingEstimateMinutes), head.Header().Get("X-Remaining-Estimate-Minutes"))
+		assert.Equal(t, "65", head.Header().Get("X-Total-Estimate-Minutes"))
+		assert.Equal(t, strconv.Itoa(stats.Total), head.Header().Get("X-Todos-Total"))
+		assert.Equal(t, "2", head.Header().Get("X-Todos-Medium-Priority"))
+		assert.Equal(t, "0", head.Header().Get("X-Todos-Overdue"))
+	})
+
+	t.Run("returns 404 for non-existent list", func(t *testing.T) {
+		handler, _ := setupListHandler()
+
+		nonExistentID := uuid.New()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("HEAD", "/lists/"+nonExistentID.String()+"/stats", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: nonExistentID.String()}}
+
+		handler.HeadListStats(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("X-Total-Estimate-Minutes"))
+	})
 }
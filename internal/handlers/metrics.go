@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"todolist-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler serves lightweight request counters for quick-glance monitoring
+type MetricsHandler struct {
+	tracker *middleware.InFlightTracker
+}
+
+// NewMetricsHandler creates a metrics handler reporting the given tracker's counters
+func NewMetricsHandler(tracker *middleware.InFlightTracker) *MetricsHandler {
+	return &MetricsHandler{tracker: tracker}
+}
+
+// MetricsResponse represents the request metrics response
+type MetricsResponse struct {
+	middleware.RequestMetrics
+	Timestamp string `json:"timestamp"`
+	Uptime    string `json:"uptime"`
+}
+
+// RequestMetrics reports in-flight, served and failed request counts since start
+// @Summary Request metrics
+// @Description Returns in-flight request count and totals of requests served, client errors and server errors since start
+// @Tags Health
+// @Produce json
+// @Success 200 {object} MetricsResponse
+// @Router /health/metrics [get]
+func (h *MetricsHandler) RequestMetrics(c *gin.Context) {
+	metrics := h.tracker.Snapshot()
+	respondJSON(c, http.StatusOK, MetricsResponse{
+		RequestMetrics: metrics,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Uptime:         formatDuration(time.Since(metrics.StartedAt)),
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tracker := middleware.NewInFlightTracker()
+	handler := NewMetricsHandler(tracker)
+
+	router := gin.New()
+	router.Use(tracker.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	router.GET("/broken", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	router.GET("/health/metrics", handler.RequestMetrics)
+
+	getMetrics := func() MetricsResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health/metrics", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp MetricsResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return resp
+	}
+
+	t.Run("reports the metrics request itself as in flight", func(t *testing.T) {
+		resp := getMetrics()
+		assert.Equal(t, int64(1), resp.InFlight)
+		assert.Equal(t, int64(0), resp.RequestsServed)
+		assert.NotEmpty(t, resp.Uptime)
+	})
+
+	t.Run("counts served requests and errors", func(t *testing.T) {
+		for _, path := range []string{"/ok", "/missing", "/broken", "/broken"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, http.NoBody))
+		}
+
+		resp := getMetrics()
+		// The earlier metrics request has completed and is counted as served
+		assert.Equal(t, int64(5), resp.RequestsServed)
+		assert.Equal(t, int64(1), resp.ClientErrors)
+		assert.Equal(t, int64(2), resp.ServerErrors)
+		assert.Equal(t, int64(1), resp.InFlight)
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications handles GET /notifications, listing the user's overdue
+// and due-soon todos that have not been acknowledged, soonest due first
+func (h *TodoHandler) GetNotifications(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	notifications, err := h.storage.GetNotifications(userID, time.Now(), h.dueSoonWindow())
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve notifications",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, notifications)
+}
+
+// AcknowledgeNotification handles POST /notifications/:notificationId/ack,
+// dismissing a notification so it is not returned again
+func (h *TodoHandler) AcknowledgeNotification(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	notificationType, todoID, ok := models.ParseNotificationID(c.Param("notificationId"))
+	if !ok {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_NOTIFICATION_ID",
+			Message: "Notification ID must be <type>:<todoId> with type overdue or due_soon",
+		})
+		return
+	}
+
+	err := h.storage.AcknowledgeNotification(userID, todoID, notificationType)
+	if err != nil {
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "NOTIFICATION_NOT_FOUND",
+				Message: "The requested notification was not found",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to acknowledge notification",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// dueSoonWindow returns the configured due-soon window, or the default
+func (h *TodoHandler) dueSoonWindow() time.Duration {
+	if h.config.DueSoonWindow <= 0 {
+		return defaultDueSoonWindow
+	}
+	return h.config.DueSoonWindow
+}
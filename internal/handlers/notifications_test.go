@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	call := func(handle gin.HandlerFunc, method string, params gin.Params) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/notifications", http.NoBody)
+		c.Params = params
+		handle(c)
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	t.Run("lists an overdue todo until it is acknowledged", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		past := time.Now().Add(-time.Hour)
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "File taxes",
+			Priority:    models.PriorityHigh,
+			DueDate:     &past,
+		})
+		require.NoError(t, err)
+
+		w := call(handler.GetNotifications, "GET", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var notifications []models.Notification
+		testutil.ParseJSONResponse(t, w, &notifications)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationOverdue, notifications[0].Type)
+		assert.Equal(t, todo.ID, notifications[0].TodoID)
+		assert.Equal(t, "File taxes", notifications[0].Description)
+
+		w = call(handler.AcknowledgeNotification, "POST", gin.Params{{Key: "notificationId", Value: notifications[0].ID}})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = call(handler.GetNotifications, "GET", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		testutil.ParseJSONResponse(t, w, &notifications)
+		assert.Empty(t, notifications)
+	})
+
+	t.Run("rejects malformed notification IDs", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		for _, id := range []string{"overdue", "snoozed:" + uuid.New().String(), "overdue:not-a-uuid"} {
+			w := call(handler.AcknowledgeNotification, "POST", gin.Params{{Key: "notificationId", Value: id}})
+			assert.Equal(t, http.StatusBadRequest, w.Code, id)
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, "INVALID_NOTIFICATION_ID", errResp.Code)
+		}
+	})
+
+	t.Run("returns 404 for unknown todos", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		id := models.NotificationID(models.NotificationDueSoon, uuid.New())
+		w := call(handler.AcknowledgeNotification, "POST", gin.Params{{Key: "notificationId", Value: id}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "NOTIFICATION_NOT_FOUND", errResp.Code)
+	})
+}
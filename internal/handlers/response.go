@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"todolist-api/internal/i18n"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSON writes obj as the JSON response body, renaming fields to
+// snake_case when the request is configured for it (see JSON_FIELD_CASE).
+// The message of an error response is translated to the request's locale.
+func respondJSON(c *gin.Context, status int, obj interface{}) {
+	obj = localizeError(c, obj)
+	if middleware.GetJSONFieldCase(c) != middleware.FieldCaseSnake {
+		c.JSON(status, obj)
+		return
+	}
+
+	converted, err := snakeCaseJSON(obj)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "RESPONSE_ENCODING_FAILED",
+			Message: "Failed to encode response",
+		})
+		return
+	}
+	c.JSON(status, converted)
+}
+
+// respondDBBusy responds 503 DB_BUSY with Retry-After when err is
+// storage.ErrDBBusy, reporting whether it did so the caller can return
+func respondDBBusy(c *gin.Context, err error) bool {
+	if err != storage.ErrDBBusy {
+		return false
+	}
+	c.Header("Retry-After", "1")
+	respondJSON(c, http.StatusServiceUnavailable, models.ErrorResponse{
+		Code:    "DB_BUSY",
+		Message: "The database is busy. Please try again shortly.",
+	})
+	return true
+}
+
+// localizeError returns obj with its message translated to the request's
+// locale when obj is an error response, and obj unchanged otherwise. Only the
+// message is translated; the code stays stable for clients.
+func localizeError(c *gin.Context, obj interface{}) interface{} {
+	locale := middleware.GetLocale(c)
+	switch errResp := obj.(type) {
+	case models.ErrorResponse:
+		errResp.Message = i18n.Translate(locale, errResp.Message)
+		return errResp
+	case *models.ErrorResponse:
+		translated := *errResp
+		translated.Message = i18n.Translate(locale, errResp.Message)
+		return &translated
+	}
+	return obj
+}
+
+// respondCreated answers a successful create with 201 and a Location header
+// for the new resource at id under the request path. With Prefer:
+// return=minimal the body is left empty; otherwise it holds obj.
+func respondCreated(c *gin.Context, id string, obj interface{}) {
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+id)
+	if prefersMinimal(c) {
+		c.Header("Preference-Applied", "return=minimal")
+		c.Status(http.StatusCreated)
+		return
+	}
+	respondJSON(c, http.StatusCreated, obj)
+}
+
+// prefersMinimal reports whether the request's Prefer headers (RFC 7240) ask
+// for return=minimal. The last return preference wins.
+func prefersMinimal(c *gin.Context) bool {
+	minimal := false
+	for _, header := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			// Drop any parameters after the preference itself
+			token, _, _ := strings.Cut(preference, ";")
+			name, value, _ := strings.Cut(strings.TrimSpace(token), "=")
+			if strings.EqualFold(name, "return") {
+				minimal = strings.EqualFold(strings.Trim(value, `"`), "minimal")
+			}
+		}
+	}
+	return minimal
+}
+
+// snakeCaseJSON round-trips obj through JSON and rewrites every object key to
+// snake_case, preserving numbers exactly
+func snakeCaseJSON(obj interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return snakeCaseKeys(generic), nil
+}
+
+// snakeCaseKeys recursively renames map keys to snake_case
+func snakeCaseKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, inner := range v {
+			renamed[toSnakeCase(key)] = snakeCaseKeys(inner)
+		}
+		return renamed
+	case []interface{}:
+		for i := range v {
+			v[i] = snakeCaseKeys(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// toSnakeCase converts a camelCase identifier such as "createdAt" or
+// "userID" to snake_case ("created_at", "user_id")
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
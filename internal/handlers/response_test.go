@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/i18n"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondJSONFieldCase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	getList := func(t *testing.T, fieldCase string) map[string]interface{} {
+		handler, store := setupListHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Groceries"})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+list.ID.String(), http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		if fieldCase != "" {
+			c.Set(middleware.ContextKeyJSONFieldCase, fieldCase)
+		}
+
+		handler.GetListByID(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("camelCase by default", func(t *testing.T) {
+		body := getList(t, "")
+		assert.Contains(t, body, "createdAt")
+		assert.NotContains(t, body, "created_at")
+	})
+
+	t.Run("snake_case when configured", func(t *testing.T) {
+		body := getList(t, middleware.FieldCaseSnake)
+		assert.Contains(t, body, "created_at")
+		assert.Contains(t, body, "updated_at")
+		assert.NotContains(t, body, "createdAt")
+		assert.Equal(t, "Groceries", body["name"])
+	})
+}
+
+func TestRespondJSONLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, _ := setupListHandler()
+	router := gin.New()
+	router.Use(middleware.Locale(i18n.DefaultLocale))
+	router.GET("/lists/:listId", handler.GetListByID)
+
+	getMissingList := func(t *testing.T, acceptLanguage string) models.ErrorResponse {
+		req := httptest.NewRequest("GET", "/lists/"+uuid.New().String(), http.NoBody)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+		return errResp
+	}
+
+	t.Run("translates the message for a supported language", func(t *testing.T) {
+		for _, acceptLanguage := range []string{"es", "es-MX,en;q=0.5", "fr, es;q=0.8"} {
+			errResp := getMissingList(t, acceptLanguage)
+			assert.Equal(t, "No se encontró la lista de tareas solicitada", errResp.Message, acceptLanguage)
+		}
+	})
+
+	t.Run("falls back to English", func(t *testing.T) {
+		for _, acceptLanguage := range []string{"", "fr-FR", "de, *", "es;q=0"} {
+			errResp := getMissingList(t, acceptLanguage)
+			assert.Equal(t, "The requested todo list was not found", errResp.Message, acceptLanguage)
+		}
+	})
+}
+
+func TestRespondCreatedPrefer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	createList := func(t *testing.T, prefer string) *httptest.ResponseRecorder {
+		handler, _ := setupListHandler()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/api/v1/lists", models.CreateTodoListRequest{Name: "Groceries"})
+		if prefer != "" {
+			c.Request.Header.Set("Prefer", prefer)
+		}
+		handler.CreateList(c)
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	for _, prefer := range []string{"", "return=representation"} {
+		t.Run("full body with Prefer "+prefer, func(t *testing.T) {
+			w := createList(t, prefer)
+			require.Equal(t, http.StatusCreated, w.Code)
+
+			var list models.TodoList
+			testutil.ParseJSONResponse(t, w, &list)
+			assert.Equal(t, "Groceries", list.Name)
+			assert.Equal(t, "/api/v1/lists/"+list.ID.String(), w.Header().Get("Location"))
+			assert.Empty(t, w.Header().Get("Preference-Applied"))
+		})
+	}
+
+	t.Run("empty body with Prefer return=minimal", func(t *testing.T) {
+		w := createList(t, "return=minimal")
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Regexp(t, `^/api/v1/lists/[0-9a-f-]{36}$`, w.Header().Get("Location"))
+		assert.Equal(t, "return=minimal", w.Header().Get("Preference-Applied"))
+	})
+
+	t.Run("todos report their location under the list", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		path := "/api/v1/lists/" + listID.String() + "/todos"
+		c.Request = testutil.MakeJSONRequest(t, "POST", path, models.CreateTodoRequest{
+			Description: "Milk",
+			Priority:    models.PriorityLow,
+		})
+		c.Request.Header.Set("Prefer", "respond-async, return=minimal")
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.CreateTodo(c)
+		c.Writer.WriteHeaderNow()
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Regexp(t, `^`+path+`/[0-9a-f-]{36}$`, w.Header().Get("Location"))
+	})
+}
+
+func TestPrefersMinimal(t *testing.T) {
+	for prefer, want := range map[string]bool{
+		"":                                      false,
+		"return=representation":                 false,
+		"return=minimal":                        true,
+		"RETURN=\"minimal\"":                    true,
+		"handling=lenient; foo, return=minimal": true,
+		"return=minimal, return=representation": false,
+		"wait=10":                               false,
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/lists", http.NoBody)
+		if prefer != "" {
+			c.Request.Header.Set("Prefer", prefer)
+		}
+		assert.Equal(t, want, prefersMinimal(c), "Prefer: %s", prefer)
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"createdAt":            "created_at",
+		"name":                 "name",
+		"userID":               "user_id",
+		"HTTPStatus":           "http_status",
+		"autoArchiveCompleted": "auto_archive_completed",
+		"db_open_connections":  "db_open_connections",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, toSnakeCase(input), input)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchTodos handles GET /search?q=..., searching the descriptions of the
+// user's active todos (and list names with includeLists=true), newest first
+func (h *TodoHandler) SearchTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_SEARCH_QUERY",
+			Message: "q must be a non-empty search string",
+		})
+		return
+	}
+
+	priority, ok := parsePriorityFilter(c)
+	if !ok {
+		return
+	}
+
+	completed, ok := parseCompletedFilter(c, false)
+	if !ok {
+		return
+	}
+
+	includeLists, ok := parseBoolFlag(c, "includeLists", "INVALID_INCLUDE_LISTS")
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	todos, pagination, err := h.storage.SearchTodos(userID, storage.SearchOptions{
+		Query:            query,
+		IncludeListNames: includeLists,
+		Priority:         priority,
+		Completed:        completed,
+		Page:             page,
+		Limit:            limit,
+	})
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to search todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PaginatedTodosResponse{
+		Data:       todos,
+		Pagination: pagination,
+	})
+}
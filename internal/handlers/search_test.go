@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	search := func(handler *TodoHandler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/search"+query, http.NoBody)
+		handler.SearchTodos(c)
+		return w
+	}
+
+	t.Run("matches descriptions across lists with list names", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		other, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Errands"})
+		require.NoError(t, err)
+
+		_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Write REPORT", Priority: models.PriorityHigh})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, other.ID, models.CreateTodoRequest{Description: "Print report", Priority: models.PriorityLow})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, other.ID, models.CreateTodoRequest{Description: "Buy milk", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := search(handler, "?q=report")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, 2, response.Pagination.TotalItems)
+		listNames := map[string]string{}
+		for _, todo := range response.Data {
+			listNames[todo.Description] = todo.ListName
+		}
+		assert.Equal(t, map[string]string{"Write REPORT": "Test List", "Print report": "Errands"}, listNames)
+
+		w = search(handler, "?q=report&priority=low")
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Print report", response.Data[0].Description)
+	})
+
+	t.Run("includes list name matches only when asked", func(t *testing.T) {
+		handler, store, _ := setupTodoHandler()
+		errands, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Errands"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, errands.ID, models.CreateTodoRequest{Description: "Buy milk", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, search(handler, "?q=errand"), &response)
+		assert.Empty(t, response.Data)
+
+		testutil.ParseJSONResponse(t, search(handler, "?q=errand&includeLists=true"), &response)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Buy milk", response.Data[0].Description)
+	})
+
+	t.Run("paginates results", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		for i := 0; i < 3; i++ {
+			_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Task", Priority: models.PriorityLow})
+			require.NoError(t, err)
+		}
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, search(handler, "?q=task&limit=2&page=2"), &response)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, 3, response.Pagination.TotalItems)
+		assert.Equal(t, 2, response.Pagination.TotalPages)
+	})
+
+	t.Run("rejects an empty query", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		for _, query := range []string{"", "?q=", "?q=%20%20"} {
+			w := search(handler, query)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &resp)
+			assert.Equal(t, "INVALID_SEARCH_QUERY", resp.Code)
+		}
+	})
+
+	t.Run("rejects an invalid priority", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		w := search(handler, "?q=task&priority=urgent")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
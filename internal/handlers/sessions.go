@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"todolist-api/internal/auth"
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// clientInfo describes the client making the request, for recording on a new session
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// ListSessions returns the current user's active sessions
+// @Summary List sessions
+// @Description List the authenticated user's active sessions (refresh tokens that are neither expired nor revoked),
+// @Description newest first. The session of the access token used for the request is flagged current.
+// @Tags User
+// @Produce json
+// @Success 200 {array} models.Session
+// @Failure 401 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	sessions, err := h.authService.ListActiveSessions(userID, middleware.GetSessionID(c))
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "SESSIONS_FETCH_FAILED",
+			Message: "Failed to fetch sessions",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, sessions)
+}
+
+// RevokeSession revokes one of the current user's sessions
+// @Summary Revoke session
+// @Description Revoke one of the authenticated user's sessions so its refresh token can no longer be used
+// @Tags User
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 204
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/sessions/{sessionId} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		respondJSON(c, http.StatusUnauthorized, models.ErrorResponse{
+			Code:    "UNAUTHORIZED",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_SESSION_ID",
+			Message: "Invalid session ID format",
+		})
+		return
+	}
+
+	err = h.authService.RevokeRefreshTokenByID(userID, sessionID)
+	if err != nil {
+		// Other users' sessions are reported as missing, not forbidden
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "SESSION_NOT_FOUND",
+				Message: "Session not found",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "SESSION_REVOKE_FAILED",
+			Message: "Failed to revoke session",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultShareLinkTTL is how long a share link works unless the request
+	// asks for another expiry
+	defaultShareLinkTTL = 7 * 24 * time.Hour
+	// maxSharedTodos caps the todos shown on a shared list
+	maxSharedTodos = 1000
+)
+
+// CreateShareLink handles POST /lists/:listId/share-link
+// @Summary Share a list read-only
+// @Description Create a link anyone can use, without an account, to view the list and its active todos until it
+// @Description expires (after expiresInHours, default 7 days) or is revoked. The token is only returned here.
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param listId path string true "List ID"
+// @Param request body models.CreateShareLinkRequest false "Link expiry"
+// @Success 201 {object} models.ShareLinkResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /lists/{listId}/share-link [post]
+func (h *ListHandler) CreateShareLink(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if c.Request.ContentLength != 0 {
+		if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: "Invalid request body",
+				Details: bindingErrorDetails(bindErr),
+			})
+			return
+		}
+	}
+
+	ttl := defaultShareLinkTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	link, err := h.storage.CreateShareLink(userID, listID, time.Now().Add(ttl))
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to create share link",
+		})
+		return
+	}
+
+	respondCreated(c, link.ID.String(), models.ShareLinkResponse{
+		ListShareLink: *link,
+		URL:           sharedListPath(c.Request.URL.Path, link.Token),
+	})
+}
+
+// sharedListPath returns the public path of a shared list, under the same
+// API prefix as the /lists request path that created it
+func sharedListPath(requestPath, token string) string {
+	prefix := requestPath
+	if i := strings.Index(requestPath, "/lists/"); i >= 0 {
+		prefix = requestPath[:i]
+	}
+	return prefix + "/public/lists/" + token
+}
+
+// RevokeShareLink handles DELETE /lists/:listId/share-link/:linkId
+// @Summary Revoke a share link
+// @Description Stop a share link of the list from working. Revoking a link twice returns 404.
+// @Tags Lists
+// @Param listId path string true "List ID"
+// @Param linkId path string true "Share link ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /lists/{listId}/share-link/{linkId} [delete]
+func (h *ListHandler) RevokeShareLink(c *gin.Context) {
+	// Get user ID (or default for unauthenticated access)
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_SHARE_LINK_ID",
+			Message: "Invalid share link ID format",
+		})
+		return
+	}
+
+	if err := h.storage.RevokeShareLink(userID, listID, linkID); err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrShareLinkNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "SHARE_LINK_NOT_FOUND",
+				Message: "The requested share link was not found",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to revoke share link",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSharedList handles GET /public/lists/:token
+// @Summary View a shared list
+// @Description Read-only view of a list and its active todos through a share link, without authentication.
+// @Description Unknown, expired and revoked links return 404.
+// @Tags Public
+// @Produce json
+// @Param token path string true "Share link token"
+// @Success 200 {object} models.SharedList
+// @Failure 404 {object} models.ErrorResponse
+// @Router /public/lists/{token} [get]
+func (h *ListHandler) GetSharedList(c *gin.Context) {
+	link, err := h.storage.GetSharedList(c.Param("token"), time.Now())
+	if err != nil {
+		if err == storage.ErrShareLinkNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "SHARED_LIST_NOT_FOUND",
+				Message: "This share link does not exist, has expired or was revoked",
+			})
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to fetch shared list",
+		})
+		return
+	}
+
+	byList, err := h.storage.GetTodosForLists(link.List.UserID, []uuid.UUID{link.ListID}, maxSharedTodos)
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to fetch shared list",
+		})
+		return
+	}
+
+	todos := make([]models.SharedTodo, 0, len(byList[link.ListID]))
+	for _, todo := range byList[link.ListID] {
+		todos = append(todos, models.SharedTodo{
+			Description: todo.Description,
+			Priority:    todo.Priority,
+			DueDate:     todo.DueDate,
+			Completed:   todo.Completed,
+		})
+	}
+
+	respondJSON(c, http.StatusOK, models.SharedList{
+		Name:        link.List.Name,
+		Description: link.List.Description,
+		Todos:       todos,
+		ExpiresAt:   link.ExpiresAt,
+	})
+}
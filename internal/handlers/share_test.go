@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store := setupListHandler()
+	router := gin.New()
+	router.POST("/api/v1/lists/:listId/share-link", handler.CreateShareLink)
+	router.DELETE("/api/v1/lists/:listId/share-link/:linkId", handler.RevokeShareLink)
+	router.GET("/api/v1/public/lists/:token", handler.GetSharedList)
+
+	serve := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, testutil.MakeJSONRequest(t, method, path, body))
+		return w
+	}
+	share := func(listID uuid.UUID, body interface{}) models.ShareLinkResponse {
+		w := serve("POST", "/api/v1/lists/"+listID.String()+"/share-link", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var link models.ShareLinkResponse
+		testutil.ParseJSONResponse(t, w, &link)
+		assert.Equal(t, "/api/v1/lists/"+listID.String()+"/share-link/"+link.ID.String(), w.Header().Get("Location"))
+		return link
+	}
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Camping", Description: "Packing"})
+	require.NoError(t, err)
+	for _, description := range []string{"Tent", "Stove"} {
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: description, Priority: models.PriorityHigh})
+		require.NoError(t, err)
+	}
+
+	t.Run("the public link returns the list and its todos", func(t *testing.T) {
+		link := share(list.ID, nil)
+		require.NotEmpty(t, link.Token)
+		assert.Equal(t, "/api/v1/public/lists/"+link.Token, link.URL)
+
+		w := serve("GET", link.URL, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var shared models.SharedList
+		testutil.ParseJSONResponse(t, w, &shared)
+		assert.Equal(t, "Camping", shared.Name)
+		assert.Equal(t, "Packing", shared.Description)
+		require.Len(t, shared.Todos, 2)
+		assert.Equal(t, "Tent", shared.Todos[0].Description)
+		assert.Equal(t, models.PriorityHigh, shared.Todos[0].Priority)
+		assert.WithinDuration(t, link.ExpiresAt, shared.ExpiresAt, 0)
+		assert.NotContains(t, w.Body.String(), testUserID.String())
+		assert.NotContains(t, w.Body.String(), list.ID.String())
+	})
+
+	t.Run("the public link is read-only", func(t *testing.T) {
+		link := share(list.ID, nil)
+
+		for _, method := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+			w := serve(method, link.URL, map[string]interface{}{"name": "Hijacked"})
+			assert.Equal(t, http.StatusNotFound, w.Code, method)
+		}
+
+		current, err := store.GetListByID(testUserID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Camping", current.Name)
+		assert.Equal(t, 2, current.TodoCount)
+	})
+
+	t.Run("expired and revoked links return 404", func(t *testing.T) {
+		expiring := share(list.ID, map[string]interface{}{"expiresInHours": 1})
+		assert.WithinDuration(t, expiring.CreatedAt.Add(time.Hour), expiring.ExpiresAt, 2*time.Second)
+
+		link := share(list.ID, nil)
+		w := serve("DELETE", "/api/v1/lists/"+list.ID.String()+"/share-link/"+link.ID.String(), nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = serve("GET", link.URL, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "SHARED_LIST_NOT_FOUND", errResp.Code)
+
+		w = serve("DELETE", "/api/v1/lists/"+list.ID.String()+"/share-link/"+link.ID.String(), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "SHARE_LINK_NOT_FOUND", errResp.Code)
+
+		expired, err := store.CreateShareLink(testUserID, list.ID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		w = serve("GET", "/api/v1/public/lists/"+expired.Token, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve("GET", "/api/v1/public/lists/unknown-token", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		w := serve("POST", "/api/v1/lists/"+list.ID.String()+"/share-link", map[string]interface{}{"expiresInHours": 0.5})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve("POST", "/api/v1/lists/"+list.ID.String()+"/share-link", map[string]interface{}{"expiresInHours": 9000})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve("POST", "/api/v1/lists/"+uuid.New().String()+"/share-link", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
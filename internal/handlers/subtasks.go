@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetSubtasks handles GET /lists/:listId/todos/:todoId/subtasks
+func (h *TodoHandler) GetSubtasks(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+
+	subtasks, err := h.storage.GetSubtasks(userID, listID, todoID)
+	if err != nil {
+		respondSubtaskError(c, err, "Failed to retrieve subtasks")
+		return
+	}
+
+	respondJSON(c, http.StatusOK, subtasks)
+}
+
+// CreateSubtask handles POST /lists/:listId/todos/:todoId/subtasks, adding the
+// subtask after the todo's existing ones
+func (h *TodoHandler) CreateSubtask(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSubtaskRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	subtask, err := h.storage.CreateSubtask(userID, listID, todoID, req)
+	if err != nil {
+		respondSubtaskError(c, err, "Failed to create subtask")
+		return
+	}
+
+	respondCreated(c, subtask.ID.String(), subtask)
+}
+
+// UpdateSubtask handles PUT /lists/:listId/todos/:todoId/subtasks/:subtaskId.
+// With completeParent set, completing the last open subtask completes the todo.
+func (h *TodoHandler) UpdateSubtask(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+	subtaskID, ok := parseSubtaskID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSubtaskRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	subtask, err := h.storage.UpdateSubtask(userID, listID, todoID, subtaskID, req)
+	if err != nil {
+		respondSubtaskError(c, err, "Failed to update subtask")
+		return
+	}
+
+	respondJSON(c, http.StatusOK, subtask)
+}
+
+// DeleteSubtask handles DELETE /lists/:listId/todos/:todoId/subtasks/:subtaskId
+func (h *TodoHandler) DeleteSubtask(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+	subtaskID, ok := parseSubtaskID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.storage.DeleteSubtask(userID, listID, todoID, subtaskID); err != nil {
+		respondSubtaskError(c, err, "Failed to delete subtask")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseTodoPath parses the listId and todoId path parameters, writing a 400
+// response when either is malformed
+func parseTodoPath(c *gin.Context) (listID, todoID uuid.UUID, ok bool) {
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	todoID, err = uuid.Parse(c.Param("todoId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_TODO_ID",
+			Message: "Invalid todo ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return listID, todoID, true
+}
+
+// parseSubtaskID parses the subtaskId path parameter, writing a 400 response
+// when it is malformed
+func parseSubtaskID(c *gin.Context) (uuid.UUID, bool) {
+	subtaskID, err := uuid.Parse(c.Param("subtaskId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_SUBTASK_ID",
+			Message: "Invalid subtask ID format",
+		})
+		return uuid.Nil, false
+	}
+	return subtaskID, true
+}
+
+// respondSubtaskError maps a storage error from a subtask operation to its
+// response, falling back to a 500 with the given message
+func respondSubtaskError(c *gin.Context, err error, failure string) {
+	switch err {
+	case storage.ErrListNotFound:
+		respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    "LIST_NOT_FOUND",
+			Message: "The requested todo list was not found",
+		})
+	case storage.ErrTodoNotFound:
+		respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    "TODO_NOT_FOUND",
+			Message: "The requested todo was not found",
+		})
+	case storage.ErrSubtaskNotFound:
+		respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    "SUBTASK_NOT_FOUND",
+			Message: "The requested subtask was not found",
+		})
+	case storage.ErrDBBusy:
+		respondDBBusy(c, err)
+	default:
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: failure,
+		})
+	}
+}
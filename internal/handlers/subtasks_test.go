@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// call runs a subtask handler against the given path parameters
+	call := func(t *testing.T, handle gin.HandlerFunc, method string, params gin.Params, body interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		if body != nil {
+			c.Request = testutil.MakeJSONRequest(t, method, "/subtasks", body)
+		} else {
+			c.Request = httptest.NewRequest(method, "/subtasks", http.NoBody)
+		}
+		c.Params = params
+		handle(c)
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	setup := func(t *testing.T) (*TodoHandler, gin.Params, uuid.UUID, uuid.UUID) {
+		t.Helper()
+		handler, store, listID := setupTodoHandler()
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Pack",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		params := gin.Params{{Key: "listId", Value: listID.String()}, {Key: "todoId", Value: todo.ID.String()}}
+		return handler, params, listID, todo.ID
+	}
+
+	t.Run("creates, lists, updates and deletes subtasks", func(t *testing.T) {
+		handler, params, _, todoID := setup(t)
+
+		w := call(t, handler.CreateSubtask, "POST", params, models.CreateSubtaskRequest{Description: "Passport"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.Subtask
+		testutil.ParseJSONResponse(t, w, &created)
+		assert.Equal(t, "Passport", created.Description)
+		assert.Equal(t, todoID, created.TodoID)
+
+		w = call(t, handler.CreateSubtask, "POST", params, models.CreateSubtaskRequest{Description: "Tickets"})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		subtaskParams := append(gin.Params{{Key: "subtaskId", Value: created.ID.String()}}, params...)
+		w = call(t, handler.UpdateSubtask, "PUT", subtaskParams, models.UpdateSubtaskRequest{Completed: boolPtr(true)})
+		require.Equal(t, http.StatusOK, w.Code)
+		var updated models.Subtask
+		testutil.ParseJSONResponse(t, w, &updated)
+		assert.True(t, updated.Completed)
+
+		w = call(t, handler.GetSubtasks, "GET", params, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var subtasks []models.Subtask
+		testutil.ParseJSONResponse(t, w, &subtasks)
+		require.Len(t, subtasks, 2)
+		assert.Equal(t, "Passport", subtasks[0].Description)
+		assert.Equal(t, "Tickets", subtasks[1].Description)
+
+		// The parent todo reports its subtask counts
+		w = call(t, handler.GetTodoByID, "GET", params, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, 2, todo.SubtaskCount)
+		assert.Equal(t, 1, todo.CompletedSubtaskCount)
+
+		w = call(t, handler.DeleteSubtask, "DELETE", subtaskParams, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = call(t, handler.DeleteSubtask, "DELETE", subtaskParams, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "SUBTASK_NOT_FOUND", errResp.Code)
+	})
+
+	t.Run("completes the parent with completeParent", func(t *testing.T) {
+		handler, params, listID, todoID := setup(t)
+
+		w := call(t, handler.CreateSubtask, "POST", params, models.CreateSubtaskRequest{Description: "Passport"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.Subtask
+		testutil.ParseJSONResponse(t, w, &created)
+
+		subtaskParams := append(gin.Params{{Key: "subtaskId", Value: created.ID.String()}}, params...)
+		w = call(t, handler.UpdateSubtask, "PUT", subtaskParams, models.UpdateSubtaskRequest{
+			Completed:      boolPtr(true),
+			CompleteParent: true,
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		todo, err := handler.storage.GetTodoByID(testUserID, listID, todoID)
+		require.NoError(t, err)
+		assert.True(t, todo.Completed)
+	})
+
+	t.Run("rejects invalid input and unknown parents", func(t *testing.T) {
+		handler, params, _, _ := setup(t)
+
+		w := call(t, handler.CreateSubtask, "POST", params, map[string]string{"description": ""})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		missingTodo := gin.Params{params[0], {Key: "todoId", Value: uuid.New().String()}}
+		w = call(t, handler.GetSubtasks, "GET", missingTodo, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "TODO_NOT_FOUND", errResp.Code)
+
+		badSubtask := append(gin.Params{{Key: "subtaskId", Value: "not-a-uuid"}}, params...)
+		w = call(t, handler.DeleteSubtask, "DELETE", badSubtask, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_SUBTASK_ID", errResp.Code)
+	})
+}
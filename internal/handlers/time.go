@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeHandler reports the server clock so clients can correct for clock skew
+type TimeHandler struct {
+	now      func() time.Time
+	location *time.Location
+}
+
+// NewTimeHandler creates a time handler reporting the server's local timezone,
+// which can be set with the standard TZ environment variable
+func NewTimeHandler() *TimeHandler {
+	return &TimeHandler{now: time.Now, location: time.Local}
+}
+
+// TimeResponse represents the server time response
+type TimeResponse struct {
+	UTC           string `json:"utc"`
+	Local         string `json:"local"`
+	Timezone      string `json:"timezone"`
+	OffsetSeconds int    `json:"offsetSeconds"`
+	Unix          int64  `json:"unix"`
+}
+
+// ServerTime handles GET /time
+// @Summary Server time
+// @Description Returns the server's current time in UTC and in its configured timezone
+// @Tags Health
+// @Produce json
+// @Success 200 {object} TimeResponse
+// @Router /time [get]
+func (h *TimeHandler) ServerTime(c *gin.Context) {
+	now := h.now()
+	local := now.In(h.location)
+	_, offset := local.Zone()
+
+	respondJSON(c, http.StatusOK, TimeResponse{
+		UTC:           now.UTC().Format(time.RFC3339Nano),
+		Local:         local.Format(time.RFC3339Nano),
+		Timezone:      h.location.String(),
+		OffsetSeconds: offset,
+		Unix:          now.Unix(),
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("returns current time close to now", func(t *testing.T) {
+		handler := NewTimeHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/time", http.NoBody)
+		handler.ServerTime(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp TimeResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+
+		utc, err := time.Parse(time.RFC3339Nano, resp.UTC)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), utc, 5*time.Second)
+		assert.Equal(t, time.Local.String(), resp.Timezone)
+		assert.InDelta(t, time.Now().Unix(), resp.Unix, 5)
+	})
+
+	t.Run("reports the configured timezone", func(t *testing.T) {
+		location, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skip("timezone database not available")
+		}
+		fixed := time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC)
+		handler := &TimeHandler{now: func() time.Time { return fixed }, location: location}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/time", http.NoBody)
+		handler.ServerTime(c)
+
+		var resp TimeResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+
+		assert.Equal(t, "2026-01-15T17:00:00Z", resp.UTC)
+		assert.Equal(t, "2026-01-15T12:00:00-05:00", resp.Local)
+		assert.Equal(t, "America/New_York", resp.Timezone)
+		assert.Equal(t, -5*60*60, resp.OffsetSeconds)
+	})
+}
@@ -1,126 +1,801 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"todolist-api/internal/middleware"
 	"todolist-api/internal/models"
 	"todolist-api/internal/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 )
 
+// SettingsProvider supplies per-user preferences that adjust handler defaults
+type SettingsProvider interface {
+	GetSettings(userID uuid.UUID) (*models.UserSettings, error)
+}
+
 // TodoHandler handles todo operations
 type TodoHandler struct {
-	storage storage.Store
+	storage  storage.Store
+	config   *TodoConfig
+	settings SettingsProvider // optional; nil when user settings are unavailable
+}
+
+// NewTodoHandler creates a new todo handler with default configuration
+func NewTodoHandler(store storage.Store) *TodoHandler {
+	return NewTodoHandlerWithConfig(store, &TodoConfig{})
+}
+
+// NewTodoHandlerWithConfig creates a new todo handler using the given config
+func NewTodoHandlerWithConfig(store storage.Store, config *TodoConfig) *TodoHandler {
+	return NewTodoHandlerWithSettings(store, config, nil)
+}
+
+// NewTodoHandlerWithSettings creates a new todo handler whose listing defaults
+// follow each user's saved settings
+func NewTodoHandlerWithSettings(store storage.Store, config *TodoConfig, settings SettingsProvider) *TodoHandler {
+	return &TodoHandler{storage: store, config: config, settings: settings}
+}
+
+// listingDefaults returns the hide-completed and sort defaults for a user's
+// todo listings. Saved user settings take precedence over server config; if
+// they cannot be loaded the server defaults are used, and a user who never
+// saved settings gets the server's hide-completed default.
+func (h *TodoHandler) listingDefaults(userID uuid.UUID) (hideCompleted bool, sortBy, sortOrder string) {
+	hideCompleted, sortBy, sortOrder = h.config.DefaultHideCompleted, "createdAt", "asc"
+	if h.settings == nil {
+		return hideCompleted, sortBy, sortOrder
+	}
+
+	settings, err := h.settings.GetSettings(userID)
+	if err != nil {
+		return hideCompleted, sortBy, sortOrder
+	}
+	if settings.Saved() {
+		hideCompleted = settings.HideCompleted
+	}
+	return hideCompleted, settings.DefaultSortBy, settings.DefaultSortOrder
+}
+
+// GetTodosByList handles GET /lists/:listId/todos
+// @Summary List todos in a list
+// @Description Returns a page of the list's todos. When dueAfter or dueBefore is given, only todos with a due date
+// @Description inside the inclusive range are returned; todos without a due date are excluded.
+// @Tags Todos
+// @Produce json
+// @Param listId path string true "List ID"
+// @Param priority query string false "Filter by priority" Enums(low, medium, high)
+// @Param completed query bool false "Filter by completion"
+// @Param tag query []string false "Only todos carrying every given tag" collectionFormat(multi)
+// @Param archived query bool false "Only archived todos instead of active ones"
+// @Param overdue query bool false "Only incomplete todos past their due date"
+// @Param dueAfter query string false "Inclusive lower bound on due date (RFC3339)"
+// @Param dueBefore query string false "Inclusive upper bound on due date (RFC3339)"
+// @Param createdAfter query string false "Inclusive lower bound on creation time (RFC3339)"
+// @Param createdBefore query string false "Inclusive upper bound on creation time (RFC3339)"
+// @Param sortBy query string false "Sort field"
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param groupBy query string false "Return the page as groups instead of a flat array" Enums(priority, dueBucket)
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} models.PaginatedListTodosResponse
+// @Success 200 {object} models.GroupedListTodosResponse "With groupBy"
+// @Failure 400 {object} models.ErrorResponse "Invalid filter, e.g. INVALID_DUE_AFTER, INVALID_DUE_BEFORE or INVALID_DUE_RANGE"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /lists/{listId}/todos [get]
+func (h *TodoHandler) GetTodosByList(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	// Parse and validate query parameters
+	priority, ok := parsePriorityFilter(c)
+	if !ok {
+		return
+	}
+
+	hideCompleted, defaultSortBy, defaultSortOrder := h.listingDefaults(userID)
+
+	completed, ok := parseCompletedFilter(c, hideCompleted)
+	if !ok {
+		return
+	}
+
+	archived, ok := parseBoolFlag(c, "archived", "INVALID_ARCHIVED")
+	if !ok {
+		return
+	}
+
+	overdue, ok := parseBoolFlag(c, "overdue", "INVALID_OVERDUE")
+	if !ok {
+		return
+	}
+
+	dueAfter, dueBefore, ok := parseDueRange(c, "INVALID_DUE_AFTER", "INVALID_DUE_BEFORE")
+	if !ok {
+		return
+	}
+
+	createdAfter, createdBefore, ok := parseCreatedRange(c)
+	if !ok {
+		return
+	}
+
+	sortBy, sortOrder, ok := parseSortParams(c, defaultSortBy, defaultSortOrder)
+	if !ok {
+		return
+	}
+
+	groupBy, ok := parseGroupBy(c)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	// Repeated tag parameters must all match
+	tags := c.QueryArray("tag")
+
+	todos, pagination, err := h.storage.GetTodosByList(userID, listID, storage.ListTodosOptions{
+		Priority:      priority,
+		Completed:     completed,
+		Tags:          tags,
+		Archived:      archived,
+		Overdue:       overdue,
+		DueAfter:      dueAfter,
+		DueBefore:     dueBefore,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		SortBy:        sortBy,
+		SortOrder:     sortOrder,
+		Page:          page,
+		Limit:         limit,
+	})
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve todos",
+		})
+		return
+	}
+
+	if groupBy != "" {
+		respondJSON(c, http.StatusOK, models.GroupedListTodosResponse{
+			Groups:     groupTodos(todos, groupBy, time.Now().In(h.userLocation(userID))),
+			Pagination: pagination,
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PaginatedListTodosResponse{
+		Data:       todos,
+		Pagination: pagination,
+	})
+}
+
+// GetAllTodos handles GET /todos, listing todos across all of the user's lists
+func (h *TodoHandler) GetAllTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	// Parse and validate query parameters
+	priority, ok := parsePriorityFilter(c)
+	if !ok {
+		return
+	}
+
+	hideCompleted, defaultSortBy, defaultSortOrder := h.listingDefaults(userID)
+
+	completed, ok := parseCompletedFilter(c, hideCompleted)
+	if !ok {
+		return
+	}
+
+	overdue, ok := parseBoolFlag(c, "overdue", "INVALID_OVERDUE")
+	if !ok {
+		return
+	}
+
+	archived, ok := parseBoolFlag(c, "archived", "INVALID_ARCHIVED")
+	if !ok {
+		return
+	}
+
+	dueAfter, dueBefore, ok := parseDueRange(c, "INVALID_DUE_DATE", "INVALID_DUE_DATE")
+	if !ok {
+		return
+	}
+
+	createdAfter, createdBefore, ok := parseCreatedRange(c)
+	if !ok {
+		return
+	}
+
+	sortBy, sortOrder, ok := parseSortParams(c, defaultSortBy, defaultSortOrder)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	todos, pagination, err := h.storage.QueryUserTodos(userID, storage.TodoQueryOptions{
+		Priority:      priority,
+		Completed:     completed,
+		Archived:      archived,
+		Overdue:       overdue,
+		DueAfter:      dueAfter,
+		DueBefore:     dueBefore,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Search:        c.Query("search"),
+		SortBy:        sortBy,
+		SortOrder:     sortOrder,
+		Page:          page,
+		Limit:         limit,
+	})
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PaginatedTodosResponse{
+		Data:       todos,
+		Pagination: pagination,
+	})
+}
+
+// GetOverdueTodos handles GET /todos/overdue, listing incomplete todos past
+// their due date across all of the user's lists, soonest due first by default
+func (h *TodoHandler) GetOverdueTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	priority, ok := parsePriorityFilter(c)
+	if !ok {
+		return
+	}
+
+	sortBy, sortOrder, ok := parseSortParams(c, "dueDate", "asc")
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	todos, pagination, err := h.storage.QueryUserTodos(userID, storage.TodoQueryOptions{
+		Priority:  priority,
+		Overdue:   true,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		Page:      page,
+		Limit:     limit,
+	})
+	if err != nil {
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve overdue todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PaginatedTodosResponse{
+		Data:       todos,
+		Pagination: pagination,
+	})
+}
+
+// TriageOverdueTodos handles POST /todos/triage, raising every overdue todo
+// across the user's lists to high priority, or to the priority in the
+// optional body, in one transaction
+func (h *TodoHandler) TriageOverdueTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	// The body is optional; without one overdue todos become high priority
+	var req models.TriageTodosRequest
+	if c.Request.ContentLength != 0 {
+		if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: "Invalid request body",
+				Details: bindingErrorDetails(bindErr),
+			})
+			return
+		}
+	}
+	priority := models.PriorityHigh
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	affected, err := h.storage.ReprioritizeTodos(userID, storage.TodoQueryOptions{Overdue: true}, priority)
+	if err != nil {
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to triage overdue todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.TriageResult{Affected: affected, Priority: priority})
+}
+
+// CreateTodo handles POST /lists/:listId/todos. A todo without a priority
+// gets the defaultPriority query parameter, or medium without one.
+func (h *TodoHandler) CreateTodo(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	defaultPriority, ok := parsePriorityQuery(c, "defaultPriority")
+	if !ok {
+		return
+	}
+
+	var req models.CreateTodoRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+	applyDefaultPriority(&req, defaultPriority)
+
+	todo, err := h.storage.CreateTodo(userID, listID, req)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDueDateRequired {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "DUE_DATE_REQUIRED",
+				Message: "This list requires a due date on every todo",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to create todo",
+		})
+		return
+	}
+
+	respondCreated(c, todo.ID.String(), todo)
+}
+
+// maxBatchTodos caps the number of todos created by a single batch request
+const maxBatchTodos = 100
+
+// BatchCreateTodos handles POST /lists/:listId/todos/batch, creating every todo
+// in the request array or none of them
+func (h *TodoHandler) BatchCreateTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	defaultPriority, ok := parsePriorityQuery(c, "defaultPriority")
+	if !ok {
+		return
+	}
+
+	// Decode without validating so each item can be validated and reported by index
+	var reqs []models.CreateTodoRequest
+	if decodeErr := json.NewDecoder(c.Request.Body).Decode(&reqs); decodeErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Request body must be a JSON array of todos",
+			Details: map[string]interface{}{"error": decodeErr.Error()},
+		})
+		return
+	}
+	if len(reqs) == 0 {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "At least one todo is required",
+		})
+		return
+	}
+	if len(reqs) > maxBatchTodos {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "BATCH_TOO_LARGE",
+			Message: "Too many todos in one batch",
+			Details: map[string]interface{}{"maxItems": maxBatchTodos},
+		})
+		return
+	}
+
+	itemErrors := make([]map[string]interface{}, 0)
+	for i := range reqs {
+		if validateErr := binding.Validator.ValidateStruct(&reqs[i]); validateErr != nil {
+			details := bindingErrorDetails(validateErr)
+			details["index"] = i
+			itemErrors = append(itemErrors, details)
+		}
+	}
+	if len(itemErrors) > 0 {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "One or more todos are invalid",
+			Details: map[string]interface{}{"errors": itemErrors},
+		})
+		return
+	}
+
+	for i := range reqs {
+		applyDefaultPriority(&reqs[i], defaultPriority)
+	}
+
+	todos, err := h.storage.BatchCreateTodos(userID, listID, reqs)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDueDateRequired {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "DUE_DATE_REQUIRED",
+				Message: "This list requires a due date on every todo",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to create todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, todos)
+}
+
+// BatchUpdateTodos handles PATCH /lists/:listId/todos/batch, applying either an
+// action (complete, uncomplete, delete) or field updates to several todos. IDs
+// not found in the list are reported in the response instead of failing it.
+// With verbose=true, a batch where only some IDs succeed responds 207
+// Multi-Status with a result for each ID.
+func (h *TodoHandler) BatchUpdateTodos(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	verbose, ok := parseBoolFlag(c, "verbose", "INVALID_VERBOSE")
+	if !ok {
+		return
+	}
+
+	var req models.BatchUpdateTodosRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+	if (req.Action == "") == (req.Updates == nil) {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Exactly one of action or updates is required",
+		})
+		return
+	}
+	if req.Updates != nil && req.Updates.Version != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "version is not supported in batch updates",
+		})
+		return
+	}
+
+	var result *models.BatchResult
+	switch req.Action {
+	case models.BatchActionDelete:
+		result, err = h.storage.BatchDeleteTodos(userID, listID, req.IDs)
+	case models.BatchActionComplete, models.BatchActionUncomplete:
+		completed := req.Action == models.BatchActionComplete
+		result, err = h.storage.BatchUpdateTodos(userID, listID, req.IDs, models.UpdateTodoRequest{Completed: &completed})
+	default:
+		result, err = h.storage.BatchUpdateTodos(userID, listID, req.IDs, *req.Updates)
+	}
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to update todos",
+		})
+		return
+	}
+
+	if verbose && len(result.NotFound) > 0 && len(result.NotFound) < len(req.IDs) {
+		itemStatus := http.StatusOK
+		if req.Action == models.BatchActionDelete {
+			itemStatus = http.StatusNoContent
+		}
+		respondJSON(c, http.StatusMultiStatus, batchMultiStatus(req.IDs, result.NotFound, itemStatus))
+		return
+	}
+
+	respondJSON(c, http.StatusOK, result)
 }
 
-// NewTodoHandler creates a new todo handler
-func NewTodoHandler(store storage.Store) *TodoHandler {
-	return &TodoHandler{storage: store}
+// batchMultiStatus builds the per-ID results of a batch operation in request
+// order: IDs in notFound get a 404 and every other ID gets itemStatus. Todos
+// in another list or owned by another user are indistinguishable from missing
+// ones.
+func batchMultiStatus(ids, notFound []uuid.UUID, itemStatus int) models.BatchMultiStatus {
+	missing := make(map[uuid.UUID]bool, len(notFound))
+	for _, id := range notFound {
+		missing[id] = true
+	}
+
+	results := make([]models.BatchItemResult, 0, len(ids))
+	for _, id := range ids {
+		if missing[id] {
+			results = append(results, models.BatchItemResult{ID: id, Status: http.StatusNotFound, Error: "TODO_NOT_FOUND"})
+			continue
+		}
+		results = append(results, models.BatchItemResult{ID: id, Status: itemStatus})
+	}
+	return models.BatchMultiStatus{Results: results}
 }
 
-// GetTodosByList handles GET /lists/:listId/todos
-func (h *TodoHandler) GetTodosByList(c *gin.Context) {
+// GetTodoByID handles GET /lists/:listId/todos/:todoId
+func (h *TodoHandler) GetTodoByID(c *gin.Context) {
 	// Get authenticated user ID
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
 		return
 	}
 
-	// Parse and validate query parameters
-	priority, ok := parsePriorityFilter(c)
-	if !ok {
+	todoID, err := uuid.Parse(c.Param("todoId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_TODO_ID",
+			Message: "Invalid todo ID format",
+		})
 		return
 	}
 
-	completed, ok := parseCompletedFilter(c)
+	todo, ok := h.loadTodo(c, userID, listID, todoID)
 	if !ok {
 		return
 	}
 
-	sortBy, sortOrder, ok := parseSortParams(c)
-	if !ok {
-		return
-	}
+	respondWithETag(c, todo.Version, todo)
+}
 
-	todos, err := h.storage.GetTodosByList(userID, listID, priority, completed, sortBy, sortOrder)
+// loadTodo fetches a todo, responding with the matching error if it cannot
+func (h *TodoHandler) loadTodo(c *gin.Context, userID, listID, todoID uuid.UUID) (*models.Todo, bool) {
+	todo, err := h.storage.GetTodoByID(userID, listID, todoID)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
-			return
+			return nil, false
+		}
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo was not found",
+			})
+			return nil, false
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
-			Message: "Failed to retrieve todos",
+			Message: "Failed to retrieve todo",
 		})
-		return
+		return nil, false
 	}
-
-	c.JSON(http.StatusOK, todos)
+	return todo, true
 }
 
-// CreateTodo handles POST /lists/:listId/todos
-func (h *TodoHandler) CreateTodo(c *gin.Context) {
+// UpdateTodo handles PUT and PATCH /lists/:listId/todos/:todoId. A PATCH
+// with an empty body changes nothing and returns the todo as it is.
+func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 	// Get authenticated user ID
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
 		return
 	}
 
-	var req models.CreateTodoRequest
+	todoID, err := uuid.Parse(c.Param("todoId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_TODO_ID",
+			Message: "Invalid todo ID format",
+		})
+		return
+	}
+
+	includeDiff, ok := parseBoolFlag(c, "includeDiff", "INVALID_INCLUDE_DIFF")
+	if !ok {
+		return
+	}
+
+	// Not a conditional GET, so no ETag check: a PATCH never returns 304
+	if isEmptyPatch(c) {
+		if todo, found := h.loadTodo(c, userID, listID, todoID); found {
+			respondJSON(c, http.StatusOK, todo)
+		}
+		return
+	}
+
+	var req models.UpdateTodoRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_INPUT",
 			Message: "Invalid request body",
-			Details: map[string]interface{}{"error": bindErr.Error()},
+			Details: bindingErrorDetails(bindErr),
 		})
 		return
 	}
 
-	todo, err := h.storage.CreateTodo(userID, listID, req)
+	todo, err := h.storage.UpdateTodo(userID, listID, todoID, req)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo was not found",
+			})
+			return
+		}
+		if err == storage.ErrVersionConflict {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "VERSION_CONFLICT",
+				Message: "The todo was modified by another request. Reload it and try again.",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
-			Message: "Failed to create todo",
+			Message: "Failed to update todo",
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, todo)
+	if !includeDiff {
+		todo.Changed = nil
+	}
+	respondJSON(c, http.StatusOK, todo)
 }
 
-// GetTodoByID handles GET /lists/:listId/todos/:todoId
-func (h *TodoHandler) GetTodoByID(c *gin.Context) {
+// DeleteTodo handles DELETE /lists/:listId/todos/:todoId
+func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 	// Get authenticated user ID
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
@@ -129,47 +804,62 @@ func (h *TodoHandler) GetTodoByID(c *gin.Context) {
 
 	todoID, err := uuid.Parse(c.Param("todoId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_TODO_ID",
 			Message: "Invalid todo ID format",
 		})
 		return
 	}
 
-	todo, err := h.storage.GetTodoByID(userID, listID, todoID)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.storage.DeleteTodo(userID, listID, todoID, version)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
 		if err == storage.ErrTodoNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "TODO_NOT_FOUND",
 				Message: "The requested todo was not found",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if err == storage.ErrVersionConflict {
+			respondJSON(c, http.StatusPreconditionFailed, models.ErrorResponse{
+				Code:    "PRECONDITION_FAILED",
+				Message: "The todo was modified since it was last read. Reload it and try again.",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
-			Message: "Failed to retrieve todo",
+			Message: "Failed to delete todo",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, todo)
+	c.Status(http.StatusNoContent)
 }
 
-// UpdateTodo handles PUT /lists/:listId/todos/:todoId
-func (h *TodoHandler) UpdateTodo(c *gin.Context) {
+// CloneTodo handles POST /lists/:listId/todos/:todoId/clone
+func (h *TodoHandler) CloneTodo(c *gin.Context) {
 	// Get authenticated user ID
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
@@ -178,57 +868,191 @@ func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 
 	todoID, err := uuid.Parse(c.Param("todoId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_TODO_ID",
 			Message: "Invalid todo ID format",
 		})
 		return
 	}
 
-	var req models.UpdateTodoRequest
+	// The body is optional; without one the todo is cloned into its own list
+	var req models.CloneTodoRequest
+	if c.Request.ContentLength != 0 {
+		if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+			respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: "Invalid request body",
+				Details: bindingErrorDetails(bindErr),
+			})
+			return
+		}
+	}
+
+	targetListID := uuid.Nil
+	if req.TargetListID != nil {
+		targetListID = *req.TargetListID
+	}
+
+	todo, err := h.storage.CloneTodo(userID, listID, todoID, targetListID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo was not found",
+			})
+			return
+		}
+		if err == storage.ErrTargetNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TARGET_LIST_NOT_FOUND",
+				Message: "The target todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to clone todo",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, todo)
+}
+
+// MoveTodo handles POST /lists/:listId/todos/:todoId/move, moving the todo to
+// the end of another of the user's lists
+func (h *TodoHandler) MoveTodo(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.MoveTodoRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_INPUT",
 			Message: "Invalid request body",
-			Details: map[string]interface{}{"error": bindErr.Error()},
+			Details: bindingErrorDetails(bindErr),
 		})
 		return
 	}
 
-	todo, err := h.storage.UpdateTodo(userID, listID, todoID, req)
+	todo, err := h.storage.MoveTodo(userID, listID, todoID, req.TargetListID)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
 		if err == storage.ErrTodoNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "TODO_NOT_FOUND",
 				Message: "The requested todo was not found",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
-			Message: "Failed to update todo",
+			Message: "Failed to move todo",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, todo)
+	respondJSON(c, http.StatusOK, todo)
 }
 
-// DeleteTodo handles DELETE /lists/:listId/todos/:todoId
-func (h *TodoHandler) DeleteTodo(c *gin.Context) {
+// PinTodo handles POST /lists/:listId/todos/:todoId/pin, keeping the todo
+// ahead of unpinned todos whatever the listing's sort
+func (h *TodoHandler) PinTodo(c *gin.Context) {
+	h.setTodoPinned(c, true, "Failed to pin todo")
+}
+
+// UnpinTodo handles POST /lists/:listId/todos/:todoId/unpin
+func (h *TodoHandler) UnpinTodo(c *gin.Context) {
+	h.setTodoPinned(c, false, "Failed to unpin todo")
+}
+
+// setTodoPinned sets the pinned flag of the todo in the path and writes the
+// resulting todo, falling back to a 500 with the given message
+func (h *TodoHandler) setTodoPinned(c *gin.Context, pinned bool, failure string) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+
+	todo, err := h.storage.SetTodoPinned(userID, listID, todoID, pinned)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo was not found",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: failure,
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, todo)
+}
+
+// ReorderTodo handles PUT /lists/:listId/todos/:todoId/position, moving the
+// todo to the given 0-based position and shifting the todos in between
+func (h *TodoHandler) ReorderTodo(c *gin.Context) {
 	// Get authenticated user ID
 	userID := middleware.GetUserIDOrDefault(c)
 
 	listID, err := uuid.Parse(c.Param("listId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_LIST_ID",
 			Message: "Invalid list ID format",
 		})
@@ -237,50 +1061,89 @@ func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 
 	todoID, err := uuid.Parse(c.Param("todoId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_TODO_ID",
 			Message: "Invalid todo ID format",
 		})
 		return
 	}
 
-	err = h.storage.DeleteTodo(userID, listID, todoID)
+	var req models.ReorderTodoRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "Invalid request body",
+			Details: bindingErrorDetails(bindErr),
+		})
+		return
+	}
+
+	todo, err := h.storage.ReorderTodo(userID, listID, todoID, *req.Position)
 	if err != nil {
 		if err == storage.ErrListNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "LIST_NOT_FOUND",
 				Message: "The requested todo list was not found",
 			})
 			return
 		}
 		if err == storage.ErrTodoNotFound {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
 				Code:    "TODO_NOT_FOUND",
 				Message: "The requested todo was not found",
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
-			Message: "Failed to delete todo",
+			Message: "Failed to reorder todo",
 		})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	respondJSON(c, http.StatusOK, todo)
 }
 
 // Helper functions for query parameter validation
 
+// parsePagination reads the page and limit query parameters, falling back to
+// page 1 and 20 items when they are missing or invalid. Limits above 100 are
+// clamped to 100.
+func parsePagination(c *gin.Context) (page, limit int) {
+	page, pageErr := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if pageErr != nil || page < 1 {
+		page = 1
+	}
+
+	limit, limitErr := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limitErr != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	return page, limit
+}
+
 func parsePriorityFilter(c *gin.Context) (*models.Priority, bool) {
-	priorityStr := c.Query("priority")
+	return parsePriorityQuery(c, "priority")
+}
+
+// parsePriorityQuery parses an optional priority query parameter, responding
+// 400 INVALID_PRIORITY if it is not low, medium or high
+func parsePriorityQuery(c *gin.Context, name string) (*models.Priority, bool) {
+	priorityStr := c.Query(name)
 	if priorityStr == "" {
 		return nil, true
 	}
 
 	p := models.Priority(priorityStr)
 	if p != models.PriorityLow && p != models.PriorityMedium && p != models.PriorityHigh {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_PRIORITY",
 			Message: "Priority must be one of: low, medium, high",
 		})
@@ -289,8 +1152,23 @@ func parsePriorityFilter(c *gin.Context) (*models.Priority, bool) {
 	return &p, true
 }
 
-func parseCompletedFilter(c *gin.Context) (*bool, bool) {
-	completedStr := c.Query("completed")
+// applyDefaultPriority gives req the default priority when it has none, leaving
+// the storage default to apply if there is no default either
+func applyDefaultPriority(req *models.CreateTodoRequest, defaultPriority *models.Priority) {
+	if req.Priority == "" && defaultPriority != nil {
+		req.Priority = *defaultPriority
+	}
+}
+
+// parseCompletedFilter parses the completed query parameter. When the parameter is
+// absent and hideCompleted is set, only incomplete todos are returned; an explicitly
+// empty value (?completed=) always means "no filter".
+func parseCompletedFilter(c *gin.Context, hideCompleted bool) (*bool, bool) {
+	completedStr, present := c.GetQuery("completed")
+	if !present && hideCompleted {
+		f := false
+		return &f, true
+	}
 	if completedStr == "" {
 		return nil, true
 	}
@@ -303,7 +1181,7 @@ func parseCompletedFilter(c *gin.Context) (*bool, bool) {
 		f := false
 		return &f, true
 	default:
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_COMPLETED",
 			Message: "Completed must be true or false",
 		})
@@ -311,20 +1189,113 @@ func parseCompletedFilter(c *gin.Context) (*bool, bool) {
 	}
 }
 
-func parseSortParams(c *gin.Context) (sortBy, sortOrder string, ok bool) {
-	sortBy = c.DefaultQuery("sortBy", "createdAt")
-	sortOrder = c.DefaultQuery("sortOrder", "asc")
+// parseBoolFlag parses an optional true/false query parameter that defaults to false
+func parseBoolFlag(c *gin.Context, name, code string) (bool, bool) {
+	switch c.Query(name) {
+	case "", "false":
+		return false, true
+	case "true":
+		return true, true
+	default:
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    code,
+			Message: name + " must be true or false",
+		})
+		return false, false
+	}
+}
+
+// isEmptyPatch reports whether the request is a PATCH with an empty or
+// whitespace-only body. Any body read is put back for binding.
+func isEmptyPatch(c *gin.Context) bool {
+	if c.Request.Method != http.MethodPatch {
+		return false
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return true
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && len(bytes.TrimSpace(body)) == 0
+}
+
+// parseDueRange parses the dueAfter and dueBefore query parameters, reporting
+// a malformed timestamp with afterCode or beforeCode
+func parseDueRange(c *gin.Context, afterCode, beforeCode string) (dueAfter, dueBefore *time.Time, ok bool) {
+	if dueAfter, ok = parseTimeQuery(c, "dueAfter", afterCode); !ok {
+		return nil, nil, false
+	}
+	if dueBefore, ok = parseTimeQuery(c, "dueBefore", beforeCode); !ok {
+		return nil, nil, false
+	}
+
+	if dueAfter != nil && dueBefore != nil && dueAfter.After(*dueBefore) {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_DUE_RANGE",
+			Message: "dueAfter must not be later than dueBefore",
+		})
+		return nil, nil, false
+	}
+
+	return dueAfter, dueBefore, true
+}
+
+// parseCreatedRange parses the createdAfter/createdBefore query parameters
+// shared by the list and todo listings
+func parseCreatedRange(c *gin.Context) (createdAfter, createdBefore *time.Time, ok bool) {
+	if createdAfter, ok = parseTimeQuery(c, "createdAfter", "INVALID_CREATED_DATE"); !ok {
+		return nil, nil, false
+	}
+	if createdBefore, ok = parseTimeQuery(c, "createdBefore", "INVALID_CREATED_DATE"); !ok {
+		return nil, nil, false
+	}
+
+	if createdAfter != nil && createdBefore != nil && createdAfter.After(*createdBefore) {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_CREATED_DATE",
+			Message: "createdAfter must not be later than createdBefore",
+		})
+		return nil, nil, false
+	}
+
+	return createdAfter, createdBefore, true
+}
+
+// parseTimeQuery parses an optional RFC3339 timestamp query parameter
+func parseTimeQuery(c *gin.Context, name, code string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    code,
+			Message: name + " must be an RFC3339 timestamp",
+		})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// parseSortParams parses the sortBy and sortOrder query parameters, using the
+// given defaults when they are omitted
+func parseSortParams(c *gin.Context, defaultSortBy, defaultSortOrder string) (sortBy, sortOrder string, ok bool) {
+	sortBy = c.DefaultQuery("sortBy", defaultSortBy)
+	sortOrder = c.DefaultQuery("sortOrder", defaultSortOrder)
 
-	if sortBy != "dueDate" && sortBy != "priority" && sortBy != "createdAt" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+	if sortBy != "dueDate" && sortBy != "priority" && sortBy != "createdAt" && sortBy != "position" {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_SORT_BY",
-			Message: "sortBy must be one of: dueDate, priority, createdAt",
+			Message: "sortBy must be one of: dueDate, priority, createdAt, position",
 		})
 		return "", "", false
 	}
 
 	if sortOrder != "asc" && sortOrder != "desc" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
 			Code:    "INVALID_SORT_ORDER",
 			Message: "sortOrder must be asc or desc",
 		})
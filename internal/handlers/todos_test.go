@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,8 +63,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		assert.Len(t, todos, 2)
 	})
@@ -102,8 +104,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		assert.Len(t, todos, 1)
 		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
@@ -145,8 +148,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		assert.Len(t, todos, 1)
 		assert.True(t, todos[0].Completed)
@@ -188,8 +192,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		assert.Len(t, todos, 1)
 		assert.False(t, todos[0].Completed)
@@ -225,8 +230,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		require.Len(t, todos, 2)
 		assert.Equal(t, todo1.ID, todos[0].ID)
@@ -262,8 +268,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		require.Len(t, todos, 2)
 		assert.Equal(t, todo2.ID, todos[0].ID)
@@ -304,8 +311,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		require.Len(t, todos, 3)
 		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
@@ -327,8 +335,9 @@ func TestGetTodosByList(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var todos []models.Todo
-		testutil.ParseJSONResponse(t, w, &todos)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
 
 		assert.Len(t, todos, 0)
 	})
@@ -455,239 +464,1454 @@ func TestGetTodosByList(t *testing.T) {
 	})
 }
 
-func TestCreateTodo(t *testing.T) {
+func TestGetTodosByListDefaultHideCompleted(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	t.Run("successfully creates todo with all fields", func(t *testing.T) {
-		handler, _, listID := setupTodoHandler()
+	setup := func(t *testing.T) (storage.Store, uuid.UUID) {
+		_, store, listID := setupTodoHandler()
 
-		dueDate := time.Now().Add(24 * time.Hour)
-		reqBody := models.CreateTodoRequest{
-			Description: "New Todo",
-			Priority:    models.PriorityHigh,
-			DueDate:     &dueDate,
-		}
+		done, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Done",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+		_, err = store.UpdateTodo(testUserID, listID, done.ID, models.UpdateTodoRequest{Completed: boolPtr(true)})
+		require.NoError(t, err)
 
-		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Open",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		return store, listID
+	}
+	hiding := func(store storage.Store) *TodoHandler {
+		return NewTodoHandlerWithConfig(store, &TodoConfig{DefaultHideCompleted: true})
+	}
+
+	get := func(handler *TodoHandler, listID uuid.UUID, query string) []models.Todo {
+		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos"+query, http.NoBody)
 		w := httptest.NewRecorder()
 
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
 
-		handler.CreateTodo(c)
+		handler.GetTodosByList(c)
 
-		assert.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, http.StatusOK, w.Code)
 
-		var todo models.Todo
-		testutil.ParseJSONResponse(t, w, &todo)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return resp.Data
+	}
 
-		assert.NotEqual(t, uuid.Nil, todo.ID)
-		assert.Equal(t, "New Todo", todo.Description)
-		assert.Equal(t, models.PriorityHigh, todo.Priority)
-		assert.False(t, todo.Completed)
-		assert.Nil(t, todo.CompletedAt)
-		assert.NotNil(t, todo.DueDate)
+	t.Run("hides completed todos when completed is omitted", func(t *testing.T) {
+		store, listID := setup(t)
+
+		todos := get(hiding(store), listID, "")
+		require.Len(t, todos, 1)
+		assert.Equal(t, "Open", todos[0].Description)
 	})
 
-	t.Run("successfully creates todo with minimal fields", func(t *testing.T) {
-		handler, _, listID := setupTodoHandler()
+	t.Run("explicit empty completed shows all todos", func(t *testing.T) {
+		store, listID := setup(t)
 
-		reqBody := models.CreateTodoRequest{
-			Description: "Minimal Todo",
-			Priority:    models.PriorityMedium,
-		}
+		todos := get(hiding(store), listID, "?completed=")
+		assert.Len(t, todos, 2)
+	})
 
-		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
-		w := httptest.NewRecorder()
+	t.Run("explicit completed=true still filters", func(t *testing.T) {
+		store, listID := setup(t)
 
-		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		todos := get(hiding(store), listID, "?completed=true")
+		require.Len(t, todos, 1)
+		assert.Equal(t, "Done", todos[0].Description)
+	})
 
-		handler.CreateTodo(c)
+	t.Run("default config shows all todos", func(t *testing.T) {
+		store, listID := setup(t)
 
-		assert.Equal(t, http.StatusCreated, w.Code)
+		todos := get(NewTodoHandler(store), listID, "")
+		assert.Len(t, todos, 2)
+	})
+}
 
-		var todo models.Todo
-		testutil.ParseJSONResponse(t, w, &todo)
+func TestGetTodosByListArchived(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-		assert.Equal(t, "Minimal Todo", todo.Description)
-		assert.Equal(t, models.PriorityMedium, todo.Priority)
-		assert.Nil(t, todo.DueDate)
-	})
+	store := storage.NewStorage()
+	handler := NewTodoHandler(store)
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Chores", AutoArchiveCompleted: true})
+	require.NoError(t, err)
 
-	t.Run("returns error for invalid JSON", func(t *testing.T) {
-		handler, _, listID := setupTodoHandler()
+	todo, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Dishes", Priority: models.PriorityLow})
+	require.NoError(t, err)
+	_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Laundry", Priority: models.PriorityLow})
+	require.NoError(t, err)
 
-		req := httptest.NewRequest("POST", "/lists/"+listID.String()+"/todos", http.NoBody)
-		req.Header.Set("Content-Type", "application/json")
-		w := httptest.NewRecorder()
+	done := true
+	_, err = store.UpdateTodo(testUserID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+	require.NoError(t, err)
 
+	getTodos := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/todos"+query, http.NoBody)
+		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
-		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		handler.GetTodosByList(c)
+		return w
+	}
 
-		handler.CreateTodo(c)
+	t.Run("hides archived todos by default", func(t *testing.T) {
+		w := getTodos("")
+		assert.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
+		require.Len(t, todos, 1)
+		assert.Equal(t, "Laundry", todos[0].Description)
+	})
 
-		var errResp models.ErrorResponse
-		testutil.ParseJSONResponse(t, w, &errResp)
+	t.Run("archived=true reveals archived todos", func(t *testing.T) {
+		w := getTodos("?archived=true")
+		assert.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
+		require.Len(t, todos, 1)
+		assert.Equal(t, "Dishes", todos[0].Description)
+		assert.NotNil(t, todos[0].ArchivedAt)
 	})
 
-	t.Run("returns error for missing title", func(t *testing.T) {
-		handler, _, listID := setupTodoHandler()
+	t.Run("rejects invalid archived value", func(t *testing.T) {
+		w := getTodos("?archived=yes")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		reqBody := models.CreateTodoRequest{
-			Priority: models.PriorityMedium,
-		}
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "INVALID_ARCHIVED", response.Code)
+	})
+}
 
-		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
-		w := httptest.NewRecorder()
+// staticSettings is a SettingsProvider returning the same settings for every user
+type staticSettings struct {
+	settings *models.UserSettings
+}
+
+func (s staticSettings) GetSettings(_ uuid.UUID) (*models.UserSettings, error) {
+	return s.settings, nil
+}
+
+func TestGetTodosByListUserSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := storage.NewStorage()
+	settings := models.DefaultUserSettings(testUserID)
+	settings.DefaultSortBy = "priority"
+	settings.DefaultSortOrder = "asc"
+	handler := NewTodoHandlerWithSettings(store, &TodoConfig{}, staticSettings{settings: settings})
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Settings"})
+	require.NoError(t, err)
+	for _, p := range []models.Priority{models.PriorityLow, models.PriorityHigh, models.PriorityMedium} {
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: string(p), Priority: p})
+		require.NoError(t, err)
+	}
 
+	getTodos := func(query string) []models.Todo {
+		req := httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/todos"+query, http.NoBody)
+		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
-		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
-
-		handler.CreateTodo(c)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		handler.GetTodosByList(c)
+		require.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return resp.Data
+	}
 
-		var errResp models.ErrorResponse
-		testutil.ParseJSONResponse(t, w, &errResp)
+	t.Run("default sort follows user settings", func(t *testing.T) {
+		todos := getTodos("")
+		require.Len(t, todos, 3)
+		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
+		assert.Equal(t, models.PriorityMedium, todos[1].Priority)
+		assert.Equal(t, models.PriorityLow, todos[2].Priority)
+	})
 
-		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	t.Run("explicit sort overrides user settings", func(t *testing.T) {
+		todos := getTodos("?sortBy=createdAt")
+		require.Len(t, todos, 3)
+		assert.Equal(t, models.PriorityLow, todos[0].Priority)
 	})
+}
 
-	t.Run("returns error for non-existent list", func(t *testing.T) {
-		handler, _, _ := setupTodoHandler()
+func TestGetTodosByListHideCompletedSetting(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-		nonExistentID := uuid.New()
-		reqBody := models.CreateTodoRequest{
-			Description: "Todo",
-			Priority:    models.PriorityMedium,
-		}
+	// completedShown reports whether a user with settings sees a completed
+	// todo while the server hides completed todos by default
+	completedShown := func(t *testing.T, settings *models.UserSettings) bool {
+		store := storage.NewStorage()
+		handler := NewTodoHandlerWithSettings(store, &TodoConfig{DefaultHideCompleted: true}, staticSettings{settings: settings})
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Settings"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+			Description: "Done", Priority: models.PriorityLow, Completed: true,
+		})
+		require.NoError(t, err)
 
-		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+nonExistentID.String()+"/todos", reqBody)
 		w := httptest.NewRecorder()
-
 		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{{Key: "listId", Value: nonExistentID.String()}}
-
-		handler.CreateTodo(c)
+		c.Request = httptest.NewRequest("GET", "/lists/"+list.ID.String()+"/todos", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+		handler.GetTodosByList(c)
+		require.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return len(resp.Data) == 1
+	}
 
-		var errResp models.ErrorResponse
-		testutil.ParseJSONResponse(t, w, &errResp)
+	t.Run("a saved false setting overrides the server default", func(t *testing.T) {
+		settings := models.DefaultUserSettings(testUserID)
+		settings.CreatedAt = time.Now()
+		settings.HideCompleted = false
+		assert.True(t, completedShown(t, settings))
+	})
 
-		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+	t.Run("users without saved settings get the server default", func(t *testing.T) {
+		assert.False(t, completedShown(t, models.DefaultUserSettings(testUserID)))
 	})
+}
 
-	t.Run("returns error for invalid list ID format", func(t *testing.T) {
-		handler, _, _ := setupTodoHandler()
+func TestGetTodosByListPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-		reqBody := models.CreateTodoRequest{
+	handler, store, listID := setupTodoHandler()
+	for i := 0; i < 25; i++ {
+		_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
 			Description: "Todo",
-			Priority:    models.PriorityMedium,
-		}
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+	}
 
-		req := testutil.MakeJSONRequest(t, "POST", "/lists/invalid-uuid/todos", reqBody)
+	get := func(query string) (*httptest.ResponseRecorder, models.PaginatedListTodosResponse) {
 		w := httptest.NewRecorder()
-
 		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{{Key: "listId", Value: "invalid-uuid"}}
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos"+query, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		require.Equal(t, http.StatusOK, w.Code)
 
-		handler.CreateTodo(c)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		return w, resp
+	}
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+	t.Run("defaults to the first 20 todos", func(t *testing.T) {
+		_, resp := get("")
+		assert.Len(t, resp.Data, 20)
+		require.NotNil(t, resp.Pagination)
+		assert.Equal(t, 1, resp.Pagination.Page)
+		assert.Equal(t, 20, resp.Pagination.Limit)
+		assert.Equal(t, 25, resp.Pagination.TotalItems)
+		assert.Equal(t, 2, resp.Pagination.TotalPages)
+	})
 
-		var errResp models.ErrorResponse
-		testutil.ParseJSONResponse(t, w, &errResp)
+	t.Run("returns the requested page", func(t *testing.T) {
+		_, resp := get("?page=3&limit=10")
+		assert.Len(t, resp.Data, 5)
+		assert.Equal(t, 3, resp.Pagination.Page)
+		assert.Equal(t, 3, resp.Pagination.TotalPages)
+	})
 
-		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
+	t.Run("clamps limits above 100 to 100", func(t *testing.T) {
+		_, resp := get("?limit=500")
+		assert.Len(t, resp.Data, 25)
+		assert.Equal(t, 100, resp.Pagination.Limit)
+		assert.Equal(t, 1, resp.Pagination.TotalPages)
+	})
+
+	t.Run("page past the end returns an empty data array", func(t *testing.T) {
+		w, resp := get("?page=10&limit=10")
+		assert.Contains(t, w.Body.String(), `"data":[]`)
+		assert.Empty(t, resp.Data)
+		assert.Equal(t, 3, resp.Pagination.TotalPages)
+		assert.Equal(t, 25, resp.Pagination.TotalItems)
 	})
 }
 
-func TestGetTodoByID(t *testing.T) {
+func TestGetTodosByListTags(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	t.Run("successfully retrieves todo by ID", func(t *testing.T) {
-		handler, store, listID := setupTodoHandler()
-
-		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
-			Description: "Test Todo",
-			Priority:    models.PriorityMedium,
-		})
+	handler, store, listID := setupTodoHandler()
+	for _, req := range []models.CreateTodoRequest{
+		{Description: "Both", Priority: models.PriorityHigh, Tags: []string{"urgent", "home"}},
+		{Description: "Urgent", Priority: models.PriorityHigh, Tags: []string{"urgent"}},
+		{Description: "Untagged", Priority: models.PriorityLow},
+	} {
+		_, err := store.CreateTodo(testUserID, listID, req)
 		require.NoError(t, err)
+	}
 
-		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos/"+created.ID.String(), http.NoBody)
+	getTodos := func(query string) []string {
 		w := httptest.NewRecorder()
-
 		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{
-			{Key: "listId", Value: listID.String()},
-			{Key: "todoId", Value: created.ID.String()},
-		}
-
-		handler.GetTodoByID(c)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos"+query, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		require.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		names := make([]string, 0, len(response.Data))
+		for _, todo := range response.Data {
+			names = append(names, todo.Description)
+		}
+		return names
+	}
 
-		var todo models.Todo
-		testutil.ParseJSONResponse(t, w, &todo)
+	assert.ElementsMatch(t, []string{"Both", "Urgent"}, getTodos("?tag=Urgent"))
+	assert.Equal(t, []string{"Both"}, getTodos("?tag=urgent&tag=home"))
+	assert.Empty(t, getTodos("?tag=work"))
+	assert.Len(t, getTodos(""), 3)
+}
 
-		assert.Equal(t, created.ID, todo.ID)
-		assert.Equal(t, "Test Todo", todo.Description)
-	})
+func TestGetTodosByListCreatedRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-	t.Run("returns error for non-existent todo", func(t *testing.T) {
-		handler, _, listID := setupTodoHandler()
+	handler, store, listID := setupTodoHandler()
+	var created []*models.Todo
+	for _, req := range []models.CreateTodoRequest{
+		{Description: "Old", Priority: models.PriorityHigh},
+		{Description: "Boundary high", Priority: models.PriorityHigh},
+		{Description: "Boundary low", Priority: models.PriorityLow},
+		{Description: "New", Priority: models.PriorityHigh},
+	} {
+		todo, err := store.CreateTodo(testUserID, listID, req)
+		require.NoError(t, err)
+		created = append(created, todo)
+		time.Sleep(2 * time.Millisecond)
+	}
 
-		nonExistentID := uuid.New()
-		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos/"+nonExistentID.String(), http.NoBody)
+	getTodos := func(query string) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
-
 		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{
-			{Key: "listId", Value: listID.String()},
-			{Key: "todoId", Value: nonExistentID.String()},
-		}
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos"+query, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		return w
+	}
 
-		handler.GetTodoByID(c)
+	t.Run("includes todos created exactly at the bounds", func(t *testing.T) {
+		after := created[1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		before := created[2].CreatedAt.UTC().Format(time.RFC3339Nano)
+		w := getTodos("?createdAfter=" + after + "&createdBefore=" + before + "&sortBy=createdAt")
+		assert.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
+		require.Len(t, todos, 2)
+		assert.Equal(t, "Boundary high", todos[0].Description)
+		assert.Equal(t, "Boundary low", todos[1].Description)
+	})
 
-		var errResp models.ErrorResponse
-		testutil.ParseJSONResponse(t, w, &errResp)
+	t.Run("composes with the priority filter", func(t *testing.T) {
+		after := created[1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		w := getTodos("?createdAfter=" + after + "&priority=high&sortBy=createdAt")
+		assert.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, "TODO_NOT_FOUND", errResp.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		todos := resp.Data
+		require.Len(t, todos, 2)
+		assert.Equal(t, "Boundary high", todos[0].Description)
+		assert.Equal(t, "New", todos[1].Description)
 	})
 
-	t.Run("returns error for non-existent list", func(t *testing.T) {
-		handler, _, _ := setupTodoHandler()
+	t.Run("rejects a malformed timestamp", func(t *testing.T) {
+		w := getTodos("?createdBefore=2025-13-01")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-		nonExistentListID := uuid.New()
-		todoID := uuid.New()
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "INVALID_CREATED_DATE", response.Code)
+	})
+}
 
-		req := httptest.NewRequest("GET", "/lists/"+nonExistentListID.String()+"/todos/"+todoID.String(), http.NoBody)
-		w := httptest.NewRecorder()
+func TestGetTodosByListDueRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		c.Params = gin.Params{
-			{Key: "listId", Value: nonExistentListID.String()},
-			{Key: "todoId", Value: todoID.String()},
-		}
+	handler, store, listID := setupTodoHandler()
+	due := time.Date(2030, 6, 3, 9, 0, 0, 0, time.UTC)
+	_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Dated", Priority: models.PriorityLow, DueDate: &due})
+	require.NoError(t, err)
+	_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow})
+	require.NoError(t, err)
 
-		handler.GetTodoByID(c)
+	getTodos := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos"+query, http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		return w
+	}
+
+	t.Run("returns only dated todos inside the window", func(t *testing.T) {
+		w := getTodos("?dueAfter=2030-06-01T00:00:00Z&dueBefore=2030-06-03T09:00:00Z")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "Dated", resp.Data[0].Description)
+	})
+
+	t.Run("rejects malformed bounds and an inverted range", func(t *testing.T) {
+		for query, code := range map[string]string{
+			"?dueAfter=tomorrow":  "INVALID_DUE_AFTER",
+			"?dueBefore=2030-6-3": "INVALID_DUE_BEFORE",
+			"?dueAfter=2030-06-04T00:00:00Z&dueBefore=2030-06-01T00:00:00Z": "INVALID_DUE_RANGE",
+		} {
+			w := getTodos(query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+
+			var response models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &response)
+			assert.Equal(t, code, response.Code, query)
+		}
+	})
+}
+
+func TestGetAllTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	getAll := func(handler *TodoHandler, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/todos"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		handler.GetAllTodos(c)
+		return w
+	}
+
+	t.Run("returns todos from every list annotated with list name", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		other, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Other List"})
+		require.NoError(t, err)
+
+		past := time.Now().Add(-24 * time.Hour)
+		_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Overdue report", Priority: models.PriorityHigh, DueDate: &past,
+		})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, other.ID, models.CreateTodoRequest{
+			Description: "Groceries", Priority: models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w := getAll(handler, "?sortBy=priority&sortOrder=asc")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, 2, response.Pagination.TotalItems)
+		assert.Equal(t, "Overdue report", response.Data[0].Description)
+		assert.Equal(t, listID, response.Data[0].ListID)
+		assert.Equal(t, "Test List", response.Data[0].ListName)
+		assert.Equal(t, "Other List", response.Data[1].ListName)
+	})
+
+	t.Run("composes overdue and search filters", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		past := time.Now().Add(-24 * time.Hour)
+		_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Overdue report", Priority: models.PriorityHigh, DueDate: &past,
+		})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Overdue dishes", Priority: models.PriorityLow, DueDate: &past,
+		})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Future report", Priority: models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w := getAll(handler, "?overdue=true&search=report")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Overdue report", response.Data[0].Description)
+	})
+
+	t.Run("includes created range boundaries and composes with search", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		var created []*models.Todo
+		for _, description := range []string{"Old report", "Middle report", "Middle dishes", "New report"} {
+			todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+				Description: description, Priority: models.PriorityLow,
+			})
+			require.NoError(t, err)
+			created = append(created, todo)
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		after := created[1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		before := created[2].CreatedAt.UTC().Format(time.RFC3339Nano)
+		w := getAll(handler, "?createdAfter="+after+"&createdBefore="+before+"&sortBy=createdAt")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 2, "both boundaries are inclusive")
+		assert.Equal(t, "Middle report", response.Data[0].Description)
+		assert.Equal(t, "Middle dishes", response.Data[1].Description)
+
+		w = getAll(handler, "?createdAfter="+after+"&search=report&sortBy=createdAt")
+		assert.Equal(t, http.StatusOK, w.Code)
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, "Middle report", response.Data[0].Description)
+		assert.Equal(t, "New report", response.Data[1].Description)
+		assert.Equal(t, 2, response.Pagination.TotalItems)
+	})
+
+	t.Run("paginates results", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		for i := 0; i < 3; i++ {
+			_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+				Description: "Todo", Priority: models.PriorityLow,
+			})
+			require.NoError(t, err)
+		}
+
+		w := getAll(handler, "?page=2&limit=2")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, 2, response.Pagination.Page)
+		assert.Equal(t, 2, response.Pagination.TotalPages)
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		tests := []struct {
+			query string
+			code  string
+		}{
+			{"?overdue=maybe", "INVALID_OVERDUE"},
+			{"?dueBefore=tomorrow", "INVALID_DUE_DATE"},
+			{"?dueAfter=2025-02-01T00:00:00Z&dueBefore=2025-01-01T00:00:00Z", "INVALID_DUE_RANGE"},
+			{"?sortBy=description", "INVALID_SORT_BY"},
+			{"?priority=urgent", "INVALID_PRIORITY"},
+			{"?createdAfter=yesterday", "INVALID_CREATED_DATE"},
+			{"?createdAfter=2025-02-01T00:00:00Z&createdBefore=2025-01-01T00:00:00Z", "INVALID_CREATED_DATE"},
+		}
+
+		for _, tt := range tests {
+			w := getAll(handler, tt.query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, tt.query)
+
+			var response models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &response)
+			assert.Equal(t, tt.code, response.Code, tt.query)
+		}
+	})
+}
+
+func TestGetOverdueTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store, listID := setupTodoHandler()
+	other, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Other List"})
+	require.NoError(t, err)
+
+	older := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	for _, create := range []struct {
+		listID uuid.UUID
+		req    models.CreateTodoRequest
+	}{
+		{listID, models.CreateTodoRequest{Description: "Recently late", Priority: models.PriorityHigh, DueDate: &recent}},
+		{other.ID, models.CreateTodoRequest{Description: "Long late", Priority: models.PriorityLow, DueDate: &older}},
+		{listID, models.CreateTodoRequest{Description: "Done late", Priority: models.PriorityLow, DueDate: &older, Completed: true}},
+		{listID, models.CreateTodoRequest{Description: "Upcoming", Priority: models.PriorityLow, DueDate: &future}},
+		{listID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow}},
+	} {
+		_, err = store.CreateTodo(testUserID, create.listID, create.req)
+		require.NoError(t, err)
+	}
+
+	t.Run("aggregates overdue todos across lists, soonest due first", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/todos/overdue", http.NoBody)
+		handler.GetOverdueTodos(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, "Long late", response.Data[0].Description)
+		assert.Equal(t, "Other List", response.Data[0].ListName)
+		assert.Equal(t, "Recently late", response.Data[1].Description)
+	})
+
+	t.Run("filters a single list with overdue=true", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos?overdue=true", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Recently late", response.Data[0].Description)
+	})
+
+	t.Run("rejects an invalid overdue value", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos?overdue=yes", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTriageOverdueTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// setup creates overdue, completed, upcoming and undated todos in two
+	// lists and returns them by description
+	setup := func(t *testing.T) (*TodoHandler, storage.Store, map[string]*models.Todo) {
+		handler, store, listID := setupTodoHandler()
+		other, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Other List"})
+		require.NoError(t, err)
+
+		past := time.Now().Add(-48 * time.Hour)
+		future := time.Now().Add(time.Hour)
+		todos := make(map[string]*models.Todo)
+		for _, create := range []struct {
+			listID uuid.UUID
+			req    models.CreateTodoRequest
+		}{
+			{listID, models.CreateTodoRequest{Description: "Late", Priority: models.PriorityLow, DueDate: &past}},
+			{other.ID, models.CreateTodoRequest{Description: "Late elsewhere", Priority: models.PriorityMedium, DueDate: &past}},
+			{listID, models.CreateTodoRequest{Description: "Done late", Priority: models.PriorityLow, DueDate: &past, Completed: true}},
+			{listID, models.CreateTodoRequest{Description: "Upcoming", Priority: models.PriorityLow, DueDate: &future}},
+			{listID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow}},
+		} {
+			todo, err := store.CreateTodo(testUserID, create.listID, create.req)
+			require.NoError(t, err)
+			todos[todo.Description] = todo
+		}
+		return handler, store, todos
+	}
+	triage := func(handler *TodoHandler, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/todos/triage", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.TriageOverdueTodos(c)
+		return w
+	}
+	priorities := func(t *testing.T, store storage.Store, todos map[string]*models.Todo) map[string]models.Priority {
+		result := make(map[string]models.Priority)
+		for description, todo := range todos {
+			got, err := store.GetTodoByID(testUserID, todo.ListID, todo.ID)
+			require.NoError(t, err)
+			result[description] = got.Priority
+		}
+		return result
+	}
+
+	t.Run("raises only overdue incomplete todos to high priority", func(t *testing.T) {
+		handler, store, todos := setup(t)
+
+		w := triage(handler, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var result models.TriageResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, models.TriageResult{Affected: 2, Priority: models.PriorityHigh}, result)
+		assert.Equal(t, map[string]models.Priority{
+			"Late":           models.PriorityHigh,
+			"Late elsewhere": models.PriorityHigh,
+			"Done late":      models.PriorityLow,
+			"Upcoming":       models.PriorityLow,
+			"Undated":        models.PriorityLow,
+		}, priorities(t, store, todos))
+	})
+
+	t.Run("uses a supplied priority and counts only changed todos", func(t *testing.T) {
+		handler, store, todos := setup(t)
+
+		w := triage(handler, `{"priority": "medium"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var result models.TriageResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, models.TriageResult{Affected: 1, Priority: models.PriorityMedium}, result)
+		assert.Equal(t, models.PriorityMedium, priorities(t, store, todos)["Late"])
+	})
+
+	t.Run("rejects an unknown priority", func(t *testing.T) {
+		handler, store, todos := setup(t)
+
+		w := triage(handler, `{"priority": "urgent"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+		assert.Equal(t, models.PriorityLow, priorities(t, store, todos)["Late"])
+	})
+}
+
+func TestCreateTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("successfully creates todo with all fields", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		dueDate := time.Now().Add(24 * time.Hour)
+		reqBody := models.CreateTodoRequest{
+			Description: "New Todo",
+			Priority:    models.PriorityHigh,
+			DueDate:     &dueDate,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.NotEqual(t, uuid.Nil, todo.ID)
+		assert.Equal(t, "New Todo", todo.Description)
+		assert.Equal(t, models.PriorityHigh, todo.Priority)
+		assert.False(t, todo.Completed)
+		assert.Nil(t, todo.CompletedAt)
+		assert.NotNil(t, todo.DueDate)
+	})
+
+	t.Run("defaults the priority of a description-only todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		create := func(query string, body interface{}) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos"+query, body)
+			c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+			handler.CreateTodo(c)
+			return w
+		}
+
+		w := create("", map[string]string{"description": "Quick add"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, "Quick add", todo.Description)
+		assert.Equal(t, models.PriorityMedium, todo.Priority)
+
+		w = create("?defaultPriority=high", map[string]string{"description": "Urgent quick add"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, models.PriorityHigh, todo.Priority)
+
+		// An explicit priority wins over the default
+		w = create("?defaultPriority=high", map[string]string{"description": "Later", "priority": "low"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, models.PriorityLow, todo.Priority)
+
+		w = create("?defaultPriority=urgent", map[string]string{"description": "Bad default"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_PRIORITY", errResp.Code)
+
+		w = create("", map[string]string{"description": "Bad priority", "priority": "urgent"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	})
+
+	t.Run("normalizes tags and rejects too many", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		create := func(reqBody models.CreateTodoRequest) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+			c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+			handler.CreateTodo(c)
+			return w
+		}
+
+		w := create(models.CreateTodoRequest{Description: "Tagged", Priority: models.PriorityLow, Tags: []string{"Home", "URGENT"}})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, []string{"home", "urgent"}, todo.Tags)
+
+		tooMany := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+		w = create(models.CreateTodoRequest{Description: "Tagged", Priority: models.PriorityLow, Tags: tooMany})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = create(models.CreateTodoRequest{Description: "Tagged", Priority: models.PriorityLow, Tags: []string{strings.Repeat("t", 51)}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("enforces the list due date requirement", func(t *testing.T) {
+		handler, store, _ := setupTodoHandler()
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Deadlines", RequireDueDate: true})
+		require.NoError(t, err)
+
+		create := func(reqBody models.CreateTodoRequest) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+list.ID.String()+"/todos", reqBody)
+			c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+			handler.CreateTodo(c)
+			return w
+		}
+
+		w := create(models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "DUE_DATE_REQUIRED", resp.Code)
+
+		due := time.Now().Add(24 * time.Hour)
+		w = create(models.CreateTodoRequest{Description: "Dated", Priority: models.PriorityLow, DueDate: &due})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("successfully creates todo with minimal fields", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		reqBody := models.CreateTodoRequest{
+			Description: "Minimal Todo",
+			Priority:    models.PriorityMedium,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.Equal(t, "Minimal Todo", todo.Description)
+		assert.Equal(t, models.PriorityMedium, todo.Priority)
+		assert.Nil(t, todo.DueDate)
+	})
+
+	t.Run("returns error for invalid JSON", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		req := httptest.NewRequest("POST", "/lists/"+listID.String()+"/todos", http.NoBody)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	})
+
+	t.Run("returns error for missing title", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		reqBody := models.CreateTodoRequest{
+			Priority: models.PriorityMedium,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	})
+
+	t.Run("returns error for non-existent list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		nonExistentID := uuid.New()
+		reqBody := models.CreateTodoRequest{
+			Description: "Todo",
+			Priority:    models.PriorityMedium,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+nonExistentID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: nonExistentID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+	})
+
+	t.Run("returns error for invalid list ID format", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		reqBody := models.CreateTodoRequest{
+			Description: "Todo",
+			Priority:    models.PriorityMedium,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/invalid-uuid/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: "invalid-uuid"}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
+	})
+
+	t.Run("creates todo with estimate", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		estimate := 45
+		reqBody := models.CreateTodoRequest{
+			Description:     "Estimated Todo",
+			Priority:        models.PriorityMedium,
+			EstimateMinutes: &estimate,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		require.NotNil(t, todo.EstimateMinutes)
+		assert.Equal(t, 45, *todo.EstimateMinutes)
+	})
+
+	t.Run("returns error for negative estimate", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		estimate := -5
+		reqBody := models.CreateTodoRequest{
+			Description:     "Bad Estimate",
+			Priority:        models.PriorityMedium,
+			EstimateMinutes: &estimate,
+		}
+
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	})
+
+	t.Run("returns conflict for a duplicate description", func(t *testing.T) {
+		store := storage.NewStorageWithConfig(&storage.Config{UniqueTodoDescriptions: true})
+		handler := NewTodoHandler(store)
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+		require.NoError(t, err)
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		reqBody := models.CreateTodoRequest{Description: "pack", Priority: models.PriorityLow}
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+list.ID.String()+"/todos", reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: list.ID.String()}}
+
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "TODO_DUPLICATE", errResp.Code)
+	})
+}
+
+func TestBatchCreateTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	batchRequest := func(t *testing.T, listID uuid.UUID, body interface{}) (*httptest.ResponseRecorder, *gin.Context) {
+		req := testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos/batch", body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		return w, c
+	}
+
+	t.Run("applies the default priority to todos without one", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := batchRequest(t, listID, []models.CreateTodoRequest{
+			{Description: "Defaulted"},
+			{Description: "Explicit", Priority: models.PriorityHigh},
+		})
+		c.Request.URL.RawQuery = "defaultPriority=low"
+		handler.BatchCreateTodos(c)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var todos []models.Todo
+		testutil.ParseJSONResponse(t, w, &todos)
+		require.Len(t, todos, 2)
+		assert.Equal(t, models.PriorityLow, todos[0].Priority)
+		assert.Equal(t, models.PriorityHigh, todos[1].Priority)
+	})
+
+	t.Run("creates all todos in request order", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := batchRequest(t, listID, []models.CreateTodoRequest{
+			{Description: "First", Priority: models.PriorityHigh},
+			{Description: "Second", Priority: models.PriorityLow},
+			{Description: "Third", Priority: models.PriorityMedium},
+		})
+		handler.BatchCreateTodos(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var todos []models.Todo
+		testutil.ParseJSONResponse(t, w, &todos)
+		require.Len(t, todos, 3)
+		assert.Equal(t, "First", todos[0].Description)
+		assert.Equal(t, "Second", todos[1].Description)
+		assert.Equal(t, "Third", todos[2].Description)
+		for _, todo := range todos {
+			assert.NotEqual(t, uuid.Nil, todo.ID)
+			assert.Equal(t, listID, todo.ListID)
+		}
+	})
+
+	t.Run("reports invalid items by index and creates nothing", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		w, c := batchRequest(t, listID, []models.CreateTodoRequest{
+			{Description: "Valid", Priority: models.PriorityHigh},
+			{Description: "", Priority: models.PriorityLow},
+			{Description: "Bad priority", Priority: "urgent"},
+		})
+		handler.BatchCreateTodos(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "INVALID_INPUT", resp.Code)
+		itemErrors, ok := resp.Details["errors"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, itemErrors, 2)
+		assert.Equal(t, float64(1), itemErrors[0].(map[string]interface{})["index"])
+		assert.Equal(t, float64(2), itemErrors[1].(map[string]interface{})["index"])
+
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+	})
+
+	t.Run("rejects batches over the cap", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		reqs := make([]models.CreateTodoRequest, maxBatchTodos+1)
+		for i := range reqs {
+			reqs[i] = models.CreateTodoRequest{Description: "Todo", Priority: models.PriorityLow}
+		}
+		w, c := batchRequest(t, listID, reqs)
+		handler.BatchCreateTodos(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "BATCH_TOO_LARGE", resp.Code)
+	})
+
+	t.Run("rejects an empty array or a non-array body", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := batchRequest(t, listID, []models.CreateTodoRequest{})
+		handler.BatchCreateTodos(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, c = batchRequest(t, listID, models.CreateTodoRequest{Description: "Single", Priority: models.PriorityLow})
+		handler.BatchCreateTodos(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 404 for a missing list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		w, c := batchRequest(t, uuid.New(), []models.CreateTodoRequest{{Description: "Todo", Priority: models.PriorityLow}})
+		handler.BatchCreateTodos(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBatchUpdateTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	batchPatchQuery := func(t *testing.T, handler *TodoHandler, listID uuid.UUID, query string, body interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "PATCH", "/lists/"+listID.String()+"/todos/batch"+query, body)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.BatchUpdateTodos(c)
+		return w
+	}
+
+	batchPatch := func(t *testing.T, handler *TodoHandler, listID uuid.UUID, body interface{}) *httptest.ResponseRecorder {
+		return batchPatchQuery(t, handler, listID, "", body)
+	}
+
+	createTodos := func(t *testing.T, store storage.Store, listID uuid.UUID, n int) []uuid.UUID {
+		ids := make([]uuid.UUID, 0, n)
+		for i := 0; i < n; i++ {
+			todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Todo", Priority: models.PriorityLow})
+			require.NoError(t, err)
+			ids = append(ids, todo.ID)
+		}
+		return ids
+	}
+
+	t.Run("completes todos and reports missing IDs", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 2)
+		missing := uuid.New()
+
+		w := batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{
+			IDs: []uuid.UUID{ids[0], missing, ids[1]}, Action: models.BatchActionComplete,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var result models.BatchResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 2, result.Affected)
+		assert.Equal(t, []uuid.UUID{missing}, result.NotFound)
+
+		for _, id := range ids {
+			todo, err := store.GetTodoByID(testUserID, listID, id)
+			require.NoError(t, err)
+			assert.True(t, todo.Completed)
+			assert.NotNil(t, todo.CompletedAt)
+		}
+
+		w = batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{IDs: ids[:1], Action: models.BatchActionUncomplete})
+		assert.Equal(t, http.StatusOK, w.Code)
+		todo, err := store.GetTodoByID(testUserID, listID, ids[0])
+		require.NoError(t, err)
+		assert.False(t, todo.Completed)
+		assert.Nil(t, todo.CompletedAt)
+	})
+
+	t.Run("applies field updates", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 2)
+
+		high := models.PriorityHigh
+		w := batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{
+			IDs: ids, Updates: &models.UpdateTodoRequest{Priority: &high},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		for _, id := range ids {
+			todo, err := store.GetTodoByID(testUserID, listID, id)
+			require.NoError(t, err)
+			assert.Equal(t, models.PriorityHigh, todo.Priority)
+		}
+	})
+
+	t.Run("deletes todos", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 3)
+
+		w := batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{IDs: ids[:2], Action: models.BatchActionDelete})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var result models.BatchResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 2, result.Affected)
+		assert.Empty(t, result.NotFound)
+
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		require.Len(t, todos, 1)
+		assert.Equal(t, ids[2], todos[0].ID)
+	})
+
+	t.Run("requires exactly one of action or updates", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 1)
+		high := models.PriorityHigh
+
+		w := batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{IDs: ids})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{
+			IDs: ids, Action: models.BatchActionComplete, Updates: &models.UpdateTodoRequest{Priority: &high},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = batchPatch(t, handler, listID, models.BatchUpdateTodosRequest{IDs: ids, Action: "archive"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("verbose reports mixed results as 207 Multi-Status", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 2)
+		missing := uuid.New()
+
+		// A todo in another of the user's lists and one owned by another user
+		otherList, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Other List"})
+		require.NoError(t, err)
+		otherListTodo, err := store.CreateTodo(testUserID, otherList.ID, models.CreateTodoRequest{
+			Description: "Elsewhere", Priority: models.PriorityLow,
+		})
+		require.NoError(t, err)
+		stranger := uuid.New()
+		strangerList, err := store.CreateList(stranger, models.CreateTodoListRequest{Name: "Theirs"})
+		require.NoError(t, err)
+		strangerTodo, err := store.CreateTodo(stranger, strangerList.ID, models.CreateTodoRequest{
+			Description: "Theirs", Priority: models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		requested := []uuid.UUID{ids[0], missing, otherListTodo.ID, strangerTodo.ID, ids[1]}
+		w := batchPatchQuery(t, handler, listID, "?verbose=true", models.BatchUpdateTodosRequest{
+			IDs: requested, Action: models.BatchActionComplete,
+		})
+		require.Equal(t, http.StatusMultiStatus, w.Code)
+
+		var body models.BatchMultiStatus
+		testutil.ParseJSONResponse(t, w, &body)
+		require.Len(t, body.Results, len(requested))
+		for i, want := range []int{http.StatusOK, http.StatusNotFound, http.StatusNotFound, http.StatusNotFound, http.StatusOK} {
+			assert.Equal(t, requested[i], body.Results[i].ID)
+			assert.Equal(t, want, body.Results[i].Status)
+			if want == http.StatusNotFound {
+				assert.Equal(t, "TODO_NOT_FOUND", body.Results[i].Error)
+			} else {
+				assert.Empty(t, body.Results[i].Error)
+			}
+		}
+
+		// Foreign todos are left untouched
+		untouched, err := store.GetTodoByID(stranger, strangerList.ID, strangerTodo.ID)
+		require.NoError(t, err)
+		assert.False(t, untouched.Completed)
+
+		// Deleted items report 204
+		w = batchPatchQuery(t, handler, listID, "?verbose=true", models.BatchUpdateTodosRequest{
+			IDs: []uuid.UUID{missing, ids[0]}, Action: models.BatchActionDelete,
+		})
+		require.Equal(t, http.StatusMultiStatus, w.Code)
+		var deleted models.BatchMultiStatus
+		testutil.ParseJSONResponse(t, w, &deleted)
+		assert.Equal(t, []models.BatchItemResult{
+			{ID: missing, Status: http.StatusNotFound, Error: "TODO_NOT_FOUND"},
+			{ID: ids[0], Status: http.StatusNoContent},
+		}, deleted.Results)
+	})
+
+	t.Run("verbose keeps the count when results are not mixed", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		ids := createTodos(t, store, listID, 2)
+
+		w := batchPatchQuery(t, handler, listID, "?verbose=true", models.BatchUpdateTodosRequest{IDs: ids, Action: models.BatchActionComplete})
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result models.BatchResult
+		testutil.ParseJSONResponse(t, w, &result)
+		assert.Equal(t, 2, result.Affected)
+
+		w = batchPatchQuery(t, handler, listID, "?verbose=true", models.BatchUpdateTodosRequest{
+			IDs: []uuid.UUID{uuid.New()}, Action: models.BatchActionComplete,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = batchPatchQuery(t, handler, listID, "?verbose=yes", models.BatchUpdateTodosRequest{IDs: ids, Action: models.BatchActionComplete})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "INVALID_VERBOSE", errResp.Code)
+	})
+
+	t.Run("returns 404 for a missing list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		w := batchPatch(t, handler, uuid.New(), models.BatchUpdateTodosRequest{
+			IDs: []uuid.UUID{uuid.New()}, Action: models.BatchActionDelete,
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetTodoByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("successfully retrieves todo by ID", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Test Todo",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos/"+created.ID.String(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.GetTodoByID(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.Equal(t, created.ID, todo.ID)
+		assert.Equal(t, "Test Todo", todo.Description)
+	})
+
+	t.Run("returns error for non-existent todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		nonExistentID := uuid.New()
+		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos/"+nonExistentID.String(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: nonExistentID.String()},
+		}
+
+		handler.GetTodoByID(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "TODO_NOT_FOUND", errResp.Code)
+	})
+
+	t.Run("returns error for non-existent list", func(t *testing.T) {
+		handler, _, _ := setupTodoHandler()
+
+		nonExistentListID := uuid.New()
+		todoID := uuid.New()
+
+		req := httptest.NewRequest("GET", "/lists/"+nonExistentListID.String()+"/todos/"+todoID.String(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: nonExistentListID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+
+		handler.GetTodoByID(c)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
 
@@ -734,21 +1958,315 @@ func TestGetTodoByID(t *testing.T) {
 			{Key: "todoId", Value: todoID.String()},
 		}
 
-		handler.GetTodoByID(c)
+		handler.GetTodoByID(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
+	})
+}
+
+func TestGetTodoByIDETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, store, listID := setupTodoHandler()
+	router := gin.New()
+	router.GET("/lists/:listId/todos/:todoId", handler.GetTodoByID)
+	router.DELETE("/lists/:listId/todos/:todoId", handler.DeleteTodo)
+
+	todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Poll me", Priority: models.PriorityMedium})
+	require.NoError(t, err)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos/"+todo.ID.String(), http.NoBody)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	w := get(etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Zero(t, w.Body.Len())
+
+	_, err = store.CreateSubtask(testUserID, listID, todo.ID, models.CreateSubtaskRequest{Description: "Step"})
+	require.NoError(t, err)
+	w = get(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	afterSubtask := w.Header().Get("ETag")
+	assert.NotEqual(t, etag, afterSubtask)
+
+	completed := true
+	_, err = store.UpdateTodo(testUserID, listID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+	require.NoError(t, err)
+	w = get(afterSubtask)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, afterSubtask, w.Header().Get("ETag"))
+
+	// The ETag doubles as an If-Match precondition for DELETE
+	remove := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", "/lists/"+listID.String()+"/todos/"+todo.ID.String(), http.NoBody)
+		req.Header.Set("If-Match", ifMatch)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	stale := get("").Header().Get("ETag")
+	_, err = store.UpdateTodo(testUserID, listID, todo.ID, models.UpdateTodoRequest{Description: strPtr("Polled")})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, remove(stale).Code)
+
+	assert.Equal(t, http.StatusNoContent, remove(get("").Header().Get("ETag")).Code)
+	_, err = store.GetTodoByID(testUserID, listID, todo.ID)
+	assert.ErrorIs(t, err, storage.ErrTodoNotFound)
+}
+
+func TestUpdateTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("successfully updates todo title", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		reqBody := models.UpdateTodoRequest{
+			Description: strPtr("Updated Title"),
+		}
+
+		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+created.ID.String(), reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.Equal(t, "Updated Title", todo.Description)
+	})
+
+	t.Run("returns the unchanged todo for an empty PATCH body", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		version := created.Version
+
+		for _, body := range []string{"", "  \n"} {
+			req := httptest.NewRequest("PATCH", "/lists/"+listID.String()+"/todos/"+created.ID.String(), strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{
+				{Key: "listId", Value: listID.String()},
+				{Key: "todoId", Value: created.ID.String()},
+			}
+
+			handler.UpdateTodo(c)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var todo models.Todo
+			testutil.ParseJSONResponse(t, w, &todo)
+
+			assert.Equal(t, created.ID, todo.ID)
+			assert.Equal(t, "Original Title", todo.Description)
+			assert.Equal(t, version, todo.Version)
+		}
+	})
+
+	t.Run("empty PATCH body ignores If-None-Match", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		path := "/lists/" + listID.String() + "/todos/" + created.ID.String()
+		params := gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", path, http.NoBody)
+		c.Params = params
+		handler.GetTodoByID(c)
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		req := httptest.NewRequest("PATCH", path, http.NoBody)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = params
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+		assert.Equal(t, "Original Title", todo.Description)
+	})
+
+	t.Run("rejects an empty PUT body", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("PUT", "/lists/"+listID.String()+"/todos/"+created.ID.String(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+	})
+
+	t.Run("PATCH with a body updates like PUT", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		reqBody := models.UpdateTodoRequest{Description: strPtr("Patched Title")}
+		req := testutil.MakeJSONRequest(t, "PATCH", "/lists/"+listID.String()+"/todos/"+created.ID.String(), reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.Equal(t, "Patched Title", todo.Description)
+	})
+
+	t.Run("returns the next occurrence when completing a recurring todo", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		dueDate := time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC)
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description:    "Take out trash",
+			Priority:       models.PriorityLow,
+			DueDate:        &dueDate,
+			RecurrenceRule: "weekly",
+		})
+		require.NoError(t, err)
+
+		reqBody := models.UpdateTodoRequest{Completed: boolPtr(true)}
+		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+created.ID.String(), reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var todo models.Todo
+		testutil.ParseJSONResponse(t, w, &todo)
+
+		assert.True(t, todo.Completed)
+		require.NotNil(t, todo.NextOccurrence)
+		assert.False(t, todo.NextOccurrence.Completed)
+		assert.Equal(t, "weekly", todo.NextOccurrence.RecurrenceRule)
+		require.NotNil(t, todo.NextOccurrence.DueDate)
+		assert.True(t, todo.NextOccurrence.DueDate.Equal(dueDate.AddDate(0, 0, 7)))
+	})
+
+	t.Run("rejects an invalid recurrence rule", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Take out trash",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		reqBody := models.UpdateTodoRequest{RecurrenceRule: strPtr("FREQ=HOURLY")}
+		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+created.ID.String(), reqBody)
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 
 		var errResp models.ErrorResponse
 		testutil.ParseJSONResponse(t, w, &errResp)
 
-		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
+		assert.Equal(t, "INVALID_INPUT", errResp.Code)
+		assert.Equal(t, "recurrenceRule", errResp.Details["field"])
 	})
-}
-
-func TestUpdateTodo(t *testing.T) {
-	gin.SetMode(gin.TestMode)
 
-	t.Run("successfully updates todo title", func(t *testing.T) {
+	t.Run("rejects stale version with conflict", func(t *testing.T) {
 		handler, store, listID := setupTodoHandler()
 
 		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
@@ -756,9 +2274,13 @@ func TestUpdateTodo(t *testing.T) {
 			Priority:    models.PriorityMedium,
 		})
 		require.NoError(t, err)
+		stale := created.Version
+		_, err = store.UpdateTodo(testUserID, listID, created.ID, models.UpdateTodoRequest{Description: strPtr("Someone else")})
+		require.NoError(t, err)
 
 		reqBody := models.UpdateTodoRequest{
 			Description: strPtr("Updated Title"),
+			Version:     &stale,
 		}
 
 		req := testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+created.ID.String(), reqBody)
@@ -773,12 +2295,8 @@ func TestUpdateTodo(t *testing.T) {
 
 		handler.UpdateTodo(c)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-
-		var todo models.Todo
-		testutil.ParseJSONResponse(t, w, &todo)
-
-		assert.Equal(t, "Updated Title", todo.Description)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "VERSION_CONFLICT")
 	})
 
 	t.Run("successfully marks todo as completed", func(t *testing.T) {
@@ -1032,6 +2550,72 @@ func TestUpdateTodo(t *testing.T) {
 
 		assert.Equal(t, "INVALID_LIST_ID", errResp.Code)
 	})
+	t.Run("returns a field diff only when includeDiff=true", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		update := func(query string, body models.UpdateTodoRequest) map[string]interface{} {
+			t.Helper()
+			path := "/lists/" + listID.String() + "/todos/" + created.ID.String() + query
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = testutil.MakeJSONRequest(t, "PUT", path, body)
+			c.Params = gin.Params{
+				{Key: "listId", Value: listID.String()},
+				{Key: "todoId", Value: created.ID.String()},
+			}
+			handler.UpdateTodo(c)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var resp map[string]interface{}
+			testutil.ParseJSONResponse(t, w, &resp)
+			return resp
+		}
+
+		resp := update("", models.UpdateTodoRequest{Description: strPtr("Renamed")})
+		assert.NotContains(t, resp, "changed")
+
+		high := models.PriorityHigh
+		resp = update("?includeDiff=true", models.UpdateTodoRequest{Description: strPtr("Renamed"), Priority: &high})
+		assert.Equal(t, map[string]interface{}{
+			"priority": map[string]interface{}{"old": "medium", "new": "high"},
+		}, resp["changed"])
+	})
+
+	t.Run("rejects an invalid includeDiff value", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Original Title",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		path := "/lists/" + listID.String() + "/todos/" + created.ID.String() + "?includeDiff=yes"
+		req := testutil.MakeJSONRequest(t, "PUT", path, models.UpdateTodoRequest{Description: strPtr("Renamed")})
+		w := httptest.NewRecorder()
+
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: created.ID.String()},
+		}
+
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+
+		assert.Equal(t, "INVALID_INCLUDE_DIFF", errResp.Code)
+	})
 }
 
 func TestDeleteTodo(t *testing.T) {
@@ -1066,6 +2650,46 @@ func TestDeleteTodo(t *testing.T) {
 		assert.ErrorIs(t, err, storage.ErrTodoNotFound)
 	})
 
+	t.Run("rejects a stale If-Match and accepts the current version", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Todo to Delete",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		_, err = store.UpdateTodo(testUserID, listID, created.ID, models.UpdateTodoRequest{Completed: boolPtr(true)})
+		require.NoError(t, err)
+
+		deleteWith := func(ifMatch string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("DELETE", "/lists/"+listID.String()+"/todos/"+created.ID.String(), http.NoBody)
+			req.Header.Set("If-Match", ifMatch)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{
+				{Key: "listId", Value: listID.String()},
+				{Key: "todoId", Value: created.ID.String()},
+			}
+			handler.DeleteTodo(c)
+			c.Writer.WriteHeaderNow()
+			return w
+		}
+
+		w := deleteWith(`"1"`)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "PRECONDITION_FAILED", response.Code)
+		_, err = store.GetTodoByID(testUserID, listID, created.ID)
+		require.NoError(t, err)
+
+		w = deleteWith(`"2"`)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		_, err = store.GetTodoByID(testUserID, listID, created.ID)
+		assert.ErrorIs(t, err, storage.ErrTodoNotFound)
+	})
+
 	t.Run("returns error for non-existent todo", func(t *testing.T) {
 		handler, _, listID := setupTodoHandler()
 
@@ -1165,6 +2789,329 @@ func TestDeleteTodo(t *testing.T) {
 }
 
 // Helper functions for pointer creation
+func TestCloneTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cloneRequest := func(t *testing.T, listID, todoID uuid.UUID, body interface{}) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos/"+todoID.String()+"/clone", body)
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+		return w, c
+	}
+
+	t.Run("clones within the same list", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Water plants",
+			Priority:    models.PriorityHigh,
+		})
+		require.NoError(t, err)
+		_, err = store.UpdateTodo(testUserID, listID, created.ID, models.UpdateTodoRequest{Completed: boolPtr(true)})
+		require.NoError(t, err)
+
+		w, c := cloneRequest(t, listID, created.ID, nil)
+		handler.CloneTodo(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var clone models.Todo
+		testutil.ParseJSONResponse(t, w, &clone)
+		assert.NotEqual(t, created.ID, clone.ID)
+		assert.Equal(t, listID, clone.ListID)
+		assert.Equal(t, "Water plants (copy)", clone.Description)
+		assert.Equal(t, models.PriorityHigh, clone.Priority)
+		assert.False(t, clone.Completed)
+		assert.Nil(t, clone.CompletedAt)
+	})
+
+	t.Run("clones into another owned list", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Book flights",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		w, c := cloneRequest(t, listID, created.ID, models.CloneTodoRequest{TargetListID: &target.ID})
+		handler.CloneTodo(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var clone models.Todo
+		testutil.ParseJSONResponse(t, w, &clone)
+		assert.Equal(t, target.ID, clone.ListID)
+
+		_, err = store.GetTodoByID(testUserID, target.ID, clone.ID)
+		assert.NoError(t, err)
+		_, err = store.GetTodoByID(testUserID, listID, created.ID)
+		assert.NoError(t, err, "source todo must be left in place")
+	})
+
+	t.Run("rejects a target list owned by another user", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		foreign, err := store.CreateList(uuid.New(), models.CreateTodoListRequest{Name: "Not Mine"})
+		require.NoError(t, err)
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Private task",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w, c := cloneRequest(t, listID, created.ID, models.CloneTodoRequest{TargetListID: &foreign.ID})
+		handler.CloneTodo(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "TARGET_LIST_NOT_FOUND", response.Code)
+
+		todos, _, err := store.GetTodosByList(foreign.UserID, foreign.ID, storage.ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+	})
+
+	t.Run("returns error for non-existent todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w, c := cloneRequest(t, listID, uuid.New(), nil)
+		handler.CloneTodo(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "TODO_NOT_FOUND", response.Code)
+	})
+}
+
+func TestMoveTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	moveRequest := func(t *testing.T, listID, todoID uuid.UUID, body interface{}) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos/"+todoID.String()+"/move", body)
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+		return w, c
+	}
+
+	t.Run("moves a todo into another owned list", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		target, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Target"})
+		require.NoError(t, err)
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Misfiled",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w, c := moveRequest(t, listID, created.ID, models.MoveTodoRequest{TargetListID: target.ID})
+		handler.MoveTodo(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var moved models.Todo
+		testutil.ParseJSONResponse(t, w, &moved)
+		assert.Equal(t, created.ID, moved.ID)
+		assert.Equal(t, target.ID, moved.ListID)
+
+		_, err = store.GetTodoByID(testUserID, listID, created.ID)
+		assert.ErrorIs(t, err, storage.ErrTodoNotFound)
+	})
+
+	t.Run("moving to the same list is a no-op", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Filed correctly",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+		version := created.Version
+
+		w, c := moveRequest(t, listID, created.ID, models.MoveTodoRequest{TargetListID: listID})
+		handler.MoveTodo(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var moved models.Todo
+		testutil.ParseJSONResponse(t, w, &moved)
+		assert.Equal(t, listID, moved.ListID)
+		assert.Equal(t, version, moved.Version)
+	})
+
+	t.Run("returns LIST_NOT_FOUND for a target the user does not own", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		foreign, err := store.CreateList(uuid.New(), models.CreateTodoListRequest{Name: "Foreign"})
+		require.NoError(t, err)
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Mine",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		for _, targetID := range []uuid.UUID{foreign.ID, uuid.New()} {
+			w, c := moveRequest(t, listID, created.ID, models.MoveTodoRequest{TargetListID: targetID})
+			handler.MoveTodo(c)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var errResp models.ErrorResponse
+			testutil.ParseJSONResponse(t, w, &errResp)
+			assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+		}
+	})
+
+	t.Run("requires a target list", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		created, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Mine",
+			Priority:    models.PriorityLow,
+		})
+		require.NoError(t, err)
+
+		w, c := moveRequest(t, listID, created.ID, map[string]string{})
+		handler.MoveTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPinTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	call := func(handle gin.HandlerFunc, listID, todoID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/lists/"+listID.String()+"/todos/"+todoID.String()+"/pin", http.NoBody)
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+		handle(c)
+		return w
+	}
+
+	t.Run("a pinned low-priority todo lists above an unpinned high-priority one", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Urgent", Priority: models.PriorityHigh})
+		require.NoError(t, err)
+		low, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Someday", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := call(handler.PinTodo, listID, low.ID)
+		require.Equal(t, http.StatusOK, w.Code)
+		var pinned models.Todo
+		testutil.ParseJSONResponse(t, w, &pinned)
+		assert.True(t, pinned.Pinned)
+
+		w = httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lists/"+listID.String()+"/todos?sortBy=priority&sortOrder=asc", http.NoBody)
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.GetTodosByList(c)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.PaginatedListTodosResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, "Someday", resp.Data[0].Description)
+		assert.Equal(t, "Urgent", resp.Data[1].Description)
+
+		w = call(handler.UnpinTodo, listID, low.ID)
+		require.Equal(t, http.StatusOK, w.Code)
+		var unpinned models.Todo
+		testutil.ParseJSONResponse(t, w, &unpinned)
+		assert.False(t, unpinned.Pinned)
+	})
+
+	t.Run("returns 404 for an unknown todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w := call(handler.PinTodo, listID, uuid.New())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "TODO_NOT_FOUND", errResp.Code)
+	})
+}
+
+func TestReorderTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reorder := func(t *testing.T, handler *TodoHandler, listID, todoID uuid.UUID, body interface{}) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+todoID.String()+"/position", body)
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todoID.String()},
+		}
+		handler.ReorderTodo(c)
+		return w
+	}
+
+	t.Run("moves a todo and shifts the others", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		var created []*models.Todo
+		for _, description := range []string{"First", "Second", "Third"} {
+			todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: description, Priority: models.PriorityLow})
+			require.NoError(t, err)
+			created = append(created, todo)
+		}
+
+		w := reorder(t, handler, listID, created[2].ID, map[string]int{"position": 0})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var moved models.Todo
+		testutil.ParseJSONResponse(t, w, &moved)
+		assert.Equal(t, 0, moved.Position)
+
+		todos, _, err := store.GetTodosByList(testUserID, listID, storage.ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, "Third", todos[0].Description)
+		assert.Equal(t, "First", todos[1].Description)
+		assert.Equal(t, "Second", todos[2].Description)
+	})
+
+	t.Run("rejects a missing or negative position", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Only", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		for _, body := range []interface{}{map[string]int{}, map[string]int{"position": -1}} {
+			w := reorder(t, handler, listID, todo.ID, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("returns 404 for an unknown todo", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w := reorder(t, handler, listID, uuid.New(), map[string]int{"position": 0})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &response)
+		assert.Equal(t, "TODO_NOT_FOUND", response.Code)
+	})
+}
+
 func strPtr(s string) *string {
 	return &s
 }
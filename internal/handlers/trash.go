@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"todolist-api/internal/middleware"
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetTrash handles GET /lists/:listId/trash, listing the list's deleted todos
+// most recently deleted first
+func (h *TodoHandler) GetTrash(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, err := uuid.Parse(c.Param("listId"))
+	if err != nil {
+		respondJSON(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    "INVALID_LIST_ID",
+			Message: "Invalid list ID format",
+		})
+		return
+	}
+
+	todos, err := h.storage.GetDeletedTodos(userID, listID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve deleted todos",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, todos)
+}
+
+// RestoreTodo handles POST /lists/:listId/trash/:todoId/restore, returning a
+// deleted todo to the end of its list
+func (h *TodoHandler) RestoreTodo(c *gin.Context) {
+	// Get authenticated user ID
+	userID := middleware.GetUserIDOrDefault(c)
+
+	listID, todoID, ok := parseTodoPath(c)
+	if !ok {
+		return
+	}
+
+	todo, err := h.storage.RestoreTodo(userID, listID, todoID)
+	if err != nil {
+		if err == storage.ErrListNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "LIST_NOT_FOUND",
+				Message: "The requested todo list was not found",
+			})
+			return
+		}
+		if err == storage.ErrTodoNotFound {
+			respondJSON(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    "TODO_NOT_FOUND",
+				Message: "The requested todo is not in the trash",
+			})
+			return
+		}
+		if err == storage.ErrDuplicateTodo {
+			respondJSON(c, http.StatusConflict, models.ErrorResponse{
+				Code:    "TODO_DUPLICATE",
+				Message: "An open todo with this description already exists in the list",
+			})
+			return
+		}
+		if respondDBBusy(c, err) {
+			return
+		}
+		respondJSON(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to restore todo",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, todo)
+}
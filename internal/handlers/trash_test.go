@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	call := func(handle gin.HandlerFunc, method string, params gin.Params) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/trash", http.NoBody)
+		c.Params = params
+		handle(c)
+		return w
+	}
+
+	t.Run("lists and restores deleted todos", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Deleted by accident",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteTodo(testUserID, listID, todo.ID, nil))
+
+		listParams := gin.Params{{Key: "listId", Value: listID.String()}}
+		w := call(handler.GetTrash, "GET", listParams)
+		require.Equal(t, http.StatusOK, w.Code)
+		var trash []models.DeletedTodo
+		testutil.ParseJSONResponse(t, w, &trash)
+		require.Len(t, trash, 1)
+		assert.Equal(t, todo.ID, trash[0].ID)
+		assert.False(t, trash[0].DeletedAt.IsZero())
+
+		todoParams := append(gin.Params{{Key: "todoId", Value: todo.ID.String()}}, listParams...)
+		w = call(handler.RestoreTodo, "POST", todoParams)
+		require.Equal(t, http.StatusOK, w.Code)
+		var restored models.Todo
+		testutil.ParseJSONResponse(t, w, &restored)
+		assert.Equal(t, "Deleted by accident", restored.Description)
+
+		_, err = store.GetTodoByID(testUserID, listID, todo.ID)
+		assert.NoError(t, err)
+
+		w = call(handler.GetTrash, "GET", listParams)
+		testutil.ParseJSONResponse(t, w, &trash)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("returns 404 for todos not in the trash", func(t *testing.T) {
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{
+			Description: "Still here",
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, err)
+
+		params := gin.Params{{Key: "listId", Value: listID.String()}, {Key: "todoId", Value: todo.ID.String()}}
+		w := call(handler.RestoreTodo, "POST", params)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var errResp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "TODO_NOT_FOUND", errResp.Code)
+
+		w = call(handler.GetTrash, "GET", gin.Params{{Key: "listId", Value: uuid.New().String()}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		testutil.ParseJSONResponse(t, w, &errResp)
+		assert.Equal(t, "LIST_NOT_FOUND", errResp.Code)
+	})
+}
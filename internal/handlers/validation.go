@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/storage"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// Binding tags for the configurable length limits on request fields
+	listNameLengthTag        = "list_name_length"
+	todoDescriptionLengthTag = "todo_description_length"
+
+	// Binding tag for a todo's recurrence rule
+	recurrenceRuleTag = "recurrence_rule"
+
+	// Database column sizes, which configured limits can never exceed
+	listNameColumnLength        = 100
+	todoDescriptionColumnLength = 500
+)
+
+var (
+	// limitsMu guards the configured length limits read by the validators
+	limitsMu                 sync.RWMutex
+	listNameMaxLength        = listNameColumnLength
+	todoDescriptionMaxLength = todoDescriptionColumnLength
+)
+
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	engine.RegisterTagNameFunc(jsonFieldName)
+	if err := engine.RegisterValidation(listNameLengthTag, func(fl validator.FieldLevel) bool {
+		return utf8.RuneCountInString(fl.Field().String()) <= ListNameMaxLength()
+	}); err != nil {
+		panic(err)
+	}
+	if err := engine.RegisterValidation(todoDescriptionLengthTag, func(fl validator.FieldLevel) bool {
+		return utf8.RuneCountInString(fl.Field().String()) <= TodoDescriptionMaxLength()
+	}); err != nil {
+		panic(err)
+	}
+	if err := engine.RegisterValidation(recurrenceRuleTag, func(fl validator.FieldLevel) bool {
+		return storage.ValidRecurrenceRule(fl.Field().String())
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// ApplyValidationConfig sets the length limits enforced when binding requests.
+// Limits outside 1..column size fall back to the column size.
+func ApplyValidationConfig(config *ValidationConfig) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	listNameMaxLength = clampLimit(config.ListNameMaxLength, listNameColumnLength)
+	todoDescriptionMaxLength = clampLimit(config.TodoDescriptionMaxLength, todoDescriptionColumnLength)
+}
+
+// ListNameMaxLength returns the configured maximum list name length in characters
+func ListNameMaxLength() int {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return listNameMaxLength
+}
+
+// TodoDescriptionMaxLength returns the configured maximum todo description length in characters
+func TodoDescriptionMaxLength() int {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return todoDescriptionMaxLength
+}
+
+// clampLimit returns limit, or columnLength when limit is not a usable value
+func clampLimit(limit, columnLength int) int {
+	if limit <= 0 || limit > columnLength {
+		return columnLength
+	}
+	return limit
+}
+
+// jsonFieldName names struct fields in validation errors after their JSON
+// tags, so errors name the fields clients actually send
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// bindingErrorDetails describes a request binding error for an error
+// response. Validation failures list each invalid field under "fields", along
+// with the configured limit when a length limit was exceeded; a body that is
+// not valid JSON gets a generic message rather than the decoder's error.
+func bindingErrorDetails(err error) map[string]interface{} {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		return map[string]interface{}{"error": "Request body must be " + jsonTypeName(typeErr.Type)}
+	case errors.As(err, &typeErr):
+		return map[string]interface{}{
+			"error": "Request body has a field of the wrong type",
+			"fields": []models.FieldError{{
+				Field:   typeErr.Field,
+				Rule:    "type",
+				Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+			}},
+		}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return map[string]interface{}{"error": "Request body must be valid JSON"}
+	default:
+		return map[string]interface{}{"error": "Request body could not be read"}
+	}
+
+	details := map[string]interface{}{
+		"error":  "One or more fields are invalid",
+		"fields": validationFieldErrors(validationErrs),
+	}
+	for _, fieldErr := range validationErrs {
+		switch fieldErr.Tag() {
+		case listNameLengthTag:
+			details["field"] = "name"
+			details["maxLength"] = ListNameMaxLength()
+		case todoDescriptionLengthTag:
+			details["field"] = "description"
+			details["maxLength"] = TodoDescriptionMaxLength()
+		case recurrenceRuleTag:
+			details["field"] = "recurrenceRule"
+		}
+	}
+	return details
+}
+
+// validationFieldErrors converts validator errors into one FieldError per
+// invalid field
+func validationFieldErrors(validationErrs validator.ValidationErrors) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, models.FieldError{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Message: fieldErr.Field() + " " + validationMessage(fieldErr),
+		})
+	}
+	return fields
+}
+
+// validationMessage explains the rule a field broke, to follow the field name
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "min", "max":
+		bound := "at least"
+		if fieldErr.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fieldErr.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must have %s %s items", bound, fieldErr.Param())
+		}
+		return fmt.Sprintf("must be %s %s", bound, fieldErr.Param())
+	case listNameLengthTag:
+		return fmt.Sprintf("must be at most %d characters long", ListNameMaxLength())
+	case todoDescriptionLengthTag:
+		return fmt.Sprintf("must be at most %d characters long", TodoDescriptionMaxLength())
+	case recurrenceRuleTag:
+		return "must be a valid recurrence rule"
+	}
+	return "is invalid"
+}
+
+// jsonTypeName describes a Go type the way a JSON client would see it
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
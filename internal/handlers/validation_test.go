@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withValidationConfig applies config for the duration of a test
+func withValidationConfig(t *testing.T, config *ValidationConfig) {
+	t.Helper()
+	ApplyValidationConfig(config)
+	t.Cleanup(func() {
+		ApplyValidationConfig(&ValidationConfig{})
+	})
+}
+
+func TestApplyValidationConfig(t *testing.T) {
+	t.Run("uses configured limits", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{ListNameMaxLength: 20, TodoDescriptionMaxLength: 50})
+		assert.Equal(t, 20, ListNameMaxLength())
+		assert.Equal(t, 50, TodoDescriptionMaxLength())
+	})
+
+	t.Run("caps limits at column sizes", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{ListNameMaxLength: 1000, TodoDescriptionMaxLength: -1})
+		assert.Equal(t, 100, ListNameMaxLength())
+		assert.Equal(t, 500, TodoDescriptionMaxLength())
+	})
+}
+
+func TestConfiguredLengthLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("rejects list name over configured limit", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{ListNameMaxLength: 10})
+		handler, _ := setupListHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists", models.CreateTodoListRequest{Name: "Eleven char"})
+		handler.CreateList(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "INVALID_INPUT", resp.Code)
+		assert.Equal(t, "name", resp.Details["field"])
+		assert.Equal(t, float64(10), resp.Details["maxLength"])
+	})
+
+	t.Run("accepts list name at configured limit", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{ListNameMaxLength: 10})
+		handler, _ := setupListHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists", models.CreateTodoListRequest{Name: "Ten chars!"})
+		handler.CreateList(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("rejects todo description update over configured limit", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{TodoDescriptionMaxLength: 20})
+		handler, store, listID := setupTodoHandler()
+
+		todo, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Short", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "PUT", "/lists/"+listID.String()+"/todos/"+todo.ID.String(),
+			models.UpdateTodoRequest{Description: strPtr(strings.Repeat("x", 21))})
+		c.Params = gin.Params{
+			{Key: "listId", Value: listID.String()},
+			{Key: "todoId", Value: todo.ID.String()},
+		}
+		handler.UpdateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		testutil.ParseJSONResponse(t, w, &resp)
+		assert.Equal(t, "description", resp.Details["field"])
+		assert.Equal(t, float64(20), resp.Details["maxLength"])
+	})
+
+	t.Run("defaults to column sizes", func(t *testing.T) {
+		handler, _, listID := setupTodoHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = testutil.MakeJSONRequest(t, "POST", "/lists/"+listID.String()+"/todos",
+			models.CreateTodoRequest{Description: strings.Repeat("x", 501), Priority: models.PriorityLow})
+		c.Params = gin.Params{{Key: "listId", Value: listID.String()}}
+		handler.CreateTodo(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"maxLength":500`)
+	})
+}
+
+func TestBindingErrorFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// bind sends body to handler and returns the error details
+	type errorDetails struct {
+		Code    string `json:"code"`
+		Details struct {
+			Error  string              `json:"error"`
+			Fields []models.FieldError `json:"fields"`
+		} `json:"details"`
+	}
+	bind := func(t *testing.T, handler gin.HandlerFunc, body string, params gin.Params) errorDetails {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = params
+		handler(c)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var resp errorDetails
+		testutil.ParseJSONResponse(t, w, &resp)
+		return resp
+	}
+
+	t.Run("lists each invalid field by its JSON name", func(t *testing.T) {
+		authHandler, _ := setupAuthHandler(t)
+
+		resp := bind(t, authHandler.Register, `{"email": "not-an-email"}`, nil)
+		assert.Equal(t, "INVALID_REQUEST", resp.Code)
+		assert.Equal(t, []models.FieldError{
+			{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+			{Field: "password", Rule: "required", Message: "password is required"},
+		}, resp.Details.Fields)
+	})
+
+	t.Run("explains rule parameters", func(t *testing.T) {
+		withValidationConfig(t, &ValidationConfig{ListNameMaxLength: 10})
+		listHandler, _ := setupListHandler()
+		todoHandler, _, listID := setupTodoHandler()
+
+		resp := bind(t, todoHandler.CreateTodo, `{"description": "Buy milk", "priority": "urgent"}`,
+			gin.Params{{Key: "listId", Value: listID.String()}})
+		assert.Equal(t, "INVALID_INPUT", resp.Code)
+		assert.Equal(t, []models.FieldError{
+			{Field: "priority", Rule: "oneof", Message: "priority must be one of: low, medium, high"},
+		}, resp.Details.Fields)
+
+		resp = bind(t, listHandler.CreateList, `{"name": "Eleven char"}`, nil)
+		assert.Equal(t, []models.FieldError{
+			{Field: "name", Rule: listNameLengthTag, Message: "name must be at most 10 characters long"},
+		}, resp.Details.Fields)
+	})
+
+	t.Run("reports values of the wrong type", func(t *testing.T) {
+		listHandler, _ := setupListHandler()
+
+		resp := bind(t, listHandler.CreateList, `{"name": 5}`, nil)
+		assert.Equal(t, []models.FieldError{
+			{Field: "name", Rule: "type", Message: "name must be a string"},
+		}, resp.Details.Fields)
+
+		resp = bind(t, listHandler.CreateList, `["Groceries"]`, nil)
+		assert.Equal(t, "Request body must be an object", resp.Details.Error)
+		assert.Empty(t, resp.Details.Fields)
+	})
+
+	t.Run("malformed JSON gets a generic message", func(t *testing.T) {
+		authHandler, _ := setupAuthHandler(t)
+		listHandler, _ := setupListHandler()
+
+		for _, body := range []string{`{"email": `, `{"email": "a@example.com",}`, ``} {
+			resp := bind(t, authHandler.Login, body, nil)
+			assert.Equal(t, "Request body must be valid JSON", resp.Details.Error, body)
+			assert.Empty(t, resp.Details.Fields, body)
+		}
+
+		resp := bind(t, listHandler.CreateList, `{"name": "Groceries"`, nil)
+		assert.Equal(t, "INVALID_INPUT", resp.Code)
+		assert.Equal(t, "Request body must be valid JSON", resp.Details.Error)
+	})
+}
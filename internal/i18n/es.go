@@ -0,0 +1,65 @@
+package i18n
+
+// spanish translates the most common error messages into Spanish
+var spanish = map[string]string{
+	// Request validation
+	"Invalid request body":                         "El cuerpo de la solicitud no es válido",
+	"Invalid request payload":                      "El contenido de la solicitud no es válido",
+	"Invalid list ID format":                       "El formato del ID de la lista no es válido",
+	"Invalid todo ID format":                       "El formato del ID de la tarea no es válido",
+	"Invalid subtask ID format":                    "El formato del ID de la subtarea no es válido",
+	"Invalid share link ID format":                 "El formato del ID del enlace compartido no es válido",
+	"Invalid user ID format":                       "El formato del ID del usuario no es válido",
+	"Invalid session ID format":                    "El formato del ID de la sesión no es válido",
+	"Priority must be one of: low, medium, high":   "La prioridad debe ser low, medium o high",
+	"Completed must be true or false":              "Completed debe ser true o false",
+	"sortOrder must be asc or desc":                "sortOrder debe ser asc o desc",
+	"q must be a non-empty search string":          "q debe ser un texto de búsqueda no vacío",
+	"Timezone must be a valid IANA time zone name": "La zona horaria debe ser un nombre de zona horaria IANA válido",
+	"This list requires a due date on every todo":  "Esta lista requiere una fecha de vencimiento en cada tarea",
+	"Too many todos in one batch":                  "Demasiadas tareas en un solo lote",
+	"Too many lines in one import":                 "Demasiadas líneas en una sola importación",
+	"Too many rows in one import":                  "Demasiadas filas en una sola importación",
+	"Text contains no todos to import":             "El texto no contiene tareas para importar",
+	"One or more todos are invalid":                "Una o más tareas no son válidas",
+	"A list cannot be merged into itself":          "Una lista no se puede fusionar consigo misma",
+	"Exactly one of action or updates is required": "Se requiere exactamente uno de action o updates",
+	"At least one todo is required":                "Se requiere al menos una tarea",
+
+	// Missing resources
+	"The requested todo list was not found":                      "No se encontró la lista de tareas solicitada",
+	"The requested todo was not found":                           "No se encontró la tarea solicitada",
+	"The requested subtask was not found":                        "No se encontró la subtarea solicitada",
+	"The requested share link was not found":                     "No se encontró el enlace compartido solicitado",
+	"The requested notification was not found":                   "No se encontró la notificación solicitada",
+	"The requested todo is not in the trash":                     "La tarea solicitada no está en la papelera",
+	"The target todo list was not found":                         "No se encontró la lista de tareas de destino",
+	"The source todo list was not found":                         "No se encontró la lista de tareas de origen",
+	"This share link does not exist, has expired or was revoked": "Este enlace compartido no existe, caducó o fue revocado",
+	"User not found": "No se encontró el usuario",
+
+	// Conflicts
+	"A list with this name already exists":                          "Ya existe una lista con este nombre",
+	"An open todo with this description already exists in the list": "Ya existe una tarea abierta con esta descripción en la lista",
+	"A user with this email already exists":                         "Ya existe un usuario con este correo electrónico",
+
+	// Authentication
+	"User not authenticated":              "Usuario no autenticado",
+	"Authorization header is required":    "Se requiere el encabezado Authorization",
+	"Invalid email or password":           "Correo electrónico o contraseña incorrectos",
+	"User account is inactive":            "La cuenta de usuario está inactiva",
+	"Password is incorrect":               "La contraseña es incorrecta",
+	"Current password is incorrect":       "La contraseña actual es incorrecta",
+	"Refresh token is invalid or expired": "El token de actualización no es válido o caducó",
+
+	// Server errors
+	"The database is busy. Please try again shortly.": "La base de datos está ocupada. Inténtalo de nuevo en unos momentos.",
+	"Failed to retrieve lists":                        "No se pudieron obtener las listas",
+	"Failed to retrieve todos":                        "No se pudieron obtener las tareas",
+	"Failed to create list":                           "No se pudo crear la lista",
+	"Failed to create todo":                           "No se pudo crear la tarea",
+	"Failed to update list":                           "No se pudo actualizar la lista",
+	"Failed to update todo":                           "No se pudo actualizar la tarea",
+	"Failed to delete list":                           "No se pudo eliminar la lista",
+	"Failed to delete todo":                           "No se pudo eliminar la tarea",
+}
@@ -0,0 +1,84 @@
+// Package i18n translates user-facing error messages. Messages are written
+// in English in the code and looked up by that English text in the catalog
+// of the requested locale; messages a catalog lacks stay in English. Error
+// codes are never translated.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale messages are written in
+const DefaultLocale = "en"
+
+// catalogs holds the translations of each supported locale other than the
+// default, keyed by the English message
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+}
+
+// Supported reports whether messages can be served in locale
+func Supported(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Locales returns the supported locales, the default first
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs)+1)
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return append([]string{DefaultLocale}, locales...)
+}
+
+// Translate returns message in locale, or message unchanged when the locale
+// has no translation for it
+func Translate(locale, message string) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Match returns the supported locale an Accept-Language header value prefers
+// most, or fallback when it names none. Language ranges match by their
+// primary subtag, so "es-MX" selects "es"; "*" and ranges with q=0 never
+// select a locale.
+func Match(acceptLanguage, fallback string) string {
+	best, bestQuality := fallback, 0.0
+	for _, element := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(element, ";")
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "-")
+		if !Supported(language) {
+			continue
+		}
+		if quality := languageQuality(params[1:]); quality > bestQuality {
+			best, bestQuality = language, quality
+		}
+	}
+	return best
+}
+
+// languageQuality returns the q parameter of a language range, 1 without one
+// and 0 when it is malformed
+func languageQuality(params []string) float64 {
+	for _, param := range params {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
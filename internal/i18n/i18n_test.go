@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"ES-ar", "es"},
+		{"en, es;q=0.9", "en"},
+		{"en;q=0.5, es", "es"},
+		{"fr, es;q=0.1", "es"},
+		{"fr, de", "en"},
+		{"*", "en"},
+		{"es;q=0", "en"},
+		{"es;q=bad", "en"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Match(tt.acceptLanguage, DefaultLocale), tt.acceptLanguage)
+	}
+
+	assert.Equal(t, "es", Match("fr", "es"))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "No se encontró la tarea solicitada", Translate("es", "The requested todo was not found"))
+	assert.Equal(t, "The requested todo was not found", Translate("en", "The requested todo was not found"))
+	assert.Equal(t, "Not in the catalog", Translate("es", "Not in the catalog"))
+	assert.Equal(t, "Not in the catalog", Translate("fr", "Not in the catalog"))
+}
+
+func TestLocales(t *testing.T) {
+	assert.Equal(t, []string{"en", "es"}, Locales())
+	assert.True(t, Supported("es"))
+	assert.False(t, Supported("fr"))
+}
@@ -0,0 +1,152 @@
+// Package ical renders todos with due dates as an RFC 5545 iCalendar feed,
+// one VTODO per todo. Like the export package it writes a page of todos at a
+// time so a large list streams.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todolist-api/internal/models"
+)
+
+// ContentType is the MIME type of an iCalendar feed
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	// productID identifies this service as the feed's producer (PRODID)
+	productID = "-//todolist-api//Todo List Calendar//EN"
+	// uidDomain makes todo UIDs globally unique, as RFC 5545 recommends
+	uidDomain = "todolist-api"
+	// maxLineOctets is the longest a content line may be before folding,
+	// excluding the CRLF
+	maxLineOctets = 75
+	// utcLayout is the RFC 5545 DATE-TIME form in UTC
+	utcLayout = "20060102T150405Z"
+)
+
+// icsPriorities maps todo priorities to the PRIORITY property, where 1 is the
+// highest, 5 medium and 9 the lowest
+var icsPriorities = map[models.Priority]string{
+	models.PriorityHigh:   "1",
+	models.PriorityMedium: "5",
+	models.PriorityLow:    "9",
+}
+
+// Encoder writes a VCALENDAR: Begin once with the list, WriteTodos for each
+// page of its todos, then End. Todos without a due date are left out.
+type Encoder struct {
+	w *bufio.Writer
+}
+
+// NewEncoder returns an encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Begin writes the calendar header, named after the list
+func (e *Encoder) Begin(list *models.TodoList) error {
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + productID)
+	e.line("CALSCALE:GREGORIAN")
+	e.line("X-WR-CALNAME:" + escapeText(list.Name))
+	return e.w.Flush()
+}
+
+// WriteTodos writes a VTODO for each todo with a due date
+func (e *Encoder) WriteTodos(todos []models.Todo) error {
+	for i := range todos {
+		if todos[i].DueDate != nil {
+			e.writeTodo(&todos[i])
+		}
+	}
+	return e.w.Flush()
+}
+
+// End closes the calendar
+func (e *Encoder) End() error {
+	e.line("END:VCALENDAR")
+	return e.w.Flush()
+}
+
+func (e *Encoder) writeTodo(todo *models.Todo) {
+	e.line("BEGIN:VTODO")
+	// The todo ID keeps the UID stable across fetches so clients dedupe
+	e.line("UID:" + todo.ID.String() + "@" + uidDomain)
+	e.line("DTSTAMP:" + formatUTC(todo.UpdatedAt))
+	e.line("CREATED:" + formatUTC(todo.CreatedAt))
+	e.line("LAST-MODIFIED:" + formatUTC(todo.UpdatedAt))
+	e.line("SUMMARY:" + escapeText(todo.Description))
+	e.line("DUE:" + formatUTC(*todo.DueDate))
+	if priority, ok := icsPriorities[todo.Priority]; ok {
+		e.line("PRIORITY:" + priority)
+	}
+	if len(todo.Tags) > 0 {
+		categories := make([]string, len(todo.Tags))
+		for i, tag := range todo.Tags {
+			categories[i] = escapeText(tag)
+		}
+		e.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if todo.Completed {
+		e.line("STATUS:COMPLETED")
+		if todo.CompletedAt != nil {
+			e.line("COMPLETED:" + formatUTC(*todo.CompletedAt))
+		}
+	} else {
+		e.line("STATUS:NEEDS-ACTION")
+	}
+	e.line("END:VTODO")
+}
+
+// line writes a content line folded to maxLineOctets and ended with CRLF.
+// Write errors are sticky in the bufio.Writer and reported by the next Flush.
+func (e *Encoder) line(content string) {
+	_, _ = e.w.WriteString(fold(content))
+}
+
+// fold splits a content line into lines of at most maxLineOctets octets,
+// continuing each with CRLF and a single space and never splitting a UTF-8
+// sequence, then ends it with CRLF (RFC 5545 section 3.1)
+func fold(content string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space of a continuation line counts toward its length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// textEscaper escapes a TEXT value: backslashes, semicolons, commas and
+// newlines (RFC 5545 section 3.3.11)
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatUTC formats t as an RFC 5545 UTC DATE-TIME
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
@@ -0,0 +1,124 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, list *models.TodoList, pages ...[]models.Todo) string {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	require.NoError(t, encoder.Begin(list))
+	for _, page := range pages {
+		require.NoError(t, encoder.WriteTodos(page))
+	}
+	require.NoError(t, encoder.End())
+	return buf.String()
+}
+
+// unfold reverses line folding, returning the logical content lines
+func unfold(feed string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(feed, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestEncoder(t *testing.T) {
+	list := &models.TodoList{ID: uuid.New(), Name: "Work; Q3, planning"}
+	local := time.FixedZone("UTC+2", 2*60*60)
+	created := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+	due := time.Date(2030, 2, 3, 11, 0, 0, 0, local)
+	completedAt := time.Date(2030, 2, 1, 8, 30, 0, 0, time.UTC)
+
+	open := models.Todo{
+		ID: uuid.New(), Description: "Draft plan, then review", Priority: models.PriorityHigh,
+		DueDate: &due, Tags: []string{"planning", "q3"}, CreatedAt: created, UpdatedAt: created,
+	}
+	done := models.Todo{
+		ID: uuid.New(), Description: "Book room", Priority: models.PriorityLow, DueDate: &due,
+		Completed: true, CompletedAt: &completedAt, CreatedAt: created, UpdatedAt: completedAt,
+	}
+	undated := models.Todo{ID: uuid.New(), Description: "Someday", Priority: models.PriorityMedium, CreatedAt: created}
+
+	t.Run("writes one VTODO per dated todo", func(t *testing.T) {
+		feed := encode(t, list, []models.Todo{open, undated}, []models.Todo{done})
+		lines := unfold(feed)
+
+		assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+		assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+		assert.Contains(t, lines, "VERSION:2.0")
+		assert.Contains(t, lines, `X-WR-CALNAME:Work\; Q3\, planning`)
+		assert.Equal(t, 2, strings.Count(feed, "BEGIN:VTODO\r\n"))
+		assert.NotContains(t, feed, "Someday")
+
+		assert.Contains(t, lines, "UID:"+open.ID.String()+"@todolist-api")
+		assert.Contains(t, lines, `SUMMARY:Draft plan\, then review`)
+		assert.Contains(t, lines, "DUE:20300203T090000Z")
+		assert.Contains(t, lines, "DTSTAMP:20300102T150405Z")
+		assert.Contains(t, lines, "PRIORITY:1")
+		assert.Contains(t, lines, "CATEGORIES:planning,q3")
+		assert.Contains(t, lines, "STATUS:NEEDS-ACTION")
+
+		assert.Contains(t, lines, "PRIORITY:9")
+		assert.Contains(t, lines, "STATUS:COMPLETED")
+		assert.Contains(t, lines, "COMPLETED:20300201T083000Z")
+	})
+
+	t.Run("uses CRLF line endings", func(t *testing.T) {
+		feed := encode(t, list, []models.Todo{open})
+		assert.True(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+		assert.NotContains(t, strings.ReplaceAll(feed, "\r\n", ""), "\n")
+	})
+
+	t.Run("keeps UIDs stable across encodes", func(t *testing.T) {
+		first := encode(t, list, []models.Todo{open})
+		second := encode(t, list, []models.Todo{open})
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("escapes newlines and backslashes in text", func(t *testing.T) {
+		todo := open
+		todo.Description = "Line one\nC:\\path"
+		lines := unfold(encode(t, list, []models.Todo{todo}))
+		assert.Contains(t, lines, `SUMMARY:Line one\nC:\\path`)
+	})
+}
+
+func TestFold(t *testing.T) {
+	t.Run("leaves short lines alone", func(t *testing.T) {
+		assert.Equal(t, "SUMMARY:Short\r\n", fold("SUMMARY:Short"))
+	})
+
+	t.Run("folds long lines at 75 octets", func(t *testing.T) {
+		content := "SUMMARY:" + strings.Repeat("a", 200)
+		folded := fold(content)
+
+		physical := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+		require.Greater(t, len(physical), 1)
+		for i, line := range physical {
+			assert.LessOrEqual(t, len(line), 75)
+			if i > 0 {
+				assert.True(t, strings.HasPrefix(line, " "))
+			}
+		}
+		assert.Equal(t, content, strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", ""))
+	})
+
+	t.Run("never splits a multi-byte character", func(t *testing.T) {
+		content := "SUMMARY:" + strings.Repeat("é", 100)
+		folded := fold(content)
+
+		for _, line := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+			assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %q splits a character", line)
+		}
+		assert.Equal(t, content, strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", ""))
+	})
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccountChecker looks up the current state of an account, which may have
+// changed since its access token was issued
+type AccountChecker interface {
+	// AccountStatus returns whether the user may still use the API and their
+	// current role. Unknown users are reported as inactive.
+	AccountStatus(userID uuid.UUID) (active bool, role models.UserRole, err error)
+}
+
+// RequireActiveAccount rejects requests with 403 USER_INACTIVE from users who
+// have been deactivated or deleted since their access token was issued, and
+// replaces the role from the token with the user's current role, so that role
+// changes apply immediately. It must run after AuthMiddleware; with no checker
+// (in-memory mode) it lets every request through.
+func RequireActiveAccount(checker AccountChecker) gin.HandlerFunc {
+	if checker == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		userID, err := GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    "UNAUTHORIZED",
+				Message: "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		active, role, err := checker.AccountStatus(userID)
+		if err != nil {
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to check account status",
+			})
+			c.Abort()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    "USER_INACTIVE",
+				Message: "User account is inactive",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextKeyUserRole, role)
+		c.Next()
+	}
+}
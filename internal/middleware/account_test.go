@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAccounts reports the users in roles as active with that role, failing
+// with err if set
+type fakeAccounts struct {
+	roles map[uuid.UUID]models.UserRole
+	err   error
+}
+
+func (f *fakeAccounts) AccountStatus(userID uuid.UUID) (bool, models.UserRole, error) {
+	role, ok := f.roles[userID]
+	return ok, role, f.err
+}
+
+func TestRequireActiveAccount(t *testing.T) {
+	setupTest()
+
+	admin, user, deactivated := uuid.New(), uuid.New(), uuid.New()
+	accounts := &fakeAccounts{roles: map[uuid.UUID]models.UserRole{admin: models.RoleAdmin, user: models.RoleUser}}
+
+	// serve authenticates every request as userID with the role from its token
+	serve := func(checker AccountChecker, userID uuid.UUID, tokenRole models.UserRole) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/api/v1/admin/users", func(c *gin.Context) {
+			c.Set(ContextKeyUserID, userID)
+			c.Set(ContextKeyUserRole, tokenRole)
+			c.Next()
+		}, RequireActiveAccount(checker), AdminMiddleware(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/users", http.NoBody))
+		return w
+	}
+
+	t.Run("lets active admins through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(accounts, admin, models.RoleAdmin).Code)
+	})
+
+	t.Run("forbids non-admins", func(t *testing.T) {
+		w := serve(accounts, user, models.RoleUser)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"FORBIDDEN"`)
+	})
+
+	t.Run("uses the current role rather than the token's", func(t *testing.T) {
+		w := serve(accounts, user, models.RoleAdmin)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"FORBIDDEN"`)
+	})
+
+	t.Run("rejects deactivated users", func(t *testing.T) {
+		w := serve(accounts, deactivated, models.RoleAdmin)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"USER_INACTIVE"`)
+	})
+
+	t.Run("reports lookup failures", func(t *testing.T) {
+		failing := &fakeAccounts{err: assert.AnError}
+		assert.Equal(t, http.StatusInternalServerError, serve(failing, admin, models.RoleAdmin).Code)
+	})
+
+	t.Run("trusts the token without a checker", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(nil, deactivated, models.RoleAdmin).Code)
+		assert.Equal(t, http.StatusForbidden, serve(nil, deactivated, models.RoleUser).Code)
+	})
+}
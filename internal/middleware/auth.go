@@ -18,6 +18,8 @@ const (
 	ContextKeyUserEmail = "user_email"
 	// ContextKeyUserRole is the context key for storing user role
 	ContextKeyUserRole = "user_role"
+	// ContextKeySessionID is the context key for storing the session (refresh token) ID
+	ContextKeySessionID = "session_id"
 )
 
 // AuthMiddleware creates a middleware that validates JWT tokens
@@ -67,11 +69,19 @@ func AuthMiddleware(jwtConfig *auth.JWTConfig) gin.HandlerFunc {
 		c.Set(ContextKeyUserID, claims.UserID)
 		c.Set(ContextKeyUserEmail, claims.Email)
 		c.Set(ContextKeyUserRole, claims.Role)
+		c.Set(ContextKeySessionID, claims.SessionID)
 
 		c.Next()
 	}
 }
 
+// AdminMiddleware rejects requests from users who are not admins with 403
+// FORBIDDEN. It must run after AuthMiddleware, and after RequireActiveAccount
+// so that a demoted admin's token no longer grants access.
+func AdminMiddleware() gin.HandlerFunc {
+	return RequireRole(models.RoleAdmin)
+}
+
 // RequireRole creates a middleware that requires a specific role
 func RequireRole(requiredRole models.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -146,6 +156,7 @@ func OptionalAuth(jwtConfig *auth.JWTConfig) gin.HandlerFunc {
 		c.Set(ContextKeyUserID, claims.UserID)
 		c.Set(ContextKeyUserEmail, claims.Email)
 		c.Set(ContextKeyUserRole, claims.Role)
+		c.Set(ContextKeySessionID, claims.SessionID)
 
 		c.Next()
 	}
@@ -198,6 +209,22 @@ func GetUserRole(c *gin.Context) (models.UserRole, error) {
 	return roleValue, nil
 }
 
+// GetSessionID retrieves the ID of the session the request's access token
+// belongs to, or uuid.Nil if it is not known
+func GetSessionID(c *gin.Context) uuid.UUID {
+	sessionID, exists := c.Get(ContextKeySessionID)
+	if !exists {
+		return uuid.Nil
+	}
+
+	id, ok := sessionID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+
+	return id
+}
+
 // IsAuthenticated checks if the current request is authenticated
 func IsAuthenticated(c *gin.Context) bool {
 	_, exists := c.Get(ContextKeyUserID)
@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressionEncodings are the content codings Compression can produce, in
+// order of preference when the client accepts several
+var compressionEncodings = []string{"gzip", "deflate"}
+
+// CompressionConfig holds response compression configuration
+type CompressionConfig struct {
+	Enabled      bool     // Compress responses for clients that send Accept-Encoding
+	MinSize      int      // Smallest response body in bytes worth compressing
+	ContentTypes []string // Media types that are compressed; others are sent as is
+}
+
+// NewCompressionConfigFromEnv creates response compression config from
+// environment variables
+func NewCompressionConfigFromEnv() *CompressionConfig {
+	return &CompressionConfig{
+		Enabled:      getEnvBool("COMPRESSION_ENABLED", true),
+		MinSize:      getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		ContentTypes: parseCommaSeparated(getEnv("COMPRESSION_CONTENT_TYPES", mimeJSON)),
+	}
+}
+
+// allowsContentType reports whether a Content-Type header value names one of
+// the media types configured for compression
+func (config *CompressionConfig) allowsContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, allowed := range config.ContentTypes {
+		if strings.EqualFold(allowed, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compression gzip- or deflate-encodes responses for clients whose
+// Accept-Encoding allows it. Only bodies of at least MinSize bytes with an
+// allowed Content-Type are compressed; responses that set their own
+// Content-Encoding pass through untouched. Compressed responses drop
+// Content-Length, since it would describe the uncompressed body.
+func Compression(config *CompressionConfig) gin.HandlerFunc {
+	// If compression is disabled, return a no-op middleware
+	if !config.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, config: config, encoding: encoding}
+		c.Writer = writer
+		defer func() {
+			if err := writer.close(); err != nil {
+				_ = c.Error(err)
+			}
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the preferred content coding the Accept-Encoding
+// header allows, or "" if it allows none
+func negotiateEncoding(acceptEncoding string) string {
+	for _, encoding := range compressionEncodings {
+		if acceptsEncoding(acceptEncoding, encoding) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header value allows
+// encoding, either by name or through "*". A named entry takes precedence
+// over "*", and either is refused with q=0.
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	wildcard := false
+	for _, element := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(element, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		switch coding {
+		case encoding:
+			return !hasZeroQuality(params[1:])
+		case "*":
+			wildcard = !hasZeroQuality(params[1:])
+		}
+	}
+	return wildcard
+}
+
+// encoder is a compressing writer that can push out what it has buffered
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter buffers the start of a response body until it knows whether
+// the response is worth compressing, then streams the rest through the
+// encoder or straight to the client
+type compressWriter struct {
+	gin.ResponseWriter
+	config   *CompressionConfig
+	encoding string
+	buf      bytes.Buffer
+	encoder  encoder
+	started  bool
+}
+
+// compressible reports whether the headers written so far allow compressing
+// the response
+func (w *compressWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	return w.config.allowsContentType(w.Header().Get("Content-Type"))
+}
+
+// start commits to sending the response compressed or as is, and writes out
+// what has been buffered so far
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	var out io.Writer = w.ResponseWriter
+	if compress {
+		w.Header().Set("Content-Encoding", w.encoding)
+		w.Header().Del("Content-Length")
+		if w.encoding == "gzip" {
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			w.encoder = zlib.NewWriter(w.ResponseWriter)
+		}
+		out = w.encoder
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := out.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// Write buffers the body until it reaches MinSize, so small responses are
+// sent uncompressed
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		if w.compressible() {
+			w.buf.Write(data)
+			if w.buf.Len() < w.config.MinSize {
+				return len(data), nil
+			}
+			return len(data), w.start(true)
+		}
+		if err := w.start(false); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes s through Write
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether the body has started, including buffered bytes
+func (w *compressWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what has been written so far. A streamed response has no known
+// size, so it is compressed whenever its Content-Type allows.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		if err := w.start(w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close writes out a body that stayed under MinSize and finishes the
+// compressed stream
+func (w *compressWriter) close() error {
+	if !w.started && w.buf.Len() > 0 {
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+	return nil
+}
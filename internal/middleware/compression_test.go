@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompressionConfigFromEnv(t *testing.T) {
+	setupTest()
+
+	t.Run("enabled for JSON by default", func(t *testing.T) {
+		os.Unsetenv("COMPRESSION_ENABLED")
+		os.Unsetenv("COMPRESSION_MIN_SIZE")
+		os.Unsetenv("COMPRESSION_CONTENT_TYPES")
+
+		config := NewCompressionConfigFromEnv()
+		assert.True(t, config.Enabled)
+		assert.Equal(t, 1024, config.MinSize)
+		assert.Equal(t, []string{"application/json"}, config.ContentTypes)
+	})
+
+	t.Run("reads environment", func(t *testing.T) {
+		t.Setenv("COMPRESSION_ENABLED", "false")
+		t.Setenv("COMPRESSION_MIN_SIZE", "256")
+		t.Setenv("COMPRESSION_CONTENT_TYPES", "application/json, text/csv")
+
+		config := NewCompressionConfigFromEnv()
+		assert.False(t, config.Enabled)
+		assert.Equal(t, 256, config.MinSize)
+		assert.Equal(t, []string{"application/json", "text/csv"}, config.ContentTypes)
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip;q=0.5", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"*", "gzip"},
+		{"*;q=0", ""},
+		{"gzip;q=0, *", "deflate"},
+		{"br, identity", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, negotiateEncoding(tt.acceptEncoding), tt.acceptEncoding)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	setupTest()
+
+	large := strings.Repeat("todo ", 500)
+	config := &CompressionConfig{Enabled: true, MinSize: 1024, ContentTypes: []string{"application/json"}}
+
+	newRouter := func(config *CompressionConfig) *gin.Engine {
+		router := gin.New()
+		router.Use(SecurityHeaders(), Compression(config))
+		router.GET("/large", func(c *gin.Context) {
+			c.Header("Content-Length", strconv.Itoa(len(large)+2))
+			c.JSON(http.StatusOK, large)
+		})
+		router.GET("/small", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+		router.GET("/csv", func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/csv", []byte(large))
+		})
+		router.GET("/encoded", func(c *gin.Context) {
+			c.Header("Content-Encoding", "br")
+			c.Data(http.StatusOK, "application/json", []byte(large))
+		})
+		router.GET("/stream", func(c *gin.Context) {
+			c.Header("Content-Type", "application/json")
+			c.Status(http.StatusOK)
+			for _, chunk := range []string{"[", `"a"`, ",", `"b"`, "]"} {
+				_, _ = c.Writer.WriteString(chunk)
+				c.Writer.Flush()
+			}
+		})
+		router.GET("/empty", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+
+	serve := func(router *gin.Engine, method, path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, http.NoBody)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		var reader io.Reader
+		var err error
+		switch w.Header().Get("Content-Encoding") {
+		case "gzip":
+			reader, err = gzip.NewReader(w.Body)
+		case "deflate":
+			reader, err = zlib.NewReader(w.Body)
+		default:
+			return w.Body.String()
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	router := newRouter(config)
+
+	t.Run("compresses large JSON responses", func(t *testing.T) {
+		for _, encoding := range []string{"gzip", "deflate"} {
+			w := serve(router, "GET", "/large", encoding)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+			assert.Empty(t, w.Header().Get("Content-Length"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+			assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			assert.Less(t, w.Body.Len(), len(large))
+			assert.Equal(t, `"`+large+`"`, decode(t, w))
+		}
+	})
+
+	t.Run("sends responses as is when not worth compressing", func(t *testing.T) {
+		w := serve(router, "GET", "/large", "")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, strconv.Itoa(len(large)+2), w.Header().Get("Content-Length"))
+		assert.Equal(t, `"`+large+`"`, w.Body.String())
+
+		w = serve(router, "GET", "/small", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+
+		w = serve(router, "GET", "/csv", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+
+		w = serve(router, "GET", "/encoded", "gzip")
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+
+		w = serve(router, "GET", "/empty", "gzip")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Zero(t, w.Body.Len())
+
+		w = serve(router, "HEAD", "/large", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("compresses streamed responses as they flush", func(t *testing.T) {
+		w := serve(router, "GET", "/stream", "gzip")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, `["a","b"]`, decode(t, w))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := &CompressionConfig{Enabled: false, MinSize: 1024, ContentTypes: []string{"application/json"}}
+		w := serve(newRouter(disabled), "GET", "/large", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Header().Values("Vary"))
+		assert.Equal(t, `"`+large+`"`, w.Body.String())
+	})
+}
@@ -273,10 +273,10 @@ func TestNewCORSConfigFromEnv(t *testing.T) {
 			name:                 "returns default values when no env vars set",
 			envVars:              map[string]string{},
 			expectedEnabled:      true,
-			expectedOriginsCount: 1, // ["*"]
-			expectedMethodsCount: 6, // GET,POST,PUT,DELETE,OPTIONS,PATCH
-			expectedHeadersCount: 5, // Origin,Content-Type,Accept,Authorization,X-API-Key
-			expectedExposeCount:  2, // Content-Length,Content-Type
+			expectedOriginsCount: 1,  // ["*"]
+			expectedMethodsCount: 6,  // GET,POST,PUT,DELETE,OPTIONS,PATCH
+			expectedHeadersCount: 10, // Origin,Content-Type,Accept,Authorization,X-API-Key,If-Match,If-None-Match,Prefer and the ID headers
+			expectedExposeCount:  9,  // Content-Length,Content-Type,Location,ETag,X-Request-ID,Idempotent-Replayed and the three X-RateLimit headers
 			expectedAllowCred:    false,
 			expectedMaxAge:       3600,
 		},
@@ -307,8 +307,8 @@ func TestNewCORSConfigFromEnv(t *testing.T) {
 			expectedEnabled:      false,
 			expectedOriginsCount: 1,
 			expectedMethodsCount: 6,
-			expectedHeadersCount: 5,
-			expectedExposeCount:  2,
+			expectedHeadersCount: 10,
+			expectedExposeCount:  9,
 			expectedAllowCred:    false,
 			expectedMaxAge:       3600,
 		},
@@ -320,8 +320,8 @@ func TestNewCORSConfigFromEnv(t *testing.T) {
 			expectedEnabled:      true,
 			expectedOriginsCount: 1,
 			expectedMethodsCount: 6,
-			expectedHeadersCount: 5,
-			expectedExposeCount:  2,
+			expectedHeadersCount: 10,
+			expectedExposeCount:  9,
 			expectedAllowCred:    false,
 			expectedMaxAge:       3600,
 		},
@@ -333,8 +333,8 @@ func TestNewCORSConfigFromEnv(t *testing.T) {
 			expectedEnabled:      true,
 			expectedOriginsCount: 3,
 			expectedMethodsCount: 6,
-			expectedHeadersCount: 5,
-			expectedExposeCount:  2,
+			expectedHeadersCount: 10,
+			expectedExposeCount:  9,
 			expectedAllowCred:    false,
 			expectedMaxAge:       3600,
 		},
@@ -346,6 +346,7 @@ func TestNewCORSConfigFromEnv(t *testing.T) {
 			envKeys := []string{
 				"CORS_ENABLED",
 				"CORS_ALLOWED_ORIGINS",
+				"CORS_ALLOWED_ORIGINS_FILE",
 				"CORS_ALLOWED_METHODS",
 				"CORS_ALLOWED_HEADERS",
 				"CORS_EXPOSE_HEADERS",
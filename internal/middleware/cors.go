@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"os"
 	"strings"
 
 	"todolist-api/internal/logging"
@@ -24,25 +25,37 @@ type CORSConfig struct {
 func NewCORSConfigFromEnv() *CORSConfig {
 	enabled := getEnvBool("CORS_ENABLED", true)
 
-	// Parse allowed origins
-	originsStr := getEnv("CORS_ALLOWED_ORIGINS", "*")
+	// Parse allowed origins. With an origins file the default is no origins
+	// from the environment rather than "*", which would override the file.
+	originsFile := getEnv("CORS_ALLOWED_ORIGINS_FILE", "")
+	defaultOrigins := "*"
+	if originsFile != "" {
+		defaultOrigins = ""
+	}
+	originsStr := getEnv("CORS_ALLOWED_ORIGINS", defaultOrigins)
 	var origins []string
 	if originsStr == "*" {
 		origins = []string{"*"}
 	} else {
 		origins = parseCommaSeparated(originsStr)
 	}
+	if originsFile != "" {
+		origins = mergeOrigins(origins, loadOriginsFile(originsFile))
+	}
 
 	// Parse allowed methods
 	methodsStr := getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS,PATCH")
 	methods := parseCommaSeparated(methodsStr)
 
 	// Parse allowed headers
-	headersStr := getEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-API-Key")
+	headersStr := getEnv("CORS_ALLOWED_HEADERS",
+		"Origin,Content-Type,Accept,Authorization,X-API-Key,If-Match,If-None-Match,Prefer,"+RequestIDHeader+","+IdempotencyKeyHeader)
 	headers := parseCommaSeparated(headersStr)
 
 	// Parse expose headers
-	exposeStr := getEnv("CORS_EXPOSE_HEADERS", "Content-Length,Content-Type")
+	exposeStr := getEnv("CORS_EXPOSE_HEADERS",
+		"Content-Length,Content-Type,Location,ETag,"+RequestIDHeader+","+IdempotentReplayedHeader+","+
+			HeaderRateLimitLimit+","+HeaderRateLimitRemaining+","+HeaderRateLimitReset)
 	expose := parseCommaSeparated(exposeStr)
 
 	allowCredentials := getEnvBool("CORS_ALLOW_CREDENTIALS", false)
@@ -75,6 +88,45 @@ func parseCommaSeparated(s string) []string {
 	return result
 }
 
+// loadOriginsFile reads newline-delimited origins from path, skipping blank
+// lines and # comments. An unreadable file is logged and contributes no
+// origins, so a broken deployment denies cross-origin requests rather than
+// allowing them all.
+func loadOriginsFile(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logging.Logger.WithFields(map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		}).Error("Failed to read CORS allowed origins file")
+		return nil
+	}
+
+	var origins []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			origins = append(origins, line)
+		}
+	}
+	return origins
+}
+
+// mergeOrigins appends the origins in extra that are not already in origins
+func mergeOrigins(origins, extra []string) []string {
+	seen := make(map[string]bool, len(origins)+len(extra))
+	for _, origin := range origins {
+		seen[origin] = true
+	}
+	for _, origin := range extra {
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
 // CORS middleware handles Cross-Origin Resource Sharing
 func CORS(config *CORSConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -3,6 +3,8 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -177,6 +179,66 @@ func TestCORS(t *testing.T) {
 	})
 }
 
+func TestCORSOriginsFile(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	writeOrigins := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "origins.txt")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	allowedOrigin := func(config *CORSConfig, origin string) string {
+		router := gin.New()
+		router.Use(CORS(config))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/test", http.NoBody)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	t.Run("merges file origins with the env list", func(t *testing.T) {
+		path := writeOrigins(t, "# Partner sites\nhttps://partner.example.com\r\n\n  https://app.example.com  \nhttps://env.example.com\n")
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://env.example.com")
+		t.Setenv("CORS_ALLOWED_ORIGINS_FILE", path)
+
+		config := NewCORSConfigFromEnv()
+
+		assert.Equal(t, []string{"https://env.example.com", "https://partner.example.com", "https://app.example.com"}, config.AllowedOrigins)
+		assert.Equal(t, "https://partner.example.com", allowedOrigin(config, "https://partner.example.com"))
+		assert.Equal(t, "https://env.example.com", allowedOrigin(config, "https://env.example.com"))
+		assert.Empty(t, allowedOrigin(config, "https://evil.example.com"))
+	})
+
+	t.Run("file alone does not fall back to wildcard", func(t *testing.T) {
+		path := writeOrigins(t, "https://partner.example.com\n")
+		t.Setenv("CORS_ALLOWED_ORIGINS_FILE", path)
+
+		config := NewCORSConfigFromEnv()
+
+		assert.Equal(t, []string{"https://partner.example.com"}, config.AllowedOrigins)
+		assert.Empty(t, allowedOrigin(config, "https://other.example.com"))
+	})
+
+	t.Run("unreadable file adds no origins", func(t *testing.T) {
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://env.example.com")
+		t.Setenv("CORS_ALLOWED_ORIGINS_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+
+		config := NewCORSConfigFromEnv()
+
+		assert.Equal(t, []string{"https://env.example.com"}, config.AllowedOrigins)
+	})
+}
+
 func TestIsOriginAllowed(t *testing.T) {
 	setupTest()
 	tests := []struct {
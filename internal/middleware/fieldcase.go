@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// FieldCaseCamel serializes JSON response fields in camelCase (default)
+	FieldCaseCamel = "camel"
+	// FieldCaseSnake serializes JSON response fields in snake_case
+	FieldCaseSnake = "snake"
+
+	// ContextKeyJSONFieldCase is the context key for the response field naming
+	ContextKeyJSONFieldCase = "json_field_case"
+)
+
+// NewJSONFieldCaseFromEnv reads JSON_FIELD_CASE, falling back to camelCase
+// for unset or unrecognized values
+func NewJSONFieldCaseFromEnv() string {
+	if strings.EqualFold(getEnv("JSON_FIELD_CASE", FieldCaseCamel), FieldCaseSnake) {
+		return FieldCaseSnake
+	}
+	return FieldCaseCamel
+}
+
+// JSONFieldCase records the response field naming in the request context
+// so response helpers can serialize accordingly
+func JSONFieldCase(fieldCase string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ContextKeyJSONFieldCase, fieldCase)
+		c.Next()
+	}
+}
+
+// GetJSONFieldCase returns the response field naming for the request
+func GetJSONFieldCase(c *gin.Context) string {
+	if fieldCase := c.GetString(ContextKeyJSONFieldCase); fieldCase != "" {
+		return fieldCase
+	}
+	return FieldCaseCamel
+}
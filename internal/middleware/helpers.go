@@ -3,6 +3,9 @@ package middleware
 import (
 	"os"
 	"strconv"
+	"time"
+
+	"todolist-api/internal/logging"
 )
 
 // getEnv retrieves an environment variable or returns a default value
@@ -32,3 +35,25 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvDuration retrieves a duration environment variable (e.g. "2h") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvPositiveDuration retrieves a duration environment variable that must be
+// positive, such as a ticker interval, falling back to the default with a
+// warning when it is zero or negative
+func getEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnvDuration(key, defaultValue)
+	if value <= 0 {
+		logging.Logger.Warnf("%s must be positive, got %s; using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return value
+}
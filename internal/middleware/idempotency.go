@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a create request it
+	// may retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed for a repeated key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength caps keys; UUIDs and similar tokens fit easily
+	maxIdempotencyKeyLength = 255
+	// idempotencyLease is how long a key stays reserved for a request still in
+	// progress, so a crashed request does not block retries until the TTL
+	idempotencyLease = time.Minute
+)
+
+// Idempotency store types
+const (
+	IdempotencyStoreMemory   = "memory"
+	IdempotencyStorePostgres = "postgres"
+)
+
+// ErrIdempotencyKeyInUse is returned by IdempotencyStore.Reserve while
+// another request holds the key
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use by another request")
+
+// IdempotencyConfig holds Idempotency-Key handling configuration
+type IdempotencyConfig struct {
+	Enabled bool          // Honor the Idempotency-Key header on create endpoints
+	TTL     time.Duration // How long a completed request's response is replayed
+	Store   string        // "memory" (the default) or "postgres" to share keys between replicas
+	// PurgeInterval is how often expired keys are deleted from the store
+	PurgeInterval time.Duration
+}
+
+// NewIdempotencyConfigFromEnv creates Idempotency-Key config from environment
+// variables
+func NewIdempotencyConfigFromEnv() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		Enabled: getEnvBool("IDEMPOTENCY_ENABLED", true),
+		TTL:     getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		Store:   getEnv("IDEMPOTENCY_STORE", IdempotencyStoreMemory),
+
+		PurgeInterval: getEnvPositiveDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+	}
+}
+
+// IdempotentResponse is the stored response to a request with an
+// Idempotency-Key
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Location    string
+	Body        []byte
+}
+
+// IdempotencyStore keeps the responses to requests sent with an
+// Idempotency-Key. Keys arrive already scoped to the user and route.
+type IdempotencyStore interface {
+	// Reserve claims key for a request until lease passes. It returns the
+	// stored response when a request with the key has completed,
+	// ErrIdempotencyKeyInUse while another request holds it, and nil, nil
+	// once the caller holds it. Only one of several concurrent callers can
+	// hold a key.
+	Reserve(key string, userID uuid.UUID, lease time.Duration) (*IdempotentResponse, error)
+	// Complete stores the response for a reserved key, replayed until ttl passes
+	Complete(key string, response *IdempotentResponse, ttl time.Duration) error
+	// Release drops a reservation that did not complete, so the key can be retried
+	Release(key string) error
+	// PurgeExpired deletes keys whose lease or TTL passed before now and
+	// returns how many were deleted
+	PurgeExpired(now time.Time) (int, error)
+}
+
+// NewIdempotencyStore creates the store named by the config. The Postgres
+// store needs a database; without one, or for an unknown store name, the
+// memory store is used.
+func NewIdempotencyStore(config *IdempotencyConfig, db *gorm.DB) IdempotencyStore {
+	switch config.Store {
+	case IdempotencyStorePostgres:
+		if db != nil {
+			return NewPostgresIdempotencyStore(db)
+		}
+		logging.Logger.Warn("IDEMPOTENCY_STORE=postgres needs PostgreSQL storage, using the memory store")
+	case IdempotencyStoreMemory, "":
+	default:
+		logging.Logger.Warnf("Unknown IDEMPOTENCY_STORE %q, using the memory store", config.Store)
+	}
+	return NewMemoryIdempotencyStore()
+}
+
+// Idempotency makes create endpoints safe to retry. A request with an
+// Idempotency-Key header reserves the key for its user, method and path; a
+// repeat of the key gets the first response back, marked with
+// Idempotent-Replayed, instead of running the handler again, and a repeat
+// arriving while the first is still running gets 409 IDEMPOTENCY_KEY_IN_USE.
+// Server errors (5xx) are not stored, so the request can be retried. Requests
+// without the header are not affected.
+func Idempotency(config *IdempotencyConfig, store IdempotencyStore) gin.HandlerFunc {
+	// If idempotency keys are disabled, return a no-op middleware
+	if !config.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    "INVALID_IDEMPOTENCY_KEY",
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		userID := GetUserIDOrDefault(c)
+		scoped := scopeIdempotencyKey(userID, c.Request.Method, c.Request.URL.Path, key)
+		stored, err := store.Reserve(scoped, userID, idempotencyLease)
+		switch {
+		case errors.Is(err, ErrIdempotencyKeyInUse):
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    "IDEMPOTENCY_KEY_IN_USE",
+				Message: "A request with this Idempotency-Key is still in progress",
+			})
+			c.Abort()
+			return
+		case err != nil:
+			// Fail open: serving the request beats rejecting it because the
+			// key store is unavailable
+			logging.Logger.WithError(err).Warn("Idempotency store error, handling request without it")
+			c.Next()
+			return
+		case stored != nil:
+			replayIdempotentResponse(c, stored)
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			if !completed {
+				if releaseErr := store.Release(scoped); releaseErr != nil {
+					logging.Logger.WithError(releaseErr).Warn("Failed to release idempotency key")
+				}
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		err = store.Complete(scoped, &IdempotentResponse{
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Location:    recorder.Header().Get("Location"),
+			Body:        recorder.body.Bytes(),
+		}, config.TTL)
+		if err != nil {
+			logging.Logger.WithError(err).Warn("Failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+// scopeIdempotencyKey hashes a client key together with its user and route,
+// so the same key sent by another user or to another endpoint is unrelated
+func scopeIdempotencyKey(userID uuid.UUID, method, path, key string) string {
+	sum := sha256.Sum256([]byte(userID.String() + "\n" + method + "\n" + path + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// replayIdempotentResponse answers a repeated request with the stored response
+func replayIdempotentResponse(c *gin.Context, stored *IdempotentResponse) {
+	if stored.Location != "" {
+		c.Header("Location", stored.Location)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	if len(stored.Body) == 0 {
+		c.Status(stored.Status)
+	} else {
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+	}
+	c.Abort()
+}
+
+// idempotencyRecorder copies the response body as it is written so it can be
+// stored for replay
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write records data and passes it on
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString records s and passes it on
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
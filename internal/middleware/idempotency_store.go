@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MemoryIdempotencyStore keeps idempotency keys in process memory, so they
+// are lost on restart and not shared between replicas
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+// idempotencyEntry is a reserved key, pending until response is set
+type idempotencyEntry struct {
+	response  *IdempotentResponse
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory idempotency store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]*idempotencyEntry), now: time.Now}
+}
+
+// Reserve claims key, treating an expired entry for it as absent
+func (s *MemoryIdempotencyStore) Reserve(key string, _ uuid.UUID, lease time.Duration) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok && entry.expiresAt.After(now) {
+		if entry.response == nil {
+			return nil, ErrIdempotencyKeyInUse
+		}
+		return entry.response, nil
+	}
+	s.entries[key] = &idempotencyEntry{expiresAt: now.Add(lease)}
+	return nil, nil
+}
+
+// Complete stores the response for key
+func (s *MemoryIdempotencyStore) Complete(key string, response *IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &idempotencyEntry{response: response, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Release drops key if it is still pending
+func (s *MemoryIdempotencyStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.response == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// PurgeExpired drops keys that expired before now
+func (s *MemoryIdempotencyStore) PurgeExpired(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for k, entry := range s.entries {
+		if !entry.expiresAt.After(now) {
+			delete(s.entries, k)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// PostgresIdempotencyStore keeps idempotency keys in the idempotency_keys
+// table, shared between replicas. The primary key on the scoped key hash
+// lets only one concurrent request insert a reservation.
+type PostgresIdempotencyStore struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// NewPostgresIdempotencyStore creates an idempotency store on db
+func NewPostgresIdempotencyStore(db *gorm.DB) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{db: db, now: time.Now}
+}
+
+// Reserve claims key by inserting a pending record, or by taking over the
+// record for key when it has expired
+func (s *PostgresIdempotencyStore) Reserve(key string, userID uuid.UUID, lease time.Duration) (*IdempotentResponse, error) {
+	now := s.now()
+	result := s.db.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(&models.IdempotencyKey{
+		KeyHash:   key,
+		UserID:    userID,
+		ExpiresAt: now.Add(lease),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	// The conditional update lets only one of several concurrent callers
+	// take over an expired record
+	result = s.db.Model(&models.IdempotencyKey{}).
+		Where("key_hash = ? AND expires_at <= ?", key, now).
+		Updates(map[string]interface{}{
+			"user_id":      userID,
+			"completed":    false,
+			"status_code":  0,
+			"content_type": "",
+			"location":     "",
+			"body":         nil,
+			"expires_at":   now.Add(lease),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var record models.IdempotencyKey
+	if err := s.db.Where("key_hash = ?", key).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released since the insert; let the client retry
+			return nil, ErrIdempotencyKeyInUse
+		}
+		return nil, err
+	}
+	if !record.Completed {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	return &IdempotentResponse{
+		Status:      record.StatusCode,
+		ContentType: record.ContentType,
+		Location:    record.Location,
+		Body:        record.Body,
+	}, nil
+}
+
+// Complete stores the response on the pending record for key
+func (s *PostgresIdempotencyStore) Complete(key string, response *IdempotentResponse, ttl time.Duration) error {
+	return s.db.Model(&models.IdempotencyKey{}).Where("key_hash = ?", key).Updates(map[string]interface{}{
+		"completed":    true,
+		"status_code":  response.Status,
+		"content_type": response.ContentType,
+		"location":     response.Location,
+		"body":         response.Body,
+		"expires_at":   s.now().Add(ttl),
+	}).Error
+}
+
+// Release deletes the record for key if it is still pending
+func (s *PostgresIdempotencyStore) Release(key string) error {
+	return s.db.Where("key_hash = ? AND completed = ?", key, false).Delete(&models.IdempotencyKey{}).Error
+}
+
+// PurgeExpired deletes records that expired before now
+func (s *PostgresIdempotencyStore) PurgeExpired(now time.Time) (int, error) {
+	result := s.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return int(result.RowsAffected), result.Error
+}
+
+// IdempotencyPurger periodically deletes expired idempotency keys, so stores
+// only check the key they are asked about on each request
+type IdempotencyPurger struct {
+	store    IdempotencyStore
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewIdempotencyPurger creates a purger that removes expired keys from store
+// every interval
+func NewIdempotencyPurger(store IdempotencyStore, interval time.Duration) *IdempotencyPurger {
+	return &IdempotencyPurger{
+		store:    store,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the purge loop in the background until Stop is called
+func (p *IdempotencyPurger) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.purge(now)
+			}
+		}
+	}()
+}
+
+// Stop ends the purge loop, waiting for a purge in progress to finish
+func (p *IdempotencyPurger) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// purge deletes keys that expired before now
+func (p *IdempotencyPurger) purge(now time.Time) {
+	purged, err := p.store.PurgeExpired(now)
+	if err != nil {
+		logging.Logger.Errorf("Idempotency key purge failed: %v", err)
+		return
+	}
+	if purged > 0 {
+		logging.Logger.WithField("purged", purged).Debug("Purged expired idempotency keys")
+	}
+}
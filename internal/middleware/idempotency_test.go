@@ -0,0 +1,271 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"todolist-api/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdempotencyConfigFromEnv(t *testing.T) {
+	setupTest()
+
+	t.Run("enabled in memory for a day by default", func(t *testing.T) {
+		os.Unsetenv("IDEMPOTENCY_ENABLED")
+		os.Unsetenv("IDEMPOTENCY_TTL")
+		os.Unsetenv("IDEMPOTENCY_STORE")
+		os.Unsetenv("IDEMPOTENCY_PURGE_INTERVAL")
+
+		config := NewIdempotencyConfigFromEnv()
+		assert.True(t, config.Enabled)
+		assert.Equal(t, 24*time.Hour, config.TTL)
+		assert.Equal(t, IdempotencyStoreMemory, config.Store)
+		assert.Equal(t, time.Hour, config.PurgeInterval)
+	})
+
+	t.Run("reads environment", func(t *testing.T) {
+		t.Setenv("IDEMPOTENCY_ENABLED", "false")
+		t.Setenv("IDEMPOTENCY_TTL", "2h")
+		t.Setenv("IDEMPOTENCY_STORE", "postgres")
+		t.Setenv("IDEMPOTENCY_PURGE_INTERVAL", "5m")
+
+		config := NewIdempotencyConfigFromEnv()
+		assert.False(t, config.Enabled)
+		assert.Equal(t, 2*time.Hour, config.TTL)
+		assert.Equal(t, IdempotencyStorePostgres, config.Store)
+		assert.Equal(t, 5*time.Minute, config.PurgeInterval)
+	})
+
+	t.Run("ignores a purge interval that is not positive", func(t *testing.T) {
+		for _, value := range []string{"0", "-1m"} {
+			t.Setenv("IDEMPOTENCY_PURGE_INTERVAL", value)
+			assert.Equal(t, time.Hour, NewIdempotencyConfigFromEnv().PurgeInterval, value)
+		}
+	})
+
+	t.Run("falls back to memory without a database", func(t *testing.T) {
+		store := NewIdempotencyStore(&IdempotencyConfig{Store: IdempotencyStorePostgres}, nil)
+		assert.IsType(t, &MemoryIdempotencyStore{}, store)
+	})
+}
+
+func TestIdempotencyStores(t *testing.T) {
+	setupTest()
+
+	userID := uuid.New()
+	response := &IdempotentResponse{
+		Status:      http.StatusCreated,
+		ContentType: "application/json",
+		Location:    "/lists/1",
+		Body:        []byte(`{"id":"1"}`),
+	}
+
+	stores := map[string]func(t *testing.T, now func() time.Time) IdempotencyStore{
+		"memory": func(_ *testing.T, now func() time.Time) IdempotencyStore {
+			store := NewMemoryIdempotencyStore()
+			store.now = now
+			return store
+		},
+		"postgres": func(t *testing.T, now func() time.Time) IdempotencyStore {
+			store := NewPostgresIdempotencyStore(testutil.SetupTestDB(t))
+			store.now = now
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			clock := time.Now()
+			store := newStore(t, func() time.Time { return clock })
+
+			stored, err := store.Reserve("key", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+
+			_, err = store.Reserve("key", userID, time.Minute)
+			assert.ErrorIs(t, err, ErrIdempotencyKeyInUse)
+
+			require.NoError(t, store.Complete("key", response, time.Hour))
+			require.NoError(t, store.Release("key"), "releasing a completed key keeps it")
+			stored, err = store.Reserve("key", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, response, stored)
+
+			// A released reservation can be claimed again
+			_, err = store.Reserve("other", userID, time.Minute)
+			require.NoError(t, err)
+			require.NoError(t, store.Release("other"))
+			stored, err = store.Reserve("other", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+
+			// A pending reservation lapses after its lease, a response after the TTL
+			clock = clock.Add(2 * time.Minute)
+			stored, err = store.Reserve("other", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+			stored, err = store.Reserve("key", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, response, stored)
+
+			clock = clock.Add(time.Hour)
+			stored, err = store.Reserve("key", userID, time.Minute)
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+
+			// Purging removes only the keys that have expired
+			_, err = store.Reserve("stale", userID, time.Minute)
+			require.NoError(t, err)
+			clock = clock.Add(2 * time.Minute)
+			_, err = store.Reserve("fresh", userID, time.Minute)
+			require.NoError(t, err)
+			purged, err := store.PurgeExpired(clock)
+			require.NoError(t, err)
+			assert.Equal(t, 3, purged, "key, other and stale have expired")
+			_, err = store.Reserve("fresh", userID, time.Minute)
+			assert.ErrorIs(t, err, ErrIdempotencyKeyInUse)
+		})
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	setupTest()
+
+	config := &IdempotencyConfig{Enabled: true, TTL: time.Hour}
+
+	// newRouter serves POST /lists and /other, counting the resources it
+	// creates. Requests for user "b" run as another user.
+	newRouter := func(config *IdempotencyConfig, handler gin.HandlerFunc) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if c.Query("user") == "b" {
+				c.Set(ContextKeyUserID, uuid.MustParse("00000000-0000-0000-0000-00000000000b"))
+			}
+			c.Next()
+		})
+		router.POST("/lists", Idempotency(config, NewMemoryIdempotencyStore()), handler)
+		router.POST("/other", Idempotency(config, NewMemoryIdempotencyStore()), handler)
+		return router
+	}
+	creator := func(created *int32) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			n := atomic.AddInt32(created, 1)
+			id := string(rune('0' + n))
+			c.Header("Location", "/lists/"+id)
+			c.JSON(http.StatusCreated, gin.H{"id": id})
+		}
+	}
+	post := func(router *gin.Engine, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"name":"Groceries"}`))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("replays the first response for a repeated key", func(t *testing.T) {
+		var created int32
+		router := newRouter(config, creator(&created))
+
+		first := post(router, "/lists", "retry-1")
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+		second := post(router, "/lists", "retry-1")
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, first.Header().Get("Location"), second.Header().Get("Location"))
+		assert.Equal(t, "application/json; charset=utf-8", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, int32(1), created)
+
+		post(router, "/lists", "retry-2")
+		assert.Equal(t, int32(2), created)
+	})
+
+	t.Run("keys are scoped per user and per route", func(t *testing.T) {
+		var created int32
+		router := newRouter(config, creator(&created))
+
+		post(router, "/lists", "shared")
+		post(router, "/lists?user=b", "shared")
+		post(router, "/other", "shared")
+		assert.Equal(t, int32(3), created)
+
+		post(router, "/lists?user=b", "shared")
+		assert.Equal(t, int32(3), created)
+	})
+
+	t.Run("a concurrent request with the same key does not run", func(t *testing.T) {
+		var created int32
+		started, release := make(chan struct{}), make(chan struct{})
+		create := creator(&created)
+		router := newRouter(config, func(c *gin.Context) {
+			close(started)
+			<-release
+			create(c)
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- post(router, "/lists", "concurrent") }()
+		<-started
+
+		w := post(router, "/lists", "concurrent")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "IDEMPOTENCY_KEY_IN_USE")
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+		assert.Equal(t, int32(1), created)
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		var calls int32
+		router := newRouter(config, func(c *gin.Context) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"code": "DB_BUSY"})
+				return
+			}
+			c.JSON(http.StatusCreated, gin.H{"id": "1"})
+		})
+
+		assert.Equal(t, http.StatusServiceUnavailable, post(router, "/lists", "flaky").Code)
+		assert.Equal(t, http.StatusCreated, post(router, "/lists", "flaky").Code)
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("requests without a key or with keys disabled are not affected", func(t *testing.T) {
+		var created int32
+		router := newRouter(config, creator(&created))
+		post(router, "/lists", "")
+		post(router, "/lists", "")
+		assert.Equal(t, int32(2), created)
+
+		created = 0
+		router = newRouter(&IdempotencyConfig{Enabled: false, TTL: time.Hour}, creator(&created))
+		post(router, "/lists", "same")
+		post(router, "/lists", "same")
+		assert.Equal(t, int32(2), created)
+	})
+
+	t.Run("rejects overlong keys", func(t *testing.T) {
+		var created int32
+		w := post(newRouter(config, creator(&created)), "/lists", strings.Repeat("k", maxIdempotencyKeyLength+1))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_IDEMPOTENCY_KEY")
+		assert.Zero(t, created)
+	})
+}
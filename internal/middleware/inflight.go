@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlightTracker counts requests currently being served so shutdown can
+// report how many were drained, along with totals of requests served and
+// failed since the tracker was created
+type InFlightTracker struct {
+	active       atomic.Int64
+	served       atomic.Int64
+	clientErrors atomic.Int64
+	serverErrors atomic.Int64
+	startedAt    time.Time
+}
+
+// RequestMetrics is a point-in-time snapshot of an InFlightTracker's counters
+type RequestMetrics struct {
+	InFlight       int64     `json:"inFlight"`
+	RequestsServed int64     `json:"requestsServed"`
+	ClientErrors   int64     `json:"clientErrors"`
+	ServerErrors   int64     `json:"serverErrors"`
+	StartedAt      time.Time `json:"startedAt"`
+}
+
+// NewInFlightTracker creates a new in-flight request tracker
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{startedAt: time.Now()}
+}
+
+// Middleware returns a handler that counts a request as in flight until it
+// completes, then records it as served and, for 4xx/5xx responses, as an error
+func (t *InFlightTracker) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+		c.Next()
+
+		t.served.Add(1)
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			t.serverErrors.Add(1)
+		case status >= http.StatusBadRequest:
+			t.clientErrors.Add(1)
+		}
+	}
+}
+
+// Active returns the number of requests currently in flight
+func (t *InFlightTracker) Active() int64 {
+	return t.active.Load()
+}
+
+// Snapshot returns the tracker's current counters
+func (t *InFlightTracker) Snapshot() RequestMetrics {
+	return RequestMetrics{
+		InFlight:       t.active.Load(),
+		RequestsServed: t.served.Load(),
+		ClientErrors:   t.clientErrors.Load(),
+		ServerErrors:   t.serverErrors.Load(),
+		StartedAt:      t.startedAt,
+	}
+}
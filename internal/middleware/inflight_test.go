@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInFlightTracker(t *testing.T) {
+	setupTest()
+
+	tracker := NewInFlightTracker()
+	var duringRequest int64
+
+	router := gin.New()
+	router.Use(tracker.Middleware())
+	router.GET("/test", func(c *gin.Context) {
+		duringRequest = tracker.Active()
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/test", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(1), duringRequest, "request should be counted while it is served")
+	assert.Equal(t, int64(0), tracker.Active(), "request should no longer be counted once complete")
+}
+
+func TestInFlightTrackerSnapshot(t *testing.T) {
+	setupTest()
+
+	tracker := NewInFlightTracker()
+
+	router := gin.New()
+	router.Use(tracker.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/bad", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	for _, path := range []string{"/ok", "/ok", "/bad", "/fail"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, http.NoBody))
+	}
+
+	metrics := tracker.Snapshot()
+	assert.Equal(t, int64(0), metrics.InFlight)
+	assert.Equal(t, int64(4), metrics.RequestsServed)
+	assert.Equal(t, int64(1), metrics.ClientErrors)
+	assert.Equal(t, int64(1), metrics.ServerErrors)
+	assert.False(t, metrics.StartedAt.IsZero())
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"todolist-api/internal/i18n"
+	"todolist-api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKeyLocale is the context key for the locale of error messages
+const ContextKeyLocale = "locale"
+
+// NewDefaultLocaleFromEnv reads DEFAULT_LOCALE, the locale for requests whose
+// Accept-Language names no supported one. Unsupported values fall back to
+// English.
+func NewDefaultLocaleFromEnv() string {
+	locale := strings.ToLower(getEnv("DEFAULT_LOCALE", i18n.DefaultLocale))
+	if !i18n.Supported(locale) {
+		logging.Logger.WithFields(map[string]interface{}{
+			"locale":    locale,
+			"supported": i18n.Locales(),
+		}).Warn("Unsupported DEFAULT_LOCALE, using English")
+		return i18n.DefaultLocale
+	}
+	return locale
+}
+
+// Locale records the locale for error messages in the request context,
+// chosen from the Accept-Language header with defaultLocale as the fallback,
+// so response helpers can translate them
+func Locale(defaultLocale string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Set(ContextKeyLocale, i18n.Match(c.GetHeader("Accept-Language"), defaultLocale))
+		c.Next()
+	}
+}
+
+// GetLocale returns the locale for the request's error messages
+func GetLocale(c *gin.Context) string {
+	if locale := c.GetString(ContextKeyLocale); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
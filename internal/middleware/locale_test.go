@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultLocaleFromEnv(t *testing.T) {
+	setupTest()
+
+	os.Unsetenv("DEFAULT_LOCALE")
+	assert.Equal(t, "en", NewDefaultLocaleFromEnv())
+
+	t.Setenv("DEFAULT_LOCALE", "ES")
+	assert.Equal(t, "es", NewDefaultLocaleFromEnv())
+
+	t.Setenv("DEFAULT_LOCALE", "klingon")
+	assert.Equal(t, "en", NewDefaultLocaleFromEnv())
+}
+
+func TestLocale(t *testing.T) {
+	setupTest()
+
+	serve := func(defaultLocale, acceptLanguage string) (*httptest.ResponseRecorder, string) {
+		var seen string
+		router := gin.New()
+		router.Use(Locale(defaultLocale))
+		router.GET("/test", func(c *gin.Context) {
+			seen = GetLocale(c)
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/test", http.NoBody)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w, seen
+	}
+
+	w, seen := serve("en", "es-ES,es;q=0.9")
+	assert.Equal(t, "es", seen)
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+
+	_, seen = serve("en", "fr")
+	assert.Equal(t, "en", seen)
+
+	_, seen = serve("es", "fr")
+	assert.Equal(t, "es", seen)
+}
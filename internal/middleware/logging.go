@@ -1,6 +1,11 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"todolist-api/internal/logging"
@@ -9,8 +14,32 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldMarkers are substrings of JSON keys whose values are never logged
+var sensitiveFieldMarkers = []string{"password", "token", "secret"}
+
+// RequestLogConfig holds request logging configuration
+type RequestLogConfig struct {
+	LogBody      bool // Log (redacted) request bodies of write requests, for debugging
+	MaxBodyBytes int  // Maximum number of body bytes included in a log entry
+}
+
+// NewRequestLogConfigFromEnv creates request logging config from environment variables
+func NewRequestLogConfigFromEnv() *RequestLogConfig {
+	return &RequestLogConfig{
+		LogBody:      getEnvBool("LOG_REQUEST_BODY", false),
+		MaxBodyBytes: getEnvInt("LOG_REQUEST_BODY_MAX_BYTES", 2048),
+	}
+}
+
 // RequestLogger is a middleware that logs HTTP requests with detailed information
 func RequestLogger() gin.HandlerFunc {
+	return RequestLoggerWithConfig(&RequestLogConfig{})
+}
+
+// RequestLoggerWithConfig is RequestLogger with optional request body logging
+func RequestLoggerWithConfig(config *RequestLogConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Start timer
 		startTime := time.Now()
@@ -26,6 +55,11 @@ func RequestLogger() gin.HandlerFunc {
 			"query":     c.Request.URL.RawQuery,
 		})
 
+		// Add the request ID for correlating client reports with the log
+		if requestID := GetRequestID(c); requestID != "" {
+			logEntry = logEntry.WithField("request_id", requestID)
+		}
+
 		// Add API key if present (for future authentication)
 		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
 			// Log only first 8 characters for security
@@ -41,6 +75,11 @@ func RequestLogger() gin.HandlerFunc {
 			logEntry = logEntry.WithField("user_agent", userAgent)
 		}
 
+		// Add the redacted body of write requests when debugging is enabled
+		if config.LogBody && isWriteMethod(c.Request.Method) && c.Request.Body != nil {
+			logEntry = logEntry.WithField("request_body", captureRequestBody(c, config.MaxBodyBytes))
+		}
+
 		// Process request
 		c.Next()
 
@@ -81,6 +120,88 @@ func RequestLogger() gin.HandlerFunc {
 	}
 }
 
+// isWriteMethod reports whether an HTTP method can carry a body that changes state
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// captureRequestBody reads the request body for logging and replaces it with
+// a replay of the same bytes so handlers can still bind it. Sensitive JSON
+// fields are redacted and the result is capped at maxBytes.
+func captureRequestBody(c *gin.Context, maxBytes int) string {
+	data, err := io.ReadAll(c.Request.Body)
+	replay := io.Reader(bytes.NewReader(data))
+	if err != nil {
+		// Surface the read error (e.g. body too large) to the handler as well
+		replay = io.MultiReader(replay, &errReader{err: err})
+	}
+	c.Request.Body = io.NopCloser(replay)
+
+	if len(data) == 0 {
+		return ""
+	}
+
+	var parsed interface{}
+	if json.Unmarshal(data, &parsed) != nil {
+		// Only JSON can be redacted reliably, so other bodies are not logged
+		return "[non-JSON body omitted]"
+	}
+	redacted, err := json.Marshal(redactSensitiveFields(parsed))
+	if err != nil {
+		return "[unloggable body omitted]"
+	}
+
+	if maxBytes > 0 && len(redacted) > maxBytes {
+		return string(redacted[:maxBytes]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+// redactSensitiveFields replaces the values of sensitive keys anywhere in a
+// decoded JSON value
+func redactSensitiveFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactSensitiveFields(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactSensitiveFields(v[i])
+		}
+	}
+	return value
+}
+
+// isSensitiveField reports whether a JSON key names a secret
+func isSensitiveField(key string) bool {
+	lower := strings.ToLower(key)
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// errReader always fails with err, used to replay a body read error
+type errReader struct {
+	err error
+}
+
+// Read returns the stored error
+func (r *errReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
+
 // StructuredLogger returns a middleware that logs requests in JSON format
 // This is useful for log aggregation systems
 func StructuredLogger() gin.HandlerFunc {
@@ -106,6 +227,11 @@ func StructuredLogger() gin.HandlerFunc {
 			"response_size": c.Writer.Size(),
 		}
 
+		// Add request ID
+		if requestID := GetRequestID(c); requestID != "" {
+			entry["request_id"] = requestID
+		}
+
 		// Add API key if present
 		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
 			if len(apiKey) > 8 {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"todolist-api/internal/logging"
@@ -210,6 +211,92 @@ func TestRequestLogger(t *testing.T) {
 	})
 }
 
+func TestRequestLoggerBody(t *testing.T) {
+	setupTest()
+	logging.InitLogger(&logging.LogConfig{
+		Enabled:    false,
+		Level:      "info",
+		JSONFormat: false,
+	})
+
+	gin.SetMode(gin.TestMode)
+
+	type loginRequest struct {
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	newRouter := func(config *RequestLogConfig, bound *loginRequest) *gin.Engine {
+		router := gin.New()
+		router.Use(RequestLoggerWithConfig(config))
+		router.POST("/login", func(c *gin.Context) {
+			if err := c.ShouldBindJSON(bound); err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	body := `{"email":"user@example.com","password":"hunter2-secret","refreshToken":"abc123"}`
+
+	t.Run("logs redacted body and binding still succeeds", func(t *testing.T) {
+		var buf bytes.Buffer
+		logging.Logger.SetOutput(&buf)
+
+		var bound loginRequest
+		router := newRouter(&RequestLogConfig{LogBody: true, MaxBodyBytes: 2048}, &bound)
+
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user@example.com", bound.Email)
+		assert.Equal(t, "hunter2-secret", bound.Password)
+
+		logOutput := buf.String()
+		assert.Contains(t, logOutput, "request_body")
+		assert.Contains(t, logOutput, "user@example.com")
+		assert.Contains(t, logOutput, redactedValue)
+		assert.NotContains(t, logOutput, "hunter2-secret")
+		assert.NotContains(t, logOutput, "abc123")
+	})
+
+	t.Run("caps logged body size", func(t *testing.T) {
+		var buf bytes.Buffer
+		logging.Logger.SetOutput(&buf)
+
+		var bound loginRequest
+		router := newRouter(&RequestLogConfig{LogBody: true, MaxBodyBytes: 10}, &bound)
+
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, buf.String(), "(truncated)")
+		assert.NotContains(t, buf.String(), "user@example.com")
+	})
+
+	t.Run("does not log body when disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logging.Logger.SetOutput(&buf)
+
+		var bound loginRequest
+		router := newRouter(&RequestLogConfig{}, &bound)
+
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, buf.String(), "request_body")
+	})
+}
+
 func TestStructuredLogger(t *testing.T) {
 	setupTest()
 	// Initialize logger with JSON format for structured logging
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mimeJSON is the media type every route produces unless it declares otherwise
+const mimeJSON = "application/json"
+
+// ContentNegotiationConfig holds Accept header negotiation configuration
+type ContentNegotiationConfig struct {
+	Strict   bool                // Reject requests whose Accept header no media type of the route satisfies
+	produces map[string][]string // Media types by route path, for routes that are not JSON-only
+}
+
+// NewContentNegotiationConfigFromEnv creates content negotiation config from
+// environment variables
+func NewContentNegotiationConfigFromEnv() *ContentNegotiationConfig {
+	return &ContentNegotiationConfig{
+		Strict:   getEnvBool("STRICT_CONTENT_NEGOTIATION", false),
+		produces: make(map[string][]string),
+	}
+}
+
+// Produces declares the media types a route can respond with, keyed by its
+// full path as registered (e.g. "/api/v1/lists/:listId/export"). Routes not
+// declared here produce only application/json.
+func (config *ContentNegotiationConfig) Produces(path string, mediaTypes ...string) {
+	if config.produces == nil {
+		config.produces = make(map[string][]string)
+	}
+	config.produces[path] = mediaTypes
+}
+
+// offersFor returns the media types the route at path can respond with
+func (config *ContentNegotiationConfig) offersFor(path string) []string {
+	if offers, ok := config.produces[path]; ok {
+		return offers
+	}
+	return []string{mimeJSON}
+}
+
+// ContentNegotiation rejects requests with 406 NOT_ACCEPTABLE when strict
+// negotiation is enabled and the Accept header allows none of the media types
+// the route produces. A missing Accept header accepts anything, and requests
+// that match no route are left to the 404 handler.
+func ContentNegotiation(config *ContentNegotiationConfig) gin.HandlerFunc {
+	// If strict negotiation is disabled, return a no-op middleware
+	if !config.Strict {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		accept := c.GetHeader("Accept")
+		if accept == "" || c.FullPath() == "" || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		offers := config.offersFor(c.FullPath())
+		for _, offer := range offers {
+			if acceptsMediaType(accept, offer) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusNotAcceptable, models.ErrorResponse{
+			Code:    "NOT_ACCEPTABLE",
+			Message: "The requested media type is not available. This endpoint produces: " + strings.Join(offers, ", "),
+			Details: map[string]interface{}{"available": offers},
+		})
+		c.Abort()
+	}
+}
+
+// acceptsMediaType reports whether an Accept header value allows mediaType,
+// either exactly or through a type/* or */* range. Ranges with q=0 are
+// explicitly not acceptable.
+func acceptsMediaType(accept, mediaType string) bool {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	for _, element := range strings.Split(accept, ",") {
+		params := strings.Split(element, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaRange != "*/*" && mediaRange != mainType+"/*" && mediaRange != mediaType {
+			continue
+		}
+		if !hasZeroQuality(params[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasZeroQuality reports whether media range parameters carry q=0
+func hasZeroQuality(params []string) bool {
+	for _, param := range params {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil && q == 0
+	}
+	return false
+}
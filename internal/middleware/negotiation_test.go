@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContentNegotiationConfigFromEnv(t *testing.T) {
+	setupTest()
+
+	t.Run("lenient by default", func(t *testing.T) {
+		t.Setenv("STRICT_CONTENT_NEGOTIATION", "")
+		assert.False(t, NewContentNegotiationConfigFromEnv().Strict)
+	})
+
+	t.Run("reads environment", func(t *testing.T) {
+		t.Setenv("STRICT_CONTENT_NEGOTIATION", "true")
+		assert.True(t, NewContentNegotiationConfigFromEnv().Strict)
+	})
+}
+
+func TestContentNegotiation(t *testing.T) {
+	setupTest()
+
+	newRouter := func(strict bool) *gin.Engine {
+		config := &ContentNegotiationConfig{Strict: strict}
+		config.Produces("/export", "application/json", "text/csv")
+
+		router := gin.New()
+		router.Use(ContentNegotiation(config))
+		router.GET("/lists", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"lists": []string{}})
+		})
+		router.GET("/export", func(c *gin.Context) {
+			c.String(http.StatusOK, "description\n")
+		})
+		return router
+	}
+
+	request := func(router *gin.Engine, path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, http.NoBody)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("rejects text/html on a JSON route when strict", func(t *testing.T) {
+		w := request(newRouter(true), "/lists", "text/html")
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.Contains(t, w.Body.String(), "NOT_ACCEPTABLE")
+		assert.Contains(t, w.Body.String(), `"available":["application/json"]`)
+	})
+
+	t.Run("serves text/html when not strict", func(t *testing.T) {
+		w := request(newRouter(false), "/lists", "text/html")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("accepts json, wildcards and a missing header", func(t *testing.T) {
+		router := newRouter(true)
+		for _, accept := range []string{
+			"",
+			"application/json",
+			"*/*",
+			"application/*",
+			"text/html, application/json;q=0.5",
+			"text/html,application/xhtml+xml,*/*;q=0.8",
+		} {
+			w := request(router, "/lists", accept)
+			assert.Equal(t, http.StatusOK, w.Code, "Accept: %q", accept)
+		}
+	})
+
+	t.Run("honors q=0 exclusions", func(t *testing.T) {
+		w := request(newRouter(true), "/lists", "application/json;q=0, text/html")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+
+	t.Run("accepts any media type a route declares", func(t *testing.T) {
+		router := newRouter(true)
+
+		w := request(router, "/export", "text/csv")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = request(router, "/export", "text/html")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.Contains(t, w.Body.String(), `"available":["application/json","text/csv"]`)
+	})
+
+	t.Run("leaves unknown routes to the 404 handler", func(t *testing.T) {
+		w := request(newRouter(true), "/missing", "text/html")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,16 +1,22 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"todolist-api/internal/logging"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/ulule/limiter/v3"
-	mgin "github.com/ulule/limiter/v3/drivers/middleware/gin"
 	"github.com/ulule/limiter/v3/drivers/store/memory"
+	sredis "github.com/ulule/limiter/v3/drivers/store/redis"
 )
 
 // RateLimitConfig holds rate limiting configuration
@@ -19,8 +25,48 @@ type RateLimitConfig struct {
 	RequestsPerMin  int64
 	RequestsPerHour int64
 	BurstSize       int64
+
+	// ServiceBypassToken lets trusted service accounts skip rate limiting by
+	// sending it in the ServiceTokenHeader over TLS (empty disables bypass)
+	ServiceBypassToken string
+	// TrustedProxies are the proxy IPs or CIDRs whose X-Forwarded-Proto is
+	// believed when deciding whether the bypass token arrived over TLS
+	TrustedProxies []string
+
+	// Store selects where request counters are kept: "memory" (the default)
+	// keeps them per process, "redis" shares them between replicas
+	Store string
+	// RedisURL is the redis:// URL of the shared store when Store is "redis"
+	RedisURL string
+
+	redisOnce   sync.Once
+	redisClient *redis.Client
 }
 
+// Rate limit store types
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// redisConnectTimeout bounds the startup ping of the Redis rate limit store
+const redisConnectTimeout = 5 * time.Second
+
+// Headers describing the most restrictive rate limit applied to a request.
+// HeaderRateLimitReset is the Unix time in seconds at which it resets.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// contextKeyRateLimit holds the limiter.Context reported in the rate limit
+// headers, so stacked limiters can tell whether theirs is more restrictive
+const contextKeyRateLimit = "rate_limit"
+
+// ServiceTokenHeader carries the rate limit bypass token for trusted services
+const ServiceTokenHeader = "X-Service-Token"
+
 // NewRateLimitConfigFromEnv creates rate limit config from environment variables
 func NewRateLimitConfigFromEnv() *RateLimitConfig {
 	enabled := getEnv("RATE_LIMIT_ENABLED", "true") == "true"
@@ -41,10 +87,165 @@ func NewRateLimitConfigFromEnv() *RateLimitConfig {
 	}
 
 	return &RateLimitConfig{
-		Enabled:         enabled,
-		RequestsPerMin:  requestsPerMin,
-		RequestsPerHour: requestsPerHour,
-		BurstSize:       burstSize,
+		Enabled:            enabled,
+		RequestsPerMin:     requestsPerMin,
+		RequestsPerHour:    requestsPerHour,
+		BurstSize:          burstSize,
+		ServiceBypassToken: getEnv("SERVICE_BYPASS_TOKEN", ""),
+		TrustedProxies:     parseTrustedProxies(getEnv("TRUSTED_PROXIES", "")),
+		Store:              getEnv("RATE_LIMIT_STORE", RateLimitStoreMemory),
+		RedisURL:           getEnv("REDIS_URL", ""),
+	}
+}
+
+// redis connects to the Redis store on first use. It returns nil when the
+// memory store is configured or Redis cannot be reached, so a Redis outage at
+// startup degrades to per-process limits instead of stopping the server.
+func (config *RateLimitConfig) redis() *redis.Client {
+	config.redisOnce.Do(func() {
+		if config.Store != RateLimitStoreRedis {
+			if config.Store != "" && config.Store != RateLimitStoreMemory {
+				logging.Logger.Warnf("Unknown RATE_LIMIT_STORE %q, using the memory store", config.Store)
+			}
+			return
+		}
+
+		options, err := redis.ParseURL(config.RedisURL)
+		if err != nil {
+			logging.Logger.WithError(err).Warn("Invalid REDIS_URL, falling back to the memory rate limit store")
+			return
+		}
+
+		client := redis.NewClient(options)
+		ctx, cancel := context.WithTimeout(context.Background(), redisConnectTimeout)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			logging.Logger.WithError(err).Warn("Cannot reach Redis, falling back to the memory rate limit store")
+			_ = client.Close()
+			return
+		}
+
+		logging.Logger.Infof("Rate limit counters are shared through Redis at %s", options.Addr)
+		config.redisClient = client
+	})
+	return config.redisClient
+}
+
+// newStore creates the counter store for one limiter. In Redis every limiter
+// gets its own key prefix so their counters don't mix.
+func (config *RateLimitConfig) newStore(prefix string) limiter.Store {
+	if client := config.redis(); client != nil {
+		store, err := sredis.NewStoreWithOptions(client, limiter.StoreOptions{
+			Prefix:   "ratelimit:" + prefix,
+			MaxRetry: limiter.DefaultMaxRetry,
+		})
+		if err == nil {
+			return store
+		}
+		logging.Logger.WithError(err).Warn("Failed to create the Redis rate limit store, falling back to memory")
+	}
+	return memory.NewStore()
+}
+
+// rateLimitMiddleware counts each request against instance under the key
+// returned by keyGetter, reports the limit in the X-RateLimit headers and calls
+// onLimitReached once it is exceeded
+func rateLimitMiddleware(instance *limiter.Limiter, keyGetter func(c *gin.Context) string, onLimitReached gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := instance.Get(c, keyGetter(c))
+		if err != nil {
+			allowOnStoreError(c, err)
+			return
+		}
+
+		setRateLimitHeaders(c, limit)
+		if limit.Reached {
+			onLimitReached(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders reports limit in the X-RateLimit headers unless an
+// earlier limiter on the request has fewer requests remaining. A limit that
+// blocks the request is always reported, so a 429 describes the limit hit.
+func setRateLimitHeaders(c *gin.Context, limit limiter.Context) {
+	if value, exists := c.Get(contextKeyRateLimit); exists && !limit.Reached {
+		if previous, ok := value.(limiter.Context); ok && previous.Remaining <= limit.Remaining {
+			return
+		}
+	}
+
+	c.Set(contextKeyRateLimit, limit)
+	c.Header(HeaderRateLimitLimit, strconv.FormatInt(limit.Limit, 10))
+	c.Header(HeaderRateLimitRemaining, strconv.FormatInt(limit.Remaining, 10))
+	c.Header(HeaderRateLimitReset, strconv.FormatInt(limit.Reset, 10))
+}
+
+// ipKey limits requests by client IP
+func ipKey(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// allowOnStoreError lets the request through when the counter store fails,
+// such as when Redis goes away after startup, rather than rejecting all traffic
+func allowOnStoreError(c *gin.Context, err error) {
+	logging.Logger.WithError(err).WithField("path", c.Request.URL.Path).Warn("Rate limit store unavailable, request not limited")
+	c.Next()
+}
+
+// hasServiceBypass reports whether the request presents the configured service
+// bypass token. The token is only honored on TLS connections so it cannot be
+// sniffed from plaintext traffic.
+func hasServiceBypass(c *gin.Context, config *RateLimitConfig) bool {
+	if config.ServiceBypassToken == "" || !config.overTLS(c) {
+		return false
+	}
+	token := c.GetHeader(ServiceTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(config.ServiceBypassToken)) == 1
+}
+
+// overTLS reports whether the client connected over TLS, either directly or
+// through a trusted proxy that terminated TLS and set X-Forwarded-Proto
+func (config *RateLimitConfig) overTLS(c *gin.Context) bool {
+	if c.Request.TLS != nil {
+		return true
+	}
+	return config.isTrustedProxy(c.RemoteIP()) && strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
+
+// isTrustedProxy reports whether remoteIP matches one of the TrustedProxies
+func (config *RateLimitConfig) isTrustedProxy(remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range config.TrustedProxies {
+		if _, cidr, err := net.ParseCIDR(proxy); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// withServiceBypass skips the wrapped limiter for requests carrying a valid
+// service bypass token. Authentication is still enforced by its own middleware.
+func withServiceBypass(config *RateLimitConfig, limiterHandler gin.HandlerFunc) gin.HandlerFunc {
+	if config.ServiceBypassToken == "" {
+		return limiterHandler
+	}
+	return func(c *gin.Context) {
+		if hasServiceBypass(c, config) {
+			c.Next()
+			return
+		}
+		limiterHandler(c)
 	}
 }
 
@@ -64,14 +265,14 @@ func GlobalRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 		Limit:  config.RequestsPerMin,
 	}
 
-	// Create in-memory store
-	store := memory.NewStore()
+	// Create the counter store (in memory unless Redis is configured)
+	store := config.newStore("global")
 
 	// Create limiter instance
 	instance := limiter.New(store, rate)
 
 	// Create middleware with custom error handler
-	middleware := mgin.NewMiddleware(instance, mgin.WithLimitReachedHandler(func(c *gin.Context) {
+	middleware := rateLimitMiddleware(instance, ipKey, func(c *gin.Context) {
 		// Log rate limit violation with client details
 		logging.Logger.WithFields(map[string]interface{}{
 			"client_ip":     c.ClientIP(),
@@ -87,10 +288,10 @@ func GlobalRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 			"retryAfter": int(rate.Period.Seconds()),
 		})
 		c.Abort()
-	}))
+	})
 
 	logging.Logger.Infof("Rate limiting enabled: %d requests per minute", config.RequestsPerMin)
-	return middleware
+	return withServiceBypass(config, middleware)
 }
 
 // createOperationRateLimiter creates a rate limiter for specific operation types
@@ -103,7 +304,7 @@ func createOperationRateLimiter(limit int64, limitType, message string) gin.Hand
 	store := memory.NewStore()
 	instance := limiter.New(store, rate)
 
-	return mgin.NewMiddleware(instance, mgin.WithLimitReachedHandler(func(c *gin.Context) {
+	return rateLimitMiddleware(instance, ipKey, func(c *gin.Context) {
 		logging.Logger.WithFields(map[string]interface{}{
 			"client_ip":     c.ClientIP(),
 			"path":          c.Request.URL.Path,
@@ -120,7 +321,7 @@ func createOperationRateLimiter(limit int64, limitType, message string) gin.Hand
 			"limit":      rate.Limit,
 		})
 		c.Abort()
-	}))
+	})
 }
 
 // ReadRateLimiter creates a rate limiter for read operations (GET requests)
@@ -132,11 +333,11 @@ func ReadRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 	}
 
 	// Read operations can have higher limits
-	return createOperationRateLimiter(
+	return withServiceBypass(config, createOperationRateLimiter(
 		config.RequestsPerMin*2,
 		"read",
 		"Too many read requests. Please try again later.",
-	)
+	))
 }
 
 // WriteRateLimiter creates a rate limiter for write operations (POST, PUT, DELETE)
@@ -148,11 +349,11 @@ func WriteRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 	}
 
 	// Write operations have stricter limits
-	return createOperationRateLimiter(
+	return withServiceBypass(config, createOperationRateLimiter(
 		config.RequestsPerMin/2,
 		"write",
 		"Too many write requests. Please try again later.",
-	)
+	))
 }
 
 // PerUserRateLimiter creates a rate limiter that tracks limits per user
@@ -171,8 +372,8 @@ func PerUserRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 		Limit:  config.RequestsPerMin,
 	}
 
-	// Create in-memory store
-	store := memory.NewStore()
+	// Create the counter store (in memory unless Redis is configured)
+	store := config.newStore("user")
 
 	// Create limiter instance
 	instance := limiter.New(store, rate)
@@ -190,10 +391,9 @@ func PerUserRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 		return "ip:" + c.ClientIP()
 	}
 
-	// Create middleware with custom error handler and key generator
-	middleware := mgin.NewMiddleware(instance,
-		mgin.WithKeyGetter(keyGetter),
-		mgin.WithLimitReachedHandler(func(c *gin.Context) {
+	// Create middleware with custom limit handler and key generator
+	middleware := rateLimitMiddleware(instance, keyGetter,
+		func(c *gin.Context) {
 			// Determine if this is a user or IP-based limit
 			limitType := "ip"
 			identifier := c.ClientIP()
@@ -223,10 +423,10 @@ func PerUserRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 				"limit":      rate.Limit,
 			})
 			c.Abort()
-		}))
+		})
 
 	logging.Logger.Infof("Per-user rate limiting enabled: %d requests per minute", config.RequestsPerMin)
-	return middleware
+	return withServiceBypass(config, middleware)
 }
 
 // PerUserAuthRateLimiter creates a stricter rate limiter for authentication endpoints
@@ -244,7 +444,7 @@ func PerUserAuthRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 		Limit:  5, // Only 5 attempts per 15 minutes
 	}
 
-	store := memory.NewStore()
+	store := config.newStore("auth")
 	instance := limiter.New(store, rate)
 
 	// Custom key generator - use IP for auth endpoints since we can't reliably parse body
@@ -254,9 +454,8 @@ func PerUserAuthRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 		return "auth:ip:" + c.ClientIP()
 	}
 
-	middleware := mgin.NewMiddleware(instance,
-		mgin.WithKeyGetter(keyGetter),
-		mgin.WithLimitReachedHandler(func(c *gin.Context) {
+	middleware := rateLimitMiddleware(instance, keyGetter,
+		func(c *gin.Context) {
 			logging.Logger.WithFields(map[string]interface{}{
 				"client_ip":      c.ClientIP(),
 				"path":           c.Request.URL.Path,
@@ -274,8 +473,10 @@ func PerUserAuthRateLimiter(config *RateLimitConfig) gin.HandlerFunc {
 				"limit":      rate.Limit,
 			})
 			c.Abort()
-		}))
+		})
 
+	// No service bypass here: the token must not open a way around the
+	// brute-force protection on login
 	logging.Logger.Infof("Auth rate limiting enabled: %d attempts per 15 minutes", rate.Limit)
 	return middleware
 }
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -68,6 +71,119 @@ func TestNewRateLimitConfigFromEnv(t *testing.T) {
 		assert.Equal(t, int64(1000), config.RequestsPerHour)
 		assert.Equal(t, int64(10), config.BurstSize)
 	})
+
+	t.Run("selects the counter store", func(t *testing.T) {
+		t.Setenv("RATE_LIMIT_STORE", "")
+		os.Unsetenv("RATE_LIMIT_STORE")
+		assert.Equal(t, RateLimitStoreMemory, NewRateLimitConfigFromEnv().Store)
+
+		t.Setenv("RATE_LIMIT_STORE", "redis")
+		t.Setenv("REDIS_URL", "redis://cache:6379/1")
+		config := NewRateLimitConfigFromEnv()
+		assert.Equal(t, RateLimitStoreRedis, config.Store)
+		assert.Equal(t, "redis://cache:6379/1", config.RedisURL)
+	})
+}
+
+func TestRateLimitStoreFallback(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	for name, redisURL := range map[string]string{
+		"unreachable Redis": "redis://127.0.0.1:1/0",
+		"invalid REDIS_URL": "not a url",
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := &RateLimitConfig{
+				Enabled:        true,
+				RequestsPerMin: 3,
+				Store:          RateLimitStoreRedis,
+				RedisURL:       redisURL,
+			}
+
+			router := gin.New()
+			router.Use(GlobalRateLimiter(config))
+			router.GET("/test", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			assert.Nil(t, config.redis(), "should fall back to the memory store")
+
+			codes := make([]int, 0, 4)
+			for i := 0; i < 4; i++ {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest("GET", "/test", http.NoBody))
+				codes = append(codes, w.Code)
+			}
+			assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	// serve sends a request from user-1 through a global limit of globalLimit
+	// per IP followed by a per-user limit of userLimit
+	newRouter := func(globalLimit, userLimit int64) *gin.Engine {
+		router := gin.New()
+		router.Use(GlobalRateLimiter(&RateLimitConfig{Enabled: true, RequestsPerMin: globalLimit}))
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "user-1")
+			c.Next()
+		})
+		router.Use(PerUserRateLimiter(&RateLimitConfig{Enabled: true, RequestsPerMin: userLimit}))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	serve := func(router *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", http.NoBody))
+		return w
+	}
+
+	t.Run("reports the remaining requests and a Unix reset time on every response", func(t *testing.T) {
+		router := gin.New()
+		router.Use(GlobalRateLimiter(&RateLimitConfig{Enabled: true, RequestsPerMin: 5}))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		for i := 1; i <= 2; i++ {
+			w := serve(router)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "5", w.Header().Get(HeaderRateLimitLimit))
+			assert.Equal(t, strconv.Itoa(5-i), w.Header().Get(HeaderRateLimitRemaining))
+
+			reset, err := strconv.ParseInt(w.Header().Get(HeaderRateLimitReset), 10, 64)
+			require.NoError(t, err)
+			assert.InDelta(t, time.Now().Add(time.Minute).Unix(), reset, 2)
+		}
+	})
+
+	t.Run("reports the most restrictive of stacked limits", func(t *testing.T) {
+		for _, limits := range [][2]int64{{10, 3}, {3, 10}} {
+			w := serve(newRouter(limits[0], limits[1]))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "3", w.Header().Get(HeaderRateLimitLimit), "limits %v", limits)
+			assert.Equal(t, "2", w.Header().Get(HeaderRateLimitRemaining), "limits %v", limits)
+		}
+	})
+
+	t.Run("keeps the error body and reports the limit that was hit", func(t *testing.T) {
+		router := newRouter(10, 2)
+		serve(router)
+		serve(router)
+
+		w := serve(router)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"RATE_LIMIT_EXCEEDED"`)
+		assert.Equal(t, "2", w.Header().Get(HeaderRateLimitLimit))
+		assert.Equal(t, "0", w.Header().Get(HeaderRateLimitRemaining))
+		assert.NotEmpty(t, w.Header().Get(HeaderRateLimitReset))
+	})
 }
 
 func TestGlobalRateLimiter(t *testing.T) {
@@ -587,3 +703,93 @@ func TestPerUserAuthRateLimiter(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "limit")
 	})
 }
+
+func TestServiceBypassToken(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	const token = "trusted-service-token"
+
+	newRouter := func(limiter func(*RateLimitConfig) gin.HandlerFunc) *gin.Engine {
+		config := &RateLimitConfig{
+			Enabled:            true,
+			RequestsPerMin:     2,
+			ServiceBypassToken: token,
+			TrustedProxies:     []string{"10.0.0.0/8"},
+		}
+		router := gin.New()
+		router.Use(limiter(config))
+		router.GET("/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		})
+		return router
+	}
+
+	// countLimited sends requests and returns how many were rate limited
+	countLimited := func(router *gin.Engine, header string, prepare func(*http.Request)) int {
+		limited := 0
+		for i := 0; i < 6; i++ {
+			req := httptest.NewRequest("GET", "/test", http.NoBody)
+			req.RemoteAddr = "10.0.0.1:12345"
+			if header != "" {
+				req.Header.Set(ServiceTokenHeader, header)
+			}
+			prepare(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code == http.StatusTooManyRequests {
+				limited++
+			}
+		}
+		return limited
+	}
+
+	overTLS := func(req *http.Request) { req.TLS = &tls.ConnectionState{} }
+	plain := func(*http.Request) {}
+	forwarded := func(remoteAddr, proto string) func(*http.Request) {
+		return func(req *http.Request) {
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("X-Forwarded-Proto", proto)
+		}
+	}
+
+	limiters := map[string]func(*RateLimitConfig) gin.HandlerFunc{
+		"global":   GlobalRateLimiter,
+		"per-user": PerUserRateLimiter,
+		"write":    WriteRateLimiter,
+	}
+
+	for name, limiter := range limiters {
+		t.Run(name+" correct token over TLS bypasses limits", func(t *testing.T) {
+			assert.Equal(t, 0, countLimited(newRouter(limiter), token, overTLS))
+		})
+
+		t.Run(name+" wrong token is rate limited", func(t *testing.T) {
+			assert.Greater(t, countLimited(newRouter(limiter), "wrong-token", overTLS), 0)
+		})
+
+		t.Run(name+" absent token is rate limited", func(t *testing.T) {
+			assert.Greater(t, countLimited(newRouter(limiter), "", overTLS), 0)
+		})
+
+		t.Run(name+" correct token without TLS is rate limited", func(t *testing.T) {
+			assert.Greater(t, countLimited(newRouter(limiter), token, plain), 0)
+		})
+
+		t.Run(name+" correct token via TLS-terminating trusted proxy bypasses limits", func(t *testing.T) {
+			assert.Equal(t, 0, countLimited(newRouter(limiter), token, forwarded("10.0.0.1:12345", "https")))
+		})
+
+		t.Run(name+" forwarded http from trusted proxy is rate limited", func(t *testing.T) {
+			assert.Greater(t, countLimited(newRouter(limiter), token, forwarded("10.0.0.1:12345", "http")), 0)
+		})
+
+		t.Run(name+" forwarded https from untrusted client is rate limited", func(t *testing.T) {
+			assert.Greater(t, countLimited(newRouter(limiter), token, forwarded("203.0.113.7:12345", "https")), 0)
+		})
+	}
+
+	t.Run("auth limiter ignores the token", func(t *testing.T) {
+		assert.Greater(t, countLimited(newRouter(PerUserAuthRateLimiter), token, overTLS), 0)
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnlyConfig holds read-only mode configuration
+type ReadOnlyConfig struct {
+	Enabled   bool // Reject every request that could modify data
+	AllowAuth bool // Still allow login and token refresh while read-only
+}
+
+// readOnlyAuthPaths are the auth endpoints AllowAuth keeps available, so
+// clients can keep reading without being able to change anything
+var readOnlyAuthPaths = []string{"/auth/login", "/auth/refresh"}
+
+// NewReadOnlyConfigFromEnv creates read-only mode config from environment variables
+func NewReadOnlyConfigFromEnv() *ReadOnlyConfig {
+	return &ReadOnlyConfig{
+		Enabled:   getEnvBool("READ_ONLY_MODE", false),
+		AllowAuth: getEnvBool("READ_ONLY_ALLOW_AUTH", true),
+	}
+}
+
+// ReadOnly rejects mutating requests with 503 READ_ONLY while read-only mode
+// is enabled. GET, HEAD and OPTIONS requests are always served.
+func ReadOnly(config *ReadOnlyConfig) gin.HandlerFunc {
+	// If read-only mode is disabled, return a no-op middleware
+	if !config.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	logging.Logger.Warn("Read-only mode is enabled: mutating requests will be rejected")
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if config.AllowAuth && isReadOnlyAuthPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Code:    "READ_ONLY",
+			Message: "The service is in read-only mode. Please try again later.",
+		})
+		c.Abort()
+	}
+}
+
+// isReadOnlyAuthPath reports whether path is an auth endpoint allowed in read-only mode
+func isReadOnlyAuthPath(path string) bool {
+	for _, allowed := range readOnlyAuthPaths {
+		if strings.HasSuffix(path, allowed) {
+			return true
+		}
+	}
+	return false
+}
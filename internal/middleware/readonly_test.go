@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReadOnlyConfigFromEnv(t *testing.T) {
+	setupTest()
+
+	t.Run("disabled by default with auth allowed", func(t *testing.T) {
+		os.Unsetenv("READ_ONLY_MODE")
+		os.Unsetenv("READ_ONLY_ALLOW_AUTH")
+
+		config := NewReadOnlyConfigFromEnv()
+		assert.False(t, config.Enabled)
+		assert.True(t, config.AllowAuth)
+	})
+
+	t.Run("reads environment", func(t *testing.T) {
+		t.Setenv("READ_ONLY_MODE", "true")
+		t.Setenv("READ_ONLY_ALLOW_AUTH", "false")
+
+		config := NewReadOnlyConfigFromEnv()
+		assert.True(t, config.Enabled)
+		assert.False(t, config.AllowAuth)
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	setupTest()
+
+	newRouter := func(config *ReadOnlyConfig) *gin.Engine {
+		router := gin.New()
+		router.Use(ReadOnly(config))
+		router.GET("/api/v1/lists", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.HEAD("/api/v1/lists", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/api/v1/lists", func(c *gin.Context) { c.Status(http.StatusCreated) })
+		router.DELETE("/api/v1/lists/1", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		router.POST("/api/v1/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/api/v1/auth/register", func(c *gin.Context) { c.Status(http.StatusCreated) })
+		return router
+	}
+
+	serve := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, http.NoBody))
+		return w
+	}
+
+	t.Run("allows reads and rejects mutations when enabled", func(t *testing.T) {
+		router := newRouter(&ReadOnlyConfig{Enabled: true})
+
+		assert.Equal(t, http.StatusOK, serve(router, "GET", "/api/v1/lists").Code)
+		assert.Equal(t, http.StatusOK, serve(router, "HEAD", "/api/v1/lists").Code)
+
+		w := serve(router, "POST", "/api/v1/lists")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"READ_ONLY"`)
+
+		assert.Equal(t, http.StatusServiceUnavailable, serve(router, "DELETE", "/api/v1/lists/1").Code)
+	})
+
+	t.Run("optionally allows login and refresh", func(t *testing.T) {
+		allowed := newRouter(&ReadOnlyConfig{Enabled: true, AllowAuth: true})
+		assert.Equal(t, http.StatusOK, serve(allowed, "POST", "/api/v1/auth/login").Code)
+		assert.Equal(t, http.StatusServiceUnavailable, serve(allowed, "POST", "/api/v1/auth/register").Code)
+
+		blocked := newRouter(&ReadOnlyConfig{Enabled: true})
+		assert.Equal(t, http.StatusServiceUnavailable, serve(blocked, "POST", "/api/v1/auth/login").Code)
+	})
+
+	t.Run("passes everything through when disabled", func(t *testing.T) {
+		router := newRouter(&ReadOnlyConfig{})
+
+		assert.Equal(t, http.StatusCreated, serve(router, "POST", "/api/v1/lists").Code)
+		assert.Equal(t, http.StatusNoContent, serve(router, "DELETE", "/api/v1/lists/1").Code)
+	})
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the ID correlating a request with its log entries
+	RequestIDHeader = "X-Request-ID"
+	// ContextKeyRequestID is the context key for storing the request ID
+	ContextKeyRequestID = "request_id"
+
+	// maxRequestIDLength caps client-supplied request IDs so they cannot bloat logs
+	maxRequestIDLength = 128
+)
+
+// RequestID assigns every request an ID, taken from the X-Request-ID header
+// when the client sent a usable one and a new UUID otherwise. The ID is stored
+// in the context for the loggers and echoed back in the response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(ContextKeyRequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID assigned by RequestID, or "" outside it
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(ContextKeyRequestID)
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to log
+// and echo: at most maxRequestIDLength letters, digits and "-", "_", ".", ":"
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
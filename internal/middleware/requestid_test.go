@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todolist-api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	serve := func(requestID string) (*httptest.ResponseRecorder, string) {
+		var seen string
+		router := gin.New()
+		router.Use(RequestID())
+		router.GET("/test", func(c *gin.Context) {
+			seen = GetRequestID(c)
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/test", http.NoBody)
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w, seen
+	}
+
+	t.Run("generates a UUID when none is sent", func(t *testing.T) {
+		w, seen := serve("")
+		_, err := uuid.Parse(seen)
+		require.NoError(t, err)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("keeps a client-supplied ID", func(t *testing.T) {
+		w, seen := serve("client-req_42.a:1")
+		assert.Equal(t, "client-req_42.a:1", seen)
+		assert.Equal(t, "client-req_42.a:1", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("replaces overlong or unsafe IDs", func(t *testing.T) {
+		for _, bad := range []string{strings.Repeat("a", maxRequestIDLength+1), "id with spaces", "id\"quoted", "café"} {
+			w, seen := serve(bad)
+			_, err := uuid.Parse(seen)
+			assert.NoError(t, err, bad)
+			assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+		}
+
+		_, seen := serve(strings.Repeat("a", maxRequestIDLength))
+		assert.Equal(t, strings.Repeat("a", maxRequestIDLength), seen)
+	})
+}
+
+func TestRequestIDCorrelation(t *testing.T) {
+	setupTest()
+	gin.SetMode(gin.TestMode)
+
+	t.Run("request logs include the ID", func(t *testing.T) {
+		logging.InitLogger(&logging.LogConfig{Enabled: false, Level: "info", JSONFormat: true})
+		for name, logger := range map[string]gin.HandlerFunc{"RequestLogger": RequestLogger(), "StructuredLogger": StructuredLogger()} {
+			var buf bytes.Buffer
+			logging.Logger.SetOutput(&buf)
+
+			router := gin.New()
+			router.Use(RequestID(), logger)
+			router.GET("/test", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", http.NoBody)
+			req.Header.Set(RequestIDHeader, "trace-123")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Contains(t, buf.String(), `"request_id":"trace-123"`, name)
+		}
+	})
+
+	t.Run("the generic 500 body carries the ID", func(t *testing.T) {
+		logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+		router := gin.New()
+		router.Use(RequestID(), ErrorSanitizer())
+		router.GET("/test", func(c *gin.Context) {
+			_ = c.Error(assert.AnError)
+			c.Status(http.StatusInternalServerError)
+		})
+
+		req := httptest.NewRequest("GET", "/test", http.NoBody)
+		req.Header.Set(RequestIDHeader, "trace-500")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"INTERNAL_ERROR"`)
+		assert.Contains(t, w.Body.String(), `"requestId":"trace-500"`)
+	})
+}
@@ -169,22 +169,29 @@ func ErrorSanitizer() gin.HandlerFunc {
 			err := c.Errors.Last()
 
 			// Log the full error details
+			requestID := GetRequestID(c)
 			logging.Logger.WithFields(map[string]interface{}{
-				"client_ip": c.ClientIP(),
-				"path":      c.Request.URL.Path,
-				"method":    c.Request.Method,
-				"error":     err.Error(),
+				"client_ip":  c.ClientIP(),
+				"path":       c.Request.URL.Path,
+				"method":     c.Request.Method,
+				"error":      err.Error(),
+				"request_id": requestID,
 			}).Error("Request error")
 
 			// Don't expose internal error details to client
 			// The handler should have already set an appropriate response
 			// This is just a safety net
 			if c.Writer.Status() >= 500 {
-				// For 5xx errors, return generic message
-				c.JSON(http.StatusInternalServerError, gin.H{
+				// For 5xx errors, return generic message with the request ID
+				// users can quote when reporting the problem
+				body := gin.H{
 					"code":    "INTERNAL_ERROR",
 					"message": "An internal error occurred. Please try again later.",
-				})
+				}
+				if requestID != "" {
+					body["requestId"] = requestID
+				}
+				c.JSON(http.StatusInternalServerError, body)
 			}
 		}
 	}
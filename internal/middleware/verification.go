@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+
+	"todolist-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EmailVerifier reports whether a user has confirmed their email address
+type EmailVerifier interface {
+	IsEmailVerified(userID uuid.UUID) (bool, error)
+}
+
+// EmailVerificationConfig holds email verification gating configuration
+type EmailVerificationConfig struct {
+	Required bool // Reject gated requests from users who have not verified their email
+}
+
+// NewEmailVerificationConfigFromEnv creates email verification config from
+// environment variables
+func NewEmailVerificationConfigFromEnv() *EmailVerificationConfig {
+	return &EmailVerificationConfig{
+		Required: getEnvBool("REQUIRE_EMAIL_VERIFICATION", false),
+	}
+}
+
+// RequireVerifiedEmail rejects requests with 403 EMAIL_NOT_VERIFIED when
+// verification is required and the authenticated user has not confirmed their
+// email. It must run after AuthMiddleware; with no verifier (in-memory mode)
+// it lets every request through.
+func RequireVerifiedEmail(config *EmailVerificationConfig, verifier EmailVerifier) gin.HandlerFunc {
+	// If verification is not required, return a no-op middleware
+	if !config.Required || verifier == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		userID, err := GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    "UNAUTHORIZED",
+				Message: "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		verified, err := verifier.IsEmailVerified(userID)
+		if err != nil {
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to check email verification",
+			})
+			c.Abort()
+			return
+		}
+		if !verified {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    "EMAIL_NOT_VERIFIED",
+				Message: "Verify your email address before creating lists or todos",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
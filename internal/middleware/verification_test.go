@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVerifier reports users in verified as verified, failing with err if set
+type fakeVerifier struct {
+	verified map[uuid.UUID]bool
+	err      error
+}
+
+func (f *fakeVerifier) IsEmailVerified(userID uuid.UUID) (bool, error) {
+	return f.verified[userID], f.err
+}
+
+func TestNewEmailVerificationConfigFromEnv(t *testing.T) {
+	setupTest()
+
+	t.Run("not required by default", func(t *testing.T) {
+		os.Unsetenv("REQUIRE_EMAIL_VERIFICATION")
+		assert.False(t, NewEmailVerificationConfigFromEnv().Required)
+	})
+
+	t.Run("reads environment", func(t *testing.T) {
+		t.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+		assert.True(t, NewEmailVerificationConfigFromEnv().Required)
+	})
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	setupTest()
+
+	verifiedUser, unverifiedUser := uuid.New(), uuid.New()
+	verifier := &fakeVerifier{verified: map[uuid.UUID]bool{verifiedUser: true}}
+
+	serve := func(config *EmailVerificationConfig, v EmailVerifier, userID uuid.UUID) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.POST("/api/v1/lists", func(c *gin.Context) {
+			c.Set(ContextKeyUserID, userID)
+			c.Next()
+		}, RequireVerifiedEmail(config, v), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/lists", http.NoBody))
+		return w
+	}
+
+	t.Run("rejects unverified users when required", func(t *testing.T) {
+		config := &EmailVerificationConfig{Required: true}
+		assert.Equal(t, http.StatusCreated, serve(config, verifier, verifiedUser).Code)
+
+		w := serve(config, verifier, unverifiedUser)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"EMAIL_NOT_VERIFIED"`)
+	})
+
+	t.Run("reports lookup failures", func(t *testing.T) {
+		failing := &fakeVerifier{err: assert.AnError}
+		w := serve(&EmailVerificationConfig{Required: true}, failing, verifiedUser)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("passes everything through when not required or without a verifier", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, serve(&EmailVerificationConfig{}, verifier, unverifiedUser).Code)
+		assert.Equal(t, http.StatusCreated, serve(&EmailVerificationConfig{Required: true}, nil, unverifiedUser).Code)
+	})
+}
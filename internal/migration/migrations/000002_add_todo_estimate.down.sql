@@ -0,0 +1,2 @@
+-- Remove effort estimate from todos
+ALTER TABLE todos DROP COLUMN IF EXISTS estimate_minutes;
@@ -0,0 +1,2 @@
+-- Add optional effort estimate (in minutes) to todos
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER CHECK (estimate_minutes >= 0);
@@ -0,0 +1,4 @@
+-- Remove todo archiving
+DROP INDEX IF EXISTS idx_todos_archived_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS auto_archive_completed;
@@ -0,0 +1,4 @@
+-- Allow lists to archive todos automatically when they are completed
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS auto_archive_completed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_todos_archived_at ON todos(archived_at);
@@ -0,0 +1,11 @@
+-- Create user_settings table for per-user preferences
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    hide_completed BOOLEAN NOT NULL DEFAULT false,
+    default_sort_by VARCHAR(20) NOT NULL DEFAULT 'createdAt',
+    default_sort_order VARCHAR(4) NOT NULL DEFAULT 'asc',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Remove the completed todo retention opt-out
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS keep_completed;
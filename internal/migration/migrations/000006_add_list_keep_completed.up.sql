@@ -0,0 +1,2 @@
+-- Let lists opt out of the completed todo retention purge
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS keep_completed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Remove optimistic concurrency version counters
+ALTER TABLE todos DROP COLUMN IF EXISTS version;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS version;
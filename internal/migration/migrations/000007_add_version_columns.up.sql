@@ -0,0 +1,3 @@
+-- Optimistic concurrency version counters, incremented on every update
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Remove the per-list due date requirement
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS require_due_date;
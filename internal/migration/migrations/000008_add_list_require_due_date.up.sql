@@ -0,0 +1,2 @@
+-- Let lists require a due date on every new todo
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS require_due_date BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Drop todo tags
+DROP TABLE IF EXISTS todo_tags;
@@ -0,0 +1,10 @@
+-- Create todo_tags table holding lowercase tags per todo
+CREATE TABLE IF NOT EXISTS todo_tags (
+    todo_id UUID NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (todo_id, tag),
+    FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+-- Index tags for tag filtering
+CREATE INDEX IF NOT EXISTS idx_todo_tags_tag ON todo_tags(tag);
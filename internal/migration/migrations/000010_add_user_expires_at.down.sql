@@ -0,0 +1,3 @@
+-- Remove demo account expiry
+DROP INDEX IF EXISTS idx_users_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS expires_at;
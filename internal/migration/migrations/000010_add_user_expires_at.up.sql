@@ -0,0 +1,3 @@
+-- Let demo accounts expire; expired accounts are purged with their data
+ALTER TABLE users ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_users_expires_at ON users(expires_at);
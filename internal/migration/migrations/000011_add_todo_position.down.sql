@@ -0,0 +1,3 @@
+-- Remove manual todo ordering
+DROP INDEX IF EXISTS idx_todos_position;
+ALTER TABLE todos DROP COLUMN IF EXISTS position;
@@ -0,0 +1,12 @@
+-- Let todos be ordered manually within their list
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- Number existing todos in creation order
+UPDATE todos SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY created_at, id) - 1 AS position
+    FROM todos
+) AS ordered
+WHERE todos.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_todos_position ON todos(list_id, position);
@@ -0,0 +1,4 @@
+-- Drop subtasks
+ALTER TABLE todos DROP COLUMN IF EXISTS completed_subtask_count;
+ALTER TABLE todos DROP COLUMN IF EXISTS subtask_count;
+DROP TABLE IF EXISTS subtasks;
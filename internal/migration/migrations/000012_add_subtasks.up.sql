@@ -0,0 +1,17 @@
+-- Create subtasks table holding checklist items within a todo
+CREATE TABLE IF NOT EXISTS subtasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    todo_id UUID NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subtasks_position ON subtasks(todo_id, position);
+
+-- Keep subtask counts on the todo so todo reads need no extra query
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS subtask_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS completed_subtask_count INTEGER NOT NULL DEFAULT 0;
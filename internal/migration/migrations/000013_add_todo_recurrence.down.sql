@@ -0,0 +1,2 @@
+-- Remove todo recurrence
+ALTER TABLE todos DROP COLUMN IF EXISTS recurrence_rule;
@@ -0,0 +1,2 @@
+-- Let a todo recur; completing it creates the next occurrence
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(100) NOT NULL DEFAULT '';
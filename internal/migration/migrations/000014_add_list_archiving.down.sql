@@ -0,0 +1,7 @@
+-- Remove list archiving; fails if an archived list shares its name with another list
+DROP INDEX IF EXISTS idx_user_list_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_list_name ON todo_lists(user_id, name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_todo_lists_archived;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS archived;
@@ -0,0 +1,8 @@
+-- Let lists be archived instead of deleted
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_todo_lists_archived ON todo_lists(archived);
+
+-- List names only need to be unique among a user's unarchived lists
+DROP INDEX IF EXISTS idx_user_list_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_list_name ON todo_lists(user_id, name) WHERE deleted_at IS NULL AND archived = FALSE;
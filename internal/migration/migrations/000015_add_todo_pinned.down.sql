@@ -0,0 +1,2 @@
+-- Remove todo pinning
+ALTER TABLE todos DROP COLUMN IF EXISTS pinned;
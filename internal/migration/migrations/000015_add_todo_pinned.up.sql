@@ -0,0 +1,2 @@
+-- Let todos be pinned to the top of their list
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
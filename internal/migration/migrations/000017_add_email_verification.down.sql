@@ -0,0 +1,3 @@
+-- Drop email_verification_tokens table and the users.email_verified column
+DROP TABLE IF EXISTS email_verification_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,2 @@
+-- Drop acknowledged_notifications table
+DROP TABLE IF EXISTS acknowledged_notifications;
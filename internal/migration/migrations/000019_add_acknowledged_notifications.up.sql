@@ -0,0 +1,13 @@
+-- Create acknowledged_notifications table recording dismissed todo notifications
+CREATE TABLE IF NOT EXISTS acknowledged_notifications (
+    user_id UUID NOT NULL,
+    todo_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, todo_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+);
+
+-- Create indexes for acknowledged_notifications table
+CREATE INDEX IF NOT EXISTS idx_acknowledged_notifications_todo_id ON acknowledged_notifications(todo_id);
@@ -0,0 +1,2 @@
+-- Drop list_share_links table
+DROP TABLE IF EXISTS list_share_links;
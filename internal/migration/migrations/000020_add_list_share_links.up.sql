@@ -0,0 +1,13 @@
+-- Create list_share_links table holding hashed tokens for read-only public list links
+CREATE TABLE IF NOT EXISTS list_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    list_id UUID NOT NULL,
+    token_hash VARCHAR(255) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (list_id) REFERENCES todo_lists(id) ON DELETE CASCADE
+);
+
+-- Create indexes for list_share_links table
+CREATE INDEX IF NOT EXISTS idx_list_share_links_list_id ON list_share_links(list_id);
@@ -0,0 +1,17 @@
+-- Create idempotency_keys table holding the responses to create requests sent with an Idempotency-Key header
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT false,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    location VARCHAR(2048) NOT NULL DEFAULT '',
+    body BYTEA,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for idempotency_keys table
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_user_id ON idempotency_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
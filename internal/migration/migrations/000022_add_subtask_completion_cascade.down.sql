@@ -0,0 +1,4 @@
+-- Remove subtask completion times and the completion cascade settings
+ALTER TABLE subtasks DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS reopen_subtasks_with_parent;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS complete_subtasks_with_parent;
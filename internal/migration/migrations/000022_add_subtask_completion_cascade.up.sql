@@ -0,0 +1,6 @@
+-- Let lists carry a todo's completion over to its subtasks
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS complete_subtasks_with_parent BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS reopen_subtasks_with_parent BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Record when each subtask was completed
+ALTER TABLE subtasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
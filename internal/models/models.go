@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +17,9 @@ const (
 	PriorityHigh   Priority = "high"
 )
 
+// DefaultPriority is given to todos created without a priority
+const DefaultPriority = PriorityMedium
+
 // UserRole represents the role of a user
 type UserRole string
 
@@ -26,18 +30,25 @@ const (
 
 // User represents a registered user
 type User struct {
-	ID           uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
-	Email        string         `gorm:"uniqueIndex;not null;size:255" json:"email"`
-	PasswordHash string         `gorm:"not null;size:255" json:"-"` // Never expose password hash
-	FirstName    string         `gorm:"size:100" json:"firstName,omitempty"`
-	LastName     string         `gorm:"size:100" json:"lastName,omitempty"`
-	Role         UserRole       `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
-	IsActive     bool           `gorm:"default:true;index" json:"isActive"`
-	LastLoginAt  *time.Time     `gorm:"type:timestamp" json:"lastLoginAt,omitempty"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
-	TodoLists    []TodoList     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	Email         string         `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	PasswordHash  string         `gorm:"not null;size:255" json:"-"` // Never expose password hash
+	FirstName     string         `gorm:"size:100" json:"firstName,omitempty"`
+	LastName      string         `gorm:"size:100" json:"lastName,omitempty"`
+	Role          UserRole       `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+	IsActive      bool           `gorm:"default:true;index" json:"isActive"`
+	EmailVerified bool           `gorm:"not null;default:false" json:"emailVerified"`
+	LastLoginAt   *time.Time     `gorm:"type:timestamp" json:"lastLoginAt,omitempty"`
+	ExpiresAt     *time.Time     `gorm:"type:timestamp;index" json:"expiresAt,omitempty"` // Set only for demo accounts
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	TodoLists     []TodoList     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// IsExpired reports whether the user is a demo account whose lifetime has ended
+func (u *User) IsExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
 }
 
 // BeforeCreate hook to generate UUID if not set
@@ -56,6 +67,8 @@ type RefreshToken struct {
 	ExpiresAt time.Time      `gorm:"not null;index" json:"expiresAt"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	RevokedAt *time.Time     `gorm:"type:timestamp;index" json:"revokedAt,omitempty"`
+	UserAgent string         `gorm:"size:255;not null;default:''" json:"userAgent,omitempty"` // Client that logged in
+	IPAddress string         `gorm:"size:45;not null;default:''" json:"ipAddress,omitempty"`  // Address it logged in from
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 	User      User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 }
@@ -73,18 +86,106 @@ func (rt *RefreshToken) IsValid() bool {
 	return rt.RevokedAt == nil && time.Now().Before(rt.ExpiresAt)
 }
 
+// ClientInfo identifies the device a session is started from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session is an active login of the user: a refresh token that has neither
+// expired nor been revoked
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	IPAddress string    `json:"ipAddress,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Current   bool      `json:"current"` // Whether the request's access token belongs to this session
+}
+
+// PasswordHistory records a password hash a user previously had, so that
+// password changes can refuse recently used passwords
+type PasswordHistory struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	PasswordHash string    `gorm:"not null;size:255" json:"-"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"-"`
+	User         User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName pins the table name to password_history
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (ph *PasswordHistory) BeforeCreate(_ *gorm.DB) error {
+	if ph.ID == uuid.Nil {
+		ph.ID = uuid.New()
+	}
+	return nil
+}
+
+// PasswordResetToken is a single-use, time-limited token emailed to a user
+// who forgot their password. Only its hash is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	TokenHash string     `gorm:"uniqueIndex;not null;size:255" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp" json:"-"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"-"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (prt *PasswordResetToken) BeforeCreate(_ *gorm.DB) error {
+	if prt.ID == uuid.Nil {
+		prt.ID = uuid.New()
+	}
+	return nil
+}
+
+// EmailVerificationToken is a time-limited token emailed to a user to confirm
+// they own their email address. Only its hash is stored.
+type EmailVerificationToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	TokenHash string    `gorm:"uniqueIndex;not null;size:255" json:"-"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"-"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (evt *EmailVerificationToken) BeforeCreate(_ *gorm.DB) error {
+	if evt.ID == uuid.Nil {
+		evt.ID = uuid.New()
+	}
+	return nil
+}
+
 // TodoList represents a named list containing todos
 type TodoList struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"userId"`
-	Name        string         `gorm:"not null;size:100;index:idx_user_list_name,unique" json:"name" binding:"required,min=1,max=100"`
-	Description string         `gorm:"size:500" json:"description,omitempty" binding:"max=500"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-	User        User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
-	Todos       []Todo         `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE" json:"-"`
-	TodoCount   int            `gorm:"-" json:"todoCount"`
+	ID                         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID                     uuid.UUID      `gorm:"type:uuid;not null;index" json:"userId"`
+	Name                       string         `gorm:"not null;size:100" json:"name" binding:"required,min=1,max=100"`
+	Description                string         `gorm:"size:500" json:"description,omitempty" binding:"max=500"`
+	AutoArchiveCompleted       bool           `gorm:"not null;default:false" json:"autoArchiveCompleted"`
+	KeepCompleted              bool           `gorm:"not null;default:false" json:"keepCompleted"`
+	RequireDueDate             bool           `gorm:"not null;default:false" json:"requireDueDate"`
+	CompleteSubtasksWithParent bool           `gorm:"not null;default:false" json:"completeSubtasksWithParent"`
+	ReopenSubtasksWithParent   bool           `gorm:"not null;default:false" json:"reopenSubtasksWithParent"`
+	Archived                   bool           `gorm:"not null;default:false;index" json:"archived"`
+	ArchivedAt                 *time.Time     `gorm:"type:timestamp" json:"archivedAt,omitempty"`
+	Version                    int            `gorm:"not null;default:1" json:"version"`
+	CreatedAt                  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt                  time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt                  gorm.DeletedAt `gorm:"index" json:"-"`
+	User                       User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Todos                      []Todo         `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE" json:"-"`
+	TodoCount                  int            `gorm:"-" json:"todoCount"`
+	Changed                    FieldChanges   `gorm:"-" json:"changed,omitempty"`
 }
 
 // BeforeCreate hook to generate UUID if not set
@@ -97,28 +198,61 @@ func (t *TodoList) BeforeCreate(_ *gorm.DB) error {
 
 // CreateTodoListRequest represents the request to create a new todo list
 type CreateTodoListRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Description string `json:"description,omitempty" binding:"max=500"`
+	Name                       string `json:"name" binding:"required,min=1,list_name_length"`
+	Description                string `json:"description,omitempty" binding:"max=500"`
+	AutoArchiveCompleted       bool   `json:"autoArchiveCompleted,omitempty"`
+	KeepCompleted              bool   `json:"keepCompleted,omitempty"`
+	RequireDueDate             bool   `json:"requireDueDate,omitempty"`
+	CompleteSubtasksWithParent bool   `json:"completeSubtasksWithParent,omitempty"`
+	ReopenSubtasksWithParent   bool   `json:"reopenSubtasksWithParent,omitempty"`
 }
 
 // UpdateTodoListRequest represents the request to update a todo list
 type UpdateTodoListRequest struct {
-	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
-	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
+	Name                       *string `json:"name,omitempty" binding:"omitempty,min=1,list_name_length"`
+	Description                *string `json:"description,omitempty" binding:"omitempty,max=500"`
+	AutoArchiveCompleted       *bool   `json:"autoArchiveCompleted,omitempty"`
+	KeepCompleted              *bool   `json:"keepCompleted,omitempty"`
+	RequireDueDate             *bool   `json:"requireDueDate,omitempty"`
+	CompleteSubtasksWithParent *bool   `json:"completeSubtasksWithParent,omitempty"`
+	ReopenSubtasksWithParent   *bool   `json:"reopenSubtasksWithParent,omitempty"`
+	Version                    *int    `json:"version,omitempty"`
+}
+
+// ReorderTodoRequest represents the request to move a todo to a new position
+// in its list. Positions past the end move the todo last.
+type ReorderTodoRequest struct {
+	Position *int `json:"position" binding:"required,min=0"`
+}
+
+// MergeListsRequest represents the request to merge another list into a list
+type MergeListsRequest struct {
+	SourceListID uuid.UUID `json:"sourceListId" binding:"required"`
 }
 
 // Todo represents a todo item within a list
 type Todo struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
-	ListID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"listId"`
-	Description string         `gorm:"not null;size:500" json:"description" binding:"required,min=1,max=500"`
-	Priority    Priority       `gorm:"type:varchar(10);not null" json:"priority" binding:"required,oneof=low medium high"`
-	DueDate     *time.Time     `gorm:"type:timestamp" json:"dueDate,omitempty"`
-	Completed   bool           `gorm:"default:false;index" json:"completed"`
-	CompletedAt *time.Time     `gorm:"type:timestamp" json:"completedAt,omitempty"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                    uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	ListID                uuid.UUID      `gorm:"type:uuid;not null;index;index:idx_todos_position,priority:1" json:"listId"`
+	Description           string         `gorm:"not null;size:500" json:"description" binding:"required,min=1,max=500"`
+	Priority              Priority       `gorm:"type:varchar(10);not null" json:"priority" binding:"required,oneof=low medium high"`
+	DueDate               *time.Time     `gorm:"type:timestamp" json:"dueDate,omitempty"`
+	EstimateMinutes       *int           `json:"estimateMinutes,omitempty"`
+	Completed             bool           `gorm:"default:false;index" json:"completed"`
+	CompletedAt           *time.Time     `gorm:"type:timestamp" json:"completedAt,omitempty"`
+	ArchivedAt            *time.Time     `gorm:"type:timestamp;index" json:"archivedAt,omitempty"`
+	Position              int            `gorm:"not null;default:0;index:idx_todos_position,priority:2" json:"position"`
+	Pinned                bool           `gorm:"not null;default:false" json:"pinned"`
+	SubtaskCount          int            `gorm:"not null;default:0" json:"subtaskCount"`
+	CompletedSubtaskCount int            `gorm:"not null;default:0" json:"completedSubtaskCount"`
+	RecurrenceRule        string         `gorm:"size:100;not null;default:''" json:"recurrenceRule,omitempty"`
+	Version               int            `gorm:"not null;default:1" json:"version"`
+	Tags                  []string       `gorm:"-" json:"tags,omitempty"`
+	Changed               FieldChanges   `gorm:"-" json:"changed,omitempty"`
+	NextOccurrence        *Todo          `gorm:"-" json:"nextOccurrence,omitempty"`
+	CreatedAt             time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt             time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // BeforeCreate hook to generate UUID if not set
@@ -129,19 +263,347 @@ func (t *Todo) BeforeCreate(_ *gorm.DB) error {
 	return nil
 }
 
+// TodoTag attaches a lowercase tag to a todo
+type TodoTag struct {
+	TodoID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Tag    string    `gorm:"size:50;primaryKey;index" json:"-"`
+	Todo   Todo      `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName pins the table name to todo_tags
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
+
+// Subtask is a checklist item within a todo
+type Subtask struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TodoID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_subtasks_position,priority:1" json:"todoId"`
+	Description string     `gorm:"not null;size:500" json:"description"`
+	Completed   bool       `gorm:"not null;default:false" json:"completed"`
+	CompletedAt *time.Time `gorm:"type:timestamp" json:"completedAt,omitempty"`
+	Position    int        `gorm:"not null;default:0;index:idx_subtasks_position,priority:2" json:"position"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+	Todo        Todo       `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (s *Subtask) BeforeCreate(_ *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateSubtaskRequest represents the request to add a subtask to a todo
+type CreateSubtaskRequest struct {
+	Description string `json:"description" binding:"required,min=1,todo_description_length"`
+	Completed   bool   `json:"completed,omitempty"`
+}
+
+// UpdateSubtaskRequest represents the request to update a subtask. With
+// CompleteParent set, completing the last open subtask also completes the todo.
+type UpdateSubtaskRequest struct {
+	Description    *string `json:"description,omitempty" binding:"omitempty,min=1,todo_description_length"`
+	Completed      *bool   `json:"completed,omitempty"`
+	CompleteParent bool    `json:"completeParent,omitempty"`
+}
+
 // CreateTodoRequest represents the request to create a new todo
 type CreateTodoRequest struct {
-	Description string     `json:"description" binding:"required,min=1,max=500"`
-	Priority    Priority   `json:"priority" binding:"required,oneof=low medium high"`
-	DueDate     *time.Time `json:"dueDate,omitempty"`
+	Description     string     `json:"description" binding:"required,min=1,todo_description_length"`
+	Priority        Priority   `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"` // Empty means DefaultPriority
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	EstimateMinutes *int       `json:"estimateMinutes,omitempty" binding:"omitempty,min=0"`
+	Completed       bool       `json:"completed,omitempty"`
+	Tags            []string   `json:"tags,omitempty" binding:"omitempty,max=10,dive,min=1,max=50"`
+	RecurrenceRule  string     `json:"recurrenceRule,omitempty" binding:"omitempty,recurrence_rule"`
+}
+
+// ImportTextRequest represents the request to import todos from plain text, one per line
+type ImportTextRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// ImportResult summarizes an import: how many todos were created, how many
+// rows were skipped as invalid, and why each skipped row was rejected
+type ImportResult struct {
+	Imported int                      `json:"imported"`
+	Skipped  int                      `json:"skipped"`
+	Errors   []map[string]interface{} `json:"errors"`
 }
 
 // UpdateTodoRequest represents the request to update a todo
 type UpdateTodoRequest struct {
-	Description *string    `json:"description,omitempty" binding:"omitempty,min=1,max=500"`
-	Priority    *Priority  `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	Description     *string    `json:"description,omitempty" binding:"omitempty,min=1,todo_description_length"`
+	Priority        *Priority  `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	Completed       *bool      `json:"completed,omitempty"`
+	EstimateMinutes *int       `json:"estimateMinutes,omitempty" binding:"omitempty,min=0"`
+	Tags            *[]string  `json:"tags,omitempty" binding:"omitempty,max=10,dive,min=1,max=50"`
+	RecurrenceRule  *string    `json:"recurrenceRule,omitempty" binding:"omitempty,recurrence_rule"`
+	Version         *int       `json:"version,omitempty"`
+}
+
+// Batch update actions for BatchUpdateTodosRequest
+const (
+	BatchActionComplete   = "complete"
+	BatchActionUncomplete = "uncomplete"
+	BatchActionDelete     = "delete"
+)
+
+// BatchUpdateTodosRequest represents the request to apply either an action or
+// a set of field updates to several todos in a list
+type BatchUpdateTodosRequest struct {
+	IDs     []uuid.UUID        `json:"ids" binding:"required,min=1,max=100"`
+	Action  string             `json:"action,omitempty" binding:"omitempty,oneof=complete uncomplete delete"`
+	Updates *UpdateTodoRequest `json:"updates,omitempty"`
+}
+
+// FieldChange holds the previous and current value of a field changed by an update
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// FieldChanges maps the JSON name of each field an update changed to its old
+// and new values. Only returned when the client asks for it with ?includeDiff=true.
+type FieldChanges map[string]FieldChange
+
+// BatchResult reports how many todos a batch operation changed and which of
+// the requested IDs were not found in the list. Completing recurring todos in
+// a batch also reports the next occurrences it created.
+type BatchResult struct {
+	Affected        int         `json:"affected"`
+	NotFound        []uuid.UUID `json:"notFound"`
+	NextOccurrences []Todo      `json:"nextOccurrences,omitempty"`
+}
+
+// BatchItemResult is the outcome for one ID of a batch operation, reported in
+// a 207 Multi-Status response
+type BatchItemResult struct {
+	ID     uuid.UUID `json:"id"`
+	Status int       `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// BatchMultiStatus is the 207 Multi-Status body of a batch operation whose
+// IDs partly succeeded, listing each requested ID in request order
+type BatchMultiStatus struct {
+	Results []BatchItemResult `json:"results"`
+}
+
+// TriageTodosRequest represents the request to reprioritize overdue todos.
+// Without a priority they are raised to high.
+type TriageTodosRequest struct {
+	Priority *Priority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+}
+
+// TriageResult reports how many overdue todos a triage changed to priority
+type TriageResult struct {
+	Affected int      `json:"affected"`
+	Priority Priority `json:"priority"`
+}
+
+// CloneTodoRequest represents the request to clone a todo, optionally into another list
+type CloneTodoRequest struct {
+	TargetListID *uuid.UUID `json:"targetListId,omitempty"`
+}
+
+// DeletedTodo is a soft-deleted todo in a list's trash
+type DeletedTodo struct {
+	Todo
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// MoveTodoRequest represents the request to move a todo to another list
+type MoveTodoRequest struct {
+	TargetListID uuid.UUID `json:"targetListId" binding:"required"`
+}
+
+// ListSummary counts a user's lists by state
+type ListSummary struct {
+	Active   int `json:"active"`
+	Archived int `json:"archived"`
+	Total    int `json:"total"`
+}
+
+// ListStats represents aggregate statistics for a single todo list
+type ListStats struct {
+	ListID                   uuid.UUID      `json:"listId"`
+	Total                    int            `json:"total"`
+	Completed                int            `json:"completed"`
+	Incomplete               int            `json:"incomplete"`
+	Overdue                  int            `json:"overdue"`
+	ByPriority               PriorityCounts `json:"byPriority"`
+	TotalEstimateMinutes     int            `json:"totalEstimateMinutes"`
+	RemainingEstimateMinutes int            `json:"remainingEstimateMinutes"`
+}
+
+// UserStats summarizes a user's todos across all of their lists
+type UserStats struct {
+	Lists               int     `json:"lists"`
+	TotalTodos          int     `json:"totalTodos"`
+	CompletedTodos      int     `json:"completedTodos"`
+	CompletionRate      float64 `json:"completionRate"`
+	CompletedLast7Days  int     `json:"completedLast7Days"`
+	CompletedLast30Days int     `json:"completedLast30Days"`
+	Overdue             int     `json:"overdue"`
+}
+
+// PriorityCounts counts todos by priority
+type PriorityCounts struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+// Add counts n more todos of the given priority; unknown priorities are ignored
+func (p *PriorityCounts) Add(priority Priority, n int) {
+	switch priority {
+	case PriorityHigh:
+		p.High += n
+	case PriorityMedium:
+		p.Medium += n
+	case PriorityLow:
+		p.Low += n
+	}
+}
+
+// TodoWithList is a todo annotated with the name of the list it belongs to,
+// used by views that span multiple lists
+type TodoWithList struct {
+	Todo
+	ListName string `json:"listName"`
+}
+
+// NotificationType is why a todo needs the user's attention
+type NotificationType string
+
+const (
+	NotificationOverdue NotificationType = "overdue"
+	NotificationDueSoon NotificationType = "due_soon"
+)
+
+// Notification points the user at an incomplete todo that is overdue or due
+// soon. Notifications are computed on request rather than stored.
+type Notification struct {
+	ID          string           `json:"id"` // "<type>:<todoId>", stable until acknowledged
+	Type        NotificationType `json:"type"`
+	TodoID      uuid.UUID        `json:"todoId"`
+	ListID      uuid.UUID        `json:"listId"`
+	ListName    string           `json:"listName"`
+	Description string           `json:"description"`
+	Priority    Priority         `json:"priority"`
+	DueDate     time.Time        `json:"dueDate"`
+}
+
+// NotificationID returns the ID of the notification of a type for a todo
+func NotificationID(notificationType NotificationType, todoID uuid.UUID) string {
+	return string(notificationType) + ":" + todoID.String()
+}
+
+// ParseNotificationID splits a notification ID into its type and todo ID,
+// reporting false if it is not a valid ID
+func ParseNotificationID(id string) (NotificationType, uuid.UUID, bool) {
+	typ, rawTodoID, found := strings.Cut(id, ":")
+	notificationType := NotificationType(typ)
+	if !found || (notificationType != NotificationOverdue && notificationType != NotificationDueSoon) {
+		return "", uuid.Nil, false
+	}
+	todoID, err := uuid.Parse(rawTodoID)
+	if err != nil {
+		return "", uuid.Nil, false
+	}
+	return notificationType, todoID, true
+}
+
+// AcknowledgedNotification records that a user dismissed a notification of
+// a type for a todo, so it is not shown again
+type AcknowledgedNotification struct {
+	UserID    uuid.UUID        `gorm:"type:uuid;primaryKey" json:"-"`
+	TodoID    uuid.UUID        `gorm:"type:uuid;primaryKey" json:"-"`
+	Type      NotificationType `gorm:"type:varchar(20);primaryKey" json:"-"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"-"`
+	User      User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Todo      Todo             `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// IdempotencyKey records the response to a create request sent with an
+// Idempotency-Key header, so a retry with the same key gets that response
+// back instead of creating a duplicate. KeyHash is a SHA-256 hash of the key
+// scoped to the user, method and path. Until the request finishes the record
+// is pending (Completed false) and ExpiresAt is a short lease.
+type IdempotencyKey struct {
+	KeyHash     string    `gorm:"primaryKey;size:64" json:"-"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	Completed   bool      `gorm:"not null;default:false" json:"-"`
+	StatusCode  int       `gorm:"not null;default:0" json:"-"`
+	ContentType string    `gorm:"size:255;not null;default:''" json:"-"`
+	Location    string    `gorm:"size:2048;not null;default:''" json:"-"`
+	Body        []byte    `json:"-"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"-"`
+	User        User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// ListShareLink grants anyone holding its token read-only access to a list
+// until it expires or is revoked. Only a SHA-256 hash of the token is stored;
+// Token is filled in once, when the link is created.
+type ListShareLink struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ListID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"listId"`
+	TokenHash string     `gorm:"uniqueIndex;not null;size:255" json:"-"`
+	Token     string     `gorm:"-" json:"token,omitempty"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt *time.Time `gorm:"type:timestamp" json:"-"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	List      TodoList   `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (l *ListShareLink) BeforeCreate(_ *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateShareLinkRequest represents the request to share a list read-only
+type CreateShareLinkRequest struct {
+	// ExpiresInHours is how long the link works (0 = the server default)
+	ExpiresInHours int `json:"expiresInHours" binding:"omitempty,min=1,max=8760"`
+}
+
+// ShareLinkResponse is a newly created share link with the public URL to hand out
+type ShareLinkResponse struct {
+	ListShareLink
+	URL string `json:"url"`
+}
+
+// SharedList is the public, read-only view of a list opened through a share
+// link. It leaves out IDs and anything else that identifies the owner.
+type SharedList struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Todos       []SharedTodo `json:"todos"`
+	ExpiresAt   time.Time    `json:"expiresAt"`
+}
+
+// SharedTodo is a todo as shown in a SharedList
+type SharedTodo struct {
+	Description string     `json:"description"`
+	Priority    Priority   `json:"priority"`
 	DueDate     *time.Time `json:"dueDate,omitempty"`
-	Completed   *bool      `json:"completed,omitempty"`
+	Completed   bool       `json:"completed"`
+}
+
+// TodoListWithTodos is a todo list with its first active todos embedded, used
+// by GET /lists?expand=todos
+type TodoListWithTodos struct {
+	TodoList
+	Todos []Todo `json:"todos"`
 }
 
 // Pagination represents pagination information
@@ -158,6 +620,45 @@ type PaginatedListsResponse struct {
 	Pagination *Pagination `json:"pagination"`
 }
 
+// PaginatedListsWithTodosResponse represents a paginated response of todo
+// lists with their todos embedded
+type PaginatedListsWithTodosResponse struct {
+	Data       []TodoListWithTodos `json:"data"`
+	Pagination *Pagination         `json:"pagination"`
+}
+
+// PaginatedListTodosResponse represents a paginated response of the todos in one list
+type PaginatedListTodosResponse struct {
+	Data       []Todo      `json:"data"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+// TodoGroup is one group of a grouped todo listing, such as the high
+// priority todos or those due today
+type TodoGroup struct {
+	Key   string `json:"key"`
+	Todos []Todo `json:"todos"`
+}
+
+// GroupedListTodosResponse represents a page of the todos in one list split
+// into groups (see groupBy)
+type GroupedListTodosResponse struct {
+	Groups     []TodoGroup `json:"groups"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+// PaginatedTodosResponse represents a paginated response of todos across lists
+type PaginatedTodosResponse struct {
+	Data       []TodoWithList `json:"data"`
+	Pagination *Pagination    `json:"pagination"`
+}
+
+// PaginatedUsersResponse represents a paginated response of users, for admins
+type PaginatedUsersResponse struct {
+	Data       []User      `json:"data"`
+	Pagination *Pagination `json:"pagination"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Code    string                 `json:"code"`
@@ -165,20 +666,30 @@ type ErrorResponse struct {
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
+// FieldError describes one invalid request field in an error response's
+// details.fields: the field's JSON name, the rule it broke and a readable
+// message
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
 // Authentication DTOs
 
 // RegisterRequest represents a user registration request
 type RegisterRequest struct {
 	Email     string `json:"email" binding:"required,email,max=255"`
-	Password  string `json:"password" binding:"required,min=8,max=72"` // bcrypt max is 72 bytes
+	Password  string `json:"password" binding:"required"` // Length and complexity are checked against the password policy
 	FirstName string `json:"firstName,omitempty" binding:"max=100"`
 	LastName  string `json:"lastName,omitempty" binding:"max=100"`
 }
 
 // LoginRequest represents a user login request
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email    string     `json:"email" binding:"required,email"`
+	Password string     `json:"password" binding:"required"`
+	Client   ClientInfo `json:"-"` // Filled in by the handler, recorded on the session
 }
 
 // RefreshTokenRequest represents a refresh token request
@@ -195,19 +706,54 @@ type AuthResponse struct {
 	User         *UserInfo `json:"user"`
 }
 
+// TokenStatus reports whether an access token is still accepted and how long
+// it has left, so clients can refresh before it expires
+type TokenStatus struct {
+	Valid            bool       `json:"valid"`
+	Reason           string     `json:"reason,omitempty"` // Why the token is not valid: expired, invalid or inactive
+	UserID           *uuid.UUID `json:"userId,omitempty"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	SecondsRemaining int64      `json:"secondsRemaining"`
+}
+
 // UserInfo represents public user information (safe to expose)
 type UserInfo struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"firstName,omitempty"`
-	LastName  string    `json:"lastName,omitempty"`
-	Role      UserRole  `json:"role"`
+	ID            uuid.UUID  `json:"id"`
+	Email         string     `json:"email"`
+	FirstName     string     `json:"firstName,omitempty"`
+	LastName      string     `json:"lastName,omitempty"`
+	Role          UserRole   `json:"role"`
+	EmailVerified bool       `json:"emailVerified"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"` // When a demo account and its data are deleted
 }
 
 // ChangePasswordRequest represents a password change request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword" binding:"required"`
-	NewPassword     string `json:"newPassword" binding:"required,min=8,max=72"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+// DeleteAccountRequest confirms account deletion with the current password
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// AdminUpdateUserRequest represents an admin's change to a user's account;
+// fields left out are unchanged
+type AdminUpdateUserRequest struct {
+	IsActive *bool     `json:"isActive,omitempty"`
+	Role     *UserRole `json:"role,omitempty" binding:"omitempty,oneof=user admin"`
+}
+
+// ForgotPasswordRequest represents a request for a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents a password reset using an emailed token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
 }
 
 // UpdateProfileRequest represents a profile update request
@@ -215,3 +761,39 @@ type UpdateProfileRequest struct {
 	FirstName *string `json:"firstName,omitempty" binding:"omitempty,max=100"`
 	LastName  *string `json:"lastName,omitempty" binding:"omitempty,max=100"`
 }
+
+// UserSettings holds per-user preferences, stored one row per user
+type UserSettings struct {
+	UserID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Timezone         string    `gorm:"size:64;not null;default:'UTC'" json:"timezone"`
+	HideCompleted    bool      `gorm:"not null;default:false" json:"hideCompleted"`
+	DefaultSortBy    string    `gorm:"size:20;not null;default:'createdAt'" json:"defaultSortBy"`
+	DefaultSortOrder string    `gorm:"size:4;not null;default:'asc'" json:"defaultSortOrder"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+	User             User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// DefaultUserSettings returns the settings used for users who have not saved any
+func DefaultUserSettings(userID uuid.UUID) *UserSettings {
+	return &UserSettings{
+		UserID:           userID,
+		Timezone:         "UTC",
+		DefaultSortBy:    "createdAt",
+		DefaultSortOrder: "asc",
+	}
+}
+
+// Saved reports whether the settings were loaded from a stored row, rather
+// than being DefaultUserSettings for a user who never saved any
+func (s *UserSettings) Saved() bool {
+	return !s.CreatedAt.IsZero()
+}
+
+// UpdateUserSettingsRequest represents a user settings update request
+type UpdateUserSettingsRequest struct {
+	Timezone         *string `json:"timezone,omitempty" binding:"omitempty,max=64"`
+	HideCompleted    *bool   `json:"hideCompleted,omitempty"`
+	DefaultSortBy    *string `json:"defaultSortBy,omitempty" binding:"omitempty,oneof=dueDate priority createdAt position"`
+	DefaultSortOrder *string `json:"defaultSortOrder,omitempty" binding:"omitempty,oneof=asc desc"`
+}
@@ -42,6 +42,14 @@ func TestTodoListBeforeCreate(t *testing.T) {
 		user_id TEXT NOT NULL,
 		name TEXT NOT NULL,
 		description TEXT,
+		auto_archive_completed INTEGER DEFAULT 0,
+		keep_completed INTEGER DEFAULT 0,
+		require_due_date INTEGER DEFAULT 0,
+		complete_subtasks_with_parent INTEGER DEFAULT 0,
+		reopen_subtasks_with_parent INTEGER DEFAULT 0,
+		archived INTEGER NOT NULL DEFAULT 0,
+		archived_at DATETIME,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -88,6 +96,14 @@ func TestTodoBeforeCreate(t *testing.T) {
 		user_id TEXT NOT NULL,
 		name TEXT NOT NULL,
 		description TEXT,
+		auto_archive_completed INTEGER DEFAULT 0,
+		keep_completed INTEGER DEFAULT 0,
+		require_due_date INTEGER DEFAULT 0,
+		complete_subtasks_with_parent INTEGER DEFAULT 0,
+		reopen_subtasks_with_parent INTEGER DEFAULT 0,
+		archived INTEGER NOT NULL DEFAULT 0,
+		archived_at DATETIME,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -100,8 +116,16 @@ func TestTodoBeforeCreate(t *testing.T) {
 		description TEXT NOT NULL,
 		priority TEXT NOT NULL,
 		due_date DATETIME,
+		estimate_minutes INTEGER,
 		completed INTEGER DEFAULT 0,
 		completed_at DATETIME,
+		archived_at DATETIME,
+		position INTEGER NOT NULL DEFAULT 0,
+		subtask_count INTEGER NOT NULL DEFAULT 0,
+		completed_subtask_count INTEGER NOT NULL DEFAULT 0,
+		recurrence_rule TEXT NOT NULL DEFAULT '',
+		pinned INTEGER NOT NULL DEFAULT 0,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME,
@@ -292,7 +316,9 @@ func TestUserBeforeCreate(t *testing.T) {
 		last_name TEXT NOT NULL,
 		role TEXT NOT NULL DEFAULT 'user',
 		is_active INTEGER DEFAULT 1,
+		email_verified INTEGER NOT NULL DEFAULT 0,
 		last_login_at DATETIME,
+		expires_at DATETIME,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -346,7 +372,9 @@ func TestRefreshTokenBeforeCreate(t *testing.T) {
 		last_name TEXT NOT NULL,
 		role TEXT NOT NULL DEFAULT 'user',
 		is_active INTEGER DEFAULT 1,
+		email_verified INTEGER NOT NULL DEFAULT 0,
 		last_login_at DATETIME,
+		expires_at DATETIME,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -361,6 +389,8 @@ func TestRefreshTokenBeforeCreate(t *testing.T) {
 		expires_at DATETIME NOT NULL,
 		created_at DATETIME,
 		revoked_at DATETIME,
+		user_agent TEXT NOT NULL DEFAULT '',
+		ip_address TEXT NOT NULL DEFAULT '',
 		deleted_at DATETIME,
 		FOREIGN KEY(user_id) REFERENCES users(id)
 	)`).Error
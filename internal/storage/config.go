@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"todolist-api/internal/logging"
+)
+
+const (
+	// DeleteModeSoft keeps deleted lists and todos as tombstones
+	DeleteModeSoft = "soft"
+	// DeleteModeHard removes deleted lists and todos permanently
+	DeleteModeHard = "hard"
+)
+
+// Config holds storage configuration
+type Config struct {
+	MaxDBConcurrency   int           // Maximum concurrent write operations (0 = unlimited)
+	DBBusyTimeout      time.Duration // How long a write waits for a free slot before failing
+	CompletedRetention time.Duration // Age after which completed todos are purged (0 = keep forever)
+	RetentionInterval  time.Duration // How often the completed-todo purge runs
+	TrashRetention     time.Duration // Age after which deleted todos are purged from the trash (0 = keep forever)
+	DeleteMode         string        // DeleteModeSoft or DeleteModeHard
+	// Reject open todos repeating another open todo's description in a list, ignoring case
+	UniqueTodoDescriptions bool
+}
+
+// NewConfigFromEnv creates a storage config from environment variables
+func NewConfigFromEnv() *Config {
+	return &Config{
+		MaxDBConcurrency:       getEnvInt("MAX_DB_CONCURRENCY", 0),
+		DBBusyTimeout:          time.Duration(getEnvInt("DB_BUSY_TIMEOUT_MS", 5000)) * time.Millisecond,
+		CompletedRetention:     getEnvDuration("COMPLETED_RETENTION", 0),
+		RetentionInterval:      getEnvPositiveDuration("COMPLETED_RETENTION_INTERVAL", time.Hour),
+		TrashRetention:         time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 0)) * 24 * time.Hour,
+		DeleteMode:             getEnvDeleteMode("DELETE_MODE"),
+		UniqueTodoDescriptions: getEnvBool("UNIQUE_TODO_DESCRIPTIONS", false),
+	}
+}
+
+// getEnvDeleteMode retrieves the delete mode from an environment variable,
+// defaulting to soft deletes when unset or unrecognized
+func getEnvDeleteMode(key string) string {
+	if os.Getenv(key) == DeleteModeHard {
+		return DeleteModeHard
+	}
+	return DeleteModeSoft
+}
+
+// getEnvInt retrieves an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool retrieves a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration retrieves a duration environment variable (e.g. "720h") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvPositiveDuration retrieves a duration environment variable that must be
+// positive, such as a ticker interval, falling back to the default with a
+// warning when it is zero or negative
+func getEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnvDuration(key, defaultValue)
+	if value <= 0 {
+		logging.Logger.Warnf("%s must be positive, got %s; using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"todolist-api/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigFromEnvRetentionInterval(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", time.Hour},
+		{"15m", 15 * time.Minute},
+		{"0s", time.Hour},
+		{"-1m", time.Hour},
+		{"soon", time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run("COMPLETED_RETENTION_INTERVAL="+tt.value, func(t *testing.T) {
+			t.Setenv("COMPLETED_RETENTION_INTERVAL", tt.value)
+			assert.Equal(t, tt.want, NewConfigFromEnv().RetentionInterval)
+		})
+	}
+}
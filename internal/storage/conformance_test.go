@@ -0,0 +1,2008 @@
+package storage
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// StoreFactory returns a fresh, empty Store for a single conformance case
+type StoreFactory func(t *testing.T) Store
+
+// conformanceCase is a single behavior every Store implementation must share
+type conformanceCase struct {
+	name string
+	run  func(t *testing.T, store Store, userID uuid.UUID)
+}
+
+// StoreConformanceSuite runs the shared behavioral contract against a Store
+// implementation. Each case gets its own store from newStore so cases are
+// independent of each other and of execution order.
+func StoreConformanceSuite(t *testing.T, newStore StoreFactory, userID uuid.UUID) {
+	runConformanceCases(t, conformanceCases, newStore, userID)
+}
+
+func runConformanceCases(t *testing.T, cases []conformanceCase, newStore StoreFactory, userID uuid.UUID) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newStore(t), userID)
+		})
+	}
+}
+
+func TestStoreConformance(t *testing.T) {
+	// Both delete modes must be indistinguishable through the Store interface
+	for _, mode := range []string{DeleteModeSoft, DeleteModeHard} {
+		config := &Config{DeleteMode: mode}
+
+		t.Run("memory/"+mode, func(t *testing.T) {
+			StoreConformanceSuite(t, func(_ *testing.T) Store {
+				return NewStorageWithConfig(config)
+			}, testMemoryUserID)
+		})
+
+		t.Run("postgres/"+mode, func(t *testing.T) {
+			StoreConformanceSuite(t, func(t *testing.T) Store {
+				db := testutil.SetupTestDB(t)
+				t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+				return NewPostgresStorageWithConfig(db, config)
+			}, testUserID)
+		})
+	}
+}
+
+// TestStoreTrashConformance runs the trash cases, which only hold in soft
+// delete mode, against both Store implementations
+func TestStoreTrashConformance(t *testing.T) {
+	config := &Config{DeleteMode: DeleteModeSoft}
+
+	t.Run("memory", func(t *testing.T) {
+		runConformanceCases(t, trashCases, func(_ *testing.T) Store {
+			return NewStorageWithConfig(config)
+		}, testMemoryUserID)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		runConformanceCases(t, trashCases, func(t *testing.T) Store {
+			db := testutil.SetupTestDB(t)
+			t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+			return NewPostgresStorageWithConfig(db, config)
+		}, testUserID)
+	})
+}
+
+// mustCreateList creates a list or fails the test
+func mustCreateList(t *testing.T, store Store, userID uuid.UUID, name string) *models.TodoList {
+	t.Helper()
+	list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: name})
+	require.NoError(t, err)
+	return list
+}
+
+// mustCreateTodo creates a todo or fails the test
+func mustCreateTodo(t *testing.T, store Store, userID, listID uuid.UUID, req models.CreateTodoRequest) *models.Todo {
+	t.Helper()
+	todo, err := store.CreateTodo(userID, listID, req)
+	require.NoError(t, err)
+	return todo
+}
+
+// descriptions extracts todo descriptions in order
+func descriptions(todos []models.Todo) []string {
+	result := make([]string, len(todos))
+	for i := range todos {
+		result[i] = todos[i].Description
+	}
+	return result
+}
+
+var conformanceCases = []conformanceCase{
+	// List CRUD
+	{"list create and get round-trip", func(t *testing.T, store Store, userID uuid.UUID) {
+		created, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Work", Description: "Desk"})
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, created.ID)
+		assert.Equal(t, userID, created.UserID)
+
+		got, err := store.GetListByID(userID, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Work", got.Name)
+		assert.Equal(t, "Desk", got.Description)
+		assert.Equal(t, 0, got.TodoCount)
+	}},
+	{"list update changes only provided fields", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Work", Description: "Desk"})
+		require.NoError(t, err)
+
+		desc := "Office"
+		updated, err := store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{Description: &desc})
+		require.NoError(t, err)
+		assert.Equal(t, "Work", updated.Name)
+		assert.Equal(t, "Office", updated.Description)
+	}},
+	{"list delete removes the list and hides its todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Doomed")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "A", Priority: models.PriorityLow})
+
+		require.NoError(t, store.DeleteList(userID, list.ID, nil))
+
+		_, err := store.GetListByID(userID, list.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		assert.ErrorIs(t, store.DeleteList(userID, list.ID, nil), ErrListNotFound)
+	}},
+	{"list summary counts only the user's remaining lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		summary, err := store.CountLists(userID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ListSummary{}, *summary)
+
+		mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		doomed := mustCreateList(t, store, userID, "Doomed")
+		mustCreateList(t, store, uuid.New(), "Foreign")
+		require.NoError(t, store.DeleteList(userID, doomed.ID, nil))
+		_, err = store.ArchiveList(userID, home.ID)
+		require.NoError(t, err)
+
+		summary, err = store.CountLists(userID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ListSummary{Active: 1, Archived: 1, Total: 2}, *summary)
+	}},
+	{"archived lists are hidden from the default listing", func(t *testing.T, store Store, userID uuid.UUID) {
+		mustCreateList(t, store, userID, "Active")
+		done := mustCreateList(t, store, userID, "Done")
+		version := done.Version
+
+		archived, err := store.ArchiveList(userID, done.ID)
+		require.NoError(t, err)
+		assert.True(t, archived.Archived)
+		require.NotNil(t, archived.ArchivedAt)
+		assert.Equal(t, version+1, archived.Version)
+
+		// Archiving again changes nothing
+		again, err := store.ArchiveList(userID, done.ID)
+		require.NoError(t, err)
+		assert.Equal(t, archived.Version, again.Version)
+
+		names := func(filter ArchivedLists) []string {
+			lists, _, listErr := store.GetAllLists(userID, 1, 10, "", nil, nil, filter)
+			require.NoError(t, listErr)
+			var got []string
+			for i := range lists {
+				got = append(got, lists[i].Name)
+			}
+			sort.Strings(got)
+			return got
+		}
+		assert.Equal(t, []string{"Active"}, names(ActiveLists))
+		assert.Equal(t, []string{"Done"}, names(OnlyArchivedLists))
+		assert.Equal(t, []string{"Active", "Done"}, names(AllLists))
+
+		// Archived lists can still be fetched directly
+		got, err := store.GetListByID(userID, done.ID)
+		require.NoError(t, err)
+		assert.True(t, got.Archived)
+
+		unarchived, err := store.UnarchiveList(userID, done.ID)
+		require.NoError(t, err)
+		assert.False(t, unarchived.Archived)
+		assert.Nil(t, unarchived.ArchivedAt)
+		assert.Equal(t, []string{"Active", "Done"}, names(ActiveLists))
+
+		_, err = store.ArchiveList(uuid.New(), done.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.UnarchiveList(userID, uuid.New())
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"duplicate copies a list with its todos reopened", func(t *testing.T, store Store, userID uuid.UUID) {
+		source, err := store.CreateList(userID, models.CreateTodoListRequest{
+			Name: "Launch", Description: "Q3", RequireDueDate: true,
+		})
+		require.NoError(t, err)
+		due := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		done := mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{
+			Description: "Book venue", Priority: models.PriorityHigh, DueDate: &due, Tags: []string{"venue"},
+		})
+		mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{
+			Description: "Send invites", Priority: models.PriorityLow, DueDate: &due,
+		})
+		gone := mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{
+			Description: "Scrapped", Priority: models.PriorityLow, DueDate: &due,
+		})
+		completed := true
+		_, err = store.UpdateTodo(userID, source.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteTodo(userID, source.ID, gone.ID, nil))
+
+		copied, err := store.DuplicateList(userID, source.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, source.ID, copied.ID)
+		assert.Equal(t, "Copy of Launch", copied.Name)
+		assert.Equal(t, "Q3", copied.Description)
+		assert.True(t, copied.RequireDueDate)
+		assert.Equal(t, 2, copied.TodoCount)
+
+		todos, _, err := store.GetTodosByList(userID, copied.ID, ListTodosOptions{SortBy: "position", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Book venue", "Send invites"}, descriptions(todos))
+		for _, todo := range todos {
+			assert.NotEqual(t, done.ID, todo.ID)
+			assert.False(t, todo.Completed)
+			assert.Nil(t, todo.CompletedAt)
+			assert.Equal(t, 1, todo.Version)
+			assert.False(t, todo.CreatedAt.Before(copied.CreatedAt.Add(-time.Second)), "copies get fresh timestamps")
+		}
+		assert.Equal(t, models.PriorityHigh, todos[0].Priority)
+		assert.Equal(t, []string{"venue"}, todos[0].Tags)
+		require.NotNil(t, todos[0].DueDate)
+		assert.True(t, due.Equal(*todos[0].DueDate))
+
+		// The source is untouched
+		original, err := store.GetTodoByID(userID, source.ID, done.ID)
+		require.NoError(t, err)
+		assert.True(t, original.Completed)
+	}},
+	{"duplicate picks a free name among the user's lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		source := mustCreateList(t, store, userID, "Sprint")
+		mustCreateList(t, store, uuid.New(), "Copy of Sprint")
+
+		first, err := store.DuplicateList(userID, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Copy of Sprint", first.Name, "other users' lists do not count")
+		second, err := store.DuplicateList(userID, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Copy of Sprint (2)", second.Name)
+		third, err := store.DuplicateList(userID, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Copy of Sprint (3)", third.Name)
+
+		_, err = store.DuplicateList(uuid.New(), source.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"archived lists do not take part in name uniqueness", func(t *testing.T, store Store, userID uuid.UUID) {
+		old := mustCreateList(t, store, userID, "Project")
+		_, err := store.ArchiveList(userID, old.ID)
+		require.NoError(t, err)
+
+		// The archived list's name is free for a new list
+		current := mustCreateList(t, store, userID, "Project")
+
+		// Unarchiving would duplicate an active name, so it is refused until
+		// one of the lists is renamed
+		_, err = store.UnarchiveList(userID, old.ID)
+		assert.ErrorIs(t, err, ErrListNameExists)
+
+		// Renaming an archived list is not checked against active lists either
+		other := mustCreateList(t, store, userID, "Other")
+		renamed := "Other"
+		_, err = store.UpdateList(userID, old.ID, models.UpdateTodoListRequest{Name: &renamed})
+		require.NoError(t, err)
+		_, err = store.UnarchiveList(userID, old.ID)
+		assert.ErrorIs(t, err, ErrListNameExists)
+
+		renamed = "Project (2024)"
+		_, err = store.UpdateList(userID, old.ID, models.UpdateTodoListRequest{Name: &renamed})
+		require.NoError(t, err)
+		_, err = store.UnarchiveList(userID, old.ID)
+		require.NoError(t, err)
+
+		// Active lists still may not share a name
+		_, err = store.UpdateList(userID, other.ID, models.UpdateTodoListRequest{Name: &current.Name})
+		assert.ErrorIs(t, err, ErrListNameExists)
+	}},
+	{"merge moves todos into the target and deletes the source", func(t *testing.T, store Store, userID uuid.UUID) {
+		target := mustCreateList(t, store, userID, "Target")
+		source := mustCreateList(t, store, userID, "Source")
+		foreign := mustCreateList(t, store, uuid.New(), "Foreign")
+		mustCreateTodo(t, store, userID, target.ID, models.CreateTodoRequest{Description: "Existing", Priority: models.PriorityLow})
+		moved := mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{Description: "Moved", Priority: models.PriorityHigh})
+		mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{Description: "Also moved", Priority: models.PriorityLow})
+
+		merged, err := store.MergeLists(userID, target.ID, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, target.ID, merged.ID)
+		assert.Equal(t, 3, merged.TodoCount)
+
+		got, err := store.GetTodoByID(userID, target.ID, moved.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Moved", got.Description)
+		assert.Equal(t, target.ID, got.ListID)
+
+		_, err = store.GetListByID(userID, source.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+
+		_, err = store.MergeLists(userID, target.ID, source.ID)
+		assert.ErrorIs(t, err, ErrSourceNotFound)
+		_, err = store.MergeLists(userID, target.ID, foreign.ID)
+		assert.ErrorIs(t, err, ErrSourceNotFound)
+		_, err = store.MergeLists(userID, target.ID, target.ID)
+		assert.ErrorIs(t, err, ErrMergeSameList)
+		_, err = store.MergeLists(userID, foreign.ID, target.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"deleted lists and todos stay gone and free their names", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Reused")
+		kept := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Keep", Priority: models.PriorityLow})
+		dropped := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Drop", Priority: models.PriorityLow})
+
+		require.NoError(t, store.DeleteTodo(userID, list.ID, dropped.ID, nil))
+		_, err := store.GetTodoByID(userID, list.ID, dropped.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		assert.ErrorIs(t, store.DeleteTodo(userID, list.ID, dropped.ID, nil), ErrTodoNotFound)
+		got, err := store.GetListByID(userID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.TodoCount)
+
+		require.NoError(t, store.DeleteList(userID, list.ID, nil))
+		todos, _, err := store.QueryUserTodos(userID, TodoQueryOptions{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+
+		recreated := mustCreateList(t, store, userID, "Reused")
+		assert.NotEqual(t, list.ID, recreated.ID)
+		assert.Equal(t, 0, recreated.TodoCount)
+		_, err = store.GetTodoByID(userID, recreated.ID, kept.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+	}},
+	{"list pagination and todo counts", func(t *testing.T, store Store, userID uuid.UUID) {
+		first := mustCreateList(t, store, userID, "First")
+		mustCreateList(t, store, userID, "Second")
+		mustCreateList(t, store, userID, "Third")
+		mustCreateTodo(t, store, userID, first.ID, models.CreateTodoRequest{Description: "A", Priority: models.PriorityLow})
+
+		lists, pagination, err := store.GetAllLists(userID, 1, 2, "", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		assert.Len(t, lists, 2)
+		assert.Equal(t, 3, pagination.TotalItems)
+		assert.Equal(t, 2, pagination.TotalPages)
+
+		lists, _, err = store.GetAllLists(userID, 2, 2, "", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		require.Len(t, lists, 1)
+		// Newest first, so the oldest list lands on the last page
+		assert.Equal(t, "First", lists[0].Name)
+		assert.Equal(t, 1, lists[0].TodoCount)
+	}},
+
+	{"list search matches name or description and paginates the matches", func(t *testing.T, store Store, userID uuid.UUID) {
+		_, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Groceries", Description: "Weekly Shopping"})
+		require.NoError(t, err)
+		_, err = store.CreateList(userID, models.CreateTodoListRequest{Name: "Shopping Mall"})
+		require.NoError(t, err)
+		_, err = store.CreateList(userID, models.CreateTodoListRequest{Name: "Work", Description: "Goals"})
+		require.NoError(t, err)
+
+		lists, pagination, err := store.GetAllLists(userID, 1, 1, "shopping", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		assert.Len(t, lists, 1)
+		assert.Equal(t, 2, pagination.TotalItems)
+		assert.Equal(t, 2, pagination.TotalPages)
+
+		lists, pagination, err = store.GetAllLists(userID, 1, 10, "weekly", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		require.Len(t, lists, 1)
+		assert.Equal(t, "Groceries", lists[0].Name)
+		assert.Equal(t, 1, pagination.TotalItems)
+
+		lists, _, err = store.GetAllLists(userID, 1, 10, "nothing", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		assert.Empty(t, lists)
+	}},
+
+	// Todo CRUD
+	{"todo create, get, update and delete", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Todos")
+		due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Write report",
+			Priority:    models.PriorityHigh,
+			DueDate:     &due,
+		})
+		assert.Equal(t, list.ID, todo.ListID)
+		assert.False(t, todo.Completed)
+		assert.Nil(t, todo.CompletedAt)
+
+		got, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Write report", got.Description)
+		require.NotNil(t, got.DueDate)
+		assert.True(t, due.Equal(*got.DueDate))
+
+		done := true
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.True(t, updated.Completed)
+		assert.NotNil(t, updated.CompletedAt)
+
+		undone := false
+		updated, err = store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &undone})
+		require.NoError(t, err)
+		assert.False(t, updated.Completed)
+		assert.Nil(t, updated.CompletedAt)
+
+		require.NoError(t, store.DeleteTodo(userID, list.ID, todo.ID, nil))
+		_, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+	}},
+
+	{"batch create returns todos in request order", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Batch")
+		todos, err := store.BatchCreateTodos(userID, list.ID, []models.CreateTodoRequest{
+			{Description: "First", Priority: models.PriorityHigh},
+			{Description: "Second", Priority: models.PriorityLow, Completed: true},
+			{Description: "Third", Priority: models.PriorityMedium},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"First", "Second", "Third"}, descriptions(todos))
+		assert.False(t, todos[0].Completed)
+		assert.True(t, todos[1].Completed)
+		assert.NotNil(t, todos[1].CompletedAt)
+
+		stored, err := store.GetTodoByID(userID, list.ID, todos[1].ID)
+		require.NoError(t, err)
+		assert.True(t, stored.Completed)
+
+		_, err = store.BatchCreateTodos(uuid.New(), list.ID, []models.CreateTodoRequest{{Description: "X", Priority: models.PriorityLow}})
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+
+	{"lists requiring due dates reject undated todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Deadlines", RequireDueDate: true})
+		require.NoError(t, err)
+		assert.True(t, list.RequireDueDate)
+
+		_, err = store.CreateTodo(userID, list.ID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow})
+		assert.ErrorIs(t, err, ErrDueDateRequired)
+
+		due := time.Now().Add(24 * time.Hour)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Dated", Priority: models.PriorityLow, DueDate: &due})
+
+		_, err = store.BatchCreateTodos(userID, list.ID, []models.CreateTodoRequest{
+			{Description: "Dated too", Priority: models.PriorityLow, DueDate: &due},
+			{Description: "Undated", Priority: models.PriorityLow},
+		})
+		assert.ErrorIs(t, err, ErrDueDateRequired)
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Dated"}, descriptions(todos))
+
+		off := false
+		_, err = store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{RequireDueDate: &off})
+		require.NoError(t, err)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow})
+	}},
+
+	{"batch update sets completion and reports missing todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Batch", AutoArchiveCompleted: true})
+		require.NoError(t, err)
+		other := mustCreateList(t, store, userID, "Other")
+		first := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "First", Priority: models.PriorityLow})
+		second := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Second", Priority: models.PriorityLow})
+		elsewhere := mustCreateTodo(t, store, userID, other.ID, models.CreateTodoRequest{Description: "Elsewhere", Priority: models.PriorityLow})
+
+		done := true
+		result, err := store.BatchUpdateTodos(userID, list.ID, []uuid.UUID{first.ID, elsewhere.ID, first.ID},
+			models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Affected)
+		assert.Equal(t, []uuid.UUID{elsewhere.ID}, result.NotFound)
+
+		got, err := store.GetTodoByID(userID, list.ID, first.ID)
+		require.NoError(t, err)
+		assert.True(t, got.Completed)
+		require.NotNil(t, got.CompletedAt)
+		assert.NotNil(t, got.ArchivedAt)
+		assert.Equal(t, 2, got.Version)
+		completedAt := *got.CompletedAt
+
+		// Completing again keeps the original completion time
+		result, err = store.BatchUpdateTodos(userID, list.ID, []uuid.UUID{first.ID, second.ID}, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Affected)
+		got, err = store.GetTodoByID(userID, list.ID, first.ID)
+		require.NoError(t, err)
+		assert.True(t, completedAt.Equal(*got.CompletedAt))
+
+		undone := false
+		high := models.PriorityHigh
+		_, err = store.BatchUpdateTodos(userID, list.ID, []uuid.UUID{first.ID, second.ID},
+			models.UpdateTodoRequest{Completed: &undone, Priority: &high})
+		require.NoError(t, err)
+		for _, id := range []uuid.UUID{first.ID, second.ID} {
+			got, err = store.GetTodoByID(userID, list.ID, id)
+			require.NoError(t, err)
+			assert.False(t, got.Completed)
+			assert.Nil(t, got.CompletedAt)
+			assert.Nil(t, got.ArchivedAt)
+			assert.Equal(t, models.PriorityHigh, got.Priority)
+		}
+
+		_, err = store.BatchUpdateTodos(uuid.New(), list.ID, []uuid.UUID{first.ID}, models.UpdateTodoRequest{Completed: &done})
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+
+	{"batch delete removes only todos in the list", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Batch")
+		other := mustCreateList(t, store, userID, "Other")
+		first := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "First", Priority: models.PriorityLow})
+		second := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Second", Priority: models.PriorityLow})
+		elsewhere := mustCreateTodo(t, store, userID, other.ID, models.CreateTodoRequest{Description: "Elsewhere", Priority: models.PriorityLow})
+
+		result, err := store.BatchDeleteTodos(userID, list.ID, []uuid.UUID{first.ID, elsewhere.ID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Affected)
+		assert.Equal(t, []uuid.UUID{elsewhere.ID}, result.NotFound)
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{second.Description}, descriptions(todos))
+		_, err = store.GetTodoByID(userID, other.ID, elsewhere.ID)
+		assert.NoError(t, err)
+	}},
+
+	{"tags are normalized, updated and filtered with AND semantics", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Tagged")
+		both := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Both", Priority: models.PriorityLow, Tags: []string{" Urgent", "home", "HOME", ""},
+		})
+		assert.Equal(t, []string{"home", "urgent"}, both.Tags)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Urgent only", Priority: models.PriorityLow, Tags: []string{"urgent"},
+		})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Untagged", Priority: models.PriorityLow})
+
+		got, err := store.GetTodoByID(userID, list.ID, both.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home", "urgent"}, got.Tags)
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{Tags: []string{"URGENT"}, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Both", "Urgent only"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{Tags: []string{"urgent", "home"}, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Both"}, descriptions(todos))
+		assert.Equal(t, []string{"home", "urgent"}, todos[0].Tags)
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, todos, 3)
+
+		newTags := []string{"Work"}
+		updated, err := store.UpdateTodo(userID, list.ID, both.ID, models.UpdateTodoRequest{Tags: &newTags})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, updated.Tags)
+
+		done := true
+		updated, err = store.UpdateTodo(userID, list.ID, both.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, updated.Tags, "tags are kept when not in the update")
+
+		clone, err := store.CloneTodo(userID, list.ID, both.ID, uuid.Nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, clone.Tags)
+
+		cleared := []string{}
+		updated, err = store.UpdateTodo(userID, list.ID, both.ID, models.UpdateTodoRequest{Tags: &cleared})
+		require.NoError(t, err)
+		assert.Empty(t, updated.Tags)
+
+		batchTags := []string{"Batch"}
+		_, err = store.BatchUpdateTodos(userID, list.ID, []uuid.UUID{both.ID, clone.ID}, models.UpdateTodoRequest{Tags: &batchTags})
+		require.NoError(t, err)
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{Tags: []string{"batch"}, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, todos, 2)
+	}},
+
+	{"overdue excludes undated and completed todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Deadlines")
+		other := mustCreateList(t, store, userID, "Other")
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(time.Hour)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Late", Priority: models.PriorityLow, DueDate: &past})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Upcoming", Priority: models.PriorityLow, DueDate: &future,
+		})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Undated", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Late but done", Priority: models.PriorityLow, DueDate: &past, Completed: true,
+		})
+		mustCreateTodo(t, store, userID, other.ID, models.CreateTodoRequest{
+			Description: "Late elsewhere", Priority: models.PriorityLow, DueDate: &past,
+		})
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{Overdue: true, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Late"}, descriptions(todos))
+
+		across, _, err := store.QueryUserTodos(userID, TodoQueryOptions{Overdue: true, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		names := make([]string, 0, len(across))
+		for _, todo := range across {
+			names = append(names, todo.Description)
+		}
+		assert.ElementsMatch(t, []string{"Late", "Late elsewhere"}, names)
+	}},
+	{"due range is inclusive and excludes undated todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Calendar")
+		monday := time.Date(2030, 6, 3, 9, 0, 0, 0, time.UTC)
+		wednesday := monday.AddDate(0, 0, 2)
+		friday := monday.AddDate(0, 0, 4)
+		for name, due := range map[string]time.Time{"Monday": monday, "Wednesday": wednesday, "Friday": friday} {
+			due := due
+			mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: name, Priority: models.PriorityLow, DueDate: &due})
+		}
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Someday", Priority: models.PriorityLow})
+
+		todos, _, err := store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{DueAfter: &monday, DueBefore: &wednesday, SortBy: "dueDate", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Monday", "Wednesday"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{DueBefore: &monday, Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Monday"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{DueAfter: &wednesday, SortBy: "dueDate", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Wednesday", "Friday"}, descriptions(todos))
+	}},
+
+	{"todo clone copies into own or target list", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Source")
+		target := mustCreateList(t, store, userID, "Target")
+		foreign := mustCreateList(t, store, uuid.New(), "Foreign")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityHigh})
+		done := true
+		_, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+
+		clone, err := store.CloneTodo(userID, list.ID, todo.ID, uuid.Nil)
+		require.NoError(t, err)
+		assert.NotEqual(t, todo.ID, clone.ID)
+		assert.Equal(t, list.ID, clone.ListID)
+		assert.Equal(t, "Pack (copy)", clone.Description)
+		assert.Equal(t, models.PriorityHigh, clone.Priority)
+		assert.False(t, clone.Completed)
+
+		moved, err := store.CloneTodo(userID, list.ID, todo.ID, target.ID)
+		require.NoError(t, err)
+		got, err := store.GetTodoByID(userID, target.ID, moved.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Pack (copy)", got.Description)
+
+		_, err = store.CloneTodo(userID, list.ID, todo.ID, foreign.ID)
+		assert.ErrorIs(t, err, ErrTargetNotFound)
+		_, err = store.CloneTodo(userID, list.ID, uuid.New(), uuid.Nil)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+	}},
+
+	{"updates bump version and reject stale versions", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Versioned")
+		assert.Equal(t, 1, list.Version)
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Draft", Priority: models.PriorityLow})
+		assert.Equal(t, 1, todo.Version)
+
+		stale := todo.Version
+		description := "Final"
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Description: &description, Version: &stale})
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.Version)
+
+		description = "Overwrite"
+		_, err = store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Description: &description, Version: &stale})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+		got, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Final", got.Description)
+		assert.Equal(t, 2, got.Version)
+
+		// Updates without a version still apply and bump it
+		updated, err = store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Description: &description})
+		require.NoError(t, err)
+		assert.Equal(t, 3, updated.Version)
+
+		staleList := list.Version
+		name := "Renamed"
+		renamed, err := store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{Name: &name, Version: &staleList})
+		require.NoError(t, err)
+		assert.Equal(t, 2, renamed.Version)
+		_, err = store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{Name: &name, Version: &staleList})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	}},
+	{"deletes with a stale version are rejected", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Chores")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Dishes", Priority: models.PriorityLow})
+		description := "Laundry"
+		_, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Description: &description})
+		require.NoError(t, err)
+
+		stale, current := 1, 2
+		assert.ErrorIs(t, store.DeleteTodo(userID, list.ID, todo.ID, &stale), ErrVersionConflict)
+		_, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteTodo(userID, list.ID, todo.ID, &current))
+		_, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+
+		name := "Errands"
+		_, err = store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{Name: &name})
+		require.NoError(t, err)
+		assert.ErrorIs(t, store.DeleteList(userID, list.ID, &stale), ErrVersionConflict)
+		_, err = store.GetListByID(userID, list.ID)
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteList(userID, list.ID, &current))
+		_, err = store.GetListByID(userID, list.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+
+	// Archiving
+	{"completing a todo archives it when the list auto-archives", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Chores", AutoArchiveCompleted: true})
+		require.NoError(t, err)
+		assert.True(t, list.AutoArchiveCompleted)
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Dishes", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Laundry", Priority: models.PriorityLow})
+
+		done := true
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.NotNil(t, updated.ArchivedAt)
+
+		active, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Laundry"}, descriptions(active))
+
+		archived, _, err := store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Archived: true, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Dishes"}, descriptions(archived))
+
+		// Reopening the todo brings it back to the active list
+		undone := false
+		updated, err = store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &undone})
+		require.NoError(t, err)
+		assert.Nil(t, updated.ArchivedAt)
+	}},
+	{"completing a todo does not archive it by default", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Plain")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Dishes", Priority: models.PriorityLow})
+
+		done := true
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+		assert.Nil(t, updated.ArchivedAt)
+
+		enabled := true
+		updatedList, err := store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{AutoArchiveCompleted: &enabled})
+		require.NoError(t, err)
+		assert.True(t, updatedList.AutoArchiveCompleted)
+
+		// Enabling the setting later does not retroactively archive
+		active, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Dishes"}, descriptions(active))
+	}},
+
+	// Retention
+	{"purge removes only old completed todos outside keep lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Chores")
+		keep, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Journal", KeepCompleted: true})
+		require.NoError(t, err)
+
+		old := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Old", Priority: models.PriorityLow})
+		kept := mustCreateTodo(t, store, userID, keep.ID, models.CreateTodoRequest{Description: "Kept", Priority: models.PriorityLow})
+		recent := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Recent", Priority: models.PriorityLow})
+		pending := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Open", Priority: models.PriorityLow})
+
+		done := true
+		for _, todo := range []*models.Todo{old, kept} {
+			_, err = store.UpdateTodo(userID, todo.ListID, todo.ID, models.UpdateTodoRequest{Completed: &done})
+			require.NoError(t, err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		cutoff := time.Now()
+		time.Sleep(2 * time.Millisecond)
+		_, err = store.UpdateTodo(userID, list.ID, recent.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+
+		purged, err := store.PurgeOldCompleted(cutoff)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = store.GetTodoByID(userID, list.ID, old.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		for _, todo := range []*models.Todo{kept, recent, pending} {
+			_, err = store.GetTodoByID(userID, todo.ListID, todo.ID)
+			assert.NoError(t, err, todo.Description)
+		}
+	}},
+
+	// Filters
+	{"todo filters by priority and completion", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Filters")
+		high := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "High", Priority: models.PriorityHigh})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Low", Priority: models.PriorityLow})
+		done := true
+		_, err := store.UpdateTodo(userID, list.ID, high.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+
+		priority := models.PriorityHigh
+		todos, _, err := store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Priority: &priority, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"High"}, descriptions(todos))
+
+		notDone := false
+		todos, _, err = store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Completed: &notDone, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Low"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Priority: &priority, Completed: &notDone, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+	}},
+
+	{"created range filters are inclusive on lists and todos", func(t *testing.T, store Store, userID uuid.UUID) {
+		var lists []*models.TodoList
+		for _, name := range []string{"First", "Second", "Third"} {
+			lists = append(lists, mustCreateList(t, store, userID, name))
+			time.Sleep(2 * time.Millisecond)
+		}
+		after, before := lists[1].CreatedAt, lists[2].CreatedAt
+
+		got, pagination, err := store.GetAllLists(userID, 1, 10, "", &after, &before, ActiveLists)
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, 2, pagination.TotalItems)
+
+		list := lists[0]
+		var todos []*models.Todo
+		for _, description := range []string{"A", "B", "C"} {
+			todos = append(todos, mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+				Description: description,
+				Priority:    models.PriorityLow,
+			}))
+			time.Sleep(2 * time.Millisecond)
+		}
+		after, before = todos[0].CreatedAt, todos[1].CreatedAt
+
+		inList, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{
+			CreatedAfter: &after, CreatedBefore: &before, SortBy: sortFieldCreatedAt, SortOrder: "asc", Page: 1, Limit: 100,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A", "B"}, descriptions(inList))
+
+		across, _, err := store.QueryUserTodos(userID, TodoQueryOptions{CreatedAfter: &before, SortBy: sortFieldCreatedAt})
+		require.NoError(t, err)
+		require.Len(t, across, 2)
+		assert.Equal(t, "B", across[0].Description)
+		assert.Equal(t, "C", across[1].Description)
+	}},
+
+	// Sorting
+	{"todo sorting by createdAt", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Sorting")
+		for _, desc := range []string{"A", "B", "C"} {
+			mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: desc, Priority: models.PriorityLow})
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A", "B", "C"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "desc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C", "B", "A"}, descriptions(todos))
+	}},
+	{"todo sorting by priority", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Priorities")
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Medium", Priority: models.PriorityMedium})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Low", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "High", Priority: models.PriorityHigh})
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "priority", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"High", "Medium", "Low"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "priority", SortOrder: "desc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Low", "Medium", "High"}, descriptions(todos))
+	}},
+	{"pinned todos sort first whatever the sort", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Pins")
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "High", Priority: models.PriorityHigh})
+		pinned := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Pinned low", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Medium", Priority: models.PriorityMedium})
+		version := pinned.Version
+
+		got, err := store.SetTodoPinned(userID, list.ID, pinned.ID, true)
+		require.NoError(t, err)
+		assert.True(t, got.Pinned)
+		assert.Equal(t, version+1, got.Version)
+
+		// Pinning again changes nothing
+		got, err = store.SetTodoPinned(userID, list.ID, pinned.ID, true)
+		require.NoError(t, err)
+		assert.Equal(t, version+1, got.Version)
+
+		for _, order := range []string{"asc", "desc"} {
+			for _, sortBy := range []string{"priority", "createdAt", "dueDate", "position"} {
+				opts := ListTodosOptions{SortBy: sortBy, SortOrder: order, Page: 1, Limit: 100}
+				todos, _, listErr := store.GetTodosByList(userID, list.ID, opts)
+				require.NoError(t, listErr)
+				require.Len(t, todos, 3)
+				assert.Equal(t, "Pinned low", todos[0].Description, "sortBy=%s sortOrder=%s", sortBy, order)
+			}
+		}
+
+		embedded, err := store.GetTodosForLists(userID, []uuid.UUID{list.ID}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Pinned low", "High", "Medium"}, descriptions(embedded[list.ID]))
+
+		got, err = store.SetTodoPinned(userID, list.ID, pinned.ID, false)
+		require.NoError(t, err)
+		assert.False(t, got.Pinned)
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "priority", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"High", "Medium", "Pinned low"}, descriptions(todos))
+
+		_, err = store.SetTodoPinned(userID, list.ID, uuid.New(), true)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.SetTodoPinned(uuid.New(), list.ID, pinned.ID, true)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"todo sorting by dueDate places missing dates consistently", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Due")
+		soon := time.Now().Add(time.Hour)
+		later := time.Now().Add(24 * time.Hour)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Later", Priority: models.PriorityLow, DueDate: &later})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "None", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Soon", Priority: models.PriorityLow, DueDate: &soon})
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "dueDate", SortOrder: "asc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Soon", "Later", "None"}, descriptions(todos))
+
+		todos, _, err = store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "dueDate", SortOrder: "desc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"None", "Later", "Soon"}, descriptions(todos))
+	}},
+	{"todos are appended and reordered by position", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Ordered")
+		byPosition := func() []string {
+			t.Helper()
+			todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+			require.NoError(t, err)
+			for i, todo := range todos {
+				assert.Equal(t, i, todo.Position, "positions are sequential")
+			}
+			return descriptions(todos)
+		}
+
+		a := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "A", Priority: models.PriorityLow})
+		assert.Equal(t, 0, a.Position)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "B", Priority: models.PriorityLow})
+		batch, err := store.BatchCreateTodos(userID, list.ID, []models.CreateTodoRequest{
+			{Description: "C", Priority: models.PriorityLow},
+			{Description: "D", Priority: models.PriorityLow},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3}, []int{batch[0].Position, batch[1].Position})
+		assert.Equal(t, []string{"A", "B", "C", "D"}, byPosition())
+
+		moved, err := store.ReorderTodo(userID, list.ID, batch[1].ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, moved.Position)
+		assert.Equal(t, []string{"A", "D", "B", "C"}, byPosition())
+
+		// Positions past the end move the todo last
+		_, err = store.ReorderTodo(userID, list.ID, a.ID, 99)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"D", "B", "C", "A"}, byPosition())
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "position", SortOrder: "desc", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A", "C", "B", "D"}, descriptions(todos))
+
+		// Clones and merged todos go after the existing ones, keeping their order
+		clone, err := store.CloneTodo(userID, list.ID, a.ID, uuid.Nil)
+		require.NoError(t, err)
+		assert.Equal(t, 4, clone.Position)
+		source := mustCreateList(t, store, userID, "Source")
+		mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{Description: "E", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, source.ID, models.CreateTodoRequest{Description: "F", Priority: models.PriorityLow})
+		_, err = store.MergeLists(userID, list.ID, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"D", "B", "C", "A", "A (copy)", "E", "F"}, byPosition())
+
+		_, err = store.ReorderTodo(userID, list.ID, uuid.New(), 0)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.ReorderTodo(uuid.New(), list.ID, a.ID, 0)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"reordering bumps the version of each repositioned todo", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Versions")
+		todos := make([]*models.Todo, 0, 4)
+		for _, desc := range []string{"A", "B", "C", "D"} {
+			todos = append(todos, mustCreateTodo(t, store, userID, list.ID,
+				models.CreateTodoRequest{Description: desc, Priority: models.PriorityLow}))
+		}
+		before := time.Now()
+
+		// Moving D before B shifts B and C; A keeps its position
+		moved, err := store.ReorderTodo(userID, list.ID, todos[3].ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, moved.Version)
+		for i, want := range []int{1, 2, 2, 2} {
+			todo, getErr := store.GetTodoByID(userID, list.ID, todos[i].ID)
+			require.NoError(t, getErr)
+			assert.Equal(t, want, todo.Version, todo.Description)
+			if want > 1 {
+				assert.False(t, todo.UpdatedAt.Before(before), todo.Description)
+			}
+		}
+
+		// Moving a todo to where it already is changes nothing
+		_, err = store.ReorderTodo(userID, list.ID, todos[0].ID, 0)
+		require.NoError(t, err)
+		todo, err := store.GetTodoByID(userID, list.ID, todos[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, todo.Version)
+	}},
+	{"todos for several lists are fetched together in position order", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		empty := mustCreateList(t, store, userID, "Empty")
+		for _, desc := range []string{"W1", "W2", "W3"} {
+			mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{Description: desc, Priority: models.PriorityLow})
+		}
+		h1 := mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{Description: "H1", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{Description: "H2", Priority: models.PriorityLow})
+		_, err := store.ReorderTodo(userID, home.ID, h1.ID, 1)
+		require.NoError(t, err)
+
+		// Archived todos are not embedded
+		archive, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Archive", AutoArchiveCompleted: true})
+		require.NoError(t, err)
+		done := mustCreateTodo(t, store, userID, archive.ID, models.CreateTodoRequest{Description: "Done", Priority: models.PriorityLow})
+		completed := true
+		_, err = store.UpdateTodo(userID, archive.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+
+		otherUser := uuid.New()
+		other := mustCreateList(t, store, otherUser, "Not mine")
+		mustCreateTodo(t, store, otherUser, other.ID, models.CreateTodoRequest{Description: "Foreign", Priority: models.PriorityLow})
+
+		byList, err := store.GetTodosForLists(userID, []uuid.UUID{work.ID, home.ID, empty.ID, archive.ID, other.ID}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"W1", "W2"}, descriptions(byList[work.ID]))
+		assert.Equal(t, []string{"H2", "H1"}, descriptions(byList[home.ID]))
+		assert.Empty(t, byList[empty.ID])
+		assert.Empty(t, byList[archive.ID])
+		assert.Empty(t, byList[other.ID])
+	}},
+	// Subtasks
+	{"subtasks are appended, counted and deleted", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Checklists")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+
+		var created []*models.Subtask
+		for _, req := range []models.CreateSubtaskRequest{
+			{Description: "Passport"},
+			{Description: "Charger", Completed: true},
+			{Description: "Tickets"},
+		} {
+			subtask, err := store.CreateSubtask(userID, list.ID, todo.ID, req)
+			require.NoError(t, err)
+			created = append(created, subtask)
+		}
+		assert.Equal(t, []int{0, 1, 2}, []int{created[0].Position, created[1].Position, created[2].Position})
+
+		got, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, got.SubtaskCount)
+		assert.Equal(t, 1, got.CompletedSubtaskCount)
+
+		renamed, done := "Passport and visa", true
+		updated, err := store.UpdateSubtask(userID, list.ID, todo.ID, created[0].ID, models.UpdateSubtaskRequest{
+			Description: &renamed, Completed: &done,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Passport and visa", updated.Description)
+		assert.True(t, updated.Completed)
+
+		require.NoError(t, store.DeleteSubtask(userID, list.ID, todo.ID, created[2].ID))
+		assert.ErrorIs(t, store.DeleteSubtask(userID, list.ID, todo.ID, created[2].ID), ErrSubtaskNotFound)
+
+		subtasks, err := store.GetSubtasks(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		require.Len(t, subtasks, 2)
+		assert.Equal(t, "Passport and visa", subtasks[0].Description)
+		assert.Equal(t, "Charger", subtasks[1].Description)
+
+		// Every subtask is complete, but the todo only completes when asked
+		got, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.SubtaskCount)
+		assert.Equal(t, 2, got.CompletedSubtaskCount)
+		assert.False(t, got.Completed)
+
+		// Subtasks of another todo are not found through this one
+		other := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Other", Priority: models.PriorityLow})
+		_, err = store.UpdateSubtask(userID, list.ID, other.ID, created[0].ID, models.UpdateSubtaskRequest{Completed: &done})
+		assert.ErrorIs(t, err, ErrSubtaskNotFound)
+		_, err = store.GetSubtasks(uuid.New(), list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.CreateSubtask(userID, list.ID, uuid.New(), models.CreateSubtaskRequest{Description: "Lost"})
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+
+		// Deleting the todo takes its subtasks with it
+		require.NoError(t, store.DeleteTodo(userID, list.ID, todo.ID, nil))
+		_, err = store.GetSubtasks(userID, list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+	}},
+	{"completing a todo carries over to its subtasks when the list asks", func(t *testing.T, store Store, userID uuid.UUID) {
+		// newTodo creates a todo in list with one open and one completed subtask
+		newTodo := func(list *models.TodoList) (*models.Todo, *models.Subtask) {
+			todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+			open, err := store.CreateSubtask(userID, list.ID, todo.ID, models.CreateSubtaskRequest{Description: "Passport"})
+			require.NoError(t, err)
+			assert.Nil(t, open.CompletedAt)
+			done, err := store.CreateSubtask(userID, list.ID, todo.ID, models.CreateSubtaskRequest{Description: "Charger", Completed: true})
+			require.NoError(t, err)
+			assert.NotNil(t, done.CompletedAt)
+			return todo, done
+		}
+		subtasksOf := func(list *models.TodoList, todo *models.Todo) []models.Subtask {
+			subtasks, err := store.GetSubtasks(userID, list.ID, todo.ID)
+			require.NoError(t, err)
+			require.Len(t, subtasks, 2)
+			return subtasks
+		}
+		completed, reopened := true, false
+
+		// Lists leave subtasks alone by default
+		plain := mustCreateList(t, store, userID, "Plain")
+		todo, _ := newTodo(plain)
+		got, err := store.UpdateTodo(userID, plain.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.CompletedSubtaskCount)
+		subtasks := subtasksOf(plain, todo)
+		assert.False(t, subtasks[0].Completed)
+		assert.Nil(t, subtasks[0].CompletedAt)
+
+		cascading, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Cascading", CompleteSubtasksWithParent: true})
+		require.NoError(t, err)
+		todo, done := newTodo(cascading)
+		got, err = store.UpdateTodo(userID, cascading.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.CompletedSubtaskCount)
+		subtasks = subtasksOf(cascading, todo)
+		assert.True(t, subtasks[0].Completed)
+		require.NotNil(t, subtasks[0].CompletedAt)
+		require.NotNil(t, subtasks[1].CompletedAt)
+		assert.WithinDuration(t, *done.CompletedAt, *subtasks[1].CompletedAt, time.Millisecond, "already completed subtasks keep their time")
+
+		// Reopening the todo keeps the subtasks completed unless the list reopens them too
+		got, err = store.UpdateTodo(userID, cascading.ID, todo.ID, models.UpdateTodoRequest{Completed: &reopened})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.CompletedSubtaskCount)
+
+		reopen := true
+		_, err = store.UpdateList(userID, cascading.ID, models.UpdateTodoListRequest{ReopenSubtasksWithParent: &reopen})
+		require.NoError(t, err)
+		_, err = store.UpdateTodo(userID, cascading.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		got, err = store.UpdateTodo(userID, cascading.ID, todo.ID, models.UpdateTodoRequest{Completed: &reopened})
+		require.NoError(t, err)
+		assert.Zero(t, got.CompletedSubtaskCount)
+		for _, subtask := range subtasksOf(cascading, todo) {
+			assert.False(t, subtask.Completed)
+			assert.Nil(t, subtask.CompletedAt)
+		}
+
+		stored, err := store.GetTodoByID(userID, cascading.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Zero(t, stored.CompletedSubtaskCount)
+	}},
+	{"reprioritizing raises only the matching todos across lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		past := time.Now().Add(-48 * time.Hour)
+		future := time.Now().Add(48 * time.Hour)
+		late := mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Late", Priority: models.PriorityLow, DueDate: &past,
+		})
+		lateHome := mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{
+			Description: "Late at home", Priority: models.PriorityMedium, DueDate: &past,
+		})
+		urgent := mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Already urgent", Priority: models.PriorityHigh, DueDate: &past,
+		})
+		done := mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Done", Priority: models.PriorityLow, DueDate: &past,
+		})
+		completed := true
+		_, err := store.UpdateTodo(userID, work.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		soon := mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Soon", Priority: models.PriorityLow, DueDate: &future,
+		})
+		undated := mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{Description: "Someday", Priority: models.PriorityLow})
+
+		changed, err := store.ReprioritizeTodos(uuid.New(), TodoQueryOptions{Overdue: true}, models.PriorityHigh)
+		require.NoError(t, err)
+		assert.Zero(t, changed, "another user's todos are not touched")
+
+		changed, err = store.ReprioritizeTodos(userID, TodoQueryOptions{Overdue: true}, models.PriorityHigh)
+		require.NoError(t, err)
+		assert.Equal(t, 2, changed)
+
+		for _, want := range []struct {
+			todo     *models.Todo
+			priority models.Priority
+			version  int
+		}{
+			{late, models.PriorityHigh, 2},
+			{lateHome, models.PriorityHigh, 2},
+			{urgent, models.PriorityHigh, 1},
+			{done, models.PriorityLow, 2}, // from being completed
+			{soon, models.PriorityLow, 1},
+			{undated, models.PriorityLow, 1},
+		} {
+			got, getErr := store.GetTodoByID(userID, want.todo.ListID, want.todo.ID)
+			require.NoError(t, getErr)
+			assert.Equal(t, want.priority, got.Priority, want.todo.Description)
+			assert.Equal(t, want.version, got.Version, want.todo.Description)
+		}
+
+		// Running it again finds nothing left to change
+		changed, err = store.ReprioritizeTodos(userID, TodoQueryOptions{Overdue: true}, models.PriorityHigh)
+		require.NoError(t, err)
+		assert.Zero(t, changed)
+	}},
+	{"list stats count live todos by state and priority", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Stats")
+
+		// An empty list reports zeros everywhere
+		stats, err := store.GetListStats(userID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ListStats{ListID: list.ID}, *stats)
+
+		past := time.Now().Add(-48 * time.Hour)
+		future := time.Now().Add(48 * time.Hour)
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Late", Priority: models.PriorityHigh, DueDate: &past})
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Soon", Priority: models.PriorityHigh, DueDate: &future})
+		done := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Done late", Priority: models.PriorityLow, DueDate: &past,
+		})
+		completed := true
+		_, err = store.UpdateTodo(userID, list.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		gone := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Gone", Priority: models.PriorityMedium, DueDate: &past,
+		})
+		require.NoError(t, store.DeleteTodo(userID, list.ID, gone.ID, nil))
+
+		stats, err = store.GetListStats(userID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.Total)
+		assert.Equal(t, 1, stats.Completed)
+		assert.Equal(t, 2, stats.Incomplete)
+		assert.Equal(t, 1, stats.Overdue)
+		assert.Equal(t, models.PriorityCounts{High: 2, Low: 1}, stats.ByPriority)
+
+		_, err = store.GetListStats(uuid.New(), list.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+	}},
+	{"user stats summarize todos across lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		// A user without lists gets zeros and no divide-by-zero
+		stats, err := store.GetUserStats(userID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStats{}, *stats)
+
+		first := mustCreateList(t, store, userID, "First")
+		second := mustCreateList(t, store, userID, "Second")
+		mustCreateList(t, store, userID, "Empty")
+		past := time.Now().Add(-48 * time.Hour)
+		mustCreateTodo(t, store, userID, first.ID, models.CreateTodoRequest{Description: "Late", Priority: models.PriorityHigh, DueDate: &past})
+		mustCreateTodo(t, store, userID, first.ID, models.CreateTodoRequest{Description: "Open", Priority: models.PriorityLow})
+		done := mustCreateTodo(t, store, userID, second.ID, models.CreateTodoRequest{Description: "Done", Priority: models.PriorityLow})
+		completed := true
+		_, err = store.UpdateTodo(userID, second.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		gone := mustCreateTodo(t, store, userID, second.ID, models.CreateTodoRequest{Description: "Gone", Priority: models.PriorityLow})
+		require.NoError(t, store.DeleteTodo(userID, second.ID, gone.ID, nil))
+
+		// Another user's todos are not counted
+		other := uuid.New()
+		theirs := mustCreateList(t, store, other, "Theirs")
+		mustCreateTodo(t, store, other, theirs.ID, models.CreateTodoRequest{Description: "Theirs", Priority: models.PriorityLow})
+
+		stats, err = store.GetUserStats(userID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.Lists)
+		assert.Equal(t, 3, stats.TotalTodos)
+		assert.Equal(t, 1, stats.CompletedTodos)
+		assert.InDelta(t, 1.0/3, stats.CompletionRate, 1e-9)
+		assert.Equal(t, 1, stats.CompletedLast7Days)
+		assert.Equal(t, 1, stats.CompletedLast30Days)
+		assert.Equal(t, 1, stats.Overdue)
+	}},
+	{"todos created without a priority get the default", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Quick add")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "No priority"})
+		assert.Equal(t, models.DefaultPriority, todo.Priority)
+
+		todos, err := store.BatchCreateTodos(userID, list.ID, []models.CreateTodoRequest{{Description: "Batched"}})
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultPriority, todos[0].Priority)
+
+		stored, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultPriority, stored.Priority)
+	}},
+	{"notifications cover overdue and due-soon todos until acknowledged", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Deadlines")
+		now := time.Now()
+		past, soon, later := now.Add(-2*time.Hour), now.Add(2*time.Hour), now.Add(72*time.Hour)
+		dated := func(description string, due *time.Time) *models.Todo {
+			return mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+				Description: description, Priority: models.PriorityMedium, DueDate: due,
+			})
+		}
+		late := dated("Late", &past)
+		upcoming := dated("Soon", &soon)
+		dated("Later", &later)
+		dated("Undated", nil)
+		done := dated("Done", &past)
+		completed := true
+		_, err := store.UpdateTodo(userID, list.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+
+		notifications, err := store.GetNotifications(userID, now, 24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, models.NotificationOverdue, notifications[0].Type)
+		assert.Equal(t, late.ID, notifications[0].TodoID)
+		assert.Equal(t, "overdue:"+late.ID.String(), notifications[0].ID)
+		assert.Equal(t, "Deadlines", notifications[0].ListName)
+		assert.Equal(t, models.NotificationDueSoon, notifications[1].Type)
+		assert.Equal(t, upcoming.ID, notifications[1].TodoID)
+
+		// Another user neither sees nor acknowledges them
+		other := uuid.New()
+		theirs, err := store.GetNotifications(other, now, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, theirs)
+		assert.ErrorIs(t, store.AcknowledgeNotification(other, late.ID, models.NotificationOverdue), ErrTodoNotFound)
+
+		require.NoError(t, store.AcknowledgeNotification(userID, late.ID, models.NotificationOverdue))
+		require.NoError(t, store.AcknowledgeNotification(userID, late.ID, models.NotificationOverdue))
+		notifications, err = store.GetNotifications(userID, now, 24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, upcoming.ID, notifications[0].TodoID)
+
+		// Acknowledging the due-soon notice does not silence the todo once it is overdue
+		require.NoError(t, store.AcknowledgeNotification(userID, upcoming.ID, models.NotificationDueSoon))
+		notifications, err = store.GetNotifications(userID, now, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+		notifications, err = store.GetNotifications(userID, now.Add(3*time.Hour), 24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationOverdue, notifications[0].Type)
+		assert.Equal(t, upcoming.ID, notifications[0].TodoID)
+
+		assert.ErrorIs(t, store.AcknowledgeNotification(userID, uuid.New(), models.NotificationOverdue), ErrTodoNotFound)
+	}},
+	{"share links open a list until they expire or are revoked", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Groceries")
+		now := time.Now()
+
+		link, err := store.CreateShareLink(userID, list.ID, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.NotEmpty(t, link.Token)
+		assert.Equal(t, list.ID, link.ListID)
+
+		shared, err := store.GetSharedList(link.Token, now)
+		require.NoError(t, err)
+		assert.Equal(t, link.ID, shared.ID)
+		assert.Equal(t, "Groceries", shared.List.Name)
+		assert.Empty(t, shared.Token, "the token is only returned on creation")
+
+		_, err = store.GetSharedList(link.Token, now.Add(2*time.Hour))
+		assert.ErrorIs(t, err, ErrShareLinkNotFound, "expired")
+		_, err = store.GetSharedList("not-a-token", now)
+		assert.ErrorIs(t, err, ErrShareLinkNotFound)
+
+		// Another user can neither share nor revoke the list
+		other := uuid.New()
+		_, err = store.CreateShareLink(other, list.ID, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrListNotFound)
+		assert.ErrorIs(t, store.RevokeShareLink(other, list.ID, link.ID), ErrListNotFound)
+
+		require.NoError(t, store.RevokeShareLink(userID, list.ID, link.ID))
+		assert.ErrorIs(t, store.RevokeShareLink(userID, list.ID, link.ID), ErrShareLinkNotFound)
+		_, err = store.GetSharedList(link.Token, now)
+		assert.ErrorIs(t, err, ErrShareLinkNotFound, "revoked")
+
+		// Links stop working when their list is deleted
+		live, err := store.CreateShareLink(userID, list.ID, now.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteList(userID, list.ID, nil))
+		_, err = store.GetSharedList(live.Token, now)
+		assert.ErrorIs(t, err, ErrShareLinkNotFound, "deleted list")
+	}},
+	{"completing the last subtask completes the parent only on request", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Auto", AutoArchiveCompleted: true})
+		require.NoError(t, err)
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		version := todo.Version
+		first, err := store.CreateSubtask(userID, list.ID, todo.ID, models.CreateSubtaskRequest{Description: "Passport"})
+		require.NoError(t, err)
+		second, err := store.CreateSubtask(userID, list.ID, todo.ID, models.CreateSubtaskRequest{Description: "Tickets"})
+		require.NoError(t, err)
+
+		// Asking while a subtask is still open leaves the todo alone
+		done := true
+		_, err = store.UpdateSubtask(userID, list.ID, todo.ID, first.ID, models.UpdateSubtaskRequest{Completed: &done, CompleteParent: true})
+		require.NoError(t, err)
+		got, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.False(t, got.Completed)
+		assert.Equal(t, version, got.Version)
+
+		_, err = store.UpdateSubtask(userID, list.ID, todo.ID, second.ID, models.UpdateSubtaskRequest{Completed: &done, CompleteParent: true})
+		require.NoError(t, err)
+		got, err = store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.True(t, got.Completed)
+		assert.NotNil(t, got.CompletedAt)
+		assert.NotNil(t, got.ArchivedAt, "the list's auto-archive still applies")
+		assert.Equal(t, version+1, got.Version)
+	}},
+
+	{"updates report only the fields that changed", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Diffed")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Write report", Priority: models.PriorityLow, Tags: []string{"work"},
+		})
+
+		// Fields sent with their current value are not reported
+		description, high := "Write report", models.PriorityHigh
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{
+			Description: &description, Priority: &high, Tags: &[]string{"work"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.FieldChanges{
+			"priority": {Old: models.PriorityLow, New: models.PriorityHigh},
+		}, updated.Changed)
+
+		completed := true
+		updated, err = store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Len(t, updated.Changed, 2)
+		assert.Equal(t, models.FieldChange{Old: false, New: true}, updated.Changed["completed"])
+		require.Contains(t, updated.Changed, "completedAt")
+		assert.Nil(t, updated.Changed["completedAt"].Old)
+		assert.NotNil(t, updated.Changed["completedAt"].New)
+
+		name, listDescription := "Diffed", "Now described"
+		updatedList, err := store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{
+			Name: &name, Description: &listDescription,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.FieldChanges{
+			"description": {Old: "", New: "Now described"},
+		}, updatedList.Changed)
+
+		// Reads never carry a diff
+		fetched, err := store.GetTodoByID(userID, list.ID, todo.ID)
+		require.NoError(t, err)
+		assert.Empty(t, fetched.Changed)
+	}},
+	{"moving a todo reassigns it to the end of another list", func(t *testing.T, store Store, userID uuid.UUID) {
+		inbox := mustCreateList(t, store, userID, "Inbox")
+		work := mustCreateList(t, store, userID, "Work")
+		foreign := mustCreateList(t, store, uuid.New(), "Foreign")
+		todo := mustCreateTodo(t, store, userID, inbox.ID, models.CreateTodoRequest{Description: "Report", Priority: models.PriorityHigh})
+		version := todo.Version
+		mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{Description: "Standup", Priority: models.PriorityLow})
+
+		// Moving within the same list changes nothing
+		same, err := store.MoveTodo(userID, inbox.ID, todo.ID, inbox.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inbox.ID, same.ListID)
+		assert.Equal(t, version, same.Version)
+
+		_, err = store.MoveTodo(userID, inbox.ID, todo.ID, foreign.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.MoveTodo(userID, inbox.ID, todo.ID, uuid.New())
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.MoveTodo(userID, work.ID, todo.ID, inbox.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.MoveTodo(uuid.New(), inbox.ID, todo.ID, work.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+
+		moved, err := store.MoveTodo(userID, inbox.ID, todo.ID, work.ID)
+		require.NoError(t, err)
+		assert.Equal(t, todo.ID, moved.ID)
+		assert.Equal(t, work.ID, moved.ListID)
+		assert.Equal(t, version+1, moved.Version)
+
+		inboxTodos, _, err := store.GetTodosByList(userID, inbox.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, inboxTodos)
+		workTodos, _, err := store.GetTodosByList(userID, work.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Standup", "Report"}, descriptions(workTodos))
+	}},
+	{"completing a recurring todo creates its next occurrence", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Chores")
+		dueDate := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+		estimate := 10
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Pay rent", Priority: models.PriorityHigh, DueDate: &dueDate,
+			EstimateMinutes: &estimate, Tags: []string{"home"}, RecurrenceRule: "monthly",
+		})
+		plain := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Once", Priority: models.PriorityLow})
+
+		completed := true
+		updated, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.True(t, updated.Completed)
+		require.NotNil(t, updated.NextOccurrence)
+
+		next := updated.NextOccurrence
+		assert.NotEqual(t, todo.ID, next.ID)
+		assert.False(t, next.Completed)
+		assert.Equal(t, "Pay rent", next.Description)
+		assert.Equal(t, models.PriorityHigh, next.Priority)
+		assert.Equal(t, "monthly", next.RecurrenceRule)
+		assert.Equal(t, []string{"home"}, next.Tags)
+		require.NotNil(t, next.EstimateMinutes)
+		assert.Equal(t, 10, *next.EstimateMinutes)
+		require.NotNil(t, next.DueDate)
+		assert.True(t, next.DueDate.Equal(time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC)), "got %s", next.DueDate)
+
+		// The next occurrence is stored after the list's other todos
+		fetched, err := store.GetTodoByID(userID, list.ID, next.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "monthly", fetched.RecurrenceRule)
+		assert.Greater(t, fetched.Position, plain.Position)
+
+		// Updating an already completed todo does not create another
+		again, err := store.UpdateTodo(userID, list.ID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Nil(t, again.NextOccurrence)
+
+		// Non-recurring todos complete as before
+		updated, err = store.UpdateTodo(userID, list.ID, plain.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Nil(t, updated.NextOccurrence)
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, todos, 3)
+	}},
+	{"batch completion repeats recurring todos and cascades to subtasks", func(t *testing.T, store Store, userID uuid.UUID) {
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{
+			Name: "Weekly", CompleteSubtasksWithParent: true, ReopenSubtasksWithParent: true,
+		})
+		require.NoError(t, err)
+		dueDate := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+		laundry := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Laundry", Priority: models.PriorityLow, DueDate: &dueDate, Tags: []string{"home"}, RecurrenceRule: "weekly",
+		})
+		bins := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Bins", Priority: models.PriorityLow, DueDate: &dueDate, RecurrenceRule: "daily",
+		})
+		trip := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Trip", Priority: models.PriorityLow})
+		_, err = store.CreateSubtask(userID, list.ID, trip.ID, models.CreateSubtaskRequest{Description: "Tickets"})
+		require.NoError(t, err)
+
+		completed := true
+		ids := []uuid.UUID{bins.ID, laundry.ID, trip.ID}
+		result, err := store.BatchUpdateTodos(userID, list.ID, ids, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Affected)
+		require.Len(t, result.NextOccurrences, 2)
+		assert.Equal(t, []string{"Bins", "Laundry"}, descriptions(result.NextOccurrences))
+		assert.Equal(t, []string{"home"}, result.NextOccurrences[1].Tags)
+		require.NotNil(t, result.NextOccurrences[1].DueDate)
+		assert.True(t, result.NextOccurrences[1].DueDate.Equal(dueDate.AddDate(0, 0, 7)), "got %s", result.NextOccurrences[1].DueDate)
+
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Laundry", "Bins", "Trip", "Bins", "Laundry"}, descriptions(todos))
+		assert.False(t, todos[3].Completed)
+		assert.False(t, todos[4].Completed)
+
+		subtasks, err := store.GetSubtasks(userID, list.ID, trip.ID)
+		require.NoError(t, err)
+		assert.True(t, subtasks[0].Completed)
+		stored, err := store.GetTodoByID(userID, list.ID, trip.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.CompletedSubtaskCount)
+
+		// Completing them again creates nothing; reopening reopens the subtasks
+		result, err = store.BatchUpdateTodos(userID, list.ID, ids, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+		assert.Empty(t, result.NextOccurrences)
+		reopened := false
+		_, err = store.BatchUpdateTodos(userID, list.ID, ids, models.UpdateTodoRequest{Completed: &reopened})
+		require.NoError(t, err)
+		subtasks, err = store.GetSubtasks(userID, list.ID, trip.ID)
+		require.NoError(t, err)
+		assert.False(t, subtasks[0].Completed)
+	}},
+
+	// Pagination
+	{"list todos paginate after filtering and sorting", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Paged")
+		for _, desc := range []string{"A", "B", "C", "D", "E"} {
+			mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: desc, Priority: models.PriorityHigh})
+			time.Sleep(2 * time.Millisecond)
+		}
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Low", Priority: models.PriorityLow})
+		high := models.PriorityHigh
+
+		todos, pagination, err := store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Priority: &high, SortBy: "createdAt", SortOrder: "desc", Page: 2, Limit: 2},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C", "B"}, descriptions(todos))
+		assert.Equal(t, 2, pagination.Page)
+		assert.Equal(t, 2, pagination.Limit)
+		assert.Equal(t, 5, pagination.TotalItems)
+		assert.Equal(t, 3, pagination.TotalPages)
+
+		todos, pagination, err = store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Priority: &high, SortBy: "createdAt", SortOrder: "desc", Page: 3, Limit: 2},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A"}, descriptions(todos))
+		assert.Equal(t, 3, pagination.TotalPages)
+
+		todos, pagination, err = store.GetTodosByList(
+			userID, list.ID, ListTodosOptions{Priority: &high, SortBy: "createdAt", SortOrder: "desc", Page: 9, Limit: 2},
+		)
+		require.NoError(t, err)
+		assert.NotNil(t, todos)
+		assert.Empty(t, todos)
+		assert.Equal(t, 5, pagination.TotalItems)
+		assert.Equal(t, 3, pagination.TotalPages)
+	}},
+
+	{"searches match %, _ and backslash literally", func(t *testing.T, store Store, userID uuid.UUID) {
+		sale := mustCreateList(t, store, userID, "50% sale")
+		mustCreateList(t, store, userID, "500 items")
+		for _, desc := range []string{"50% off", "500 apples", "a_b", "axb", `C:\temp`, "C:temp"} {
+			mustCreateTodo(t, store, userID, sale.ID, models.CreateTodoRequest{Description: desc, Priority: models.PriorityLow})
+		}
+
+		// withDescriptions returns the descriptions of found todos
+		withDescriptions := func(found []models.TodoWithList) []string {
+			result := make([]string, len(found))
+			for i := range found {
+				result[i] = found[i].Description
+			}
+			return result
+		}
+		search := func(q string) []string {
+			t.Helper()
+			found, _, err := store.SearchTodos(userID, SearchOptions{Query: q, Page: 1, Limit: 10})
+			require.NoError(t, err)
+			return withDescriptions(found)
+		}
+		assert.Equal(t, []string{"50% off"}, search("50%"))
+		assert.Equal(t, []string{"a_b"}, search("a_b"))
+		assert.Equal(t, []string{`C:\temp`}, search(`:\`))
+
+		todos, _, err := store.QueryUserTodos(userID, TodoQueryOptions{Search: "0%", SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"50% off"}, withDescriptions(todos))
+
+		lists, _, err := store.GetAllLists(userID, 1, 10, "50%", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		require.Len(t, lists, 1)
+		assert.Equal(t, "50% sale", lists[0].Name)
+	}},
+	{"search matches descriptions and optionally list names", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		errands := mustCreateList(t, store, userID, "Errands")
+		otherUser := uuid.New()
+		foreign := mustCreateList(t, store, otherUser, "Foreign")
+		mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{Description: "Draft Report", Priority: models.PriorityHigh})
+		mustCreateTodo(t, store, userID, errands.ID, models.CreateTodoRequest{Description: "Buy milk", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, otherUser, foreign.ID, models.CreateTodoRequest{Description: "Foreign report", Priority: models.PriorityLow})
+
+		found, pagination, err := store.SearchTodos(userID, SearchOptions{Query: "REPORT", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, 1, pagination.TotalItems)
+		assert.Equal(t, "Draft Report", found[0].Description)
+		assert.Equal(t, "Work", found[0].ListName)
+
+		found, _, err = store.SearchTodos(userID, SearchOptions{Query: "errand", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+
+		found, _, err = store.SearchTodos(userID, SearchOptions{Query: "errand", IncludeListNames: true, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, "Errands", found[0].ListName)
+	}},
+
+	// Ownership
+	{"lists are invisible to other users", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Private")
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Secret", Priority: models.PriorityLow})
+		other := uuid.New()
+
+		_, err := store.GetListByID(other, list.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+
+		name := "Stolen"
+		_, err = store.UpdateList(other, list.ID, models.UpdateTodoListRequest{Name: &name})
+		assert.ErrorIs(t, err, ErrListNotFound)
+		assert.ErrorIs(t, store.DeleteList(other, list.ID, nil), ErrListNotFound)
+
+		lists, pagination, err := store.GetAllLists(other, 1, 10, "", nil, nil, ActiveLists)
+		require.NoError(t, err)
+		assert.Empty(t, lists)
+		assert.Equal(t, 0, pagination.TotalItems)
+	}},
+	{"todos are invisible to other users", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Private")
+		todo := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Secret", Priority: models.PriorityLow})
+		other := uuid.New()
+
+		_, err := store.CreateTodo(other, list.ID, models.CreateTodoRequest{Description: "Intruder", Priority: models.PriorityLow})
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, _, err = store.GetTodosByList(other, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100})
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.GetTodoByID(other, list.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.UpdateTodo(other, list.ID, todo.ID, models.UpdateTodoRequest{})
+		assert.ErrorIs(t, err, ErrListNotFound)
+		assert.ErrorIs(t, store.DeleteTodo(other, list.ID, todo.ID, nil), ErrListNotFound)
+	}},
+
+	// Cross-list queries
+	{"query user todos spans only the user's lists", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{Description: "Report", Priority: models.PriorityLow})
+		mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{Description: "Dishes", Priority: models.PriorityLow})
+		other := uuid.New()
+		otherList := mustCreateList(t, store, other, "Theirs")
+		mustCreateTodo(t, store, other, otherList.ID, models.CreateTodoRequest{Description: "Not mine", Priority: models.PriorityLow})
+
+		todos, pagination, err := store.QueryUserTodos(userID, TodoQueryOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, todos, 2)
+		assert.Equal(t, 2, pagination.TotalItems)
+		assert.Equal(t, "Report", todos[0].Description)
+		assert.Equal(t, work.ID, todos[0].ListID)
+		assert.Equal(t, "Work", todos[0].ListName)
+		assert.Equal(t, "Home", todos[1].ListName)
+	}},
+	{"query user todos composes filters", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		past := time.Now().Add(-48 * time.Hour)
+		soon := time.Now().Add(24 * time.Hour)
+		far := time.Now().Add(30 * 24 * time.Hour)
+		mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Late report",
+			Priority:    models.PriorityHigh,
+			DueDate:     &past,
+		})
+		mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{
+			Description: "Late taxes",
+			Priority:    models.PriorityLow,
+			DueDate:     &past,
+		})
+		mustCreateTodo(t, store, userID, work.ID, models.CreateTodoRequest{
+			Description: "Weekly report",
+			Priority:    models.PriorityHigh,
+			DueDate:     &soon,
+		})
+		mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{
+			Description: "Renew passport",
+			Priority:    models.PriorityMedium,
+			DueDate:     &far,
+		})
+		doneLate := mustCreateTodo(t, store, userID, home.ID, models.CreateTodoRequest{
+			Description: "Late library book",
+			Priority:    models.PriorityHigh,
+			DueDate:     &past,
+		})
+		done := true
+		_, err := store.UpdateTodo(userID, home.ID, doneLate.ID, models.UpdateTodoRequest{Completed: &done})
+		require.NoError(t, err)
+
+		query := func(opts TodoQueryOptions) []string {
+			t.Helper()
+			opts.SortBy, opts.SortOrder, opts.Page, opts.Limit = "createdAt", "asc", 1, 50
+			todos, _, queryErr := store.QueryUserTodos(userID, opts)
+			require.NoError(t, queryErr)
+			result := make([]string, len(todos))
+			for i := range todos {
+				result[i] = todos[i].Description
+			}
+			return result
+		}
+
+		high := models.PriorityHigh
+		notDone := false
+		assert.Equal(t, []string{"Late report", "Late taxes"}, query(TodoQueryOptions{Overdue: true}))
+		assert.Equal(t, []string{"Late report"}, query(TodoQueryOptions{Overdue: true, Priority: &high}))
+		assert.Equal(t, []string{"Late report", "Weekly report"}, query(TodoQueryOptions{Priority: &high, Completed: &notDone}))
+		assert.Equal(t, []string{"Late report", "Weekly report"}, query(TodoQueryOptions{Search: "REPORT"}))
+		assert.Equal(t, []string{"Late report", "Late taxes", "Weekly report", "Late library book"},
+			query(TodoQueryOptions{DueBefore: &soon, DueAfter: &past}))
+		assert.Equal(t, []string{"Weekly report", "Renew passport"}, query(TodoQueryOptions{DueAfter: &soon}))
+		assert.Equal(t, []string{"Weekly report"}, query(TodoQueryOptions{DueAfter: &soon, Search: "report"}))
+	}},
+	{"query user todos paginates across lists without gaps", func(t *testing.T, store Store, userID uuid.UUID) {
+		work := mustCreateList(t, store, userID, "Work")
+		home := mustCreateList(t, store, userID, "Home")
+		for i, desc := range []string{"A", "B", "C", "D", "E"} {
+			listID := work.ID
+			if i%2 == 1 {
+				listID = home.ID
+			}
+			// Identical priorities force the tie-breaker to keep pages stable
+			mustCreateTodo(t, store, userID, listID, models.CreateTodoRequest{Description: desc, Priority: models.PriorityMedium})
+		}
+
+		var seen []string
+		for page := 1; page <= 3; page++ {
+			todos, pagination, err := store.QueryUserTodos(userID, TodoQueryOptions{
+				SortBy: "priority", SortOrder: "desc", Page: page, Limit: 2,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 5, pagination.TotalItems)
+			assert.Equal(t, 3, pagination.TotalPages)
+			for i := range todos {
+				seen = append(seen, todos[i].Description)
+			}
+		}
+		assert.Equal(t, []string{"A", "B", "C", "D", "E"}, seen)
+
+		todos, _, err := store.QueryUserTodos(userID, TodoQueryOptions{SortBy: "createdAt", Page: 4, Limit: 2})
+		require.NoError(t, err)
+		assert.Empty(t, todos)
+	}},
+
+	// Error cases
+	{"todo from another list is not found", func(t *testing.T, store Store, userID uuid.UUID) {
+		first := mustCreateList(t, store, userID, "First")
+		second := mustCreateList(t, store, userID, "Second")
+		todo := mustCreateTodo(t, store, userID, first.ID, models.CreateTodoRequest{Description: "A", Priority: models.PriorityLow})
+
+		_, err := store.GetTodoByID(userID, second.ID, todo.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.UpdateTodo(userID, second.ID, todo.ID, models.UpdateTodoRequest{})
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		assert.ErrorIs(t, store.DeleteTodo(userID, second.ID, todo.ID, nil), ErrTodoNotFound)
+	}},
+	{"missing ids map to not-found errors", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Exists")
+
+		_, err := store.GetListByID(userID, uuid.New())
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.CreateTodo(userID, uuid.New(), models.CreateTodoRequest{Description: "A", Priority: models.PriorityLow})
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.GetTodoByID(userID, list.ID, uuid.New())
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		assert.ErrorIs(t, store.DeleteTodo(userID, list.ID, uuid.New(), nil), ErrTodoNotFound)
+	}},
+	{"invalid sort field is rejected", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Sorting")
+
+		_, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "description", SortOrder: "asc", Page: 1, Limit: 100})
+		assert.ErrorIs(t, err, ErrInvalidSortField)
+	}},
+}
+
+// trashCases cover the trash of soft-deleted todos, which hard deletes leave empty
+var trashCases = []conformanceCase{
+	{"deleted todos go to the trash and can be restored", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Trash")
+		first := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "First", Priority: models.PriorityLow})
+		second := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{
+			Description: "Second", Priority: models.PriorityLow, Tags: []string{"kept"},
+		})
+		version := second.Version
+		mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Third", Priority: models.PriorityLow})
+
+		trash, err := store.GetDeletedTodos(userID, list.ID)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+
+		require.NoError(t, store.DeleteTodo(userID, list.ID, first.ID, nil))
+		time.Sleep(2 * time.Millisecond)
+		require.NoError(t, store.DeleteTodo(userID, list.ID, second.ID, nil))
+
+		trash, err = store.GetDeletedTodos(userID, list.ID)
+		require.NoError(t, err)
+		require.Len(t, trash, 2)
+		assert.Equal(t, "Second", trash[0].Description)
+		assert.Equal(t, []string{"kept"}, trash[0].Tags)
+		assert.False(t, trash[0].DeletedAt.IsZero())
+		assert.Equal(t, "First", trash[1].Description)
+
+		_, err = store.GetDeletedTodos(uuid.New(), list.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+		_, err = store.RestoreTodo(uuid.New(), list.ID, second.ID)
+		assert.ErrorIs(t, err, ErrListNotFound)
+
+		// The restored todo goes after the todos still in the list
+		restored, err := store.RestoreTodo(userID, list.ID, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, restored.ID)
+		assert.Equal(t, version+1, restored.Version)
+		assert.Equal(t, []string{"kept"}, restored.Tags)
+		todos, _, err := store.GetTodosByList(userID, list.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Third", "Second"}, descriptions(todos))
+
+		// Only trashed todos can be restored
+		_, err = store.RestoreTodo(userID, list.ID, second.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.RestoreTodo(userID, list.ID, uuid.New())
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+
+		trash, err = store.GetDeletedTodos(userID, list.ID)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, first.ID, trash[0].ID)
+	}},
+	{"purging the trash removes only todos deleted long enough ago", func(t *testing.T, store Store, userID uuid.UUID) {
+		list := mustCreateList(t, store, userID, "Purged")
+		old := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Old", Priority: models.PriorityLow})
+		live := mustCreateTodo(t, store, userID, list.ID, models.CreateTodoRequest{Description: "Live", Priority: models.PriorityLow})
+		require.NoError(t, store.DeleteTodo(userID, list.ID, old.ID, nil))
+
+		purged, err := store.PurgeDeletedTodos(time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		time.Sleep(5 * time.Millisecond)
+		purged, err = store.PurgeDeletedTodos(time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		trash, err := store.GetDeletedTodos(userID, list.ID)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+		_, err = store.RestoreTodo(userID, list.ID, old.ID)
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+		_, err = store.GetTodoByID(userID, list.ID, live.ID)
+		assert.NoError(t, err)
+	}},
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"time"
+
+	"todolist-api/internal/models"
+)
+
+// todoChanges reports the fields that differ between a todo before and after
+// an update, keyed by their JSON names. Version and UpdatedAt always change on
+// update and are left out.
+func todoChanges(before, after *models.Todo) models.FieldChanges {
+	changes := models.FieldChanges{}
+	if before.Description != after.Description {
+		changes["description"] = models.FieldChange{Old: before.Description, New: after.Description}
+	}
+	if before.Priority != after.Priority {
+		changes["priority"] = models.FieldChange{Old: before.Priority, New: after.Priority}
+	}
+	if !timesEqual(before.DueDate, after.DueDate) {
+		changes["dueDate"] = models.FieldChange{Old: timeValue(before.DueDate), New: timeValue(after.DueDate)}
+	}
+	if !intsEqual(before.EstimateMinutes, after.EstimateMinutes) {
+		changes["estimateMinutes"] = models.FieldChange{Old: intValue(before.EstimateMinutes), New: intValue(after.EstimateMinutes)}
+	}
+	if !stringsEqual(before.Tags, after.Tags) {
+		changes["tags"] = models.FieldChange{Old: before.Tags, New: after.Tags}
+	}
+	if before.RecurrenceRule != after.RecurrenceRule {
+		changes["recurrenceRule"] = models.FieldChange{Old: before.RecurrenceRule, New: after.RecurrenceRule}
+	}
+	if before.Completed != after.Completed {
+		changes["completed"] = models.FieldChange{Old: before.Completed, New: after.Completed}
+	}
+	if !timesEqual(before.CompletedAt, after.CompletedAt) {
+		changes["completedAt"] = models.FieldChange{Old: timeValue(before.CompletedAt), New: timeValue(after.CompletedAt)}
+	}
+	if !timesEqual(before.ArchivedAt, after.ArchivedAt) {
+		changes["archivedAt"] = models.FieldChange{Old: timeValue(before.ArchivedAt), New: timeValue(after.ArchivedAt)}
+	}
+	return changes
+}
+
+// listChanges reports the fields that differ between a list before and after
+// an update, keyed by their JSON names
+func listChanges(before, after *models.TodoList) models.FieldChanges {
+	changes := models.FieldChanges{}
+	if before.Name != after.Name {
+		changes["name"] = models.FieldChange{Old: before.Name, New: after.Name}
+	}
+	if before.Description != after.Description {
+		changes["description"] = models.FieldChange{Old: before.Description, New: after.Description}
+	}
+	if before.AutoArchiveCompleted != after.AutoArchiveCompleted {
+		changes["autoArchiveCompleted"] = models.FieldChange{Old: before.AutoArchiveCompleted, New: after.AutoArchiveCompleted}
+	}
+	if before.KeepCompleted != after.KeepCompleted {
+		changes["keepCompleted"] = models.FieldChange{Old: before.KeepCompleted, New: after.KeepCompleted}
+	}
+	if before.RequireDueDate != after.RequireDueDate {
+		changes["requireDueDate"] = models.FieldChange{Old: before.RequireDueDate, New: after.RequireDueDate}
+	}
+	if before.CompleteSubtasksWithParent != after.CompleteSubtasksWithParent {
+		changes["completeSubtasksWithParent"] = models.FieldChange{Old: before.CompleteSubtasksWithParent, New: after.CompleteSubtasksWithParent}
+	}
+	if before.ReopenSubtasksWithParent != after.ReopenSubtasksWithParent {
+		changes["reopenSubtasksWithParent"] = models.FieldChange{Old: before.ReopenSubtasksWithParent, New: after.ReopenSubtasksWithParent}
+	}
+	return changes
+}
+
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func intsEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// timeValue dereferences an optional time so a diff holds the value rather
+// than a pointer the store may later reuse; nil stays nil (JSON null)
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// intValue dereferences an optional int; nil stays nil (JSON null)
+func intValue(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
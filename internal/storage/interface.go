@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"todolist-api/internal/models"
 
 	"github.com/google/uuid"
@@ -10,15 +12,117 @@ import (
 type Store interface {
 	// List operations
 	CreateList(userID uuid.UUID, req models.CreateTodoListRequest) (*models.TodoList, error)
-	GetAllLists(userID uuid.UUID, page, limit int) ([]models.TodoList, *models.Pagination, error)
+	GetAllLists(
+		userID uuid.UUID, page, limit int, search string, createdAfter, createdBefore *time.Time, archived ArchivedLists,
+	) ([]models.TodoList, *models.Pagination, error)
 	GetListByID(userID, listID uuid.UUID) (*models.TodoList, error)
+	CountLists(userID uuid.UUID) (*models.ListSummary, error)
 	UpdateList(userID, listID uuid.UUID, req models.UpdateTodoListRequest) (*models.TodoList, error)
-	DeleteList(userID, listID uuid.UUID) error
+	DuplicateList(userID, listID uuid.UUID) (*models.TodoList, error)
+	ArchiveList(userID, listID uuid.UUID) (*models.TodoList, error)
+	UnarchiveList(userID, listID uuid.UUID) (*models.TodoList, error)
+	DeleteList(userID, listID uuid.UUID, version *int) error
+	MergeLists(userID, targetListID, sourceListID uuid.UUID) (*models.TodoList, error)
 
 	// Todo operations
 	CreateTodo(userID, listID uuid.UUID, req models.CreateTodoRequest) (*models.Todo, error)
-	GetTodosByList(userID, listID uuid.UUID, priority *models.Priority, completed *bool, sortBy, sortOrder string) ([]models.Todo, error)
+	BatchCreateTodos(userID, listID uuid.UUID, reqs []models.CreateTodoRequest) ([]models.Todo, error)
+	GetTodosByList(userID, listID uuid.UUID, opts ListTodosOptions) ([]models.Todo, *models.Pagination, error)
+	GetTodosForLists(userID uuid.UUID, listIDs []uuid.UUID, perList int) (map[uuid.UUID][]models.Todo, error)
 	GetTodoByID(userID, listID, todoID uuid.UUID) (*models.Todo, error)
 	UpdateTodo(userID, listID, todoID uuid.UUID, req models.UpdateTodoRequest) (*models.Todo, error)
-	DeleteTodo(userID, listID, todoID uuid.UUID) error
+	DeleteTodo(userID, listID, todoID uuid.UUID, version *int) error
+	ReorderTodo(userID, listID, todoID uuid.UUID, position int) (*models.Todo, error)
+	BatchUpdateTodos(userID, listID uuid.UUID, todoIDs []uuid.UUID, req models.UpdateTodoRequest) (*models.BatchResult, error)
+	BatchDeleteTodos(userID, listID uuid.UUID, todoIDs []uuid.UUID) (*models.BatchResult, error)
+	CloneTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error)
+	MoveTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error)
+	SetTodoPinned(userID, listID, todoID uuid.UUID, pinned bool) (*models.Todo, error)
+	GetDeletedTodos(userID, listID uuid.UUID) ([]models.DeletedTodo, error)
+	RestoreTodo(userID, listID, todoID uuid.UUID) (*models.Todo, error)
+	QueryUserTodos(userID uuid.UUID, opts TodoQueryOptions) ([]models.TodoWithList, *models.Pagination, error)
+	ReprioritizeTodos(userID uuid.UUID, opts TodoQueryOptions, priority models.Priority) (int, error)
+	SearchTodos(userID uuid.UUID, opts SearchOptions) ([]models.TodoWithList, *models.Pagination, error)
+
+	// Subtask operations
+	GetSubtasks(userID, listID, todoID uuid.UUID) ([]models.Subtask, error)
+	CreateSubtask(userID, listID, todoID uuid.UUID, req models.CreateSubtaskRequest) (*models.Subtask, error)
+	UpdateSubtask(userID, listID, todoID, subtaskID uuid.UUID, req models.UpdateSubtaskRequest) (*models.Subtask, error)
+	DeleteSubtask(userID, listID, todoID, subtaskID uuid.UUID) error
+
+	// Maintenance operations
+	PurgeOldCompleted(before time.Time) (int64, error)
+	PurgeDeletedTodos(olderThan time.Duration) (int64, error)
+
+	// Statistics operations
+	GetListStats(userID, listID uuid.UUID) (*models.ListStats, error)
+	GetUserStats(userID uuid.UUID) (*models.UserStats, error)
+
+	// Notification operations
+	GetNotifications(userID uuid.UUID, now time.Time, dueSoon time.Duration) ([]models.Notification, error)
+	AcknowledgeNotification(userID, todoID uuid.UUID, notificationType models.NotificationType) error
+
+	// Share link operations
+	CreateShareLink(userID, listID uuid.UUID, expiresAt time.Time) (*models.ListShareLink, error)
+	RevokeShareLink(userID, listID, linkID uuid.UUID) error
+	GetSharedList(token string, now time.Time) (*models.ListShareLink, error)
+}
+
+// ArchivedLists selects the lists GetAllLists returns by archive state
+type ArchivedLists int
+
+const (
+	// ActiveLists returns only lists that are not archived (the default)
+	ActiveLists ArchivedLists = iota
+	// OnlyArchivedLists returns only archived lists
+	OnlyArchivedLists
+	// AllLists returns lists whatever their archive state
+	AllLists
+)
+
+// ListTodosOptions describes filtering, sorting and pagination for the todos
+// of a single list. Nil or zero fields do not filter.
+type ListTodosOptions struct {
+	Priority      *models.Priority
+	Completed     *bool
+	Tags          []string   // todos must carry every tag
+	Archived      bool       // only archived todos instead of only active ones
+	Overdue       bool       // only incomplete todos whose due date has passed
+	DueAfter      *time.Time // inclusive lower bound on due date; excludes undated todos
+	DueBefore     *time.Time // inclusive upper bound on due date; excludes undated todos
+	CreatedAfter  *time.Time // inclusive lower bound on creation time
+	CreatedBefore *time.Time // inclusive upper bound on creation time
+	SortBy        string
+	SortOrder     string
+	Page          int
+	Limit         int
+}
+
+// TodoQueryOptions describes filtering, sorting and pagination for todo
+// queries that span all of a user's lists. Nil or zero fields do not filter.
+type TodoQueryOptions struct {
+	Priority      *models.Priority
+	Completed     *bool
+	Archived      bool       // only archived todos instead of only active ones
+	Overdue       bool       // only incomplete todos whose due date has passed
+	DueAfter      *time.Time // inclusive lower bound on due date
+	DueBefore     *time.Time // inclusive upper bound on due date
+	CreatedAfter  *time.Time // inclusive lower bound on creation time
+	CreatedBefore *time.Time // inclusive upper bound on creation time
+	Search        string     // case-insensitive substring match on description
+	SortBy        string
+	SortOrder     string
+	Page          int
+	Limit         int
+}
+
+// SearchOptions describes a search across a user's active todos. Results are
+// ordered newest first.
+type SearchOptions struct {
+	Query            string // case-insensitive substring matched against descriptions
+	IncludeListNames bool   // also match todos whose list name contains Query
+	Priority         *models.Priority
+	Completed        *bool
+	Page             int
+	Limit            int
 }
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"testing"
+
+	"todolist-api/internal/models"
+	"todolist-api/internal/testutil"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testListNameConflicts asserts list name uniqueness rules that every Store
+// implementation must enforce identically
+func testListNameConflicts(t *testing.T, newStore func(t *testing.T) Store, userID uuid.UUID) {
+	t.Run("create rejects a duplicate name", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Groceries"})
+		require.NoError(t, err)
+
+		_, err = store.CreateList(userID, models.CreateTodoListRequest{Name: "Groceries"})
+		assert.ErrorIs(t, err, ErrListNameExists)
+	})
+
+	t.Run("names are case sensitive", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Groceries"})
+		require.NoError(t, err)
+
+		_, err = store.CreateList(userID, models.CreateTodoListRequest{Name: "groceries"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("same name is allowed for different users", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Groceries"})
+		require.NoError(t, err)
+
+		_, err = store.CreateList(uuid.New(), models.CreateTodoListRequest{Name: "Groceries"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("update rejects another list's name", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Home"})
+		require.NoError(t, err)
+		work, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Work"})
+		require.NoError(t, err)
+
+		name := "Home"
+		_, err = store.UpdateList(userID, work.ID, models.UpdateTodoListRequest{Name: &name})
+		assert.ErrorIs(t, err, ErrListNameExists)
+	})
+
+	t.Run("update keeping its own name succeeds", func(t *testing.T) {
+		store := newStore(t)
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Home"})
+		require.NoError(t, err)
+
+		name := "Home"
+		desc := "Chores"
+		updated, err := store.UpdateList(userID, list.ID, models.UpdateTodoListRequest{Name: &name, Description: &desc})
+		require.NoError(t, err)
+		assert.Equal(t, "Chores", updated.Description)
+	})
+
+	t.Run("name is reusable after the list is deleted", func(t *testing.T) {
+		store := newStore(t)
+		list, err := store.CreateList(userID, models.CreateTodoListRequest{Name: "Temp"})
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteList(userID, list.ID, nil))
+
+		_, err = store.CreateList(userID, models.CreateTodoListRequest{Name: "Temp"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestListNameConflicts(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testListNameConflicts(t, func(_ *testing.T) Store {
+			return NewStorage()
+		}, testMemoryUserID)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		testListNameConflicts(t, func(t *testing.T) Store {
+			db := testutil.SetupTestDB(t)
+			t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+			return NewPostgresStorage(db)
+		}, testUserID)
+	})
+}
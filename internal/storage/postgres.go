@@ -2,16 +2,33 @@ package storage
 
 import (
 	"errors"
+	"sort"
+	"strings"
+	"time"
 
+	"todolist-api/internal/database"
 	"todolist-api/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// uniqueViolationCode is the PostgreSQL error code for a unique constraint violation
+const uniqueViolationCode = "23505"
+
 // PostgresStorage implements storage using PostgreSQL with GORM
 type PostgresStorage struct {
 	db *gorm.DB
+
+	// writeSlots bounds concurrent write operations (nil = unlimited)
+	writeSlots   chan struct{}
+	writeTimeout time.Duration
+
+	// hardDelete removes deleted lists and todos permanently instead of
+	// soft-deleting them
+	hardDelete bool
 }
 
 // NewPostgresStorage creates a new PostgreSQL storage instance
@@ -19,22 +36,57 @@ func NewPostgresStorage(db *gorm.DB) *PostgresStorage {
 	return &PostgresStorage{db: db}
 }
 
+// NewPostgresStorageWithConfig creates a new PostgreSQL storage instance using the given config
+func NewPostgresStorageWithConfig(db *gorm.DB, config *Config) *PostgresStorage {
+	s := NewPostgresStorage(db)
+	if config.MaxDBConcurrency > 0 {
+		s.writeSlots = make(chan struct{}, config.MaxDBConcurrency)
+		s.writeTimeout = config.DBBusyTimeout
+	}
+	s.hardDelete = config.DeleteMode == DeleteModeHard
+	return s
+}
+
+// acquireWrite waits for a free write slot so bursts queue instead of
+// exhausting the connection pool. The returned function releases the slot.
+func (s *PostgresStorage) acquireWrite() (func(), error) {
+	if s.writeSlots == nil {
+		return func() {}, nil
+	}
+
+	timer := time.NewTimer(s.writeTimeout)
+	defer timer.Stop()
+
+	select {
+	case s.writeSlots <- struct{}{}:
+		return func() { <-s.writeSlots }, nil
+	case <-timer.C:
+		return nil, ErrDBBusy
+	}
+}
+
 // CreateList creates a new todo list
 func (s *PostgresStorage) CreateList(userID uuid.UUID, req models.CreateTodoListRequest) (*models.TodoList, error) {
-	// Check if list with same name exists for this user
-	var existing models.TodoList
-	result := s.db.Where("user_id = ? AND name = ?", userID, req.Name).First(&existing)
-	if result.Error == nil {
-		return nil, ErrListNameExists
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
 	}
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, result.Error
+	defer release()
+
+	if err := s.checkListNameAvailable(userID, req.Name, uuid.Nil); err != nil {
+		return nil, err
 	}
 
 	list := &models.TodoList{
-		UserID:      userID,
-		Name:        req.Name,
-		Description: req.Description,
+		UserID:                     userID,
+		Name:                       req.Name,
+		Description:                req.Description,
+		AutoArchiveCompleted:       req.AutoArchiveCompleted,
+		KeepCompleted:              req.KeepCompleted,
+		RequireDueDate:             req.RequireDueDate,
+		CompleteSubtasksWithParent: req.CompleteSubtasksWithParent,
+		ReopenSubtasksWithParent:   req.ReopenSubtasksWithParent,
+		Version:                    1,
 	}
 
 	if err := s.db.Create(list).Error; err != nil {
@@ -46,12 +98,32 @@ func (s *PostgresStorage) CreateList(userID uuid.UUID, req models.CreateTodoList
 }
 
 // GetAllLists retrieves all todo lists with pagination for a specific user
-func (s *PostgresStorage) GetAllLists(userID uuid.UUID, page, limit int) ([]models.TodoList, *models.Pagination, error) {
+func (s *PostgresStorage) GetAllLists(
+	userID uuid.UUID, page, limit int, search string, createdAfter, createdBefore *time.Time, archived ArchivedLists,
+) ([]models.TodoList, *models.Pagination, error) {
 	var lists []models.TodoList
 	var totalItems int64
 
-	// Count total items for this user
-	if err := s.db.Model(&models.TodoList{}).Where("user_id = ?", userID).Count(&totalItems).Error; err != nil {
+	query := s.db.Model(&models.TodoList{}).Where("user_id = ?", userID)
+	switch archived {
+	case ActiveLists:
+		query = query.Where("archived = ?", false)
+	case OnlyArchivedLists:
+		query = query.Where("archived = ?", true)
+	}
+	if search != "" {
+		pattern := containsPattern(search)
+		query = query.Where(`(LOWER(name) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	if createdAfter != nil {
+		query = query.Where("created_at >= ?", *createdAfter)
+	}
+	if createdBefore != nil {
+		query = query.Where("created_at <= ?", *createdBefore)
+	}
+
+	// Count total matching items for this user
+	if err := query.Session(&gorm.Session{}).Count(&totalItems).Error; err != nil {
 		return nil, nil, err
 	}
 
@@ -60,7 +132,7 @@ func (s *PostgresStorage) GetAllLists(userID uuid.UUID, page, limit int) ([]mode
 	totalPages := int((totalItems + int64(limit) - 1) / int64(limit))
 
 	// Fetch paginated lists for this user
-	if err := s.db.Where("user_id = ?", userID).
+	if err := query.
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -103,8 +175,32 @@ func (s *PostgresStorage) GetListByID(userID, listID uuid.UUID) (*models.TodoLis
 	return &list, nil
 }
 
+// CountLists counts the user's lists by state
+func (s *PostgresStorage) CountLists(userID uuid.UUID) (*models.ListSummary, error) {
+	var counts struct {
+		Total    int
+		Archived int
+	}
+	if err := s.db.Model(&models.TodoList{}).
+		Select("COUNT(*) AS total, COUNT(CASE WHEN archived THEN 1 END) AS archived").
+		Where("user_id = ?", userID).
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &models.ListSummary{Total: counts.Total, Archived: counts.Archived}
+	summary.Active = summary.Total - summary.Archived
+	return summary, nil
+}
+
 // UpdateList updates an existing todo list for a specific user
 func (s *PostgresStorage) UpdateList(userID, listID uuid.UUID, req models.UpdateTodoListRequest) (*models.TodoList, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
 	var list models.TodoList
 	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -112,16 +208,18 @@ func (s *PostgresStorage) UpdateList(userID, listID uuid.UUID, req models.Update
 		}
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != list.Version {
+		return nil, ErrVersionConflict
+	}
+	before := list
 
-	// Check if new name conflicts with existing list for this user
+	// Check if new name conflicts with existing list for this user. Archived
+	// lists do not take part in name uniqueness.
 	if req.Name != nil && *req.Name != list.Name {
-		var existing models.TodoList
-		result := s.db.Where("user_id = ? AND name = ? AND id != ?", userID, *req.Name, listID).First(&existing)
-		if result.Error == nil {
-			return nil, ErrListNameExists
-		}
-		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, result.Error
+		if !list.Archived {
+			if err := s.checkListNameAvailable(userID, *req.Name, listID); err != nil {
+				return nil, err
+			}
 		}
 		list.Name = *req.Name
 	}
@@ -129,11 +227,202 @@ func (s *PostgresStorage) UpdateList(userID, listID uuid.UUID, req models.Update
 	if req.Description != nil {
 		list.Description = *req.Description
 	}
+	if req.AutoArchiveCompleted != nil {
+		list.AutoArchiveCompleted = *req.AutoArchiveCompleted
+	}
+	if req.KeepCompleted != nil {
+		list.KeepCompleted = *req.KeepCompleted
+	}
+	if req.RequireDueDate != nil {
+		list.RequireDueDate = *req.RequireDueDate
+	}
+	if req.CompleteSubtasksWithParent != nil {
+		list.CompleteSubtasksWithParent = *req.CompleteSubtasksWithParent
+	}
+	if req.ReopenSubtasksWithParent != nil {
+		list.ReopenSubtasksWithParent = *req.ReopenSubtasksWithParent
+	}
+
+	// Only write if nobody else has updated the list since it was read
+	now := s.db.NowFunc()
+	result := s.db.Model(&models.TodoList{}).
+		Where("id = ? AND version = ?", list.ID, list.Version).
+		Updates(map[string]interface{}{
+			"name":                          list.Name,
+			"description":                   list.Description,
+			"auto_archive_completed":        list.AutoArchiveCompleted,
+			"keep_completed":                list.KeepCompleted,
+			"require_due_date":              list.RequireDueDate,
+			"complete_subtasks_with_parent": list.CompleteSubtasksWithParent,
+			"reopen_subtasks_with_parent":   list.ReopenSubtasksWithParent,
+			"version":                       gorm.Expr("version + 1"),
+			"updated_at":                    now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrVersionConflict
+	}
+	list.Version++
+	list.UpdatedAt = now
+
+	// Get todo count
+	var count int64
+	s.db.Model(&models.Todo{}).Where("list_id = ?", list.ID).Count(&count)
+	list.TodoCount = int(count)
+	list.Changed = listChanges(&before, &list)
+
+	return &list, nil
+}
+
+// DuplicateList copies a list and its todos into a new list named after it,
+// "Copy of X" or, if that is taken, "Copy of X (2)" and so on. The copied todos
+// get new IDs and timestamps and are open, whatever their completion state.
+// Everything is written in one transaction.
+func (s *PostgresStorage) DuplicateList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var list *models.TodoList
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var source models.TodoList
+		if err := tx.Where("id = ? AND user_id = ?", listID, userID).First(&source).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrListNotFound
+			}
+			return err
+		}
+
+		name, nameErr := availableCopyName(tx, userID, source.Name)
+		if nameErr != nil {
+			return nameErr
+		}
+		list = &models.TodoList{
+			UserID:                     userID,
+			Name:                       name,
+			Description:                source.Description,
+			AutoArchiveCompleted:       source.AutoArchiveCompleted,
+			KeepCompleted:              source.KeepCompleted,
+			RequireDueDate:             source.RequireDueDate,
+			CompleteSubtasksWithParent: source.CompleteSubtasksWithParent,
+			ReopenSubtasksWithParent:   source.ReopenSubtasksWithParent,
+			Version:                    1,
+		}
+		if err := tx.Create(list).Error; err != nil {
+			return err
+		}
+
+		// Soft-deleted todos are left out by the default scope
+		var todos []models.Todo
+		if err := tx.Where("list_id = ?", listID).Order("position, created_at, id").Find(&todos).Error; err != nil {
+			return err
+		}
+		tagsByTodo, tagsErr := loadTodoTags(tx, todoIDs(todos))
+		if tagsErr != nil {
+			return tagsErr
+		}
+		for i := range todos {
+			todos[i].Tags = tagsByTodo[todos[i].ID]
+			copied := duplicateTodo(&todos[i], list.ID)
+			if err := tx.Create(copied).Error; err != nil {
+				return err
+			}
+			if err := saveTodoTags(tx, []uuid.UUID{copied.ID}, copied.Tags); err != nil {
+				return err
+			}
+		}
+		list.TodoCount = len(todos)
+		return nil
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
+	}
 
-	if err := s.db.Save(&list).Error; err != nil {
+	return list, nil
+}
+
+// availableCopyName returns the first name copyListName offers that none of
+// the user's unarchived lists has
+func availableCopyName(tx *gorm.DB, userID uuid.UUID, name string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		candidate := copyListName(name, attempt)
+		var count int64
+		if err := tx.Model(&models.TodoList{}).
+			Where("user_id = ? AND name = ? AND archived = ?", userID, candidate, false).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// ArchiveList archives a list, hiding it from GetAllLists by default and
+// freeing its name for new lists. Archiving an archived list changes nothing.
+func (s *PostgresStorage) ArchiveList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	return s.setListArchived(userID, listID, true)
+}
+
+// UnarchiveList returns an archived list to the active lists. It fails with
+// ErrListNameExists if an active list has taken the name in the meantime.
+// Unarchiving an active list changes nothing.
+func (s *PostgresStorage) UnarchiveList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	return s.setListArchived(userID, listID, false)
+}
+
+// setListArchived moves a list into or out of the archive
+func (s *PostgresStorage) setListArchived(userID, listID uuid.UUID, archived bool) (*models.TodoList, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var list models.TodoList
+	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
 		return nil, err
 	}
 
+	if list.Archived != archived {
+		if !archived {
+			if err := s.checkListNameAvailable(userID, list.Name, listID); err != nil {
+				return nil, err
+			}
+		}
+
+		now := s.db.NowFunc()
+		var archivedAt *time.Time
+		if archived {
+			archivedAt = &now
+		}
+		result := s.db.Model(&models.TodoList{}).
+			Where("id = ? AND version = ?", list.ID, list.Version).
+			Updates(map[string]interface{}{
+				"archived":    archived,
+				"archived_at": archivedAt,
+				"version":     gorm.Expr("version + 1"),
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, ErrVersionConflict
+		}
+		list.Archived = archived
+		list.ArchivedAt = archivedAt
+		list.Version++
+		list.UpdatedAt = now
+	}
+
 	// Get todo count
 	var count int64
 	s.db.Model(&models.Todo{}).Where("list_id = ?", list.ID).Count(&count)
@@ -143,19 +432,109 @@ func (s *PostgresStorage) UpdateList(userID, listID uuid.UUID, req models.Update
 }
 
 // DeleteList deletes a todo list and all its todos for a specific user
-func (s *PostgresStorage) DeleteList(userID, listID uuid.UUID) error {
-	result := s.db.Where("id = ? AND user_id = ?", listID, userID).Delete(&models.TodoList{})
-	if result.Error != nil {
-		return result.Error
+func (s *PostgresStorage) DeleteList(userID, listID uuid.UUID, version *int) error {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return busyErr
 	}
-	if result.RowsAffected == 0 {
-		return ErrListNotFound
+	defer release()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var list models.TodoList
+		if err := tx.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrListNotFound
+			}
+			return err
+		}
+
+		// Delete the list only if it is still at the expected version
+		deleted := s.deleteScope(tx).Where("id = ?", list.ID)
+		if version != nil {
+			deleted = deleted.Where("version = ?", *version)
+		}
+		deleted = deleted.Delete(&models.TodoList{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		// Delete the list's todos along with it, in the same delete mode
+		return s.deleteScope(tx).Where("list_id = ?", listID).Delete(&models.Todo{}).Error
+	})
+}
+
+// MergeLists moves every todo from sourceListID into targetListID and then
+// deletes the source list, in a single transaction. Both lists must belong to the user.
+func (s *PostgresStorage) MergeLists(userID, targetListID, sourceListID uuid.UUID) (*models.TodoList, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
 	}
-	return nil
+	defer release()
+
+	var target models.TodoList
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", targetListID, userID).First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrListNotFound
+			}
+			return err
+		}
+		if sourceListID == targetListID {
+			return ErrMergeSameList
+		}
+
+		var source models.TodoList
+		if err := tx.Where("id = ? AND user_id = ?", sourceListID, userID).First(&source).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSourceNotFound
+			}
+			return err
+		}
+
+		// Moved todos keep their order, after the target's own todos
+		offset, err := nextTodoPosition(tx, targetListID)
+		if err != nil {
+			return err
+		}
+
+		moved := tx.Model(&models.Todo{}).
+			Where("list_id = ?", sourceListID).
+			Updates(map[string]interface{}{
+				"list_id":    targetListID,
+				"position":   gorm.Expr("position + ?", offset),
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": s.db.NowFunc(),
+			})
+		if moved.Error != nil {
+			return moved.Error
+		}
+
+		return s.deleteScope(tx).Delete(&source).Error
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
+	}
+
+	// Get todo count
+	var count int64
+	s.db.Model(&models.Todo{}).Where("list_id = ?", target.ID).Count(&count)
+	target.TodoCount = int(count)
+
+	return &target, nil
 }
 
 // CreateTodo creates a new todo in a list owned by a specific user
 func (s *PostgresStorage) CreateTodo(userID, listID uuid.UUID, req models.CreateTodoRequest) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
 	// Check if list exists and belongs to user
 	var list models.TodoList
 	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
@@ -165,58 +544,201 @@ func (s *PostgresStorage) CreateTodo(userID, listID uuid.UUID, req models.Create
 		return nil, err
 	}
 
-	todo := &models.Todo{
-		ListID:      listID,
-		Description: req.Description,
-		Priority:    req.Priority,
-		DueDate:     req.DueDate,
-		Completed:   false,
+	if err := checkDueDatePolicy(&list, req); err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Create(todo).Error; err != nil {
-		return nil, err
+	todo := newTodo(&list, req, s.db.NowFunc())
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		position, err := nextTodoPosition(tx, list.ID)
+		if err != nil {
+			return err
+		}
+		todo.Position = position
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+		return saveTodoTags(tx, []uuid.UUID{todo.ID}, todo.Tags)
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
 	}
 
 	return todo, nil
 }
 
-// GetTodosByList retrieves all todos in a list owned by a specific user with filtering and sorting
-func (s *PostgresStorage) GetTodosByList(
-	userID, listID uuid.UUID,
-	priority *models.Priority,
-	completed *bool,
-	sortBy, sortOrder string,
-) ([]models.Todo, error) {
+// BatchCreateTodos creates several todos in a list owned by a specific user
+// in a single transaction; they are returned in request order
+func (s *PostgresStorage) BatchCreateTodos(userID, listID uuid.UUID, reqs []models.CreateTodoRequest) ([]models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	created := make([]models.Todo, 0, len(reqs))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var list models.TodoList
+		if err := tx.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrListNotFound
+			}
+			return err
+		}
+
+		for _, req := range reqs {
+			if err := checkDueDatePolicy(&list, req); err != nil {
+				return err
+			}
+		}
+
+		position, err := nextTodoPosition(tx, list.ID)
+		if err != nil {
+			return err
+		}
+
+		now := s.db.NowFunc()
+		for i, req := range reqs {
+			todo := newTodo(&list, req, now)
+			todo.Position = position + i
+			if err := tx.Create(todo).Error; err != nil {
+				return err
+			}
+			if err := saveTodoTags(tx, []uuid.UUID{todo.ID}, todo.Tags); err != nil {
+				return err
+			}
+			created = append(created, *todo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
+	}
+
+	return created, nil
+}
+
+// GetTodosByList retrieves a page of todos in a list owned by a specific user
+// with filtering and sorting applied before pagination
+func (s *PostgresStorage) GetTodosByList(userID, listID uuid.UUID, opts ListTodosOptions) ([]models.Todo, *models.Pagination, error) {
 	// Check if list exists and belongs to user
 	var list models.TodoList
 	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrListNotFound
+			return nil, nil, ErrListNotFound
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Build query
-	query := s.db.Where("list_id = ?", listID)
+	query := s.db.Model(&models.Todo{}).Where("list_id = ?", listID)
 
 	// Apply filters
-	if priority != nil {
-		query = query.Where("priority = ?", *priority)
+	if opts.Priority != nil {
+		query = query.Where("priority = ?", *opts.Priority)
+	}
+	if opts.Completed != nil {
+		query = query.Where("completed = ?", *opts.Completed)
+	}
+	for _, tag := range normalizeTags(opts.Tags) {
+		query = query.Where("EXISTS (SELECT 1 FROM todo_tags WHERE todo_tags.todo_id = todos.id AND todo_tags.tag = ?)", tag)
+	}
+	if opts.Archived {
+		query = query.Where("archived_at IS NOT NULL")
+	} else {
+		query = query.Where("archived_at IS NULL")
+	}
+	if opts.Overdue {
+		query = query.Where("completed = ? AND due_date IS NOT NULL AND due_date < ?", false, time.Now().UTC())
+	}
+	if opts.DueAfter != nil {
+		query = query.Where("due_date >= ?", *opts.DueAfter)
+	}
+	if opts.DueBefore != nil {
+		query = query.Where("due_date <= ?", *opts.DueBefore)
+	}
+	if opts.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *opts.CreatedAfter)
 	}
-	if completed != nil {
-		query = query.Where("completed = ?", *completed)
+	if opts.CreatedBefore != nil {
+		query = query.Where("created_at <= ?", *opts.CreatedBefore)
 	}
 
 	// Apply sorting
-	orderClause := buildOrderClause(sortBy, sortOrder)
-	query = query.Order(orderClause)
+	orderClause, orderErr := buildOrderClause(opts.SortBy, opts.SortOrder)
+	if orderErr != nil {
+		return nil, nil, orderErr
+	}
+
+	// Share the filtered query between the count and the page fetch
+	query = query.Session(&gorm.Session{})
+
+	var totalItems int64
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(opts.Page, opts.Limit, int(totalItems))
+
+	todos := make([]models.Todo, 0)
+	if err := query.Order(orderClause).
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Find(&todos).Error; err != nil {
+		return nil, nil, err
+	}
+
+	tagsByTodo, err := loadTodoTags(s.db, todoIDs(todos))
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+	}
+
+	return todos, pagination, nil
+}
+
+// GetTodosForLists returns up to perList active todos of each given list owned
+// by a specific user, keyed by list ID and in position order. Lists that are
+// not found are left out. All lists are read with one ranked query plus one
+// for their tags, however many lists are asked for.
+func (s *PostgresStorage) GetTodosForLists(
+	userID uuid.UUID, listIDs []uuid.UUID, perList int,
+) (map[uuid.UUID][]models.Todo, error) {
+	byList := make(map[uuid.UUID][]models.Todo, len(listIDs))
+	if len(listIDs) == 0 || perList <= 0 {
+		return byList, nil
+	}
+
+	ranked := s.db.Model(&models.Todo{}).
+		Select("todos.*, ROW_NUMBER() OVER "+
+			"(PARTITION BY todos.list_id ORDER BY todos.pinned DESC, todos.position, todos.created_at, todos.id) AS embed_rank").
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.user_id = ? AND todos.list_id IN ? AND todos.archived_at IS NULL", userID, listIDs)
 
 	var todos []models.Todo
-	if err := query.Find(&todos).Error; err != nil {
+	if err := s.db.Unscoped().Table("(?) AS ranked", ranked).
+		Where("embed_rank <= ?", perList).
+		Order("list_id, embed_rank").
+		Find(&todos).Error; err != nil {
 		return nil, err
 	}
 
-	return todos, nil
+	ids := make([]uuid.UUID, len(todos))
+	for i := range todos {
+		ids[i] = todos[i].ID
+	}
+	tagsByTodo, err := loadTodoTags(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range todos {
+		todo.Tags = tagsByTodo[todo.ID]
+		byList[todo.ListID] = append(byList[todo.ListID], todo)
+	}
+	return byList, nil
 }
 
 // GetTodoByID retrieves a specific todo from a list owned by a specific user
@@ -238,11 +760,23 @@ func (s *PostgresStorage) GetTodoByID(userID, listID, todoID uuid.UUID) (*models
 		return nil, err
 	}
 
+	tagsByTodo, err := loadTodoTags(s.db, []uuid.UUID{todo.ID})
+	if err != nil {
+		return nil, err
+	}
+	todo.Tags = tagsByTodo[todo.ID]
+
 	return &todo, nil
 }
 
 // UpdateTodo updates an existing todo in a list owned by a specific user
 func (s *PostgresStorage) UpdateTodo(userID, listID, todoID uuid.UUID, req models.UpdateTodoRequest) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
 	// Check if list exists and belongs to user
 	var list models.TodoList
 	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
@@ -259,39 +793,86 @@ func (s *PostgresStorage) UpdateTodo(userID, listID, todoID uuid.UUID, req model
 		}
 		return nil, err
 	}
-
-	// Update fields
-	if req.Description != nil {
-		todo.Description = *req.Description
+	if req.Version != nil && *req.Version != todo.Version {
+		return nil, ErrVersionConflict
 	}
-	if req.Priority != nil {
-		todo.Priority = *req.Priority
-	}
-	if req.DueDate != nil {
-		todo.DueDate = req.DueDate
+
+	tagsByTodo, err := loadTodoTags(s.db, []uuid.UUID{todo.ID})
+	if err != nil {
+		return nil, err
 	}
-	if req.Completed != nil {
-		wasCompleted := todo.Completed
-		todo.Completed = *req.Completed
+	todo.Tags = tagsByTodo[todo.ID]
+
+	before := todo
+	now := s.db.NowFunc()
+	applyTodoUpdate(&todo, &list, req, now)
+	next := nextOccurrence(&before, &todo, now)
 
-		// Update CompletedAt timestamp
-		if *req.Completed && !wasCompleted {
-			now := s.db.NowFunc()
-			todo.CompletedAt = &now
-		} else if !*req.Completed && wasCompleted {
-			todo.CompletedAt = nil
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Only write if nobody else has updated the todo since it was read
+		result := tx.Model(&models.Todo{}).
+			Where("id = ? AND version = ?", todo.ID, todo.Version).
+			Updates(map[string]interface{}{
+				"description":      todo.Description,
+				"priority":         todo.Priority,
+				"due_date":         todo.DueDate,
+				"estimate_minutes": todo.EstimateMinutes,
+				"completed":        todo.Completed,
+				"completed_at":     todo.CompletedAt,
+				"archived_at":      todo.ArchivedAt,
+				"recurrence_rule":  todo.RecurrenceRule,
+				"version":          gorm.Expr("version + 1"),
+				"updated_at":       now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if req.Tags != nil {
+			if tagsErr := saveTodoTags(tx, []uuid.UUID{todo.ID}, todo.Tags); tagsErr != nil {
+				return tagsErr
+			}
+		}
+		if completed, ok := cascadesToSubtasks(&list, &before, &todo); ok {
+			if subtasksErr := setSubtasksCompleted(tx, &todo, completed, now); subtasksErr != nil {
+				return subtasksErr
+			}
+		}
+		if next == nil {
+			return nil
 		}
-	}
 
-	if err := s.db.Save(&todo).Error; err != nil {
-		return nil, err
+		position, positionErr := nextTodoPosition(tx, list.ID)
+		if positionErr != nil {
+			return positionErr
+		}
+		next.Position = position
+		if createErr := tx.Create(next).Error; createErr != nil {
+			return createErr
+		}
+		return saveTodoTags(tx, []uuid.UUID{next.ID}, next.Tags)
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
 	}
+	todo.Version++
+	todo.UpdatedAt = now
+	todo.Changed = todoChanges(&before, &todo)
+	todo.NextOccurrence = next
 
 	return &todo, nil
 }
 
 // DeleteTodo deletes a todo from a list owned by a specific user
-func (s *PostgresStorage) DeleteTodo(userID, listID, todoID uuid.UUID) error {
+func (s *PostgresStorage) DeleteTodo(userID, listID, todoID uuid.UUID, version *int) error {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return busyErr
+	}
+	defer release()
+
 	// Check if list exists and belongs to user
 	var list models.TodoList
 	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
@@ -301,37 +882,1323 @@ func (s *PostgresStorage) DeleteTodo(userID, listID, todoID uuid.UUID) error {
 		return err
 	}
 
-	result := s.db.Where("id = ? AND list_id = ?", todoID, listID).Delete(&models.Todo{})
+	var todo models.Todo
+	if err := s.db.Where("id = ? AND list_id = ?", todoID, listID).First(&todo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	// Only delete if the todo is still at the expected version
+	query := s.deleteScope(s.db).Where("id = ? AND deleted_at IS NULL", todo.ID)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+	result := query.Delete(&models.Todo{})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return ErrTodoNotFound
+		return ErrVersionConflict
 	}
 	return nil
 }
 
-// buildOrderClause creates the ORDER BY clause for sorting
-func buildOrderClause(sortBy, sortOrder string) string {
-	// Map sort fields to actual column names
-	var column string
-	switch sortBy {
-	case "dueDate":
-		column = "due_date"
-	case "priority":
-		// PostgreSQL sorting with CASE for priority ordering
-		if sortOrder == "desc" {
-			return "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END DESC"
+// ReorderTodo moves a todo to position within its list, shifting the todos in
+// between, and renumbers the list from 0. Positions past the end move it last.
+// The list row is locked for the transaction, so concurrent reorders run one
+// after another and each renumbers from a consistent state.
+func (s *PostgresStorage) ReorderTodo(userID, listID, todoID uuid.UUID, position int) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var moved models.Todo
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var list models.TodoList
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrListNotFound
+			}
+			return err
 		}
-		return "CASE priority WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 END ASC"
-	case "createdAt", "":
-		column = "created_at"
-	default:
-		column = "created_at"
+
+		var todos []models.Todo
+		if err := tx.Where("list_id = ?", listID).Find(&todos).Error; err != nil {
+			return err
+		}
+
+		previous := make(map[uuid.UUID]int, len(todos))
+		ordered := make([]*models.Todo, 0, len(todos))
+		for i := range todos {
+			previous[todos[i].ID] = todos[i].Position
+			ordered = append(ordered, &todos[i])
+		}
+		if _, exists := previous[todoID]; !exists {
+			return ErrTodoNotFound
+		}
+		now := time.Now()
+		reorderTodos(ordered, todoID, position, now)
+
+		// Only write the todos whose position changed
+		for _, todo := range ordered {
+			if todo.ID == todoID {
+				moved = *todo
+			}
+			if todo.Position == previous[todo.ID] {
+				continue
+			}
+			if err := tx.Model(&models.Todo{}).Where("id = ?", todo.ID).Updates(map[string]interface{}{
+				"position":   todo.Position,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tagsByTodo, err := loadTodoTags(s.db, []uuid.UUID{moved.ID})
+	if err != nil {
+		return nil, err
+	}
+	moved.Tags = tagsByTodo[moved.ID]
+	return &moved, nil
+}
+
+// BatchUpdateTodos applies the same update to every listed todo found in a
+// list owned by a specific user with a single UPDATE; IDs not in the list are
+// reported, not fatal. Completing todos repeats and cascades to subtasks as in
+// UpdateTodo.
+func (s *PostgresStorage) BatchUpdateTodos(
+	userID, listID uuid.UUID, todoIDs []uuid.UUID, req models.UpdateTodoRequest,
+) (*models.BatchResult, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
 	}
+	defer release()
 
-	if sortOrder == "desc" {
-		return column + " DESC"
+	var result *models.BatchResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		list, found, err := s.findBatchTodos(tx, userID, listID, todoIDs)
+		if err != nil {
+			return err
+		}
+		result = &models.BatchResult{NotFound: notFoundIDs(todoIDs, found)}
+		if len(found) == 0 {
+			return nil
+		}
+
+		// Todos whose completion changes may repeat or cascade to their
+		// subtasks, so read them as they were before the update
+		var previous []models.Todo
+		if req.Completed != nil {
+			if findErr := tx.Where("id IN ? AND completed = ?", found, !*req.Completed).Find(&previous).Error; findErr != nil {
+				return findErr
+			}
+			if tagsErr := loadBatchTodoTags(tx, previous, todoIDs); tagsErr != nil {
+				return tagsErr
+			}
+		}
+
+		now := s.db.NowFunc()
+		updates := map[string]interface{}{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}
+		if req.Description != nil {
+			updates["description"] = *req.Description
+		}
+		if req.Priority != nil {
+			updates["priority"] = *req.Priority
+		}
+		if req.DueDate != nil {
+			updates["due_date"] = *req.DueDate
+		}
+		if req.EstimateMinutes != nil {
+			updates["estimate_minutes"] = *req.EstimateMinutes
+		}
+		if req.RecurrenceRule != nil {
+			updates["recurrence_rule"] = *req.RecurrenceRule
+		}
+		// SET expressions see the old row, so the CASEs only touch todos whose
+		// completion actually changes, matching UpdateTodo
+		if req.Completed != nil && *req.Completed {
+			updates["completed"] = true
+			updates["completed_at"] = gorm.Expr("CASE WHEN completed THEN completed_at ELSE ? END", now)
+			if list.AutoArchiveCompleted {
+				updates["archived_at"] = gorm.Expr("CASE WHEN completed THEN archived_at ELSE ? END", now)
+			}
+		} else if req.Completed != nil {
+			updates["completed"] = false
+			updates["completed_at"] = nil
+			updates["archived_at"] = gorm.Expr("CASE WHEN completed THEN NULL ELSE archived_at END")
+		}
+
+		updated := tx.Model(&models.Todo{}).
+			Where("id IN ? AND list_id = ?", found, listID).
+			Updates(updates)
+		if updated.Error != nil {
+			return updated.Error
+		}
+		result.Affected = int(updated.RowsAffected)
+		if req.Tags != nil {
+			if tagsErr := saveTodoTags(tx, found, normalizeTags(*req.Tags)); tagsErr != nil {
+				return tagsErr
+			}
+		}
+		return s.completeBatchTodos(tx, list, previous, req, now, result)
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
+	}
+
+	return result, nil
+}
+
+// loadBatchTodoTags fills in the tags of todos and puts them in the order of
+// requested, the IDs a batch request listed
+func loadBatchTodoTags(tx *gorm.DB, todos []models.Todo, requested []uuid.UUID) error {
+	tagsByTodo, err := loadTodoTags(tx, todoIDs(todos))
+	if err != nil {
+		return err
+	}
+	order := make(map[uuid.UUID]int, len(requested))
+	for i, id := range uniqueIDs(requested) {
+		order[id] = i
+	}
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+	}
+	sort.Slice(todos, func(i, j int) bool { return order[todos[i].ID] < order[todos[j].ID] })
+	return nil
+}
+
+// completeBatchTodos carries a batch completion change over to each todo's
+// subtasks and creates the next occurrence of each recurring todo completed,
+// as UpdateTodo does for a single todo. previous holds the todos whose
+// completion the batch changed, as they were before it.
+func (s *PostgresStorage) completeBatchTodos(
+	tx *gorm.DB, list *models.TodoList, previous []models.Todo, req models.UpdateTodoRequest, now time.Time, result *models.BatchResult,
+) error {
+	for i := range previous {
+		before := previous[i]
+		after := before
+		applyTodoUpdate(&after, list, req, now)
+		if completed, ok := cascadesToSubtasks(list, &before, &after); ok {
+			if err := setSubtasksCompleted(tx, &after, completed, now); err != nil {
+				return err
+			}
+		}
+
+		next := nextOccurrence(&before, &after, now)
+		if next == nil {
+			continue
+		}
+		position, err := nextTodoPosition(tx, list.ID)
+		if err != nil {
+			return err
+		}
+		next.Position = position
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		if err := saveTodoTags(tx, []uuid.UUID{next.ID}, next.Tags); err != nil {
+			return err
+		}
+		result.NextOccurrences = append(result.NextOccurrences, *next)
+	}
+	return nil
+}
+
+// BatchDeleteTodos deletes every listed todo found in a list owned by a
+// specific user with a single DELETE; IDs not in the list are reported, not fatal
+func (s *PostgresStorage) BatchDeleteTodos(userID, listID uuid.UUID, todoIDs []uuid.UUID) (*models.BatchResult, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var result *models.BatchResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		_, found, err := s.findBatchTodos(tx, userID, listID, todoIDs)
+		if err != nil {
+			return err
+		}
+		result = &models.BatchResult{NotFound: notFoundIDs(todoIDs, found)}
+		if len(found) == 0 {
+			return nil
+		}
+
+		deleted := s.deleteScope(tx).
+			Where("id IN ? AND list_id = ?", found, listID).
+			Delete(&models.Todo{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Affected = int(deleted.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// findBatchTodos loads the user's list and returns which of todoIDs belong to it
+func (s *PostgresStorage) findBatchTodos(
+	tx *gorm.DB, userID, listID uuid.UUID, todoIDs []uuid.UUID,
+) (*models.TodoList, []uuid.UUID, error) {
+	var list models.TodoList
+	if err := tx.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrListNotFound
+		}
+		return nil, nil, err
+	}
+
+	found := make([]uuid.UUID, 0, len(todoIDs))
+	if err := tx.Model(&models.Todo{}).
+		Where("id IN ? AND list_id = ?", uniqueIDs(todoIDs), listID).
+		Pluck("id", &found).Error; err != nil {
+		return nil, nil, err
+	}
+	return &list, found, nil
+}
+
+// loadTodoTags fetches the tags of the given todos in one query, keyed by todo ID
+func loadTodoTags(db *gorm.DB, ids []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tagsByTodo := make(map[uuid.UUID][]string, len(ids))
+	if len(ids) == 0 {
+		return tagsByTodo, nil
+	}
+
+	var rows []models.TodoTag
+	if err := db.Where("todo_id IN ?", ids).Order("tag").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tagsByTodo[row.TodoID] = append(tagsByTodo[row.TodoID], row.Tag)
+	}
+	return tagsByTodo, nil
+}
+
+// saveTodoTags replaces the tags of each given todo with tags, which must
+// already be normalized
+func saveTodoTags(tx *gorm.DB, ids []uuid.UUID, tags []string) error {
+	if err := tx.Where("todo_id IN ?", ids).Delete(&models.TodoTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]models.TodoTag, 0, len(ids)*len(tags))
+	for _, id := range ids {
+		for _, tag := range tags {
+			rows = append(rows, models.TodoTag{TodoID: id, Tag: tag})
+		}
+	}
+	return tx.Omit("Todo").Create(&rows).Error
+}
+
+// todoIDs returns the IDs of todos in order
+func todoIDs(todos []models.Todo) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(todos))
+	for i := range todos {
+		ids = append(ids, todos[i].ID)
+	}
+	return ids
+}
+
+// notFoundIDs returns the unique IDs in requested that are missing from found
+func notFoundIDs(requested, found []uuid.UUID) []uuid.UUID {
+	present := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		present[id] = true
+	}
+	missing := make([]uuid.UUID, 0)
+	for _, id := range uniqueIDs(requested) {
+		if !present[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// GetDeletedTodos retrieves the soft-deleted todos in a list owned by a
+// specific user, most recently deleted first
+func (s *PostgresStorage) GetDeletedTodos(userID, listID uuid.UUID) ([]models.DeletedTodo, error) {
+	var list models.TodoList
+	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+
+	var todos []models.Todo
+	if err := s.db.Unscoped().
+		Where("list_id = ? AND deleted_at IS NOT NULL", listID).
+		Order("deleted_at DESC, id").
+		Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(todos))
+	for i := range todos {
+		ids[i] = todos[i].ID
+	}
+	tagsByTodo, err := loadTodoTags(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]models.DeletedTodo, 0, len(todos))
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+		deleted = append(deleted, models.DeletedTodo{Todo: todos[i], DeletedAt: todos[i].DeletedAt.Time})
+	}
+	return deleted, nil
+}
+
+// RestoreTodo moves a soft-deleted todo out of the trash to the end of its list
+func (s *PostgresStorage) RestoreTodo(userID, listID, todoID uuid.UUID) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var list models.TodoList
+	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+
+	lastPosition := s.db.Model(&models.Todo{}).Select("COALESCE(MAX(position) + 1, 0)").Where("list_id = ?", listID)
+	result := s.db.Unscoped().Model(&models.Todo{}).
+		Where("id = ? AND list_id = ? AND deleted_at IS NOT NULL", todoID, listID).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"position":   gorm.Expr("(?)", lastPosition),
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": s.db.NowFunc(),
+		})
+	if result.Error != nil {
+		return nil, duplicateTodoError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTodoNotFound
+	}
+
+	return s.GetTodoByID(userID, listID, todoID)
+}
+
+// MoveTodo moves a todo to the end of targetListID with a single UPDATE whose
+// conditions check that both lists belong to the user. Moving a todo to its
+// own list changes nothing.
+func (s *PostgresStorage) MoveTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error) {
+	if targetListID == listID {
+		return s.GetTodoByID(userID, listID, todoID)
+	}
+
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	ownedList := func(id uuid.UUID) *gorm.DB {
+		return s.db.Model(&models.TodoList{}).Select("1").Where("id = ? AND user_id = ?", id, userID)
+	}
+	lastPosition := s.db.Model(&models.Todo{}).Select("COALESCE(MAX(position) + 1, 0)").Where("list_id = ?", targetListID)
+
+	result := s.db.Model(&models.Todo{}).
+		Where("id = ? AND list_id = ?", todoID, listID).
+		Where("EXISTS (?) AND EXISTS (?)", ownedList(listID), ownedList(targetListID)).
+		Updates(map[string]interface{}{
+			"list_id":    targetListID,
+			"position":   gorm.Expr("(?)", lastPosition),
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": s.db.NowFunc(),
+		})
+	if result.Error != nil {
+		return nil, duplicateTodoError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Report the source list or todo if either is missing, else the target
+		if _, err := s.GetTodoByID(userID, listID, todoID); err != nil {
+			return nil, err
+		}
+		return nil, ErrListNotFound
+	}
+
+	return s.GetTodoByID(userID, targetListID, todoID)
+}
+
+// SetTodoPinned pins a todo to the top of its list's listings or unpins it.
+// Setting the flag to its current value changes nothing.
+func (s *PostgresStorage) SetTodoPinned(userID, listID, todoID uuid.UUID, pinned bool) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	ownedList := s.db.Model(&models.TodoList{}).Select("1").Where("id = ? AND user_id = ?", listID, userID)
+	result := s.db.Model(&models.Todo{}).
+		Where("id = ? AND list_id = ? AND pinned <> ?", todoID, listID, pinned).
+		Where("EXISTS (?)", ownedList).
+		Updates(map[string]interface{}{
+			"pinned":     pinned,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": s.db.NowFunc(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Also reports a missing list or todo when nothing was updated
+	return s.GetTodoByID(userID, listID, todoID)
+}
+
+// CloneTodo copies a todo as a new, incomplete todo in targetListID, or in
+// its own list when targetListID is uuid.Nil. Both lists must belong to the user.
+func (s *PostgresStorage) CloneTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	// Check if list exists and belongs to user
+	var list models.TodoList
+	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+
+	var source models.Todo
+	if err := s.db.Where("id = ? AND list_id = ?", todoID, listID).First(&source).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	if targetListID == uuid.Nil {
+		targetListID = listID
+	} else if targetListID != listID {
+		var target models.TodoList
+		if err := s.db.Where("id = ? AND user_id = ?", targetListID, userID).First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTargetNotFound
+			}
+			return nil, err
+		}
+	}
+
+	clone := &models.Todo{
+		ListID:          targetListID,
+		Description:     cloneDescription(source.Description),
+		Priority:        source.Priority,
+		DueDate:         source.DueDate,
+		EstimateMinutes: source.EstimateMinutes,
+		RecurrenceRule:  source.RecurrenceRule,
+		Completed:       false,
+		Version:         1,
+	}
+
+	tagsByTodo, err := loadTodoTags(s.db, []uuid.UUID{source.ID})
+	if err != nil {
+		return nil, err
+	}
+	clone.Tags = tagsByTodo[source.ID]
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		position, positionErr := nextTodoPosition(tx, targetListID)
+		if positionErr != nil {
+			return positionErr
+		}
+		clone.Position = position
+		if createErr := tx.Create(clone).Error; createErr != nil {
+			return createErr
+		}
+		return saveTodoTags(tx, []uuid.UUID{clone.ID}, clone.Tags)
+	})
+	if err != nil {
+		return nil, duplicateTodoError(err)
+	}
+
+	return clone, nil
+}
+
+// GetSubtasks retrieves the subtasks of a todo in a list owned by a specific
+// user, in position order
+func (s *PostgresStorage) GetSubtasks(userID, listID, todoID uuid.UUID) ([]models.Subtask, error) {
+	if _, _, err := findTodo(s.db, userID, listID, todoID); err != nil {
+		return nil, err
+	}
+
+	subtasks := make([]models.Subtask, 0)
+	if err := s.db.Where("todo_id = ?", todoID).Order("position, created_at, id").Find(&subtasks).Error; err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+// CreateSubtask adds a subtask after the existing subtasks of a todo in a list
+// owned by a specific user
+func (s *PostgresStorage) CreateSubtask(
+	userID, listID, todoID uuid.UUID, req models.CreateSubtaskRequest,
+) (*models.Subtask, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	subtask := &models.Subtask{
+		TodoID:      todoID,
+		Description: req.Description,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the todo so concurrent subtask writes take turns on its
+		// positions and counts
+		todo, _, err := findTodo(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, listID, todoID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Subtask{}).Where("todo_id = ?", todoID).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&subtask.Position).Error; err != nil {
+			return err
+		}
+		setSubtaskCompleted(subtask, req.Completed, tx.NowFunc())
+		if err := tx.Create(subtask).Error; err != nil {
+			return err
+		}
+		return refreshSubtaskCounts(tx, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subtask, nil
+}
+
+// UpdateSubtask updates a subtask of a todo in a list owned by a specific user.
+// With req.CompleteParent, completing the last open subtask completes the todo.
+func (s *PostgresStorage) UpdateSubtask(
+	userID, listID, todoID, subtaskID uuid.UUID, req models.UpdateSubtaskRequest,
+) (*models.Subtask, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var subtask models.Subtask
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		todo, list, err := findTodo(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, listID, todoID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND todo_id = ?", subtaskID, todoID).First(&subtask).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSubtaskNotFound
+			}
+			return err
+		}
+
+		now := tx.NowFunc()
+		if req.Description != nil {
+			subtask.Description = *req.Description
+		}
+		if req.Completed != nil {
+			setSubtaskCompleted(&subtask, *req.Completed, now)
+		}
+		subtask.UpdatedAt = now
+		if err := tx.Model(&models.Subtask{}).Where("id = ?", subtask.ID).Updates(map[string]interface{}{
+			"description":  subtask.Description,
+			"completed":    subtask.Completed,
+			"completed_at": subtask.CompletedAt,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := refreshSubtaskCounts(tx, todo); err != nil {
+			return err
+		}
+
+		if !completesParent(todo, req) {
+			return nil
+		}
+		completed := true
+		applyTodoUpdate(todo, list, models.UpdateTodoRequest{Completed: &completed}, now)
+		return tx.Model(&models.Todo{}).Where("id = ?", todo.ID).Updates(map[string]interface{}{
+			"completed":    todo.Completed,
+			"completed_at": todo.CompletedAt,
+			"archived_at":  todo.ArchivedAt,
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// DeleteSubtask deletes a subtask of a todo in a list owned by a specific user
+func (s *PostgresStorage) DeleteSubtask(userID, listID, todoID, subtaskID uuid.UUID) error {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return busyErr
+	}
+	defer release()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		todo, _, err := findTodo(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, listID, todoID)
+		if err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND todo_id = ?", subtaskID, todoID).Delete(&models.Subtask{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubtaskNotFound
+		}
+		return refreshSubtaskCounts(tx, todo)
+	})
+}
+
+// PurgeOldCompleted permanently deletes todos, across all users, that were
+// completed before the given time, except in lists that keep completed todos
+func (s *PostgresStorage) PurgeOldCompleted(before time.Time) (int64, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return 0, busyErr
+	}
+	defer release()
+
+	purgeable := s.db.Unscoped().Model(&models.TodoList{}).Select("id").Where("keep_completed = ?", false)
+	result := s.db.Unscoped().
+		Where("completed = ? AND completed_at < ? AND list_id IN (?)", true, before, purgeable).
+		Delete(&models.Todo{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// PurgeDeletedTodos permanently deletes todos, across all users, that were
+// deleted more than olderThan ago. Their tags and subtasks go with them.
+func (s *PostgresStorage) PurgeDeletedTodos(olderThan time.Duration) (int64, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return 0, busyErr
+	}
+	defer release()
+
+	result := s.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", s.db.NowFunc().Add(-olderThan)).
+		Delete(&models.Todo{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// QueryUserTodos retrieves todos across all lists owned by a specific user
+// with filtering, sorting and pagination, annotating each with its list name
+func (s *PostgresStorage) QueryUserTodos(userID uuid.UUID, opts TodoQueryOptions) ([]models.TodoWithList, *models.Pagination, error) {
+	orderClause, orderErr := buildOrderClause(opts.SortBy, opts.SortOrder)
+	if orderErr != nil {
+		return nil, nil, orderErr
+	}
+
+	// Share the filtered query between the count and the page fetch
+	query := filterUserTodos(s.db, userID, opts).Session(&gorm.Session{})
+
+	var totalItems int64
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(opts.Page, opts.Limit, int(totalItems))
+
+	todos := make([]models.TodoWithList, 0)
+	if err := query.Select("todos.*, todo_lists.name AS list_name").
+		Order(orderClause).
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Scan(&todos).Error; err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(todos))
+	for i := range todos {
+		ids = append(ids, todos[i].ID)
+	}
+	tagsByTodo, err := loadTodoTags(s.db, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+	}
+
+	return todos, pagination, nil
+}
+
+// ReprioritizeTodos sets priority on every todo across a user's lists that
+// matches opts, ignoring its sorting and pagination, and returns how many
+// todos it changed. Todos that already have the priority are left alone.
+func (s *PostgresStorage) ReprioritizeTodos(userID uuid.UUID, opts TodoQueryOptions, priority models.Priority) (int, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return 0, busyErr
+	}
+	defer release()
+
+	var changed int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := filterUserTodos(tx, userID, opts).
+			Where("todos.priority <> ?", priority).
+			Pluck("todos.id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&models.Todo{}).
+			Where("id IN ? AND priority <> ?", ids, priority).
+			Updates(map[string]interface{}{
+				"priority":   priority,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": tx.NowFunc(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		changed = int(result.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// filterUserTodos scopes a todo query to the active lists of a user and the
+// filters in opts. Todo columns are qualified, as todo_lists is joined in.
+func filterUserTodos(db *gorm.DB, userID uuid.UUID, opts TodoQueryOptions) *gorm.DB {
+	query := db.Model(&models.Todo{}).
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.user_id = ?", userID)
+
+	if opts.Priority != nil {
+		query = query.Where("todos.priority = ?", *opts.Priority)
+	}
+	if opts.Completed != nil {
+		query = query.Where("todos.completed = ?", *opts.Completed)
+	}
+	if opts.Archived {
+		query = query.Where("todos.archived_at IS NOT NULL")
+	} else {
+		query = query.Where("todos.archived_at IS NULL")
+	}
+	if opts.Overdue {
+		query = query.Where("todos.completed = ? AND todos.due_date IS NOT NULL AND todos.due_date < ?", false, time.Now().UTC())
+	}
+	if opts.DueAfter != nil {
+		query = query.Where("todos.due_date >= ?", *opts.DueAfter)
+	}
+	if opts.DueBefore != nil {
+		query = query.Where("todos.due_date <= ?", *opts.DueBefore)
+	}
+	if opts.Search != "" {
+		query = query.Where(`LOWER(todos.description) LIKE ? ESCAPE '\'`, containsPattern(opts.Search))
+	}
+	if opts.CreatedAfter != nil {
+		query = query.Where("todos.created_at >= ?", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		query = query.Where("todos.created_at <= ?", *opts.CreatedBefore)
+	}
+	return query
+}
+
+// SearchTodos returns a page of a user's active todos matching a search query,
+// each paired with the name of its list. List names come from the same join
+// used for ownership, so no per-todo lookups are needed.
+func (s *PostgresStorage) SearchTodos(userID uuid.UUID, opts SearchOptions) ([]models.TodoWithList, *models.Pagination, error) {
+	orderClause, orderErr := buildOrderClause(sortFieldCreatedAt, sortOrderDesc)
+	if orderErr != nil {
+		return nil, nil, orderErr
+	}
+
+	pattern := containsPattern(opts.Query)
+	query := s.db.Model(&models.Todo{}).
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.user_id = ?", userID).
+		Where("todos.archived_at IS NULL")
+
+	if opts.IncludeListNames {
+		query = query.Where(`(LOWER(todos.description) LIKE ? ESCAPE '\' OR LOWER(todo_lists.name) LIKE ? ESCAPE '\')`, pattern, pattern)
+	} else {
+		query = query.Where(`LOWER(todos.description) LIKE ? ESCAPE '\'`, pattern)
+	}
+	if opts.Priority != nil {
+		query = query.Where("todos.priority = ?", *opts.Priority)
+	}
+	if opts.Completed != nil {
+		query = query.Where("todos.completed = ?", *opts.Completed)
+	}
+
+	// Share the filtered query between the count and the page fetch
+	query = query.Session(&gorm.Session{})
+
+	var totalItems int64
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(opts.Page, opts.Limit, int(totalItems))
+
+	todos := make([]models.TodoWithList, 0)
+	if err := query.Select("todos.*, todo_lists.name AS list_name").
+		Order(orderClause).
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Scan(&todos).Error; err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(todos))
+	for i := range todos {
+		ids = append(ids, todos[i].ID)
+	}
+	tagsByTodo, err := loadTodoTags(s.db, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range todos {
+		todos[i].Tags = tagsByTodo[todos[i].ID]
+	}
+
+	return todos, pagination, nil
+}
+
+// checkListNameAvailable returns ErrListNameExists if another unarchived list
+// owned by the user already has the given name. excludeID is skipped so a list
+// can keep its own name on update; pass uuid.Nil when creating.
+func (s *PostgresStorage) checkListNameAvailable(userID uuid.UUID, name string, excludeID uuid.UUID) error {
+	var count int64
+	err := s.db.Model(&models.TodoList{}).
+		Where("user_id = ? AND name = ? AND id != ? AND archived = ?", userID, name, excludeID, false).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrListNameExists
+	}
+	return nil
+}
+
+// GetListStats computes aggregate statistics for a list owned by a specific user
+func (s *PostgresStorage) GetListStats(userID, listID uuid.UUID) (*models.ListStats, error) {
+	// Check if list exists and belongs to user
+	var list models.TodoList
+	if err := s.db.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+
+	// Aggregate in the database rather than loading every todo: one row per
+	// priority, which the default scope keeps to todos that are not deleted
+	var groups []struct {
+		Priority          models.Priority
+		Total             int
+		Completed         int
+		Overdue           int
+		EstimateMinutes   int
+		RemainingEstimate int
+	}
+	err := s.db.Model(&models.Todo{}).
+		Select(`priority,
+			COUNT(*) AS total,
+			COUNT(CASE WHEN completed = ? THEN 1 END) AS completed,
+			COUNT(CASE WHEN completed = ? AND due_date IS NOT NULL AND due_date < ? THEN 1 END) AS overdue,
+			COALESCE(SUM(estimate_minutes), 0) AS estimate_minutes,
+			COALESCE(SUM(CASE WHEN completed = ? THEN estimate_minutes ELSE 0 END), 0) AS remaining_estimate`,
+			true, false, time.Now().UTC(), false).
+		Where("list_id = ?", listID).
+		Group("priority").
+		Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ListStats{ListID: listID}
+	for _, group := range groups {
+		stats.Total += group.Total
+		stats.Completed += group.Completed
+		stats.Overdue += group.Overdue
+		stats.ByPriority.Add(group.Priority, group.Total)
+		stats.TotalEstimateMinutes += group.EstimateMinutes
+		stats.RemainingEstimateMinutes += group.RemainingEstimate
+	}
+	stats.Incomplete = stats.Total - stats.Completed
+	return stats, nil
+}
+
+// GetUserStats summarizes a user's todos across all of their lists with two
+// aggregate queries, one over lists and one over their todos
+func (s *PostgresStorage) GetUserStats(userID uuid.UUID) (*models.UserStats, error) {
+	var lists int64
+	if err := s.db.Model(&models.TodoList{}).Where("user_id = ?", userID).Count(&lists).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var totals struct {
+		Total     int
+		Completed int
+		Overdue   int
+		LastWeek  int
+		LastMonth int
+	}
+	err := s.db.Model(&models.Todo{}).
+		Select(`COUNT(*) AS total,
+			COUNT(CASE WHEN todos.completed = ? THEN 1 END) AS completed,
+			COUNT(CASE WHEN todos.completed = ? AND todos.due_date IS NOT NULL AND todos.due_date < ? THEN 1 END) AS overdue,
+			COUNT(CASE WHEN todos.completed_at >= ? THEN 1 END) AS last_week,
+			COUNT(CASE WHEN todos.completed_at >= ? THEN 1 END) AS last_month`,
+			true, false, now, now.Add(-completedWeekWindow), now.Add(-completedMonthWindow)).
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.user_id = ?", userID).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserStats{
+		Lists:               int(lists),
+		TotalTodos:          totals.Total,
+		CompletedTodos:      totals.Completed,
+		CompletionRate:      completionRate(totals.Completed, totals.Total),
+		CompletedLast7Days:  totals.LastWeek,
+		CompletedLast30Days: totals.LastMonth,
+		Overdue:             totals.Overdue,
+	}, nil
+}
+
+// GetNotifications returns a notification for each of the user's incomplete,
+// unarchived todos that is overdue or due within dueSoon of now, leaving out
+// those the user acknowledged, soonest due first
+func (s *PostgresStorage) GetNotifications(userID uuid.UUID, now time.Time, dueSoon time.Duration) ([]models.Notification, error) {
+	var todos []models.TodoWithList
+	err := s.db.Model(&models.Todo{}).
+		Select("todos.*, todo_lists.name AS list_name").
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.user_id = ?", userID).
+		Where("todos.completed = ? AND todos.archived_at IS NULL", false).
+		Where("todos.due_date IS NOT NULL AND todos.due_date < ?", now.Add(dueSoon).UTC()).
+		Scan(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var acks []models.AcknowledgedNotification
+	if len(todos) > 0 {
+		ids := make([]uuid.UUID, len(todos))
+		for i := range todos {
+			ids[i] = todos[i].ID
+		}
+		if err := s.db.Where("user_id = ? AND todo_id IN ?", userID, ids).Find(&acks).Error; err != nil {
+			return nil, err
+		}
+	}
+	acknowledged := make(map[notificationKey]bool, len(acks))
+	for _, ack := range acks {
+		acknowledged[notificationKey{ack.UserID, ack.TodoID, ack.Type}] = true
+	}
+
+	notifications := make([]models.Notification, 0, len(todos))
+	for i := range todos {
+		notification, ok := notificationFor(&todos[i].Todo, todos[i].ListName, now, dueSoon)
+		if !ok || acknowledged[notificationKey{userID, todos[i].ID, notification.Type}] {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	sortNotifications(notifications)
+
+	return notifications, nil
+}
+
+// AcknowledgeNotification dismisses the notification of a type for one of
+// the user's todos so GetNotifications no longer returns it. Acknowledging
+// twice is not an error.
+func (s *PostgresStorage) AcknowledgeNotification(userID, todoID uuid.UUID, notificationType models.NotificationType) error {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return busyErr
+	}
+	defer release()
+
+	var owned int64
+	err := s.db.Model(&models.Todo{}).
+		Joins("JOIN todo_lists ON todo_lists.id = todos.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todos.id = ? AND todo_lists.user_id = ?", todoID, userID).
+		Count(&owned).Error
+	if err != nil {
+		return err
+	}
+	if owned == 0 {
+		return ErrTodoNotFound
+	}
+
+	ack := &models.AcknowledgedNotification{UserID: userID, TodoID: todoID, Type: notificationType}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(ack).Error
+}
+
+// CreateShareLink creates a link giving read-only public access to one of the
+// user's lists until expiresAt. The returned link carries the token to hand
+// out; only its hash is stored.
+func (s *PostgresStorage) CreateShareLink(userID, listID uuid.UUID, expiresAt time.Time) (*models.ListShareLink, error) {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return nil, busyErr
+	}
+	defer release()
+
+	var owned int64
+	if err := s.db.Model(&models.TodoList{}).Where("id = ? AND user_id = ?", listID, userID).Count(&owned).Error; err != nil {
+		return nil, err
+	}
+	if owned == 0 {
+		return nil, ErrListNotFound
+	}
+
+	token, tokenHash, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	link := &models.ListShareLink{ListID: listID, TokenHash: tokenHash, ExpiresAt: expiresAt.UTC()}
+	if err := s.db.Omit("List").Create(link).Error; err != nil {
+		return nil, err
+	}
+
+	link.Token = token
+	return link, nil
+}
+
+// RevokeShareLink stops a share link of one of the user's lists from working.
+// Returns ErrShareLinkNotFound if the list has no such link or it was already
+// revoked.
+func (s *PostgresStorage) RevokeShareLink(userID, listID, linkID uuid.UUID) error {
+	release, busyErr := s.acquireWrite()
+	if busyErr != nil {
+		return busyErr
+	}
+	defer release()
+
+	var owned int64
+	if err := s.db.Model(&models.TodoList{}).Where("id = ? AND user_id = ?", listID, userID).Count(&owned).Error; err != nil {
+		return err
+	}
+	if owned == 0 {
+		return ErrListNotFound
+	}
+
+	result := s.db.Model(&models.ListShareLink{}).
+		Where("id = ? AND list_id = ? AND revoked_at IS NULL", linkID, listID).
+		Update("revoked_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// GetSharedList returns the share link a token belongs to, with its list.
+// Unknown, expired and revoked tokens and links to deleted lists all give
+// ErrShareLinkNotFound.
+func (s *PostgresStorage) GetSharedList(token string, now time.Time) (*models.ListShareLink, error) {
+	var link models.ListShareLink
+	if err := s.db.Preload("List").Where("token_hash = ?", hashShareToken(token)).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	if link.List.ID == uuid.Nil || !shareLinkUsable(&link, now) {
+		return nil, ErrShareLinkNotFound
+	}
+	return &link, nil
+}
+
+// deleteScope returns db unscoped in hard delete mode so deletes remove rows
+// permanently, or unchanged so they soft-delete
+func (s *PostgresStorage) deleteScope(db *gorm.DB) *gorm.DB {
+	if s.hardDelete {
+		return db.Unscoped()
+	}
+	return db
+}
+
+// duplicateTodoError translates a violation of the unique todo description
+// index into ErrDuplicateTodo and returns other errors unchanged
+func duplicateTodoError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == database.UniqueTodoDescriptionIndex {
+			return ErrDuplicateTodo
+		}
+		return err
+	}
+	// Other drivers, such as SQLite in tests, only name the index in the message
+	if err != nil && strings.Contains(err.Error(), database.UniqueTodoDescriptionIndex) {
+		return ErrDuplicateTodo
+	}
+	return err
+}
+
+// findTodo loads a todo and its list, checking that the list belongs to the
+// user. Clauses on db, such as row locking, apply to the todo query.
+func findTodo(db *gorm.DB, userID, listID, todoID uuid.UUID) (*models.Todo, *models.TodoList, error) {
+	var list models.TodoList
+	if err := db.Session(&gorm.Session{NewDB: true}).
+		Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrListNotFound
+		}
+		return nil, nil, err
+	}
+
+	var todo models.Todo
+	if err := db.Where("id = ? AND list_id = ?", todoID, listID).First(&todo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrTodoNotFound
+		}
+		return nil, nil, err
+	}
+	return &todo, &list, nil
+}
+
+// refreshSubtaskCounts recounts a todo's subtasks and stores the counts on the
+// todo row and on todo
+func refreshSubtaskCounts(tx *gorm.DB, todo *models.Todo) error {
+	var counts struct {
+		Total     int
+		Completed int
+	}
+	if err := tx.Model(&models.Subtask{}).Where("todo_id = ?", todo.ID).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN completed THEN 1 ELSE 0 END), 0) AS completed").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	todo.SubtaskCount, todo.CompletedSubtaskCount = counts.Total, counts.Completed
+	return tx.Model(&models.Todo{}).Where("id = ?", todo.ID).UpdateColumns(map[string]interface{}{
+		"subtask_count":           counts.Total,
+		"completed_subtask_count": counts.Completed,
+	}).Error
+}
+
+// setSubtasksCompleted completes or reopens every subtask of todo that is not
+// already in that state, then recounts them
+func setSubtasksCompleted(tx *gorm.DB, todo *models.Todo, completed bool, now time.Time) error {
+	var completedAt *time.Time
+	if completed {
+		completedAt = &now
+	}
+	if err := tx.Model(&models.Subtask{}).Where("todo_id = ? AND completed = ?", todo.ID, !completed).
+		Updates(map[string]interface{}{
+			"completed":    completed,
+			"completed_at": completedAt,
+			"updated_at":   now,
+		}).Error; err != nil {
+		return err
+	}
+	return refreshSubtaskCounts(tx, todo)
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern builds a LIKE ... ESCAPE '\' pattern matching text that
+// contains search, ignoring case like the memory store's strings.Contains:
+// % and _ in search match themselves rather than acting as wildcards
+func containsPattern(search string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(search)) + "%"
+}
+
+// nextTodoPosition returns the position after the last todo in a list. It
+// locks the list row first so concurrent creates and reorders of the list
+// take turns instead of handing out the same position.
+func nextTodoPosition(tx *gorm.DB, listID uuid.UUID) (int, error) {
+	var list models.TodoList
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", listID).First(&list).Error; err != nil {
+		return 0, err
+	}
+
+	var next int
+	err := tx.Model(&models.Todo{}).Where("list_id = ?", listID).Select("COALESCE(MAX(position) + 1, 0)").Scan(&next).Error
+	return next, err
+}
+
+// buildOrderClause creates the ORDER BY clause for sorting. Ordering mirrors
+// the in-memory sortTodos: todos without a due date sort last ascending and
+// first descending, ties fall back to oldest first then ID, and unknown sort
+// fields are rejected. Columns are qualified so the clause is safe in joins.
+func buildOrderClause(sortBy, sortOrder string) (string, error) {
+	direction := " ASC"
+	if sortOrder == sortOrderDesc {
+		direction = " DESC"
+	}
+
+	// Pinned todos come first whatever the sort field and order
+	const pinned = "todos.pinned DESC, "
+
+	switch sortBy {
+	case sortFieldDueDate:
+		return pinned + "todos.due_date IS NULL" + direction + ", todos.due_date" + direction + ", todos.created_at ASC, todos.id ASC", nil
+	case sortFieldPriority:
+		// PostgreSQL sorting with CASE for priority ordering
+		priorityRank := "CASE todos.priority WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 END"
+		return pinned + priorityRank + direction + ", todos.created_at ASC, todos.id ASC", nil
+	case sortFieldPosition:
+		return pinned + "todos.position" + direction + ", todos.created_at ASC, todos.id ASC", nil
+	case sortFieldCreatedAt, "":
+		return pinned + "todos.created_at" + direction + ", todos.id ASC", nil
+	default:
+		return "", ErrInvalidSortField
 	}
-	return column + " ASC"
 }
@@ -1,15 +1,24 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"todolist-api/internal/database"
 	"todolist-api/internal/models"
 	"todolist-api/internal/testutil"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // Test user ID for all tests
@@ -67,7 +76,7 @@ func TestPostgresGetAllLists(t *testing.T) {
 	}
 
 	t.Run("returns paginated lists", func(t *testing.T) {
-		lists, pagination, err := store.GetAllLists(testUserID, 1, 10)
+		lists, pagination, err := store.GetAllLists(testUserID, 1, 10, "", nil, nil, ActiveLists)
 		require.NoError(t, err)
 		assert.Len(t, lists, 10)
 		assert.Equal(t, 1, pagination.Page)
@@ -77,7 +86,7 @@ func TestPostgresGetAllLists(t *testing.T) {
 	})
 
 	t.Run("returns correct page", func(t *testing.T) {
-		lists, pagination, err := store.GetAllLists(testUserID, 2, 10)
+		lists, pagination, err := store.GetAllLists(testUserID, 2, 10, "", nil, nil, ActiveLists)
 		require.NoError(t, err)
 		assert.Len(t, lists, 10)
 		assert.Equal(t, 2, pagination.Page)
@@ -163,7 +172,7 @@ func TestPostgresDeleteList(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("successfully deletes list (soft delete)", func(t *testing.T) {
-		err := store.DeleteList(testUserID, created.ID)
+		err := store.DeleteList(testUserID, created.ID, nil)
 		require.NoError(t, err)
 
 		// Verify list is not found (soft deleted)
@@ -172,11 +181,40 @@ func TestPostgresDeleteList(t *testing.T) {
 	})
 
 	t.Run("fails when list not found", func(t *testing.T) {
-		err := store.DeleteList(testUserID, uuid.New())
+		err := store.DeleteList(testUserID, uuid.New(), nil)
 		assert.ErrorIs(t, err, ErrListNotFound)
 	})
 }
 
+func TestPostgresDeleteListDeleteMode(t *testing.T) {
+	for _, tc := range []struct {
+		mode           string
+		wantTombstones int64
+	}{
+		{DeleteModeSoft, 1},
+		{DeleteModeHard, 0},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			store := NewPostgresStorageWithConfig(db, &Config{DeleteMode: tc.mode})
+			list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+			require.NoError(t, err)
+			_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Todo", Priority: models.PriorityLow})
+			require.NoError(t, err)
+
+			require.NoError(t, store.DeleteList(testUserID, list.ID, nil))
+
+			var lists, todos int64
+			require.NoError(t, db.Unscoped().Model(&models.TodoList{}).Where("id = ?", list.ID).Count(&lists).Error)
+			require.NoError(t, db.Unscoped().Model(&models.Todo{}).Where("list_id = ?", list.ID).Count(&todos).Error)
+			assert.Equal(t, tc.wantTombstones, lists)
+			assert.Equal(t, tc.wantTombstones, todos)
+		})
+	}
+}
+
 func TestPostgresCreateTodo(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -238,14 +276,16 @@ func TestPostgresGetTodosByList(t *testing.T) {
 	}
 
 	t.Run("gets all todos", func(t *testing.T) {
-		result, err := store.GetTodosByList(testUserID, list.ID, nil, nil, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(testUserID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100})
 		require.NoError(t, err)
 		assert.Len(t, result, 3)
 	})
 
 	t.Run("filters by priority", func(t *testing.T) {
 		priority := models.PriorityHigh
-		result, err := store.GetTodosByList(testUserID, list.ID, &priority, nil, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(
+			testUserID, list.ID, ListTodosOptions{Priority: &priority, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 		assert.Equal(t, models.PriorityHigh, result[0].Priority)
@@ -253,17 +293,19 @@ func TestPostgresGetTodosByList(t *testing.T) {
 
 	t.Run("filters by completion status", func(t *testing.T) {
 		completed := false
-		result, err := store.GetTodosByList(testUserID, list.ID, nil, &completed, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(
+			testUserID, list.ID, ListTodosOptions{Completed: &completed, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Len(t, result, 3)
 	})
 
 	t.Run("sorts by priority descending", func(t *testing.T) {
-		result, err := store.GetTodosByList(testUserID, list.ID, nil, nil, "priority", "desc")
+		result, _, err := store.GetTodosByList(testUserID, list.ID, ListTodosOptions{SortBy: "priority", SortOrder: "desc", Page: 1, Limit: 100})
 		require.NoError(t, err)
-		// Descending priority order: high -> medium -> low
-		assert.Equal(t, models.PriorityHigh, result[0].Priority)
-		assert.Equal(t, models.PriorityLow, result[2].Priority)
+		// Descending reverses ascending order: low -> medium -> high
+		assert.Equal(t, models.PriorityLow, result[0].Priority)
+		assert.Equal(t, models.PriorityHigh, result[2].Priority)
 	})
 }
 
@@ -340,7 +382,7 @@ func TestPostgresDeleteTodo(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("successfully deletes todo", func(t *testing.T) {
-		err := store.DeleteTodo(testUserID, list.ID, todo.ID)
+		err := store.DeleteTodo(testUserID, list.ID, todo.ID, nil)
 		require.NoError(t, err)
 
 		_, err = store.GetTodoByID(testUserID, list.ID, todo.ID)
@@ -348,6 +390,231 @@ func TestPostgresDeleteTodo(t *testing.T) {
 	})
 }
 
+func TestPostgresReorderTodoRepairsDuplicatePositions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	store := NewPostgresStorage(db)
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+	require.NoError(t, err)
+	var created []*models.Todo
+	for _, description := range []string{"A", "B", "C"} {
+		todo, createErr := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+			Description: description,
+			Priority:    models.PriorityMedium,
+		})
+		require.NoError(t, createErr)
+		created = append(created, todo)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Simulate rows left with clashing positions, e.g. by an older writer
+	require.NoError(t, db.Model(&models.Todo{}).Where("list_id = ?", list.ID).UpdateColumn("position", 0).Error)
+
+	_, err = store.ReorderTodo(testUserID, list.ID, created[0].ID, 2)
+	require.NoError(t, err)
+
+	todos, _, err := store.GetTodosByList(testUserID, list.ID, ListTodosOptions{SortBy: "position", Page: 1, Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"B", "C", "A"}, descriptions(todos))
+	assert.Equal(t, []int{0, 1, 2}, []int{todos[0].Position, todos[1].Position, todos[2].Position})
+}
+
+func TestPostgresGetTodosForListsUsesConstantQueries(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	store := NewPostgresStorage(db)
+
+	var listIDs []uuid.UUID
+	for i := 0; i < 5; i++ {
+		list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: fmt.Sprintf("List %d", i)})
+		require.NoError(t, err)
+		for j := 0; j < 3; j++ {
+			_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+				Description: fmt.Sprintf("Todo %d", j),
+				Priority:    models.PriorityMedium,
+				Tags:        []string{"tagged"},
+			})
+			require.NoError(t, err)
+		}
+		listIDs = append(listIDs, list.ID)
+	}
+
+	queries := 0
+	countQuery := func(tx *gorm.DB) {
+		// Subqueries are rendered in dry-run mode and never reach the database
+		if !tx.DryRun {
+			queries++
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_query", countQuery))
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:count_raw", countQuery))
+
+	byList, err := store.GetTodosForLists(testUserID, listIDs, 2)
+	require.NoError(t, err)
+
+	// One ranked todos query and one tags query, not one per list
+	assert.Equal(t, 2, queries)
+	require.Len(t, byList, 5)
+	for _, listID := range listIDs {
+		todos := byList[listID]
+		assert.Equal(t, []string{"Todo 0", "Todo 1"}, descriptions(todos))
+		assert.Equal(t, []string{"tagged"}, todos[0].Tags)
+	}
+}
+
+func TestPostgresHardDeleteCascadesToSubtasks(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	require.NoError(t, db.Exec("PRAGMA foreign_keys = ON").Error)
+
+	store := NewPostgresStorageWithConfig(db, &Config{DeleteMode: DeleteModeHard})
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+	require.NoError(t, err)
+	todo, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+	require.NoError(t, err)
+	_, err = store.CreateSubtask(testUserID, list.ID, todo.ID, models.CreateSubtaskRequest{Description: "Passport"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteTodo(testUserID, list.ID, todo.ID, nil))
+
+	var remaining int64
+	require.NoError(t, db.Model(&models.Subtask{}).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
+
+func TestPostgresMoveTodoUsesOneUpdate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	store := NewPostgresStorage(db)
+
+	inbox, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Inbox"})
+	require.NoError(t, err)
+	work, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Work"})
+	require.NoError(t, err)
+	todo, err := store.CreateTodo(testUserID, inbox.ID, models.CreateTodoRequest{Description: "Report", Priority: models.PriorityLow})
+	require.NoError(t, err)
+
+	updates := 0
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:count_update", func(tx *gorm.DB) {
+		if !tx.DryRun {
+			updates++
+		}
+	}))
+
+	moved, err := store.MoveTodo(testUserID, inbox.ID, todo.ID, work.ID)
+	require.NoError(t, err)
+	assert.Equal(t, work.ID, moved.ListID)
+	assert.Equal(t, 1, updates)
+}
+
+func TestPostgresUniqueTodoDescriptions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	require.NoError(t, database.ConfigureUniqueTodoDescriptions(db, true))
+
+	store := NewPostgresStorage(db)
+
+	list, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Test List"})
+	require.NoError(t, err)
+	other, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Other List"})
+	require.NoError(t, err)
+	pack, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+	require.NoError(t, err)
+
+	t.Run("blocks an open duplicate ignoring case", func(t *testing.T) {
+		_, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "PACK", Priority: models.PriorityLow})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+
+		_, err = store.BatchCreateTodos(testUserID, list.ID, []models.CreateTodoRequest{
+			{Description: "Unpack", Priority: models.PriorityLow},
+			{Description: "unpack", Priority: models.PriorityLow},
+		})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+
+		// A second clone repeats the first clone's description
+		_, err = store.CloneTodo(testUserID, list.ID, pack.ID, list.ID)
+		require.NoError(t, err)
+		_, err = store.CloneTodo(testUserID, list.ID, pack.ID, list.ID)
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+	})
+
+	t.Run("allows the description in another list", func(t *testing.T) {
+		_, err := store.CreateTodo(testUserID, other.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		_, err = store.MergeLists(testUserID, list.ID, other.ID)
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+	})
+
+	t.Run("allows a duplicate of a completed todo", func(t *testing.T) {
+		completed := true
+		_, err := store.UpdateTodo(testUserID, list.ID, pack.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+
+		_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		require.NoError(t, err)
+
+		// Reopening the completed todo would duplicate the new one
+		completed = false
+		_, err = store.UpdateTodo(testUserID, list.ID, pack.ID, models.UpdateTodoRequest{Completed: &completed})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+	})
+
+	t.Run("allows duplicates once disabled", func(t *testing.T) {
+		require.NoError(t, database.ConfigureUniqueTodoDescriptions(db, false))
+
+		_, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		assert.NoError(t, err)
+	})
+}
+
+func TestPostgresDuplicateTodoError(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	store := NewPostgresStorage(db)
+	listID := uuid.New()
+	createTodo := func(insertErr error) error {
+		mock.ExpectQuery(`SELECT \* FROM "todo_lists"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(listID, testUserID, "Test List"))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT "id" FROM "todo_lists"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(listID))
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "todos"`).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+		mock.ExpectExec(`INSERT INTO "todos"`).WillReturnError(insertErr)
+		mock.ExpectRollback()
+
+		_, err := store.CreateTodo(testUserID, listID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		return err
+	}
+
+	t.Run("maps the unique description violation", func(t *testing.T) {
+		err := createTodo(&pgconn.PgError{Code: "23505", ConstraintName: database.UniqueTodoDescriptionIndex})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leaves other unique violations alone", func(t *testing.T) {
+		violation := &pgconn.PgError{Code: "23505", ConstraintName: "todos_pkey"}
+		err := createTodo(violation)
+		assert.NotErrorIs(t, err, ErrDuplicateTodo)
+		assert.True(t, errors.As(err, &violation))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestPostgresTodoCount(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -374,3 +641,153 @@ func TestPostgresTodoCount(t *testing.T) {
 		assert.Equal(t, 5, updated.TodoCount)
 	})
 }
+
+func TestPostgresGetListStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	store := NewPostgresStorage(db)
+
+	req := models.CreateTodoListRequest{Name: "Test List"}
+	list, err := store.CreateList(testUserID, req)
+	require.NoError(t, err)
+
+	thirty, sixty := 30, 60
+	_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+		Description: "Short task", Priority: models.PriorityLow, EstimateMinutes: &thirty,
+	})
+	require.NoError(t, err)
+	done, err := store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+		Description: "Long task", Priority: models.PriorityHigh, EstimateMinutes: &sixty,
+	})
+	require.NoError(t, err)
+	_, err = store.CreateTodo(testUserID, list.ID, models.CreateTodoRequest{
+		Description: "Unestimated", Priority: models.PriorityMedium,
+	})
+	require.NoError(t, err)
+
+	completed := true
+	_, err = store.UpdateTodo(testUserID, list.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+	require.NoError(t, err)
+
+	t.Run("sums estimates and excludes completed from remaining", func(t *testing.T) {
+		stats, err := store.GetListStats(testUserID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 90, stats.TotalEstimateMinutes)
+		assert.Equal(t, 30, stats.RemainingEstimateMinutes)
+		assert.Equal(t, 3, stats.Total)
+		assert.Equal(t, 1, stats.Completed)
+		assert.Equal(t, 2, stats.Incomplete)
+		assert.Equal(t, models.PriorityCounts{High: 1, Medium: 1, Low: 1}, stats.ByPriority)
+	})
+
+	t.Run("empty list returns zero estimates", func(t *testing.T) {
+		empty, err := store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Empty List"})
+		require.NoError(t, err)
+
+		stats, err := store.GetListStats(testUserID, empty.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.TotalEstimateMinutes)
+		assert.Equal(t, 0, stats.RemainingEstimateMinutes)
+	})
+
+	t.Run("fails when list not found", func(t *testing.T) {
+		_, err := store.GetListStats(testUserID, uuid.New())
+		assert.ErrorIs(t, err, ErrListNotFound)
+	})
+}
+
+func TestPostgresWriteConcurrency(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	t.Run("unlimited when not configured", func(t *testing.T) {
+		store := NewPostgresStorageWithConfig(db, &Config{})
+		assert.Nil(t, store.writeSlots)
+
+		release, err := store.acquireWrite()
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("excess writer fails after timeout", func(t *testing.T) {
+		store := NewPostgresStorageWithConfig(db, &Config{
+			MaxDBConcurrency: 1,
+			DBBusyTimeout:    20 * time.Millisecond,
+		})
+
+		release, err := store.acquireWrite()
+		require.NoError(t, err)
+
+		_, err = store.acquireWrite()
+		assert.ErrorIs(t, err, ErrDBBusy)
+
+		_, err = store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Blocked"})
+		assert.ErrorIs(t, err, ErrDBBusy)
+
+		release()
+
+		_, err = store.CreateList(testUserID, models.CreateTodoListRequest{Name: "Allowed"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("waiting writer proceeds once a slot frees up", func(t *testing.T) {
+		store := NewPostgresStorageWithConfig(db, &Config{
+			MaxDBConcurrency: 1,
+			DBBusyTimeout:    time.Second,
+		})
+
+		release, err := store.acquireWrite()
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			release()
+		}()
+
+		second, err := store.acquireWrite()
+		require.NoError(t, err)
+		second()
+	})
+
+	t.Run("bounds concurrent writers", func(t *testing.T) {
+		const limit = 3
+		store := NewPostgresStorageWithConfig(db, &Config{
+			MaxDBConcurrency: limit,
+			DBBusyTimeout:    time.Second,
+		})
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		active, peak := 0, 0
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := store.acquireWrite()
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer release()
+
+				mu.Lock()
+				active++
+				if active > peak {
+					peak = active
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, peak, limit)
+		assert.Positive(t, peak)
+	})
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"todolist-api/internal/models"
+)
+
+// Recurrence frequencies, usable on their own as a rule ("weekly") or as the
+// FREQ of an RFC 5545 style rule ("FREQ=WEEKLY;INTERVAL=2")
+const (
+	frequencyDaily   = "daily"
+	frequencyWeekly  = "weekly"
+	frequencyMonthly = "monthly"
+	frequencyYearly  = "yearly"
+)
+
+// maxRecurrenceInterval caps INTERVAL so due dates stay within a sane range
+const maxRecurrenceInterval = 1000
+
+var errInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// recurrence is a parsed recurrence rule: every interval days, weeks, months
+// or years
+type recurrence struct {
+	frequency string
+	interval  int
+}
+
+// ValidRecurrenceRule reports whether rule is empty (no recurrence) or a
+// supported recurrence rule
+func ValidRecurrenceRule(rule string) bool {
+	if rule == "" {
+		return true
+	}
+	_, err := parseRecurrence(rule)
+	return err == nil
+}
+
+// parseRecurrence parses a bare frequency such as "monthly" or an RFC 5545
+// style rule limited to FREQ and INTERVAL, with an optional "RRULE:" prefix.
+// Names and values are case-insensitive.
+func parseRecurrence(rule string) (recurrence, error) {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+	if isFrequency(rule) {
+		return recurrence{frequency: rule, interval: 1}, nil
+	}
+
+	parsed := recurrence{interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "rrule:"), ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			return recurrence{}, errInvalidRecurrence
+		}
+		switch name {
+		case "freq":
+			if !isFrequency(value) {
+				return recurrence{}, errInvalidRecurrence
+			}
+			parsed.frequency = value
+		case "interval":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > maxRecurrenceInterval {
+				return recurrence{}, errInvalidRecurrence
+			}
+			parsed.interval = interval
+		default:
+			return recurrence{}, errInvalidRecurrence
+		}
+	}
+	if parsed.frequency == "" {
+		return recurrence{}, errInvalidRecurrence
+	}
+	return parsed, nil
+}
+
+func isFrequency(value string) bool {
+	switch value {
+	case frequencyDaily, frequencyWeekly, frequencyMonthly, frequencyYearly:
+		return true
+	}
+	return false
+}
+
+// next returns the occurrence after t
+func (r recurrence) next(t time.Time) time.Time {
+	switch r.frequency {
+	case frequencyDaily:
+		return t.AddDate(0, 0, r.interval)
+	case frequencyWeekly:
+		return t.AddDate(0, 0, 7*r.interval)
+	case frequencyMonthly:
+		return addMonths(t, r.interval)
+	default:
+		return addMonths(t, 12*r.interval)
+	}
+}
+
+// addMonths adds months to t keeping its day of month, clamped to the last
+// day of shorter months: Jan 31 plus one month is Feb 28 (29 in leap years),
+// where time.AddDate would overflow into March
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// nextOccurrence returns the todo that follows a recurring todo which an
+// update has just completed, due one recurrence after the completed todo's due
+// date (or after now when it had none). It returns nil when the update did not
+// complete a recurring todo. The caller assigns the ID and position.
+func nextOccurrence(before, after *models.Todo, now time.Time) *models.Todo {
+	if after.RecurrenceRule == "" || before.Completed || !after.Completed {
+		return nil
+	}
+	rule, err := parseRecurrence(after.RecurrenceRule)
+	if err != nil {
+		return nil
+	}
+
+	from := now
+	if after.DueDate != nil {
+		from = *after.DueDate
+	}
+	dueDate := rule.next(from)
+	return &models.Todo{
+		ListID:          after.ListID,
+		Description:     after.Description,
+		Priority:        after.Priority,
+		DueDate:         &dueDate,
+		EstimateMinutes: after.EstimateMinutes,
+		Tags:            after.Tags,
+		RecurrenceRule:  after.RecurrenceRule,
+		Pinned:          after.Pinned,
+		Version:         1,
+	}
+}
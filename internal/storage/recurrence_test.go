@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule string
+		want recurrence
+	}{
+		{"daily", recurrence{frequency: frequencyDaily, interval: 1}},
+		{" Weekly ", recurrence{frequency: frequencyWeekly, interval: 1}},
+		{"FREQ=MONTHLY", recurrence{frequency: frequencyMonthly, interval: 1}},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2", recurrence{frequency: frequencyWeekly, interval: 2}},
+		{"interval=3;freq=yearly", recurrence{frequency: frequencyYearly, interval: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := parseRecurrence(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, rule := range []string{"hourly", "FREQ=HOURLY", "INTERVAL=2", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;COUNT=3", "FREQ"} {
+		t.Run("rejects "+rule, func(t *testing.T) {
+			_, err := parseRecurrence(rule)
+			assert.ErrorIs(t, err, errInvalidRecurrence)
+			assert.False(t, ValidRecurrenceRule(rule))
+		})
+	}
+
+	assert.True(t, ValidRecurrenceRule(""))
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", "daily", date(2026, time.December, 31), date(2027, time.January, 1)},
+		{"every two weeks", "FREQ=WEEKLY;INTERVAL=2", date(2026, time.March, 2), date(2026, time.March, 16)},
+		{"monthly keeps the day", "monthly", date(2026, time.January, 15), date(2026, time.February, 15)},
+		{"monthly clamps Jan 31 to Feb 28", "monthly", date(2026, time.January, 31), date(2026, time.February, 28)},
+		{"monthly clamps Jan 31 to Feb 29 in leap years", "monthly", date(2028, time.January, 31), date(2028, time.February, 29)},
+		{"monthly clamps to 30 day months", "monthly", date(2026, time.March, 31), date(2026, time.April, 30)},
+		{"monthly rolls over the year", "FREQ=MONTHLY;INTERVAL=3", date(2026, time.November, 30), date(2027, time.February, 28)},
+		{"yearly clamps leap days", "yearly", date(2028, time.February, 29), date(2029, time.February, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRecurrence(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.next(tt.from))
+		})
+	}
+}
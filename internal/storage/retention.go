@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"todolist-api/internal/logging"
+)
+
+// RetentionPurger periodically deletes completed todos that are older than
+// the retention period, and optionally deleted todos that have been in the
+// trash too long, keeping lists tidy and the database small
+type RetentionPurger struct {
+	store          Store
+	retention      time.Duration
+	trashRetention time.Duration
+	interval       time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewRetentionPurger creates a purger that, every interval, removes todos
+// completed more than retention ago
+func NewRetentionPurger(store Store, retention, interval time.Duration) *RetentionPurger {
+	return &RetentionPurger{
+		store:     store,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// NewRetentionPurgerWithConfig creates a purger that, every RetentionInterval,
+// removes todos completed more than CompletedRetention ago and todos deleted
+// more than TrashRetention ago. A zero retention disables that purge.
+func NewRetentionPurgerWithConfig(store Store, config *Config) *RetentionPurger {
+	purger := NewRetentionPurger(store, config.CompletedRetention, config.RetentionInterval)
+	purger.trashRetention = config.TrashRetention
+	return purger
+}
+
+// Start runs the purge loop in the background until Stop is called
+func (p *RetentionPurger) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.purge(now)
+			}
+		}
+	}()
+}
+
+// Stop ends the purge loop, waiting for a purge in progress to finish
+func (p *RetentionPurger) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// purge deletes todos completed before now minus the retention period and
+// todos deleted longer ago than the trash retention period
+func (p *RetentionPurger) purge(now time.Time) {
+	if p.retention > 0 {
+		purged, err := p.store.PurgeOldCompleted(now.Add(-p.retention))
+		if err != nil {
+			logging.Logger.Errorf("Completed todo purge failed: %v", err)
+		} else if purged > 0 {
+			logging.Logger.WithField("purged", purged).Info("Purged old completed todos")
+		}
+	}
+
+	if p.trashRetention > 0 {
+		purged, err := p.store.PurgeDeletedTodos(p.trashRetention)
+		if err != nil {
+			logging.Logger.Errorf("Trash purge failed: %v", err)
+		} else if purged > 0 {
+			logging.Logger.WithField("purged", purged).Info("Purged old deleted todos")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"todolist-api/internal/logging"
+	"todolist-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPurger(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	store := NewStorage()
+	list, err := store.CreateList(testMemoryUserID, models.CreateTodoListRequest{Name: "Chores"})
+	require.NoError(t, err)
+
+	completed, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Done long ago",
+		Priority:    models.PriorityLow,
+	})
+	require.NoError(t, err)
+	done := true
+	_, err = store.UpdateTodo(testMemoryUserID, list.ID, completed.ID, models.UpdateTodoRequest{Completed: &done})
+	require.NoError(t, err)
+
+	pending, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Still open",
+		Priority:    models.PriorityLow,
+	})
+	require.NoError(t, err)
+
+	purger := NewRetentionPurger(store, time.Nanosecond, 5*time.Millisecond)
+	purger.Start()
+
+	assert.Eventually(t, func() bool {
+		_, getErr := store.GetTodoByID(testMemoryUserID, list.ID, completed.ID)
+		return getErr == ErrTodoNotFound
+	}, time.Second, 5*time.Millisecond)
+
+	purger.Stop()
+	purger.Stop() // stopping twice is safe
+
+	_, err = store.GetTodoByID(testMemoryUserID, list.ID, pending.ID)
+	assert.NoError(t, err)
+}
+
+func TestRetentionPurgerTrash(t *testing.T) {
+	logging.InitLogger(&logging.LogConfig{Level: "error"})
+
+	store := NewStorage()
+	list, err := store.CreateList(testMemoryUserID, models.CreateTodoListRequest{Name: "Chores"})
+	require.NoError(t, err)
+
+	deleted, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Deleted",
+		Priority:    models.PriorityLow,
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteTodo(testMemoryUserID, list.ID, deleted.ID, nil))
+
+	done := true
+	completed, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Completed",
+		Priority:    models.PriorityLow,
+	})
+	require.NoError(t, err)
+	_, err = store.UpdateTodo(testMemoryUserID, list.ID, completed.ID, models.UpdateTodoRequest{Completed: &done})
+	require.NoError(t, err)
+
+	// Only the trash has a retention period, so completed todos are kept
+	purger := NewRetentionPurgerWithConfig(store, &Config{
+		TrashRetention:    time.Nanosecond,
+		RetentionInterval: 5 * time.Millisecond,
+	})
+	purger.Start()
+
+	assert.Eventually(t, func() bool {
+		trash, trashErr := store.GetDeletedTodos(testMemoryUserID, list.ID)
+		return trashErr == nil && len(trash) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	purger.Stop()
+
+	_, err = store.GetTodoByID(testMemoryUserID, list.ID, completed.ID)
+	assert.NoError(t, err)
+}
@@ -1,14 +1,22 @@
 package storage
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"todolist-api/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
@@ -16,17 +24,37 @@ const (
 	sortFieldDueDate   = "dueDate"
 	sortFieldPriority  = "priority"
 	sortFieldCreatedAt = "createdAt"
+	sortFieldPosition  = "position"
 
 	// Sort order constants
 	sortOrderDesc = "desc"
+
+	// cloneSuffix is appended to the description of cloned todos
+	cloneSuffix = " (copy)"
+	// maxTodoDescriptionLength mirrors the description binding limit on todo requests
+	maxTodoDescriptionLength = 500
+
+	// copyPrefix starts the name of a duplicated list
+	copyPrefix = "Copy of "
+	// maxListNameLength is the size of the list name column
+	maxListNameLength = 100
 )
 
 var (
-	ErrListNotFound     = errors.New("todo list not found")
-	ErrTodoNotFound     = errors.New("todo not found")
-	ErrListNameExists   = errors.New("list with this name already exists")
-	ErrInvalidPriority  = errors.New("invalid priority value")
-	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrListNotFound      = errors.New("todo list not found")
+	ErrTodoNotFound      = errors.New("todo not found")
+	ErrListNameExists    = errors.New("list with this name already exists")
+	ErrInvalidPriority   = errors.New("invalid priority value")
+	ErrInvalidSortField  = errors.New("invalid sort field")
+	ErrDBBusy            = errors.New("database is busy")
+	ErrTargetNotFound    = errors.New("target todo list not found")
+	ErrVersionConflict   = errors.New("version conflict")
+	ErrSourceNotFound    = errors.New("source todo list not found")
+	ErrMergeSameList     = errors.New("cannot merge a list into itself")
+	ErrDueDateRequired   = errors.New("todo list requires a due date")
+	ErrSubtaskNotFound   = errors.New("subtask not found")
+	ErrDuplicateTodo     = errors.New("an open todo with this description already exists in the list")
+	ErrShareLinkNotFound = errors.New("share link not found")
 )
 
 // Storage provides in-memory storage for todo lists and todos
@@ -34,13 +62,41 @@ type Storage struct {
 	mu    sync.RWMutex
 	lists map[uuid.UUID]*models.TodoList // maps list ID to list
 	todos map[uuid.UUID]*models.Todo     // maps todo ID to todo
+
+	subtasks map[uuid.UUID]*models.Subtask // maps subtask ID to subtask
+
+	// In soft delete mode, deleted lists and todos are moved here as tombstones
+	deleteMode   string
+	deletedLists map[uuid.UUID]*models.TodoList
+	deletedTodos map[uuid.UUID]*models.Todo
+
+	// uniqueDescriptions mirrors the unique todo description index
+	uniqueDescriptions bool
+
+	// acknowledged holds the notifications users have dismissed
+	acknowledged map[notificationKey]bool
+
+	shareLinks map[uuid.UUID]*models.ListShareLink // maps share link ID to link
 }
 
-// NewStorage creates a new in-memory storage instance
+// NewStorage creates a new in-memory storage instance that soft-deletes
 func NewStorage() *Storage {
+	return NewStorageWithConfig(&Config{DeleteMode: DeleteModeSoft})
+}
+
+// NewStorageWithConfig creates a new in-memory storage instance using the given config
+func NewStorageWithConfig(config *Config) *Storage {
 	return &Storage{
-		lists: make(map[uuid.UUID]*models.TodoList),
-		todos: make(map[uuid.UUID]*models.Todo),
+		lists:        make(map[uuid.UUID]*models.TodoList),
+		todos:        make(map[uuid.UUID]*models.Todo),
+		subtasks:     make(map[uuid.UUID]*models.Subtask),
+		deleteMode:   config.DeleteMode,
+		deletedLists: make(map[uuid.UUID]*models.TodoList),
+		deletedTodos: make(map[uuid.UUID]*models.Todo),
+
+		uniqueDescriptions: config.UniqueTodoDescriptions,
+		acknowledged:       make(map[notificationKey]bool),
+		shareLinks:         make(map[uuid.UUID]*models.ListShareLink),
 	}
 }
 
@@ -49,22 +105,25 @@ func (s *Storage) CreateList(userID uuid.UUID, req models.CreateTodoListRequest)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check if list with same name exists for this user
-	for _, list := range s.lists {
-		if list.UserID == userID && list.Name == req.Name {
-			return nil, ErrListNameExists
-		}
+	if err := s.checkListNameAvailable(userID, req.Name, uuid.Nil); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 	list := &models.TodoList{
-		ID:          uuid.New(),
-		UserID:      userID,
-		Name:        req.Name,
-		Description: req.Description,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-		TodoCount:   0,
+		ID:                         uuid.New(),
+		UserID:                     userID,
+		Name:                       req.Name,
+		Description:                req.Description,
+		AutoArchiveCompleted:       req.AutoArchiveCompleted,
+		KeepCompleted:              req.KeepCompleted,
+		RequireDueDate:             req.RequireDueDate,
+		CompleteSubtasksWithParent: req.CompleteSubtasksWithParent,
+		ReopenSubtasksWithParent:   req.ReopenSubtasksWithParent,
+		Version:                    1,
+		CreatedAt:                  now,
+		UpdatedAt:                  now,
+		TodoCount:                  0,
 	}
 
 	s.lists[list.ID] = list
@@ -72,14 +131,17 @@ func (s *Storage) CreateList(userID uuid.UUID, req models.CreateTodoListRequest)
 }
 
 // GetAllLists retrieves all todo lists for a specific user with pagination
-func (s *Storage) GetAllLists(userID uuid.UUID, page, limit int) ([]models.TodoList, *models.Pagination, error) {
+func (s *Storage) GetAllLists(
+	userID uuid.UUID, page, limit int, search string, createdAfter, createdBefore *time.Time, archived ArchivedLists,
+) ([]models.TodoList, *models.Pagination, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Convert map to slice, filtering by user
+	// Convert map to slice, filtering by user, archive state and search term
 	allLists := make([]models.TodoList, 0, len(s.lists))
 	for _, list := range s.lists {
-		if list.UserID == userID {
+		if list.UserID == userID && archived.matches(list) && listMatchesSearch(list, search) &&
+			createdWithin(list.CreatedAt, createdAfter, createdBefore) {
 			listCopy := *list
 			listCopy.TodoCount = s.countTodosInList(list.ID)
 			allLists = append(allLists, listCopy)
@@ -134,6 +196,24 @@ func (s *Storage) GetListByID(userID, listID uuid.UUID) (*models.TodoList, error
 	return &listCopy, nil
 }
 
+// CountLists counts the user's lists by state
+func (s *Storage) CountLists(userID uuid.UUID) (*models.ListSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := &models.ListSummary{}
+	for _, list := range s.lists {
+		if list.UserID == userID {
+			summary.Total++
+			if list.Archived {
+				summary.Archived++
+			}
+		}
+	}
+	summary.Active = summary.Total - summary.Archived
+	return summary, nil
+}
+
 // UpdateList updates an existing todo list for a specific user
 func (s *Storage) UpdateList(userID, listID uuid.UUID, req models.UpdateTodoListRequest) (*models.TodoList, error) {
 	s.mu.Lock()
@@ -143,12 +223,17 @@ func (s *Storage) UpdateList(userID, listID uuid.UUID, req models.UpdateTodoList
 	if !exists || list.UserID != userID {
 		return nil, ErrListNotFound
 	}
+	if req.Version != nil && *req.Version != list.Version {
+		return nil, ErrVersionConflict
+	}
+	before := *list
 
-	// Check if new name conflicts with existing list for this user
+	// Check if new name conflicts with existing list for this user. Archived
+	// lists do not take part in name uniqueness.
 	if req.Name != nil && *req.Name != list.Name {
-		for _, l := range s.lists {
-			if l.UserID == userID && l.ID != listID && l.Name == *req.Name {
-				return nil, ErrListNameExists
+		if !list.Archived {
+			if err := s.checkListNameAvailable(userID, *req.Name, listID); err != nil {
+				return nil, err
 			}
 		}
 		list.Name = *req.Name
@@ -157,16 +242,141 @@ func (s *Storage) UpdateList(userID, listID uuid.UUID, req models.UpdateTodoList
 	if req.Description != nil {
 		list.Description = *req.Description
 	}
+	if req.AutoArchiveCompleted != nil {
+		list.AutoArchiveCompleted = *req.AutoArchiveCompleted
+	}
+	if req.KeepCompleted != nil {
+		list.KeepCompleted = *req.KeepCompleted
+	}
+	if req.RequireDueDate != nil {
+		list.RequireDueDate = *req.RequireDueDate
+	}
+	if req.CompleteSubtasksWithParent != nil {
+		list.CompleteSubtasksWithParent = *req.CompleteSubtasksWithParent
+	}
+	if req.ReopenSubtasksWithParent != nil {
+		list.ReopenSubtasksWithParent = *req.ReopenSubtasksWithParent
+	}
 
+	list.Version++
 	list.UpdatedAt = time.Now()
 
+	listCopy := *list
+	listCopy.TodoCount = s.countTodosInList(listID)
+	listCopy.Changed = listChanges(&before, list)
+	return &listCopy, nil
+}
+
+// DuplicateList copies a list and its todos into a new list named after it,
+// "Copy of X" or, if that is taken, "Copy of X (2)" and so on. The copied todos
+// get new IDs and timestamps and are open, whatever their completion state.
+func (s *Storage) DuplicateList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, exists := s.lists[listID]
+	if !exists || source.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	name := copyListName(source.Name, 1)
+	for attempt := 2; s.checkListNameAvailable(userID, name, uuid.Nil) != nil; attempt++ {
+		name = copyListName(source.Name, attempt)
+	}
+
+	now := time.Now()
+	list := &models.TodoList{
+		ID:                         uuid.New(),
+		UserID:                     userID,
+		Name:                       name,
+		Description:                source.Description,
+		AutoArchiveCompleted:       source.AutoArchiveCompleted,
+		KeepCompleted:              source.KeepCompleted,
+		RequireDueDate:             source.RequireDueDate,
+		CompleteSubtasksWithParent: source.CompleteSubtasksWithParent,
+		ReopenSubtasksWithParent:   source.ReopenSubtasksWithParent,
+		Version:                    1,
+		CreatedAt:                  now,
+		UpdatedAt:                  now,
+	}
+
+	var copies []*models.Todo
+	for _, todo := range s.todos {
+		if todo.ListID == listID {
+			copied := duplicateTodo(todo, list.ID)
+			copied.ID = uuid.New()
+			copied.CreatedAt = now
+			copied.UpdatedAt = now
+			copies = append(copies, copied)
+		}
+	}
+	if err := s.checkUniqueDescriptions(list.ID, copies); err != nil {
+		return nil, err
+	}
+
+	s.lists[list.ID] = list
+	for _, todo := range copies {
+		s.todos[todo.ID] = todo
+	}
+
+	listCopy := *list
+	listCopy.TodoCount = len(copies)
+	return &listCopy, nil
+}
+
+// ArchiveList archives a list, hiding it from GetAllLists by default and
+// freeing its name for new lists. Archiving an archived list changes nothing.
+func (s *Storage) ArchiveList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	if !list.Archived {
+		now := time.Now()
+		list.Archived = true
+		list.ArchivedAt = &now
+		list.Version++
+		list.UpdatedAt = now
+	}
+
+	listCopy := *list
+	listCopy.TodoCount = s.countTodosInList(listID)
+	return &listCopy, nil
+}
+
+// UnarchiveList returns an archived list to the active lists. It fails with
+// ErrListNameExists if an active list has taken the name in the meantime.
+// Unarchiving an active list changes nothing.
+func (s *Storage) UnarchiveList(userID, listID uuid.UUID) (*models.TodoList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	if list.Archived {
+		if err := s.checkListNameAvailable(userID, list.Name, listID); err != nil {
+			return nil, err
+		}
+		list.Archived = false
+		list.ArchivedAt = nil
+		list.Version++
+		list.UpdatedAt = time.Now()
+	}
+
 	listCopy := *list
 	listCopy.TodoCount = s.countTodosInList(listID)
 	return &listCopy, nil
 }
 
 // DeleteList deletes a todo list and all its todos for a specific user
-func (s *Storage) DeleteList(userID, listID uuid.UUID) error {
+func (s *Storage) DeleteList(userID, listID uuid.UUID, version *int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -174,18 +384,80 @@ func (s *Storage) DeleteList(userID, listID uuid.UUID) error {
 	if !exists || list.UserID != userID {
 		return ErrListNotFound
 	}
+	if version != nil && *version != list.Version {
+		return ErrVersionConflict
+	}
+
+	now := time.Now()
 
 	// Delete all todos in this list
 	for todoID, todo := range s.todos {
 		if todo.ListID == listID {
-			delete(s.todos, todoID)
+			s.deleteTodo(todoID, now)
 		}
 	}
 
 	delete(s.lists, listID)
+	if s.deleteMode != DeleteModeHard {
+		list.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		s.deletedLists[listID] = list
+	}
 	return nil
 }
 
+// MergeLists moves every todo from sourceListID into targetListID and then
+// deletes the source list. Both lists must belong to the user.
+func (s *Storage) MergeLists(userID, targetListID, sourceListID uuid.UUID) (*models.TodoList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, exists := s.lists[targetListID]
+	if !exists || target.UserID != userID {
+		return nil, ErrListNotFound
+	}
+	if sourceListID == targetListID {
+		return nil, ErrMergeSameList
+	}
+	source, exists := s.lists[sourceListID]
+	if !exists || source.UserID != userID {
+		return nil, ErrSourceNotFound
+	}
+
+	var moved []*models.Todo
+	for _, todo := range s.todos {
+		if todo.ListID == sourceListID {
+			movedCopy := *todo
+			movedCopy.ListID = targetListID
+			moved = append(moved, &movedCopy)
+		}
+	}
+	if err := s.checkUniqueDescriptions(targetListID, moved); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// Moved todos keep their order, after the target's own todos
+	offset := s.nextPosition(targetListID)
+	for _, todo := range s.todos {
+		if todo.ListID == sourceListID {
+			todo.ListID = targetListID
+			todo.Position += offset
+			todo.Version++
+			todo.UpdatedAt = now
+		}
+	}
+
+	delete(s.lists, sourceListID)
+	if s.deleteMode != DeleteModeHard {
+		source.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		s.deletedLists[sourceListID] = source
+	}
+
+	listCopy := *target
+	listCopy.TodoCount = s.countTodosInList(targetListID)
+	return &listCopy, nil
+}
+
 // CreateTodo creates a new todo in a list owned by a specific user
 func (s *Storage) CreateTodo(userID, listID uuid.UUID, req models.CreateTodoRequest) (*models.Todo, error) {
 	s.mu.Lock()
@@ -196,62 +468,281 @@ func (s *Storage) CreateTodo(userID, listID uuid.UUID, req models.CreateTodoRequ
 		return nil, ErrListNotFound
 	}
 
+	if err := checkDueDatePolicy(list, req); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
-	todo := &models.Todo{
-		ID:          uuid.New(),
-		ListID:      listID,
-		Description: req.Description,
-		Priority:    req.Priority,
-		DueDate:     req.DueDate,
-		Completed:   false,
-		CompletedAt: nil,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+	todo := newTodo(list, req, now)
+	todo.ID = uuid.New()
+	todo.Position = s.nextPosition(listID)
+	todo.CreatedAt = now
+	todo.UpdatedAt = now
+	if err := s.checkUniqueDescriptions(listID, []*models.Todo{todo}); err != nil {
+		return nil, err
 	}
 
 	s.todos[todo.ID] = todo
 	return todo, nil
 }
 
-// GetTodosByList retrieves all todos in a list owned by a specific user with filtering and sorting
-func (s *Storage) GetTodosByList(
-	userID, listID uuid.UUID,
-	priority *models.Priority,
-	completed *bool,
-	sortBy, sortOrder string,
-) ([]models.Todo, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// BatchCreateTodos creates several todos in a list owned by a specific user.
+// Either all todos are created or none are; they are returned in request order.
+func (s *Storage) BatchCreateTodos(userID, listID uuid.UUID, reqs []models.CreateTodoRequest) ([]models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	list, exists := s.lists[listID]
 	if !exists || list.UserID != userID {
 		return nil, ErrListNotFound
 	}
 
-	// Filter todos
-	result := make([]models.Todo, 0)
+	for _, req := range reqs {
+		if err := checkDueDatePolicy(list, req); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	position := s.nextPosition(listID)
+	todos := make([]*models.Todo, len(reqs))
+	for i, req := range reqs {
+		todo := newTodo(list, req, now)
+		todo.ID = uuid.New()
+		todo.Position = position + i
+		todo.CreatedAt = now
+		todo.UpdatedAt = now
+		todos[i] = todo
+	}
+	if err := s.checkUniqueDescriptions(listID, todos); err != nil {
+		return nil, err
+	}
+
+	created := make([]models.Todo, 0, len(reqs))
+	for _, todo := range todos {
+		s.todos[todo.ID] = todo
+		created = append(created, *todo)
+	}
+
+	return created, nil
+}
+
+// checkUniqueDescriptions returns ErrDuplicateTodo if unique descriptions are
+// enabled and, once the pending todos are added to or replace the stored ones,
+// two open todos in listID would share a description ignoring case. Must be
+// called with lock held.
+func (s *Storage) checkUniqueDescriptions(listID uuid.UUID, pending []*models.Todo) error {
+	if !s.uniqueDescriptions {
+		return nil
+	}
+
+	replaced := make(map[uuid.UUID]bool, len(pending))
+	for _, todo := range pending {
+		replaced[todo.ID] = true
+	}
+	seen := make(map[string]bool)
+	duplicate := func(todo *models.Todo) bool {
+		if todo.ListID != listID || todo.Completed {
+			return false
+		}
+		key := strings.ToLower(todo.Description)
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		return false
+	}
+
+	for id, todo := range s.todos {
+		if !replaced[id] && duplicate(todo) {
+			return ErrDuplicateTodo
+		}
+	}
+	for _, todo := range pending {
+		if duplicate(todo) {
+			return ErrDuplicateTodo
+		}
+	}
+	return nil
+}
+
+// nextPosition returns the position after the last todo in a list
+func (s *Storage) nextPosition(listID uuid.UUID) int {
+	next := 0
 	for _, todo := range s.todos {
-		if todo.ListID != listID {
-			continue
+		if todo.ListID == listID && todo.Position >= next {
+			next = todo.Position + 1
+		}
+	}
+	return next
+}
+
+// ReorderTodo moves a todo to position within its list, shifting the todos in
+// between, and renumbers the list from 0. Positions past the end move it last.
+func (s *Storage) ReorderTodo(userID, listID, todoID uuid.UUID, position int) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	moved, exists := s.todos[todoID]
+	if !exists || moved.ListID != listID {
+		return nil, ErrTodoNotFound
+	}
+
+	todos := make([]*models.Todo, 0)
+	for _, todo := range s.todos {
+		if todo.ListID == listID {
+			todos = append(todos, todo)
+		}
+	}
+	reorderTodos(todos, todoID, position, time.Now())
+
+	todoCopy := *moved
+	return &todoCopy, nil
+}
+
+// reorderTodos moves the todo with todoID to index position (clamped to the
+// list) and renumbers todos from 0. Todos are first put in their current order,
+// with ties broken by creation time then ID, so duplicate positions are repaired.
+// Each todo whose position changes gets a new version and UpdatedAt of now.
+func reorderTodos(todos []*models.Todo, todoID uuid.UUID, position int, now time.Time) {
+	sort.Slice(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
 		}
+		return a.ID.String() < b.ID.String()
+	})
 
-		// Apply filters
-		if priority != nil && todo.Priority != *priority {
+	ordered := make([]*models.Todo, 0, len(todos))
+	var moved *models.Todo
+	for _, todo := range todos {
+		if todo.ID == todoID {
+			moved = todo
 			continue
 		}
-		if completed != nil && todo.Completed != *completed {
+		ordered = append(ordered, todo)
+	}
+	if moved != nil {
+		if position > len(ordered) {
+			position = len(ordered)
+		}
+		ordered = append(ordered[:position], append([]*models.Todo{moved}, ordered[position:]...)...)
+	}
+
+	for i, todo := range ordered {
+		if todo.Position == i {
 			continue
 		}
+		todo.Position = i
+		todo.Version++
+		todo.UpdatedAt = now
+	}
+}
+
+// checkDueDatePolicy returns ErrDueDateRequired if list requires due dates
+// and the create request has none
+func checkDueDatePolicy(list *models.TodoList, req models.CreateTodoRequest) error {
+	if list.RequireDueDate && req.DueDate == nil {
+		return ErrDueDateRequired
+	}
+	return nil
+}
+
+// newTodo builds an unsaved todo for list from a create request, marking it
+// completed (and archived, if the list auto-archives) when the request asks for
+// it and giving it the default priority when the request has none
+func newTodo(list *models.TodoList, req models.CreateTodoRequest, now time.Time) *models.Todo {
+	priority := req.Priority
+	if priority == "" {
+		priority = models.DefaultPriority
+	}
+	todo := &models.Todo{
+		ListID:          list.ID,
+		Description:     req.Description,
+		Priority:        priority,
+		DueDate:         req.DueDate,
+		EstimateMinutes: req.EstimateMinutes,
+		Completed:       req.Completed,
+		Tags:            normalizeTags(req.Tags),
+		RecurrenceRule:  req.RecurrenceRule,
+		Version:         1,
+	}
+	if req.Completed {
+		todo.CompletedAt = &now
+		if list.AutoArchiveCompleted {
+			todo.ArchivedAt = &now
+		}
+	}
+	return todo
+}
+
+// GetTodosByList retrieves a page of todos in a list owned by a specific user
+// with filtering and sorting applied before pagination
+func (s *Storage) GetTodosByList(userID, listID uuid.UUID, opts ListTodosOptions) ([]models.Todo, *models.Pagination, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, nil, ErrListNotFound
+	}
 
-		result = append(result, *todo)
+	// Filter todos
+	opts.Tags = normalizeTags(opts.Tags)
+	now := time.Now().UTC()
+	result := make([]models.Todo, 0)
+	for _, todo := range s.todos {
+		if todo.ListID == listID && opts.matches(todo, now) {
+			result = append(result, *todo)
+		}
 	}
 
 	// Sort todos
-	if err := sortTodos(result, sortBy, sortOrder); err != nil {
-		return nil, err
+	if err := sortTodos(result, opts.SortBy, opts.SortOrder); err != nil {
+		return nil, nil, err
 	}
 
-	return result, nil
+	pagination := newPagination(opts.Page, opts.Limit, len(result))
+	return paginate(result, pagination), pagination, nil
+}
+
+// GetTodosForLists returns up to perList active todos of each given list owned
+// by a specific user, keyed by list ID and in position order. Lists that are
+// not found are left out.
+func (s *Storage) GetTodosForLists(userID uuid.UUID, listIDs []uuid.UUID, perList int) (map[uuid.UUID][]models.Todo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(listIDs))
+	for _, listID := range listIDs {
+		if list, exists := s.lists[listID]; exists && list.UserID == userID {
+			wanted[listID] = true
+		}
+	}
+
+	byList := make(map[uuid.UUID][]models.Todo, len(wanted))
+	for _, todo := range s.todos {
+		if wanted[todo.ListID] && todo.ArchivedAt == nil {
+			byList[todo.ListID] = append(byList[todo.ListID], *todo)
+		}
+	}
+	for listID, todos := range byList {
+		if err := sortTodos(todos, sortFieldPosition, "asc"); err != nil {
+			return nil, err
+		}
+		if len(todos) > perList {
+			byList[listID] = todos[:perList]
+		}
+	}
+	return byList, nil
 }
 
 // GetTodoByID retrieves a specific todo from a list owned by a specific user
@@ -287,54 +778,1060 @@ func (s *Storage) UpdateTodo(userID, listID, todoID uuid.UUID, req models.Update
 	if !exists || todo.ListID != listID {
 		return nil, ErrTodoNotFound
 	}
-
-	if req.Description != nil {
-		todo.Description = *req.Description
+	if req.Version != nil && *req.Version != todo.Version {
+		return nil, ErrVersionConflict
 	}
-	if req.Priority != nil {
-		todo.Priority = *req.Priority
+
+	before := *todo
+	now := time.Now()
+	updated := *todo
+	applyTodoUpdate(&updated, list, req, now)
+	pending := []*models.Todo{&updated}
+	next := nextOccurrence(&before, &updated, now)
+	if next != nil {
+		next.ID = uuid.New()
+		next.Position = s.nextPosition(listID)
+		next.CreatedAt = now
+		next.UpdatedAt = now
+		pending = append(pending, next)
 	}
-	if req.DueDate != nil {
-		todo.DueDate = req.DueDate
+	if err := s.checkUniqueDescriptions(listID, pending); err != nil {
+		return nil, err
 	}
-	if req.Completed != nil {
-		wasCompleted := todo.Completed
-		todo.Completed = *req.Completed
-
-		// Set or clear CompletedAt timestamp
-		if *req.Completed && !wasCompleted {
-			now := time.Now()
-			todo.CompletedAt = &now
-		} else if !*req.Completed && wasCompleted {
-			todo.CompletedAt = nil
-		}
+	*todo = updated
+	todo.Version++
+	todo.UpdatedAt = now
+	if completed, ok := cascadesToSubtasks(list, &before, todo); ok {
+		s.setSubtasksCompleted(todo, completed, now)
 	}
 
-	todo.UpdatedAt = time.Now()
-
 	todoCopy := *todo
+	todoCopy.Changed = todoChanges(&before, todo)
+	if next != nil {
+		s.todos[next.ID] = next
+		nextCopy := *next
+		todoCopy.NextOccurrence = &nextCopy
+	}
 	return &todoCopy, nil
 }
 
-// DeleteTodo deletes a todo from a list owned by a specific user
-func (s *Storage) DeleteTodo(userID, listID, todoID uuid.UUID) error {
+// BatchUpdateTodos applies the same update to every listed todo found in a
+// list owned by a specific user; IDs not in the list are reported, not fatal.
+// Completing todos repeats and cascades to subtasks as in UpdateTodo.
+func (s *Storage) BatchUpdateTodos(
+	userID, listID uuid.UUID, todoIDs []uuid.UUID, req models.UpdateTodoRequest,
+) (*models.BatchResult, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	list, exists := s.lists[listID]
 	if !exists || list.UserID != userID {
-		return ErrListNotFound
+		return nil, ErrListNotFound
 	}
 
-	todo, exists := s.todos[todoID]
+	now := time.Now()
+	result := &models.BatchResult{NotFound: make([]uuid.UUID, 0)}
+	var updated, next []*models.Todo
+	position := s.nextPosition(listID)
+	for _, todoID := range uniqueIDs(todoIDs) {
+		todo, exists := s.todos[todoID]
+		if !exists || todo.ListID != listID {
+			result.NotFound = append(result.NotFound, todoID)
+			continue
+		}
+		todoCopy := *todo
+		applyTodoUpdate(&todoCopy, list, req, now)
+		updated = append(updated, &todoCopy)
+		if occurrence := nextOccurrence(todo, &todoCopy, now); occurrence != nil {
+			occurrence.ID = uuid.New()
+			occurrence.Position = position
+			occurrence.CreatedAt = now
+			occurrence.UpdatedAt = now
+			position++
+			next = append(next, occurrence)
+		}
+	}
+	if err := s.checkUniqueDescriptions(listID, append(append([]*models.Todo{}, updated...), next...)); err != nil {
+		return nil, err
+	}
+
+	for _, todo := range updated {
+		stored := s.todos[todo.ID]
+		before := *stored
+		todo.Version++
+		todo.UpdatedAt = now
+		*stored = *todo
+		if completed, ok := cascadesToSubtasks(list, &before, stored); ok {
+			s.setSubtasksCompleted(stored, completed, now)
+		}
+		result.Affected++
+	}
+	for _, occurrence := range next {
+		s.todos[occurrence.ID] = occurrence
+		result.NextOccurrences = append(result.NextOccurrences, *occurrence)
+	}
+
+	return result, nil
+}
+
+// BatchDeleteTodos deletes every listed todo found in a list owned by a
+// specific user; IDs not in the list are reported, not fatal
+func (s *Storage) BatchDeleteTodos(userID, listID uuid.UUID, todoIDs []uuid.UUID) (*models.BatchResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	now := time.Now()
+	result := &models.BatchResult{NotFound: make([]uuid.UUID, 0)}
+	for _, todoID := range uniqueIDs(todoIDs) {
+		todo, exists := s.todos[todoID]
+		if !exists || todo.ListID != listID {
+			result.NotFound = append(result.NotFound, todoID)
+			continue
+		}
+		s.deleteTodo(todoID, now)
+		result.Affected++
+	}
+
+	return result, nil
+}
+
+// applyTodoUpdate applies the non-nil fields of req to todo. Completing a todo
+// sets CompletedAt (and ArchivedAt if list auto-archives); reopening clears both.
+func applyTodoUpdate(todo *models.Todo, list *models.TodoList, req models.UpdateTodoRequest, now time.Time) {
+	if req.Description != nil {
+		todo.Description = *req.Description
+	}
+	if req.Priority != nil {
+		todo.Priority = *req.Priority
+	}
+	if req.DueDate != nil {
+		todo.DueDate = req.DueDate
+	}
+	if req.EstimateMinutes != nil {
+		todo.EstimateMinutes = req.EstimateMinutes
+	}
+	if req.Tags != nil {
+		todo.Tags = normalizeTags(*req.Tags)
+	}
+	if req.RecurrenceRule != nil {
+		todo.RecurrenceRule = *req.RecurrenceRule
+	}
+	if req.Completed != nil {
+		wasCompleted := todo.Completed
+		todo.Completed = *req.Completed
+
+		if *req.Completed && !wasCompleted {
+			completedAt := now
+			todo.CompletedAt = &completedAt
+			if list.AutoArchiveCompleted {
+				todo.ArchivedAt = &completedAt
+			}
+		} else if !*req.Completed && wasCompleted {
+			todo.CompletedAt = nil
+			todo.ArchivedAt = nil
+		}
+	}
+}
+
+// normalizeTags lowercases and trims tags, dropping empty and duplicate ones,
+// and sorts them so both stores return tags in the same order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// hasAllTags reports whether todoTags contains every tag in want
+func hasAllTags(todoTags, want []string) bool {
+	for _, tag := range want {
+		found := false
+		for _, todoTag := range todoTags {
+			if todoTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueIDs returns ids without duplicates, keeping first occurrences in order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// DeleteTodo deletes a todo from a list owned by a specific user
+func (s *Storage) DeleteTodo(userID, listID, todoID uuid.UUID, version *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return ErrListNotFound
+	}
+
+	todo, exists := s.todos[todoID]
 	if !exists || todo.ListID != listID {
 		return ErrTodoNotFound
 	}
+	if version != nil && *version != todo.Version {
+		return ErrVersionConflict
+	}
+
+	s.deleteTodo(todoID, time.Now())
+	return nil
+}
+
+// CloneTodo copies a todo as a new, incomplete todo in targetListID, or in
+// its own list when targetListID is uuid.Nil. Both lists must belong to the user.
+func (s *Storage) CloneTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	source, exists := s.todos[todoID]
+	if !exists || source.ListID != listID {
+		return nil, ErrTodoNotFound
+	}
+
+	if targetListID == uuid.Nil {
+		targetListID = listID
+	}
+	target, exists := s.lists[targetListID]
+	if !exists || target.UserID != userID {
+		return nil, ErrTargetNotFound
+	}
+
+	now := time.Now()
+	clone := &models.Todo{
+		ID:              uuid.New(),
+		ListID:          targetListID,
+		Description:     cloneDescription(source.Description),
+		Priority:        source.Priority,
+		DueDate:         source.DueDate,
+		EstimateMinutes: source.EstimateMinutes,
+		Tags:            source.Tags,
+		RecurrenceRule:  source.RecurrenceRule,
+		Position:        s.nextPosition(targetListID),
+		Version:         1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.checkUniqueDescriptions(targetListID, []*models.Todo{clone}); err != nil {
+		return nil, err
+	}
+
+	s.todos[clone.ID] = clone
+	todoCopy := *clone
+	return &todoCopy, nil
+}
+
+// GetDeletedTodos retrieves the soft-deleted todos in a list owned by a
+// specific user, most recently deleted first
+func (s *Storage) GetDeletedTodos(userID, listID uuid.UUID) ([]models.DeletedTodo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	deleted := make([]models.DeletedTodo, 0)
+	for _, todo := range s.deletedTodos {
+		if todo.ListID == listID {
+			deleted = append(deleted, models.DeletedTodo{Todo: *todo, DeletedAt: todo.DeletedAt.Time})
+		}
+	}
+	sortDeletedTodos(deleted)
+	return deleted, nil
+}
+
+// RestoreTodo moves a soft-deleted todo out of the trash to the end of its list
+func (s *Storage) RestoreTodo(userID, listID, todoID uuid.UUID) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	todo, exists := s.deletedTodos[todoID]
+	if !exists || todo.ListID != listID {
+		return nil, ErrTodoNotFound
+	}
+	if err := s.checkUniqueDescriptions(listID, []*models.Todo{todo}); err != nil {
+		return nil, err
+	}
+
+	delete(s.deletedTodos, todoID)
+	todo.DeletedAt = gorm.DeletedAt{}
+	todo.Position = s.nextPosition(listID)
+	todo.Version++
+	todo.UpdatedAt = time.Now()
+	s.todos[todoID] = todo
+
+	todoCopy := *todo
+	return &todoCopy, nil
+}
+
+// MoveTodo moves a todo to the end of targetListID. Both lists must belong to
+// the user; moving a todo to its own list changes nothing.
+func (s *Storage) MoveTodo(userID, listID, todoID, targetListID uuid.UUID) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	todo, exists := s.todos[todoID]
+	if !exists || todo.ListID != listID {
+		return nil, ErrTodoNotFound
+	}
+	if targetListID == listID {
+		todoCopy := *todo
+		return &todoCopy, nil
+	}
+
+	target, exists := s.lists[targetListID]
+	if !exists || target.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	moved := *todo
+	moved.ListID = targetListID
+	if err := s.checkUniqueDescriptions(targetListID, []*models.Todo{&moved}); err != nil {
+		return nil, err
+	}
+
+	todo.Position = s.nextPosition(targetListID)
+	todo.ListID = targetListID
+	todo.Version++
+	todo.UpdatedAt = time.Now()
+
+	todoCopy := *todo
+	return &todoCopy, nil
+}
+
+// SetTodoPinned pins a todo to the top of its list's listings or unpins it.
+// Setting the flag to its current value changes nothing.
+func (s *Storage) SetTodoPinned(userID, listID, todoID uuid.UUID, pinned bool) (*models.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, _, err := s.findTodo(userID, listID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.Pinned != pinned {
+		todo.Pinned = pinned
+		todo.Version++
+		todo.UpdatedAt = time.Now()
+	}
+
+	todoCopy := *todo
+	return &todoCopy, nil
+}
+
+// GetSubtasks retrieves the subtasks of a todo in a list owned by a specific
+// user, in position order
+func (s *Storage) GetSubtasks(userID, listID, todoID uuid.UUID) ([]models.Subtask, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, _, err := s.findTodo(userID, listID, todoID); err != nil {
+		return nil, err
+	}
+	return s.subtasksOf(todoID), nil
+}
+
+// CreateSubtask adds a subtask after the existing subtasks of a todo in a list
+// owned by a specific user
+func (s *Storage) CreateSubtask(
+	userID, listID, todoID uuid.UUID, req models.CreateSubtaskRequest,
+) (*models.Subtask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, _, err := s.findTodo(userID, listID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := s.subtasksOf(todoID)
+	position := 0
+	if len(existing) > 0 {
+		position = existing[len(existing)-1].Position + 1
+	}
+
+	now := time.Now()
+	subtask := &models.Subtask{
+		ID:          uuid.New(),
+		TodoID:      todoID,
+		Description: req.Description,
+		Position:    position,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	setSubtaskCompleted(subtask, req.Completed, now)
+	s.subtasks[subtask.ID] = subtask
+	s.refreshSubtaskCounts(todo)
+
+	subtaskCopy := *subtask
+	return &subtaskCopy, nil
+}
+
+// UpdateSubtask updates a subtask of a todo in a list owned by a specific user.
+// With req.CompleteParent, completing the last open subtask completes the todo.
+func (s *Storage) UpdateSubtask(
+	userID, listID, todoID, subtaskID uuid.UUID, req models.UpdateSubtaskRequest,
+) (*models.Subtask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, list, err := s.findTodo(userID, listID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	subtask, exists := s.subtasks[subtaskID]
+	if !exists || subtask.TodoID != todoID {
+		return nil, ErrSubtaskNotFound
+	}
+
+	now := time.Now()
+	if req.Description != nil {
+		subtask.Description = *req.Description
+	}
+	if req.Completed != nil {
+		setSubtaskCompleted(subtask, *req.Completed, now)
+	}
+	subtask.UpdatedAt = now
+	s.refreshSubtaskCounts(todo)
+
+	if completesParent(todo, req) {
+		completed := true
+		applyTodoUpdate(todo, list, models.UpdateTodoRequest{Completed: &completed}, now)
+		todo.Version++
+		todo.UpdatedAt = now
+	}
+
+	subtaskCopy := *subtask
+	return &subtaskCopy, nil
+}
+
+// DeleteSubtask deletes a subtask of a todo in a list owned by a specific user
+func (s *Storage) DeleteSubtask(userID, listID, todoID, subtaskID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, _, err := s.findTodo(userID, listID, todoID)
+	if err != nil {
+		return err
+	}
+	subtask, exists := s.subtasks[subtaskID]
+	if !exists || subtask.TodoID != todoID {
+		return ErrSubtaskNotFound
+	}
+
+	delete(s.subtasks, subtaskID)
+	s.refreshSubtaskCounts(todo)
+	return nil
+}
+
+// findTodo looks up a todo and its list, checking that the list belongs to
+// the user. Must be called with lock held.
+func (s *Storage) findTodo(userID, listID, todoID uuid.UUID) (*models.Todo, *models.TodoList, error) {
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, nil, ErrListNotFound
+	}
+	todo, exists := s.todos[todoID]
+	if !exists || todo.ListID != listID {
+		return nil, nil, ErrTodoNotFound
+	}
+	return todo, list, nil
+}
+
+// subtasksOf returns copies of a todo's subtasks in position order. Must be
+// called with lock held.
+func (s *Storage) subtasksOf(todoID uuid.UUID) []models.Subtask {
+	result := make([]models.Subtask, 0)
+	for _, subtask := range s.subtasks {
+		if subtask.TodoID == todoID {
+			result = append(result, *subtask)
+		}
+	}
+	sortSubtasks(result)
+	return result
+}
+
+// refreshSubtaskCounts recounts a todo's subtasks. Must be called with lock held.
+func (s *Storage) refreshSubtaskCounts(todo *models.Todo) {
+	todo.SubtaskCount, todo.CompletedSubtaskCount = 0, 0
+	for _, subtask := range s.subtasks {
+		if subtask.TodoID != todo.ID {
+			continue
+		}
+		todo.SubtaskCount++
+		if subtask.Completed {
+			todo.CompletedSubtaskCount++
+		}
+	}
+}
+
+// setSubtasksCompleted completes or reopens every subtask of a todo that is
+// not already in that state. Must be called with lock held.
+func (s *Storage) setSubtasksCompleted(todo *models.Todo, completed bool, now time.Time) {
+	for _, subtask := range s.subtasks {
+		if subtask.TodoID == todo.ID && subtask.Completed != completed {
+			setSubtaskCompleted(subtask, completed, now)
+			subtask.UpdatedAt = now
+		}
+	}
+	s.refreshSubtaskCounts(todo)
+}
+
+// setSubtaskCompleted completes or reopens a subtask, setting CompletedAt when
+// it becomes completed and clearing it when it is reopened
+func setSubtaskCompleted(subtask *models.Subtask, completed bool, now time.Time) {
+	if completed && !subtask.Completed {
+		completedAt := now
+		subtask.CompletedAt = &completedAt
+	} else if !completed {
+		subtask.CompletedAt = nil
+	}
+	subtask.Completed = completed
+}
+
+// cascadesToSubtasks reports whether a todo update from before to after
+// changes the todo's completion in a way the list carries over to its
+// subtasks, and whether the subtasks should then be completed or reopened
+func cascadesToSubtasks(list *models.TodoList, before, after *models.Todo) (completed, ok bool) {
+	switch {
+	case after.Completed && !before.Completed:
+		return true, list.CompleteSubtasksWithParent
+	case !after.Completed && before.Completed:
+		return false, list.ReopenSubtasksWithParent
+	}
+	return false, false
+}
+
+// completesParent reports whether a subtask update asked to complete the
+// parent todo and left every one of its subtasks completed
+func completesParent(todo *models.Todo, req models.UpdateSubtaskRequest) bool {
+	return req.CompleteParent && !todo.Completed &&
+		todo.SubtaskCount > 0 && todo.CompletedSubtaskCount == todo.SubtaskCount
+}
+
+// sortSubtasks orders subtasks by position, then creation time and ID
+func sortSubtasks(subtasks []models.Subtask) {
+	sort.Slice(subtasks, func(i, j int) bool {
+		a, b := subtasks[i], subtasks[j]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+}
+
+// sortDeletedTodos orders trashed todos most recently deleted first, by ID on ties
+func sortDeletedTodos(todos []models.DeletedTodo) {
+	sort.Slice(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		if !a.DeletedAt.Equal(b.DeletedAt) {
+			return a.DeletedAt.After(b.DeletedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+}
+
+// PurgeOldCompleted permanently deletes todos, across all users, that were
+// completed before the given time, except in lists that keep completed todos
+func (s *Storage) PurgeOldCompleted(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, todo := range s.todos {
+		if !todo.Completed || todo.CompletedAt == nil || !todo.CompletedAt.Before(before) {
+			continue
+		}
+		if list, exists := s.lists[todo.ListID]; exists && list.KeepCompleted {
+			continue
+		}
+		delete(s.todos, id)
+		s.deleteSubtasksOf(id)
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeDeletedTodos permanently deletes todos, across all users, that were
+// deleted more than olderThan ago
+func (s *Storage) PurgeDeletedTodos(olderThan time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := time.Now().Add(-olderThan)
+	var purged int64
+	for id, todo := range s.deletedTodos {
+		if !todo.DeletedAt.Time.Before(before) {
+			continue
+		}
+		delete(s.deletedTodos, id)
+		s.deleteSubtasksOf(id)
+		purged++
+	}
+	return purged, nil
+}
+
+// QueryUserTodos retrieves todos across all lists owned by a specific user
+// with filtering, sorting and pagination, annotating each with its list name
+func (s *Storage) QueryUserTodos(userID uuid.UUID, opts TodoQueryOptions) ([]models.TodoWithList, *models.Pagination, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	listNames := make(map[uuid.UUID]string)
+	for _, list := range s.lists {
+		if list.UserID == userID {
+			listNames[list.ID] = list.Name
+		}
+	}
+
+	now := time.Now().UTC()
+	matched := make([]models.Todo, 0)
+	for _, todo := range s.todos {
+		if _, owned := listNames[todo.ListID]; !owned {
+			continue
+		}
+		if !opts.matches(todo, now) {
+			continue
+		}
+		matched = append(matched, *todo)
+	}
+
+	if err := sortTodos(matched, opts.SortBy, opts.SortOrder); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(opts.Page, opts.Limit, len(matched))
+	page := paginate(matched, pagination)
+
+	result := make([]models.TodoWithList, 0, len(page))
+	for _, todo := range page {
+		result = append(result, models.TodoWithList{Todo: todo, ListName: listNames[todo.ListID]})
+	}
+
+	return result, pagination, nil
+}
+
+// ReprioritizeTodos sets priority on every todo across a user's lists that
+// matches opts, ignoring its sorting and pagination, and returns how many
+// todos it changed. Todos that already have the priority are left alone.
+func (s *Storage) ReprioritizeTodos(userID uuid.UUID, opts TodoQueryOptions, priority models.Priority) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	changed := 0
+	for _, todo := range s.todos {
+		list, exists := s.lists[todo.ListID]
+		if !exists || list.UserID != userID {
+			continue
+		}
+		if todo.Priority == priority || !opts.matches(todo, now.UTC()) {
+			continue
+		}
+		todo.Priority = priority
+		todo.Version++
+		todo.UpdatedAt = now
+		changed++
+	}
+	return changed, nil
+}
+
+// SearchTodos returns a page of a user's active todos matching a search query,
+// each paired with the name of its list
+func (s *Storage) SearchTodos(userID uuid.UUID, opts SearchOptions) ([]models.TodoWithList, *models.Pagination, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := strings.ToLower(opts.Query)
+	listNames := make(map[uuid.UUID]string)
+	for _, list := range s.lists {
+		if list.UserID == userID {
+			listNames[list.ID] = list.Name
+		}
+	}
+
+	matched := make([]models.Todo, 0)
+	for _, todo := range s.todos {
+		listName, owned := listNames[todo.ListID]
+		if !owned || todo.ArchivedAt != nil {
+			continue
+		}
+		if opts.Priority != nil && todo.Priority != *opts.Priority {
+			continue
+		}
+		if opts.Completed != nil && todo.Completed != *opts.Completed {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(todo.Description), query) &&
+			!(opts.IncludeListNames && strings.Contains(strings.ToLower(listName), query)) {
+			continue
+		}
+		matched = append(matched, *todo)
+	}
+
+	if err := sortTodos(matched, sortFieldCreatedAt, sortOrderDesc); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(opts.Page, opts.Limit, len(matched))
+	page := paginate(matched, pagination)
+
+	result := make([]models.TodoWithList, 0, len(page))
+	for _, todo := range page {
+		result = append(result, models.TodoWithList{Todo: todo, ListName: listNames[todo.ListID]})
+	}
+
+	return result, pagination, nil
+}
+
+// GetListStats computes aggregate statistics for a list owned by a specific user
+func (s *Storage) GetListStats(userID, listID uuid.UUID) (*models.ListStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	now := time.Now()
+	stats := &models.ListStats{ListID: listID}
+	for _, todo := range s.todos {
+		if todo.ListID != listID {
+			continue
+		}
+
+		stats.Total++
+		stats.ByPriority.Add(todo.Priority, 1)
+		if todo.Completed {
+			stats.Completed++
+		} else {
+			stats.Incomplete++
+		}
+		if isOverdue(todo, now) {
+			stats.Overdue++
+		}
+
+		if todo.EstimateMinutes != nil {
+			stats.TotalEstimateMinutes += *todo.EstimateMinutes
+			if !todo.Completed {
+				stats.RemainingEstimateMinutes += *todo.EstimateMinutes
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// GetUserStats summarizes a user's todos across all of their lists
+func (s *Storage) GetUserStats(userID uuid.UUID) (*models.UserStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := &models.UserStats{}
+	owned := make(map[uuid.UUID]bool)
+	for _, list := range s.lists {
+		if list.UserID == userID {
+			owned[list.ID] = true
+			stats.Lists++
+		}
+	}
+
+	now := time.Now()
+	for _, todo := range s.todos {
+		if !owned[todo.ListID] {
+			continue
+		}
+
+		stats.TotalTodos++
+		if todo.Completed {
+			stats.CompletedTodos++
+		}
+		if isOverdue(todo, now) {
+			stats.Overdue++
+		}
+		if todo.CompletedAt != nil {
+			if !todo.CompletedAt.Before(now.Add(-completedWeekWindow)) {
+				stats.CompletedLast7Days++
+			}
+			if !todo.CompletedAt.Before(now.Add(-completedMonthWindow)) {
+				stats.CompletedLast30Days++
+			}
+		}
+	}
+	stats.CompletionRate = completionRate(stats.CompletedTodos, stats.TotalTodos)
+
+	return stats, nil
+}
+
+// GetNotifications returns a notification for each of the user's incomplete,
+// unarchived todos that is overdue or due within dueSoon of now, leaving out
+// those the user acknowledged, soonest due first
+func (s *Storage) GetNotifications(userID uuid.UUID, now time.Time, dueSoon time.Duration) ([]models.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notifications := make([]models.Notification, 0)
+	for _, todo := range s.todos {
+		list, ok := s.lists[todo.ListID]
+		if !ok || list.UserID != userID || todo.ArchivedAt != nil {
+			continue
+		}
+		notification, ok := notificationFor(todo, list.Name, now, dueSoon)
+		if !ok || s.acknowledged[notificationKey{userID, todo.ID, notification.Type}] {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	sortNotifications(notifications)
+
+	return notifications, nil
+}
+
+// AcknowledgeNotification dismisses the notification of a type for one of
+// the user's todos so GetNotifications no longer returns it. Acknowledging
+// twice is not an error.
+func (s *Storage) AcknowledgeNotification(userID, todoID uuid.UUID, notificationType models.NotificationType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todo, ok := s.todos[todoID]
+	if !ok {
+		return ErrTodoNotFound
+	}
+	if list, listOK := s.lists[todo.ListID]; !listOK || list.UserID != userID {
+		return ErrTodoNotFound
+	}
+
+	s.acknowledged[notificationKey{userID, todoID, notificationType}] = true
+	return nil
+}
+
+// CreateShareLink creates a link giving read-only public access to one of the
+// user's lists until expiresAt. The returned link carries the token to hand
+// out; only its hash is kept.
+func (s *Storage) CreateShareLink(userID, listID uuid.UUID, expiresAt time.Time) (*models.ListShareLink, error) {
+	token, tokenHash, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return nil, ErrListNotFound
+	}
+
+	link := &models.ListShareLink{
+		ID:        uuid.New(),
+		ListID:    listID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	s.shareLinks[link.ID] = link
+
+	result := *link
+	result.Token = token
+	return &result, nil
+}
+
+// RevokeShareLink stops a share link of one of the user's lists from working.
+// Returns ErrShareLinkNotFound if the list has no such link or it was already
+// revoked.
+func (s *Storage) RevokeShareLink(userID, listID, linkID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, exists := s.lists[listID]
+	if !exists || list.UserID != userID {
+		return ErrListNotFound
+	}
 
+	link, exists := s.shareLinks[linkID]
+	if !exists || link.ListID != listID || link.RevokedAt != nil {
+		return ErrShareLinkNotFound
+	}
+	now := time.Now()
+	link.RevokedAt = &now
+	return nil
+}
+
+// GetSharedList returns the share link a token belongs to, with its list.
+// Unknown, expired and revoked tokens and links to deleted lists all give
+// ErrShareLinkNotFound.
+func (s *Storage) GetSharedList(token string, now time.Time) (*models.ListShareLink, error) {
+	tokenHash := hashShareToken(token)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, link := range s.shareLinks {
+		if link.TokenHash != tokenHash {
+			continue
+		}
+		list, exists := s.lists[link.ListID]
+		if !exists || !shareLinkUsable(link, now) {
+			return nil, ErrShareLinkNotFound
+		}
+		result := *link
+		result.List = *list
+		return &result, nil
+	}
+	return nil, ErrShareLinkNotFound
+}
+
+// deleteTodo removes a todo, keeping it as a tombstone unless in hard delete
+// mode. Must be called with lock held.
+func (s *Storage) deleteTodo(todoID uuid.UUID, now time.Time) {
+	todo := s.todos[todoID]
 	delete(s.todos, todoID)
+	if s.deleteMode != DeleteModeHard {
+		todo.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		s.deletedTodos[todoID] = todo
+		return
+	}
+	s.deleteSubtasksOf(todoID)
+}
+
+// deleteSubtasksOf removes every subtask of a todo. Must be called with lock held.
+func (s *Storage) deleteSubtasksOf(todoID uuid.UUID) {
+	for id, subtask := range s.subtasks {
+		if subtask.TodoID == todoID {
+			delete(s.subtasks, id)
+		}
+	}
+}
+
+// listMatchesSearch reports whether a list's name or description contains
+// search, ignoring case. An empty search matches every list.
+func listMatchesSearch(list *models.TodoList, search string) bool {
+	if search == "" {
+		return true
+	}
+	search = strings.ToLower(search)
+	return strings.Contains(strings.ToLower(list.Name), search) ||
+		strings.Contains(strings.ToLower(list.Description), search)
+}
+
+// matches reports whether a list's archive state is one a selects
+func (a ArchivedLists) matches(list *models.TodoList) bool {
+	switch a {
+	case OnlyArchivedLists:
+		return list.Archived
+	case AllLists:
+		return true
+	default:
+		return !list.Archived
+	}
+}
+
+// checkListNameAvailable returns ErrListNameExists if another unarchived list
+// owned by the user already has the given name. excludeID is skipped so a list
+// can keep its own name on update; pass uuid.Nil when creating. Must be called
+// with lock held.
+func (s *Storage) checkListNameAvailable(userID uuid.UUID, name string, excludeID uuid.UUID) error {
+	for _, l := range s.lists {
+		if l.UserID == userID && l.ID != excludeID && !l.Archived && l.Name == name {
+			return ErrListNameExists
+		}
+	}
 	return nil
 }
 
+// cloneDescription marks a description as a copy, keeping it unchanged when
+// the suffix would push it past the description length limit
+func cloneDescription(description string) string {
+	if len([]rune(description))+len(cloneSuffix) > maxTodoDescriptionLength {
+		return description
+	}
+	return description + cloneSuffix
+}
+
+// copyListName names the nth attempt at a duplicate of a list: "Copy of X",
+// then "Copy of X (2)", "Copy of X (3)" and so on. The original name is
+// shortened as needed to fit the name column.
+func copyListName(name string, attempt int) string {
+	suffix := ""
+	if attempt > 1 {
+		suffix = " (" + strconv.Itoa(attempt) + ")"
+	}
+	runes := []rune(name)
+	if room := maxListNameLength - len(copyPrefix) - len(suffix); len(runes) > room {
+		runes = runes[:room]
+	}
+	return copyPrefix + string(runes) + suffix
+}
+
+// duplicateTodo copies a todo into listID as an open todo with no completion
+// or archive state. The caller assigns the ID and timestamps.
+func duplicateTodo(source *models.Todo, listID uuid.UUID) *models.Todo {
+	return &models.Todo{
+		ListID:          listID,
+		Description:     source.Description,
+		Priority:        source.Priority,
+		DueDate:         source.DueDate,
+		EstimateMinutes: source.EstimateMinutes,
+		Tags:            source.Tags,
+		RecurrenceRule:  source.RecurrenceRule,
+		Position:        source.Position,
+		Pinned:          source.Pinned,
+		Version:         1,
+	}
+}
+
 // countTodosInList counts todos in a list (must be called with lock held)
 func (s *Storage) countTodosInList(listID uuid.UUID) int {
 	count := 0
@@ -346,10 +1843,190 @@ func (s *Storage) countTodosInList(listID uuid.UUID) int {
 	return count
 }
 
+// matches reports whether a todo satisfies the list filters (tags must already be normalized)
+func (opts ListTodosOptions) matches(todo *models.Todo, now time.Time) bool {
+	if opts.Priority != nil && todo.Priority != *opts.Priority {
+		return false
+	}
+	if opts.Completed != nil && todo.Completed != *opts.Completed {
+		return false
+	}
+	if !hasAllTags(todo.Tags, opts.Tags) {
+		return false
+	}
+	if opts.Archived != (todo.ArchivedAt != nil) {
+		return false
+	}
+	if opts.Overdue && !isOverdue(todo, now) {
+		return false
+	}
+	if !dueWithin(todo.DueDate, opts.DueAfter, opts.DueBefore) {
+		return false
+	}
+	return createdWithin(todo.CreatedAt, opts.CreatedAfter, opts.CreatedBefore)
+}
+
+// matches reports whether a todo satisfies the query filters
+func (opts TodoQueryOptions) matches(todo *models.Todo, now time.Time) bool {
+	if opts.Priority != nil && todo.Priority != *opts.Priority {
+		return false
+	}
+	if opts.Completed != nil && todo.Completed != *opts.Completed {
+		return false
+	}
+	if opts.Archived != (todo.ArchivedAt != nil) {
+		return false
+	}
+	if opts.Overdue && !isOverdue(todo, now) {
+		return false
+	}
+	if !dueWithin(todo.DueDate, opts.DueAfter, opts.DueBefore) {
+		return false
+	}
+	if opts.Search != "" && !strings.Contains(strings.ToLower(todo.Description), strings.ToLower(opts.Search)) {
+		return false
+	}
+	return createdWithin(todo.CreatedAt, opts.CreatedAfter, opts.CreatedBefore)
+}
+
+// notificationKey identifies an acknowledged notification
+type notificationKey struct {
+	userID           uuid.UUID
+	todoID           uuid.UUID
+	notificationType models.NotificationType
+}
+
+// notificationFor returns the notification a todo raises at now, if any: an
+// incomplete todo is overdue once its due date has passed and due soon while
+// it falls within dueSoon of now
+func notificationFor(todo *models.Todo, listName string, now time.Time, dueSoon time.Duration) (models.Notification, bool) {
+	if todo.Completed || todo.DueDate == nil || !todo.DueDate.Before(now.Add(dueSoon)) {
+		return models.Notification{}, false
+	}
+
+	notificationType := models.NotificationDueSoon
+	if isOverdue(todo, now) {
+		notificationType = models.NotificationOverdue
+	}
+	return models.Notification{
+		ID:          models.NotificationID(notificationType, todo.ID),
+		Type:        notificationType,
+		TodoID:      todo.ID,
+		ListID:      todo.ListID,
+		ListName:    listName,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+		DueDate:     *todo.DueDate,
+	}, true
+}
+
+// newShareToken generates a random share link token and the hash stored for it
+func newShareToken() (token, tokenHash string, err error) {
+	raw := make([]byte, 32)
+	if _, readErr := rand.Read(raw); readErr != nil {
+		return "", "", fmt.Errorf("failed to generate share token: %w", readErr)
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashShareToken(token), nil
+}
+
+// hashShareToken returns the SHA-256 hash under which a share token is stored
+func hashShareToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// shareLinkUsable reports whether a share link is neither revoked nor expired at now
+func shareLinkUsable(link *models.ListShareLink, now time.Time) bool {
+	return link.RevokedAt == nil && now.Before(link.ExpiresAt)
+}
+
+// sortNotifications orders notifications soonest due first
+func sortNotifications(notifications []models.Notification) {
+	sort.SliceStable(notifications, func(i, j int) bool {
+		if !notifications[i].DueDate.Equal(notifications[j].DueDate) {
+			return notifications[i].DueDate.Before(notifications[j].DueDate)
+		}
+		return notifications[i].TodoID.String() < notifications[j].TodoID.String()
+	})
+}
+
+// Windows for the "completed in the last N days" counts of UserStats
+const (
+	completedWeekWindow  = 7 * 24 * time.Hour
+	completedMonthWindow = 30 * 24 * time.Hour
+)
+
+// completionRate returns completed as a fraction of total, or 0 when there
+// are no todos
+func completionRate(completed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(completed) / float64(total)
+}
+
+// isOverdue reports whether a todo is incomplete and its due date is before
+// now; todos without a due date are never overdue
+func isOverdue(todo *models.Todo, now time.Time) bool {
+	return !todo.Completed && todo.DueDate != nil && todo.DueDate.Before(now)
+}
+
+// dueWithin reports whether dueDate falls inside the inclusive range
+// [after, before]; a todo without a due date never matches a bound
+func dueWithin(dueDate, after, before *time.Time) bool {
+	if after == nil && before == nil {
+		return true
+	}
+	return dueDate != nil && createdWithin(*dueDate, after, before)
+}
+
+// createdWithin reports whether createdAt falls inside the inclusive range
+// [after, before]; a nil bound is open
+func createdWithin(createdAt time.Time, after, before *time.Time) bool {
+	if after != nil && createdAt.Before(*after) {
+		return false
+	}
+	if before != nil && createdAt.After(*before) {
+		return false
+	}
+	return true
+}
+
+// newPagination builds pagination info, defaulting invalid page and limit values
+func newPagination(page, limit, totalItems int) *models.Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	return &models.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (totalItems + limit - 1) / limit,
+		TotalItems: totalItems,
+	}
+}
+
+// paginate returns the slice of todos on the given page, which is empty when
+// the page is past the end
+func paginate(todos []models.Todo, pagination *models.Pagination) []models.Todo {
+	start := (pagination.Page - 1) * pagination.Limit
+	end := start + pagination.Limit
+	if start > len(todos) {
+		start = len(todos)
+	}
+	if end > len(todos) {
+		end = len(todos)
+	}
+	return todos[start:end]
+}
+
 // sortTodos sorts todos based on the specified field and order
 func sortTodos(todos []models.Todo, sortBy, sortOrder string) error {
 	// Validate sort field before creating the comparison function
-	validFields := []string{sortFieldDueDate, sortFieldPriority, sortFieldCreatedAt, ""}
+	validFields := []string{sortFieldDueDate, sortFieldPriority, sortFieldCreatedAt, sortFieldPosition, ""}
 	isValid := false
 	for _, field := range validFields {
 		if sortBy == field {
@@ -361,38 +2038,52 @@ func sortTodos(todos []models.Todo, sortBy, sortOrder string) error {
 		return ErrInvalidSortField
 	}
 
-	less := func(i, j int) bool {
-		var result bool
+	// Start from a deterministic order so ties stay oldest first (then by ID)
+	// on every backend and pagination never skips or repeats items
+	sort.Slice(todos, func(i, j int) bool {
+		if todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].ID.String() < todos[j].ID.String()
+		}
+		return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+	})
+
+	priorityOrder := map[models.Priority]int{
+		models.PriorityHigh:   3,
+		models.PriorityMedium: 2,
+		models.PriorityLow:    1,
+	}
+
+	// before reports whether a sorts ahead of b in ascending order
+	before := func(a, b *models.Todo) bool {
 		switch sortBy {
 		case sortFieldDueDate:
 			// Handle nil due dates (put them at the end)
 			switch {
-			case todos[i].DueDate == nil && todos[j].DueDate == nil:
-				result = todos[i].CreatedAt.Before(todos[j].CreatedAt)
-			case todos[i].DueDate == nil:
-				result = false
-			case todos[j].DueDate == nil:
-				result = true
+			case a.DueDate == nil:
+				return false
+			case b.DueDate == nil:
+				return true
 			default:
-				result = todos[i].DueDate.Before(*todos[j].DueDate)
+				return a.DueDate.Before(*b.DueDate)
 			}
 		case sortFieldPriority:
-			priorityOrder := map[models.Priority]int{
-				models.PriorityHigh:   3,
-				models.PriorityMedium: 2,
-				models.PriorityLow:    1,
-			}
-			result = priorityOrder[todos[i].Priority] > priorityOrder[todos[j].Priority]
-		case sortFieldCreatedAt, "":
-			result = todos[i].CreatedAt.Before(todos[j].CreatedAt)
+			return priorityOrder[a.Priority] > priorityOrder[b.Priority]
+		case sortFieldPosition:
+			return a.Position < b.Position
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
 		}
+	}
 
+	// Pinned todos come first whatever the sort field and order
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Pinned != todos[j].Pinned {
+			return todos[i].Pinned
+		}
 		if sortOrder == sortOrderDesc {
-			return !result
+			return before(&todos[j], &todos[i])
 		}
-		return result
-	}
-
-	sort.Slice(todos, less)
+		return before(&todos[i], &todos[j])
+	})
 	return nil
 }
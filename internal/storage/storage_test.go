@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -70,7 +71,7 @@ func TestGetAllLists(t *testing.T) {
 	}
 
 	t.Run("returns paginated lists", func(t *testing.T) {
-		lists, pagination, err := store.GetAllLists(testMemoryUserID, 1, 10)
+		lists, pagination, err := store.GetAllLists(testMemoryUserID, 1, 10, "", nil, nil, ActiveLists)
 		require.NoError(t, err)
 		assert.Len(t, lists, 10)
 		assert.Equal(t, 1, pagination.Page)
@@ -80,14 +81,14 @@ func TestGetAllLists(t *testing.T) {
 	})
 
 	t.Run("returns correct page", func(t *testing.T) {
-		lists, pagination, err := store.GetAllLists(testMemoryUserID, 2, 10)
+		lists, pagination, err := store.GetAllLists(testMemoryUserID, 2, 10, "", nil, nil, ActiveLists)
 		require.NoError(t, err)
 		assert.Len(t, lists, 10)
 		assert.Equal(t, 2, pagination.Page)
 	})
 
 	t.Run("returns last page with remaining items", func(t *testing.T) {
-		lists, pagination, err := store.GetAllLists(testMemoryUserID, 3, 10)
+		lists, pagination, err := store.GetAllLists(testMemoryUserID, 3, 10, "", nil, nil, ActiveLists)
 		require.NoError(t, err)
 		assert.Len(t, lists, 5)
 		assert.Equal(t, 3, pagination.Page)
@@ -179,7 +180,7 @@ func TestDeleteList(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("successfully deletes list and todos", func(t *testing.T) {
-		err := store.DeleteList(testMemoryUserID, created.ID)
+		err := store.DeleteList(testMemoryUserID, created.ID, nil)
 		require.NoError(t, err)
 
 		// Verify list is deleted
@@ -187,17 +188,59 @@ func TestDeleteList(t *testing.T) {
 		assert.ErrorIs(t, err, ErrListNotFound)
 
 		// Verify todos are deleted
-		todos, err := store.GetTodosByList(testMemoryUserID, created.ID, nil, nil, "createdAt", "asc")
+		todos, _, err := store.GetTodosByList(
+			testMemoryUserID, created.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		assert.ErrorIs(t, err, ErrListNotFound)
 		assert.Nil(t, todos)
 	})
 
 	t.Run("fails when list not found", func(t *testing.T) {
-		err := store.DeleteList(testMemoryUserID, uuid.New())
+		err := store.DeleteList(testMemoryUserID, uuid.New(), nil)
 		assert.ErrorIs(t, err, ErrListNotFound)
 	})
 }
 
+func TestDeleteListDeleteMode(t *testing.T) {
+	for _, tc := range []struct {
+		mode           string
+		wantTombstones int
+	}{
+		{DeleteModeSoft, 1},
+		{DeleteModeHard, 0},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			store := NewStorageWithConfig(&Config{DeleteMode: tc.mode})
+			list, err := store.CreateList(testMemoryUserID, models.CreateTodoListRequest{Name: "Test List"})
+			require.NoError(t, err)
+			_, err = store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{Description: "Todo", Priority: models.PriorityLow})
+			require.NoError(t, err)
+
+			require.NoError(t, store.DeleteList(testMemoryUserID, list.ID, nil))
+
+			assert.Len(t, store.deletedLists, tc.wantTombstones)
+			assert.Len(t, store.deletedTodos, tc.wantTombstones)
+			for _, tombstone := range store.deletedLists {
+				assert.True(t, tombstone.DeletedAt.Valid)
+			}
+		})
+	}
+}
+
+func TestCopyListName(t *testing.T) {
+	assert.Equal(t, "Copy of Work", copyListName("Work", 1))
+	assert.Equal(t, "Copy of Work (2)", copyListName("Work", 2))
+
+	// Long names are shortened so the copy still fits the name column
+	long := strings.Repeat("é", maxListNameLength)
+	for _, attempt := range []int{1, 12} {
+		name := copyListName(long, attempt)
+		assert.Len(t, []rune(name), maxListNameLength)
+		assert.True(t, strings.HasPrefix(name, copyPrefix))
+	}
+	assert.True(t, strings.HasSuffix(copyListName(long, 12), "é (12)"))
+}
+
 func TestCreateTodo(t *testing.T) {
 	store := NewStorage()
 
@@ -234,6 +277,46 @@ func TestCreateTodo(t *testing.T) {
 	})
 }
 
+func TestUniqueTodoDescriptions(t *testing.T) {
+	store := NewStorageWithConfig(&Config{UniqueTodoDescriptions: true})
+
+	list, err := store.CreateList(testMemoryUserID, models.CreateTodoListRequest{Name: "Test List"})
+	require.NoError(t, err)
+	pack, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+	require.NoError(t, err)
+	unpack, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{Description: "Unpack", Priority: models.PriorityLow})
+	require.NoError(t, err)
+
+	t.Run("blocks an open duplicate ignoring case", func(t *testing.T) {
+		_, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{Description: "pack", Priority: models.PriorityLow})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+
+		description := "PACK"
+		_, err = store.UpdateTodo(testMemoryUserID, list.ID, unpack.ID, models.UpdateTodoRequest{Description: &description})
+		assert.ErrorIs(t, err, ErrDuplicateTodo)
+
+		// The rejected update leaves the todo unchanged
+		todo, err := store.GetTodoByID(testMemoryUserID, list.ID, unpack.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Unpack", todo.Description)
+	})
+
+	t.Run("allows renaming a todo to its own description", func(t *testing.T) {
+		description := "PACK"
+		_, err := store.UpdateTodo(testMemoryUserID, list.ID, pack.ID, models.UpdateTodoRequest{Description: &description})
+		assert.NoError(t, err)
+	})
+
+	t.Run("allows a duplicate of a completed todo", func(t *testing.T) {
+		completed := true
+		_, err := store.UpdateTodo(testMemoryUserID, list.ID, pack.ID, models.UpdateTodoRequest{Completed: &completed})
+		require.NoError(t, err)
+
+		_, err = store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{Description: "Pack", Priority: models.PriorityLow})
+		assert.NoError(t, err)
+	})
+}
+
 func TestGetTodosByList(t *testing.T) {
 	store := NewStorage()
 
@@ -254,14 +337,18 @@ func TestGetTodosByList(t *testing.T) {
 	}
 
 	t.Run("gets all todos", func(t *testing.T) {
-		result, err := store.GetTodosByList(testMemoryUserID, list.ID, nil, nil, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(
+			testMemoryUserID, list.ID, ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Len(t, result, 3)
 	})
 
 	t.Run("filters by priority", func(t *testing.T) {
 		priority := models.PriorityHigh
-		result, err := store.GetTodosByList(testMemoryUserID, list.ID, &priority, nil, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(
+			testMemoryUserID, list.ID, ListTodosOptions{Priority: &priority, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 		assert.Equal(t, models.PriorityHigh, result[0].Priority)
@@ -269,19 +356,25 @@ func TestGetTodosByList(t *testing.T) {
 
 	t.Run("filters by completion status", func(t *testing.T) {
 		completed := false
-		result, err := store.GetTodosByList(testMemoryUserID, list.ID, nil, &completed, "createdAt", "asc")
+		result, _, err := store.GetTodosByList(
+			testMemoryUserID, list.ID, ListTodosOptions{Completed: &completed, SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Len(t, result, 3)
 	})
 
 	t.Run("sorts by priority", func(t *testing.T) {
-		result, err := store.GetTodosByList(testMemoryUserID, list.ID, nil, nil, "priority", "asc")
+		result, _, err := store.GetTodosByList(
+			testMemoryUserID, list.ID, ListTodosOptions{SortBy: "priority", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		require.NoError(t, err)
 		assert.Equal(t, models.PriorityHigh, result[0].Priority)
 	})
 
 	t.Run("fails when list not found", func(t *testing.T) {
-		_, err := store.GetTodosByList(testMemoryUserID, uuid.New(), nil, nil, "createdAt", "asc")
+		_, _, err := store.GetTodosByList(
+			testMemoryUserID, uuid.New(), ListTodosOptions{SortBy: "createdAt", SortOrder: "asc", Page: 1, Limit: 100},
+		)
 		assert.ErrorIs(t, err, ErrListNotFound)
 	})
 }
@@ -362,7 +455,7 @@ func TestDeleteTodo(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("successfully deletes todo", func(t *testing.T) {
-		err := store.DeleteTodo(testMemoryUserID, list.ID, todo.ID)
+		err := store.DeleteTodo(testMemoryUserID, list.ID, todo.ID, nil)
 		require.NoError(t, err)
 
 		_, err = store.GetTodoByID(testMemoryUserID, list.ID, todo.ID)
@@ -370,12 +463,55 @@ func TestDeleteTodo(t *testing.T) {
 	})
 
 	t.Run("fails when list not found", func(t *testing.T) {
-		err := store.DeleteTodo(testMemoryUserID, uuid.New(), uuid.New())
+		err := store.DeleteTodo(testMemoryUserID, uuid.New(), uuid.New(), nil)
 		assert.ErrorIs(t, err, ErrListNotFound)
 	})
 
 	t.Run("fails when todo not found", func(t *testing.T) {
-		err := store.DeleteTodo(testMemoryUserID, list.ID, uuid.New())
+		err := store.DeleteTodo(testMemoryUserID, list.ID, uuid.New(), nil)
 		assert.ErrorIs(t, err, ErrTodoNotFound)
 	})
 }
+
+func TestGetListStats(t *testing.T) {
+	store := NewStorage()
+
+	req := models.CreateTodoListRequest{Name: "Test List"}
+	list, err := store.CreateList(testMemoryUserID, req)
+	require.NoError(t, err)
+
+	thirty, sixty := 30, 60
+	_, err = store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Short task", Priority: models.PriorityLow, EstimateMinutes: &thirty,
+	})
+	require.NoError(t, err)
+	done, err := store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Long task", Priority: models.PriorityHigh, EstimateMinutes: &sixty,
+	})
+	require.NoError(t, err)
+	_, err = store.CreateTodo(testMemoryUserID, list.ID, models.CreateTodoRequest{
+		Description: "Unestimated", Priority: models.PriorityMedium,
+	})
+	require.NoError(t, err)
+
+	completed := true
+	_, err = store.UpdateTodo(testMemoryUserID, list.ID, done.ID, models.UpdateTodoRequest{Completed: &completed})
+	require.NoError(t, err)
+
+	t.Run("sums estimates and excludes completed from remaining", func(t *testing.T) {
+		stats, err := store.GetListStats(testMemoryUserID, list.ID)
+		require.NoError(t, err)
+		assert.Equal(t, list.ID, stats.ListID)
+		assert.Equal(t, 90, stats.TotalEstimateMinutes)
+		assert.Equal(t, 30, stats.RemainingEstimateMinutes)
+		assert.Equal(t, 3, stats.Total)
+		assert.Equal(t, 1, stats.Completed)
+		assert.Equal(t, 2, stats.Incomplete)
+		assert.Equal(t, models.PriorityCounts{High: 1, Medium: 1, Low: 1}, stats.ByPriority)
+	})
+
+	t.Run("fails when list not found", func(t *testing.T) {
+		_, err := store.GetListStats(testMemoryUserID, uuid.New())
+		assert.ErrorIs(t, err, ErrListNotFound)
+	})
+}
@@ -34,7 +34,9 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		last_name TEXT,
 		role TEXT NOT NULL DEFAULT 'user',
 		is_active INTEGER DEFAULT 1,
+		email_verified INTEGER NOT NULL DEFAULT 0,
 		last_login_at DATETIME,
+		expires_at DATETIME,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -46,6 +48,14 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		user_id TEXT NOT NULL,
 		name TEXT NOT NULL,
 		description TEXT,
+		auto_archive_completed INTEGER DEFAULT 0,
+		keep_completed INTEGER DEFAULT 0,
+		require_due_date INTEGER DEFAULT 0,
+		complete_subtasks_with_parent INTEGER DEFAULT 0,
+		reopen_subtasks_with_parent INTEGER DEFAULT 0,
+		archived INTEGER NOT NULL DEFAULT 0,
+		archived_at DATETIME,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME,
@@ -59,8 +69,16 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		description TEXT NOT NULL,
 		priority TEXT NOT NULL,
 		due_date DATETIME,
+		estimate_minutes INTEGER,
 		completed INTEGER DEFAULT 0,
 		completed_at DATETIME,
+		archived_at DATETIME,
+		position INTEGER NOT NULL DEFAULT 0,
+		subtask_count INTEGER NOT NULL DEFAULT 0,
+		completed_subtask_count INTEGER NOT NULL DEFAULT 0,
+		recurrence_rule TEXT NOT NULL DEFAULT '',
+		pinned INTEGER NOT NULL DEFAULT 0,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME,
@@ -68,6 +86,27 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	)`).Error
 	require.NoError(t, err, "Failed to create todos table")
 
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS todo_tags (
+		todo_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY(todo_id, tag),
+		FOREIGN KEY(todo_id) REFERENCES todos(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create todo_tags table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS subtasks (
+		id TEXT PRIMARY KEY,
+		todo_id TEXT NOT NULL,
+		description TEXT NOT NULL,
+		completed INTEGER NOT NULL DEFAULT 0,
+		completed_at DATETIME,
+		position INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		FOREIGN KEY(todo_id) REFERENCES todos(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create subtasks table")
+
 	err = db.Exec(`CREATE TABLE IF NOT EXISTS refresh_tokens (
 		id TEXT PRIMARY KEY,
 		user_id TEXT NOT NULL,
@@ -75,17 +114,97 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		expires_at DATETIME NOT NULL,
 		created_at DATETIME,
 		revoked_at DATETIME,
+		user_agent TEXT NOT NULL DEFAULT '',
+		ip_address TEXT NOT NULL DEFAULT '',
 		deleted_at DATETIME,
 		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
 	)`).Error
 	require.NoError(t, err, "Failed to create refresh_tokens table")
 
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS user_settings (
+		user_id TEXT PRIMARY KEY,
+		timezone TEXT NOT NULL DEFAULT 'UTC',
+		hide_completed INTEGER NOT NULL DEFAULT 0,
+		default_sort_by TEXT NOT NULL DEFAULT 'createdAt',
+		default_sort_order TEXT NOT NULL DEFAULT 'asc',
+		created_at DATETIME,
+		updated_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create user_settings table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS password_history (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		password_hash TEXT NOT NULL,
+		created_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create password_history table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create password_reset_tokens table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS email_verification_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create email_verification_tokens table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS acknowledged_notifications (
+		user_id TEXT NOT NULL,
+		todo_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		created_at DATETIME,
+		PRIMARY KEY(user_id, todo_id, type),
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY(todo_id) REFERENCES todos(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create acknowledged_notifications table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS list_share_links (
+		id TEXT PRIMARY KEY,
+		list_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		revoked_at DATETIME,
+		created_at DATETIME,
+		FOREIGN KEY(list_id) REFERENCES todo_lists(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create list_share_links table")
+
+	err = db.Exec(`CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key_hash TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		completed INTEGER NOT NULL DEFAULT 0,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		content_type TEXT NOT NULL DEFAULT '',
+		location TEXT NOT NULL DEFAULT '',
+		body BLOB,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`).Error
+	require.NoError(t, err, "Failed to create idempotency_keys table")
+
 	// Create indexes
 	db.Exec(`CREATE INDEX idx_users_deleted_at ON users(deleted_at)`)
 	db.Exec(`CREATE INDEX idx_users_is_active ON users(is_active)`)
 	db.Exec(`CREATE INDEX idx_todo_lists_user_id ON todo_lists(user_id)`)
 	db.Exec(`CREATE INDEX idx_todo_lists_deleted_at ON todo_lists(deleted_at)`)
-	db.Exec(`CREATE UNIQUE INDEX idx_user_list_name ON todo_lists(user_id, name) WHERE deleted_at IS NULL`)
+	db.Exec(`CREATE UNIQUE INDEX idx_user_list_name ON todo_lists(user_id, name) WHERE deleted_at IS NULL AND archived = 0`)
 	db.Exec(`CREATE INDEX idx_todos_list_id ON todos(list_id)`)
 	db.Exec(`CREATE INDEX idx_todos_completed ON todos(completed)`)
 	db.Exec(`CREATE INDEX idx_todos_deleted_at ON todos(deleted_at)`)